@@ -2,17 +2,80 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/permissions"
+	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+const (
+	auditDefaultPage    = "0"
+	auditDefaultPerPage = "60"
+	auditMaxPerPage     = 200
+
+	auditExportFlushInterval = 100
+
+	deliveriesDefaultPerPage = "60"
+	deliveriesMaxPerPage     = 200
+
+	notificationsSearchDefaultPerPage = "60"
+	notificationsSearchMaxPerPage     = 200
+
+	topActorsDefaultLimit = "10"
+	topActorsMaxLimit     = 100
+	topActorsDefaultSince = 24 * time.Hour
+
+	bulkBoardMembersMax = 500
+
+	usersDefaultPage    = "0"
+	usersDefaultPerPage = "60"
+	usersMaxPerPage     = 200
+
+	userBoardsDefaultPerPage = "60"
+	userBoardsMaxPerPage     = 200
+
+	// localAdminSessionID groups the password resets of the local mode admin APIs, which
+	// have no session, for rate limiting
+	localAdminSessionID = "local"
+)
+
+// exportedAuditRecord is an audit record whose create time is formatted as RFC3339 in the
+// time zone requested for an export.
+type exportedAuditRecord struct {
+	*model.AuditRecord
+	CreateAt string `json:"createAt"`
+}
+
+// parseExportTimeZone returns the time zone of the `tz` parameter of an export, or nil if
+// the timestamps should be kept in milliseconds since epoch.
+func parseExportTimeZone(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, model.NewErrBadRequest("invalid `tz` parameter: " + tz)
+	}
+	return loc, nil
+}
+
+// formatExportTime formats milliseconds since epoch as RFC3339 in the given time zone.
+func formatExportTime(millis int64, loc *time.Location) string {
+	return utils.GetTimeForMillis(millis).In(loc).Format(time.RFC3339)
+}
+
 type AdminSetPasswordData struct {
 	Password string `json:"password"`
 }
@@ -26,10 +89,30 @@ type AdminUpdateUserData struct {
 
 func (a *API) registerAdminRoutes(r *mux.Router) {
 	// Admin User Management APIs
-	r.HandleFunc("/admin/users", a.sessionRequired(a.handleAdminGetAllUsers)).Methods("GET")
+	r.HandleFunc("/admin/users", a.compressed(a.sessionRequired(a.handleAdminGetAllUsers))).Methods("GET")
 	r.HandleFunc("/admin/users/{userID}", a.sessionRequired(a.handleAdminGetUser)).Methods("GET")
 	r.HandleFunc("/admin/users/{userID}", a.sessionRequired(a.handleAdminUpdateUser)).Methods("PUT")
 	r.HandleFunc("/admin/users/{userID}", a.sessionRequired(a.handleAdminDeleteUser)).Methods("DELETE")
+	r.HandleFunc("/admin/users/{userID}/boards", a.compressed(a.sessionRequired(a.handleAdminGetUserBoardMemberships))).Methods("GET")
+	r.HandleFunc("/admin/users/{userID}/notifications", a.sessionRequired(a.handleAdminPurgeUserNotifications)).Methods("DELETE")
+
+	// Admin Audit APIs
+	r.HandleFunc("/admin/audit", a.compressed(a.sessionRequired(a.handleAdminGetAuditRecords))).Methods("GET")
+	r.HandleFunc("/admin/audit/export", a.sessionRequired(a.handleAdminExportAuditRecords)).Methods("GET")
+
+	// Admin Notification APIs
+	r.HandleFunc("/admin/notifications", a.compressed(a.sessionRequired(a.handleAdminSearchNotifications))).Methods("GET")
+	r.HandleFunc("/admin/notifications/deliveries", a.compressed(a.sessionRequired(a.handleAdminGetNotificationDeliveries))).Methods("GET")
+	r.HandleFunc("/admin/notifications/top-actors", a.compressed(a.sessionRequired(a.handleAdminGetTopNotificationActors))).Methods("GET")
+	r.HandleFunc("/admin/notifications/reassign-board", a.sessionRequired(a.handleAdminReassignNotificationsBoard)).Methods("POST")
+	r.HandleFunc("/admin/notifications/recompute-counts", a.sessionRequired(a.handleAdminRecomputeNotificationCounts)).Methods("POST")
+	r.HandleFunc("/admin/notifications/{notificationID}", a.sessionRequired(a.handleAdminPatchNotification)).Methods("PUT")
+
+	// Admin Permissions APIs
+	r.HandleFunc("/admin/permissions/refresh", a.sessionRequired(a.handleAdminRefreshPermissions)).Methods("POST")
+
+	// Admin Board Membership APIs
+	r.HandleFunc("/admin/boards/{boardID}/members/bulk", a.sessionRequired(a.handleAdminBulkSetBoardMemberRoles)).Methods("POST")
 }
 
 func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +128,7 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	var requestData AdminSetPasswordData
 	err = json.Unmarshal(requestBody, &requestData)
 	if err != nil {
-		a.errorResponse(w, r, err)
+		a.errorResponse(w, r, a.invalidPayloadError("password", err))
 		return
 	}
 
@@ -58,6 +141,11 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = a.checkAdminPasswordReset(r, auditRec); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
 	err = a.app.UpdateUserPassword(username, requestData.Password)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -70,7 +158,31 @@ func (a *API) handleAdminSetPassword(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
-// handleAdminGetAllUsers returns all registered users (admin only)
+// checkAdminPasswordReset returns a too many requests error if the admin session of the
+// request reached the AdminPasswordResetsPerMinute limit. Rate limited resets are flagged
+// in the audit record so runaway loops or a compromised admin token can be alerted on.
+func (a *API) checkAdminPasswordReset(r *http.Request, auditRec *audit.Record) error {
+	sessionID := localAdminSessionID
+	if session, ok := r.Context().Value(sessionContextKey).(*model.Session); ok {
+		sessionID = session.ID
+	}
+
+	allowed, recent := a.app.AllowAdminPasswordReset(sessionID)
+	if allowed {
+		return nil
+	}
+
+	auditRec.AddMeta("rateLimited", true)
+	auditRec.AddMeta("recentPasswordResets", recent)
+	a.logger.Warn("Admin password resets rate limited",
+		mlog.String("sessionID", sessionID),
+		mlog.Int("recentResets", recent),
+	)
+	return model.NewErrTooManyRequests("too many password resets, try again in a minute")
+}
+
+// handleAdminGetAllUsers returns all registered users, or a page of them if the page,
+// per_page or cursor parameters are set (admin only)
 func (a *API) handleAdminGetAllUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	session := ctx.Value(sessionContextKey).(*model.Session)
@@ -81,6 +193,13 @@ func (a *API) handleAdminGetAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// without pagination parameters all users are returned, as an array
+	query := r.URL.Query()
+	if query.Has("page") || query.Has("per_page") || query.Has("cursor") {
+		a.getUsersPage(w, r)
+		return
+	}
+
 	auditRec := a.makeAuditRecord(r, "adminGetAllUsers", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
 
@@ -100,6 +219,67 @@ func (a *API) handleAdminGetAllUsers(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+// getUsersPage returns a page of users for handleAdminGetAllUsers. The first pages can be
+// selected by number, deeper ones with the cursor returned with the previous page.
+func (a *API) getUsersPage(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if strPage == "" {
+		strPage = usersDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = usersDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage <= 0 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if perPage > usersMaxPerPage {
+		perPage = usersMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetUsersPage", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	opts := model.QueryUsersOptions{
+		Page:    page,
+		PerPage: perPage,
+		Cursor:  query.Get("cursor"),
+	}
+
+	users, more, err := a.app.GetUsersPage(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	response := model.UsersResponse{
+		HasNext: more,
+		Results: users,
+	}
+	if more && len(users) > 0 {
+		response.NextCursor = model.NewUsersCursor(users[len(users)-1])
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
 // handleAdminGetUser returns a specific user by ID (admin only)
 func (a *API) handleAdminGetUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -157,7 +337,7 @@ func (a *API) handleAdminUpdateUser(w http.ResponseWriter, r *http.Request) {
 	var updateData AdminUpdateUserData
 	err = json.Unmarshal(requestBody, &updateData)
 	if err != nil {
-		a.errorResponse(w, r, err)
+		a.errorResponse(w, r, a.invalidPayloadError("user", err))
 		return
 	}
 
@@ -165,6 +345,13 @@ func (a *API) handleAdminUpdateUser(w http.ResponseWriter, r *http.Request) {
 	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
 	auditRec.AddMeta("userID", userID)
 
+	if updateData.Password != "" {
+		if err = a.checkAdminPasswordReset(r, auditRec); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
+
 	// Get existing user
 	user, err := a.app.GetUser(userID)
 	if err != nil {
@@ -208,7 +395,8 @@ func (a *API) handleAdminUpdateUser(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
-// handleAdminDeleteUser deletes a user (admin only)
+// handleAdminDeleteUser deletes a user (admin only). With dryRun=true it returns what the
+// deletion would remove instead, without deleting anything.
 func (a *API) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	session := ctx.Value(sessionContextKey).(*model.Session)
@@ -232,6 +420,25 @@ func (a *API) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("dryRun") == True {
+		auditRec.AddMeta("dryRun", true)
+		summary, err := a.app.GetUserDeletionSummary(userID)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+
+		data, err := json.Marshal(summary)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+
+		jsonBytesResponse(w, http.StatusOK, data)
+		auditRec.Success()
+		return
+	}
+
 	err := a.app.DeleteUser(userID)
 	if err != nil {
 		a.errorResponse(w, r, err)
@@ -243,3 +450,1260 @@ func (a *API) handleAdminDeleteUser(w http.ResponseWriter, r *http.Request) {
 	jsonStringResponse(w, http.StatusOK, "{}")
 	auditRec.Success()
 }
+
+// handleAdminGetAuditRecords returns a page of audit records (admin only)
+func (a *API) handleAdminGetAuditRecords(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/audit adminGetAuditRecords
+	//
+	// Returns audit records, newest first. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: user_id
+	//   in: query
+	//   description: Only return records for actions performed by this user
+	//   required: false
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: Only return records for this event (action)
+	//   required: false
+	//   type: string
+	// - name: level
+	//   in: query
+	//   description: Only return records of this level (auth, mod, read)
+	//   required: false
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only return records created at or after this time (milliseconds since epoch)
+	//   required: false
+	//   type: integer
+	// - name: until
+	//   in: query
+	//   description: Only return records created at or before this time (milliseconds since epoch)
+	//   required: false
+	//   type: integer
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of records to return per page (default=60, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/AuditRecordsResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	query := r.URL.Query()
+	level := query.Get("level")
+	strSince := query.Get("since")
+	strUntil := query.Get("until")
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	switch level {
+	case "", audit.LevelAuth.Name, audit.LevelModify.Name, audit.LevelRead.Name:
+	default:
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid `level` parameter: "+level))
+		return
+	}
+
+	var since, until int64
+	var err error
+	if strSince != "" {
+		since, err = strconv.ParseInt(strSince, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `since` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+	if strUntil != "" {
+		until, err = strconv.ParseInt(strUntil, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `until` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	if strPage == "" {
+		strPage = auditDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = auditDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage <= 0 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if perPage > auditMaxPerPage {
+		perPage = auditMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetAuditRecords", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	opts := model.QueryAuditRecordsOptions{
+		UserID:  query.Get("user_id"),
+		Event:   query.Get("event"),
+		Level:   level,
+		Since:   since,
+		Until:   until,
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	records, more, err := a.app.GetAuditRecords(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminGetAuditRecords",
+		mlog.Int("recordsCount", len(records)),
+		mlog.Bool("hasNext", more),
+	)
+
+	response := model.AuditRecordsResponse{
+		HasNext: more,
+		Results: records,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// handleAdminExportAuditRecords streams audit records as NDJSON (admin only)
+func (a *API) handleAdminExportAuditRecords(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/audit/export adminExportAuditRecords
+	//
+	// Streams the audit records matching the filters as newline delimited JSON, one record
+	// per line, newest first. Meant for archiving records before the retention job purges
+	// them. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/x-ndjson
+	// parameters:
+	// - name: user_id
+	//   in: query
+	//   description: Only export records for actions performed by this user
+	//   required: false
+	//   type: string
+	// - name: event
+	//   in: query
+	//   description: Only export records for this event (action)
+	//   required: false
+	//   type: string
+	// - name: level
+	//   in: query
+	//   description: Only export records of this level (auth, mod, read)
+	//   required: false
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only export records created at or after this time (milliseconds since epoch)
+	//   required: false
+	//   type: integer
+	// - name: until
+	//   in: query
+	//   description: Only export records created at or before this time (milliseconds since epoch)
+	//   required: false
+	//   type: integer
+	// - name: tz
+	//   in: query
+	//   description: IANA time zone, e.g. Europe/Paris, to export the create times in as RFC3339 instead of milliseconds since epoch
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	query := r.URL.Query()
+	level := query.Get("level")
+	strSince := query.Get("since")
+	strUntil := query.Get("until")
+
+	switch level {
+	case "", audit.LevelAuth.Name, audit.LevelModify.Name, audit.LevelRead.Name:
+	default:
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid `level` parameter: "+level))
+		return
+	}
+
+	var since, until int64
+	var err error
+	if strSince != "" {
+		since, err = strconv.ParseInt(strSince, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `since` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+	if strUntil != "" {
+		until, err = strconv.ParseInt(strUntil, 10, 64)
+		if err != nil {
+			message := fmt.Sprintf("invalid `until` parameter: %s", err)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	loc, err := parseExportTimeZone(r)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminExportAuditRecords", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	opts := model.QueryAuditRecordsOptions{
+		UserID: query.Get("user_id"),
+		Event:  query.Get("event"),
+		Level:  level,
+		Since:  since,
+		Until:  until,
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", "attachment; filename=audit_records.ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	count := 0
+	err = a.app.ExportAuditRecords(opts, func(record *model.AuditRecord) error {
+		var line interface{} = record
+		if loc != nil {
+			line = exportedAuditRecord{AuditRecord: record, CreateAt: formatExportTime(record.CreateAt, loc)}
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+		count++
+		if flusher != nil && count%auditExportFlushInterval == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// the status is already sent, the truncated export is all we can do
+		a.logger.Error("AdminExportAuditRecords failed",
+			mlog.Int("recordsCount", count),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	a.logger.Debug("AdminExportAuditRecords",
+		mlog.Int("recordsCount", count),
+	)
+
+	auditRec.AddMeta("recordsCount", count)
+	auditRec.Success()
+}
+
+// handleAdminSearchNotifications returns a page of the notifications of every user (admin only)
+func (a *API) handleAdminSearchNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/notifications adminSearchNotifications
+	//
+	// Searches the notifications of every user, newest first, with the code path each
+	// one was created from. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userId
+	//   in: query
+	//   description: Only return the notifications targeting this user
+	//   required: false
+	//   type: string
+	// - name: boardId
+	//   in: query
+	//   description: Only return the notifications of this board
+	//   required: false
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: Only return the notifications of this type
+	//   required: false
+	//   type: string
+	// - name: source
+	//   in: query
+	//   description: Only return the notifications created from this source (api, webhook, job, plugin, server)
+	//   required: false
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of notifications to return per page (default=60, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationsSearchResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	query := r.URL.Query()
+	source := query.Get("source")
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if source != "" && !model.IsValidNotificationSource(source) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid `source` parameter: "+source))
+		return
+	}
+
+	if strPage == "" {
+		strPage = auditDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = notificationsSearchDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage <= 0 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if perPage > notificationsSearchMaxPerPage {
+		perPage = notificationsSearchMaxPerPage
+	}
+
+	opts := model.SearchUserNotificationsOptions{
+		UserID:  query.Get("userId"),
+		BoardID: query.Get("boardId"),
+		Type:    query.Get("type"),
+		Source:  source,
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminSearchNotifications", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("userID", opts.UserID)
+	auditRec.AddMeta("boardID", opts.BoardID)
+	auditRec.AddMeta("type", opts.Type)
+	auditRec.AddMeta("source", opts.Source)
+
+	notifications, more, err := a.app.SearchUserNotifications(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminSearchNotifications",
+		mlog.Int("notificationsCount", len(notifications)),
+		mlog.Bool("hasNext", more),
+	)
+
+	response := model.NotificationsSearchResponse{
+		HasNext: more,
+		Results: notifications,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// handleAdminGetNotificationDeliveries returns a page of notification deliveries (admin only)
+func (a *API) handleAdminGetNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/notifications/deliveries adminGetNotificationDeliveries
+	//
+	// Returns notification deliveries, most recently updated first. Defaults to the
+	// failed ones. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: Only return deliveries in this status (delivered, pending, sent, failed), defaults to failed
+	//   required: false
+	//   type: string
+	// - name: channel
+	//   in: query
+	//   description: Only return deliveries through this channel (inapp, email, webhook)
+	//   required: false
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of deliveries to return per page (default=60, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationDeliveriesResponse"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	query := r.URL.Query()
+	status := query.Get("status")
+	channel := query.Get("channel")
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	switch status {
+	case "":
+		status = model.NotificationDeliveryStatusFailed
+	case model.NotificationDeliveryStatusDelivered, model.NotificationDeliveryStatusPending,
+		model.NotificationDeliveryStatusSent, model.NotificationDeliveryStatusFailed:
+	default:
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid `status` parameter: "+status))
+		return
+	}
+
+	switch channel {
+	case "", model.NotificationChannelInApp, model.NotificationChannelEmail, model.NotificationChannelWebhook:
+	default:
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid `channel` parameter: "+channel))
+		return
+	}
+
+	if strPage == "" {
+		strPage = auditDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = deliveriesDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage <= 0 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if perPage > deliveriesMaxPerPage {
+		perPage = deliveriesMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetNotificationDeliveries", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("status", status)
+	auditRec.AddMeta("channel", channel)
+
+	opts := model.QueryNotificationDeliveriesOptions{
+		Channel: channel,
+		Status:  status,
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	deliveries, more, err := a.app.GetNotificationDeliveries(opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminGetNotificationDeliveries",
+		mlog.Int("deliveriesCount", len(deliveries)),
+		mlog.Bool("hasNext", more),
+	)
+
+	response := model.NotificationDeliveriesResponse{
+		HasNext: more,
+		Results: deliveries,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminGetTopNotificationActors(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/notifications/top-actors adminGetTopNotificationActors
+	//
+	// Returns the actors that generated the most notifications, most first. Caller must
+	// have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: since
+	//   in: query
+	//   description: Only count notifications created at or after this time in milliseconds since epoch, defaults to the last 24 hours
+	//   required: false
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: Number of actors to return (default=10, max=100)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationActorCount"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	query := r.URL.Query()
+	strSince := query.Get("since")
+	strLimit := query.Get("limit")
+
+	since := time.Now().Add(-topActorsDefaultSince).UnixMilli()
+	if strSince != "" {
+		var err error
+		since, err = strconv.ParseInt(strSince, 10, 64)
+		if err != nil || since < 0 {
+			message := fmt.Sprintf("invalid `since` parameter: %s", strSince)
+			a.errorResponse(w, r, model.NewErrBadRequest(message))
+			return
+		}
+	}
+
+	if strLimit == "" {
+		strLimit = topActorsDefaultLimit
+	}
+	limit, err := strconv.Atoi(strLimit)
+	if err != nil || limit <= 0 {
+		message := fmt.Sprintf("invalid `limit` parameter: %s", strLimit)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if limit > topActorsMaxLimit {
+		limit = topActorsMaxLimit
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetTopNotificationActors", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("since", since)
+	auditRec.AddMeta("limit", limit)
+
+	counts, err := a.app.CountNotificationsByActor(since, limit)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(counts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// handleAdminPatchNotification corrects a notification and pushes it again (admin only)
+func (a *API) handleAdminPatchNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /admin/notifications/{notificationID} adminPatchNotification
+	//
+	// Corrects the card title or actor name of a notification and pushes the updated
+	// notification to its target again. The target, type and creation time can't be
+	// changed. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the notification patch
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UserNotificationPatch"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserNotification"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	notificationID := mux.Vars(r)["notificationID"]
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var patch *model.UserNotificationPatch
+	if err = json.Unmarshal(requestBody, &patch); err != nil || patch == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid notification patch"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminPatchNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("notificationID", notificationID)
+
+	notification, err := a.app.PatchUserNotification(notificationID, patch)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminPatchNotification",
+		mlog.String("notificationID", notificationID),
+		mlog.String("targetUserID", notification.TargetUserID),
+	)
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("targetUserID", notification.TargetUserID)
+	auditRec.Success()
+}
+
+// handleAdminGetUserBoardMemberships returns a page of the board memberships of a user (admin only)
+func (a *API) handleAdminGetUserBoardMemberships(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /admin/users/{userID}/boards adminGetUserBoardMemberships
+	//
+	// Returns the boards a user is a member of with their effective role on each, e.g.
+	// before offboarding or merging the user. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: The page to select (default=0)
+	//   required: false
+	//   type: integer
+	// - name: per_page
+	//   in: query
+	//   description: Number of memberships to return per page (default=60, max=200)
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserBoardMembershipsResponse"
+	//   '404':
+	//     description: user not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	userID := mux.Vars(r)["userID"]
+	query := r.URL.Query()
+	strPage := query.Get("page")
+	strPerPage := query.Get("per_page")
+
+	if strPage == "" {
+		strPage = usersDefaultPage
+	}
+	if strPerPage == "" {
+		strPerPage = userBoardsDefaultPerPage
+	}
+	page, err := strconv.Atoi(strPage)
+	if err != nil || page < 0 {
+		message := fmt.Sprintf("invalid `page` parameter: %s", strPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	perPage, err := strconv.Atoi(strPerPage)
+	if err != nil || perPage <= 0 {
+		message := fmt.Sprintf("invalid `per_page` parameter: %s", strPerPage)
+		a.errorResponse(w, r, model.NewErrBadRequest(message))
+		return
+	}
+	if perPage > userBoardsMaxPerPage {
+		perPage = userBoardsMaxPerPage
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminGetUserBoardMemberships", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	memberships, more, err := a.app.GetBoardMembershipsForUser(userID, page, perPage)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminGetUserBoardMemberships",
+		mlog.String("userID", userID),
+		mlog.Int("membershipsCount", len(memberships)),
+		mlog.Bool("hasNext", more),
+	)
+
+	response := model.UserBoardMembershipsResponse{
+		HasNext: more,
+		Results: memberships,
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminPurgeUserNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /admin/users/{userID}/notifications adminPurgeUserNotifications
+	//
+	// Deletes all the notifications a user received, e.g. after they were deactivated.
+	// The notifications can't be restored, reactivating the user doesn't bring them back.
+	// Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success, returns the number of notifications deleted
+	//     schema:
+	//       type: object
+	//       properties:
+	//         count:
+	//           type: integer
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	userID := mux.Vars(r)["userID"]
+
+	auditRec := a.makeAuditRecord(r, "adminPurgeUserNotifications", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	count, err := a.app.PurgeNotificationsForUser(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminPurgeUserNotifications",
+		mlog.String("userID", userID),
+		mlog.Int("count", count),
+	)
+
+	data, err := json.Marshal(map[string]int64{"count": count})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("count", count)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminReassignNotificationsBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/notifications/reassign-board adminReassignNotificationsBoard
+	//
+	// Moves all the notifications of a board to another board, e.g. after boards were
+	// merged. Both boards must exist unless force is set. Caller must have
+	// `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: the boards to move the notifications between
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/NotificationBoardReassign"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success, returns the number of notifications moved
+	//     schema:
+	//       type: object
+	//       properties:
+	//         count:
+	//           type: integer
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var reassign *model.NotificationBoardReassign
+	if err = json.Unmarshal(requestBody, &reassign); err != nil || reassign == nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid notification board reassignment"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminReassignNotificationsBoard", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("fromBoardID", reassign.From)
+	auditRec.AddMeta("toBoardID", reassign.To)
+	auditRec.AddMeta("force", reassign.Force)
+
+	count, err := a.app.ReassignNotificationsBoard(reassign)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminReassignNotificationsBoard",
+		mlog.String("fromBoardID", reassign.From),
+		mlog.String("toBoardID", reassign.To),
+		mlog.Int("count", count),
+	)
+
+	data, err := json.Marshal(map[string]int64{"count": count})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("count", count)
+	auditRec.Success()
+}
+
+func (a *API) handleAdminRecomputeNotificationCounts(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/notifications/recompute-counts adminRecomputeNotificationCounts
+	//
+	// Counts the unread notifications again and broadcasts them to the clients, to repair
+	// badges that went out of sync with the read flags. Recomputes every user connected to
+	// this server unless userId is set. Caller must have `manage_system` permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userId
+	//   in: query
+	//   description: only recompute the count of this user, connected or not
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success, returns the number of users whose count was broadcast
+	//     schema:
+	//       type: object
+	//       properties:
+	//         count:
+	//           type: integer
+	//   '404':
+	//     description: user not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	userID := r.URL.Query().Get("userId")
+
+	auditRec := a.makeAuditRecord(r, "adminRecomputeNotificationCounts", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	count, err := a.app.RecomputeUnreadNotificationCounts(userID)
+	auditRec.AddMeta("count", count)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("AdminRecomputeNotificationCounts",
+		mlog.String("userID", userID),
+		mlog.Int("count", count),
+	)
+
+	data, err := json.Marshal(map[string]int{"count": count})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+// handleAdminBulkSetBoardMemberRoles creates or updates many board memberships at once
+func (a *API) handleAdminBulkSetBoardMemberRoles(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/boards/{boardID}/members/bulk adminBulkSetBoardMemberRoles
+	//
+	// Creates or updates the memberships of many users on a board, granting each the
+	// requested role. Caller must have `manage_board_roles` permissions on the board.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the roles to assign
+	//   required: true
+	//   schema:
+	//     type: array
+	//     items:
+	//       "$ref": "#/definitions/BoardMemberRoleAssignment"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardMemberRoleResult"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionManageBoardRoles) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to modify board members"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var assignments []model.BoardMemberRoleAssignment
+	if err = json.Unmarshal(requestBody, &assignments); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("board member roles", err))
+		return
+	}
+
+	if len(assignments) > bulkBoardMembersMax {
+		a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("too many members, the maximum is %d", bulkBoardMembersMax)))
+		return
+	}
+
+	if _, err = a.app.GetBoard(boardID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	results := make([]*model.BoardMemberRoleResult, 0, len(assignments))
+	for _, assignment := range assignments {
+		results = append(results, a.setBoardMemberRole(r, boardID, assignment))
+	}
+
+	a.logger.Debug("AdminBulkSetBoardMemberRoles",
+		mlog.String("boardID", boardID),
+		mlog.Int("count", len(results)),
+	)
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+// setBoardMemberRole applies and audits a single assignment of a bulk role change.
+func (a *API) setBoardMemberRole(r *http.Request, boardID string, assignment model.BoardMemberRoleAssignment) *model.BoardMemberRoleResult {
+	result := &model.BoardMemberRoleResult{
+		UserID: assignment.UserID,
+		Role:   assignment.Role,
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminSetBoardMemberRole", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("memberUserID", assignment.UserID)
+	auditRec.AddMeta("role", string(assignment.Role))
+
+	isGuest, err := a.userIsGuest(assignment.UserID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if isGuest && assignment.Role == model.BoardRoleAdmin {
+		result.Error = "guests cannot be board admins"
+		return result
+	}
+
+	member, err := a.app.SetBoardMemberRole(boardID, assignment.UserID, assignment.Role)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if member == nil {
+		result.Error = "board not found"
+		return result
+	}
+
+	result.Member = member
+	auditRec.Success()
+	return result
+}
+
+// handleAdminRefreshPermissions recomputes the cached system admins (admin only)
+func (a *API) handleAdminRefreshPermissions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /admin/permissions/refresh adminRefreshPermissions
+	//
+	// Recomputes who the first registered user is, for standalone servers where it is a
+	// system admin, without restarting the server. Caller must have `manage_system`
+	// permissions.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/AdminCacheRefresh"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// Check if user has system admin permission
+	if !a.permissions.HasPermissionTo(session.UserID, model.PermissionManageSystem) {
+		a.errorResponse(w, r, model.NewErrUnauthorized("not authorized to access admin panel"))
+		return
+	}
+
+	invalidator, ok := a.permissions.(permissions.AdminCacheInvalidator)
+	if !ok {
+		a.errorResponse(w, r, model.NewErrBadRequest("the permissions service does not cache admins"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "adminRefreshPermissions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelAuth, auditRec)
+
+	refresh := model.AdminCacheRefresh{FirstUserAdminID: invalidator.InvalidateAdminCache()}
+	auditRec.AddMeta("firstUserAdminID", refresh.FirstUserAdminID)
+
+	data, err := json.Marshal(refresh)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
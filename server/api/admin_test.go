@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExportTimeZone(t *testing.T) {
+	t.Run("no time zone keeps millis", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/admin/audit/export", nil)
+		loc, err := parseExportTimeZone(request)
+		require.NoError(t, err)
+		require.Nil(t, loc)
+	})
+
+	t.Run("valid time zone", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/admin/audit/export?tz=Asia/Tokyo", nil)
+		loc, err := parseExportTimeZone(request)
+		require.NoError(t, err)
+		require.Equal(t, "2021-01-01T09:00:00+09:00", formatExportTime(1609459200000, loc))
+	})
+
+	t.Run("invalid time zone", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/admin/audit/export?tz=Mars/Olympus", nil)
+		_, err := parseExportTimeZone(request)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestExportedAuditRecord(t *testing.T) {
+	record := &model.AuditRecord{ID: "record-1", CreateAt: 1609459200000}
+	data, err := json.Marshal(exportedAuditRecord{AuditRecord: record, CreateAt: "2021-01-01T00:00:00Z"})
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.Equal(t, "record-1", fields["id"])
+	require.Equal(t, "2021-01-01T00:00:00Z", fields["createAt"])
+}
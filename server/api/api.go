@@ -19,6 +19,8 @@ import (
 const (
 	HeaderRequestedWith    = "X-Requested-With"
 	HeaderRequestedWithXML = "XMLHttpRequest"
+	HeaderDeviceID         = "X-Device-Id"
+	HeaderUnreadCount      = "X-Unread-Count"
 	UploadFormFileKey      = "file"
 	True                   = "true"
 
@@ -41,6 +43,7 @@ type API struct {
 	MattermostAuth  bool
 	logger          mlog.LoggerIFace
 	audit           *audit.Audit
+	avatarCache     *avatarCache
 }
 
 func NewAPI(
@@ -51,14 +54,20 @@ func NewAPI(
 	logger mlog.LoggerIFace,
 	audit *audit.Audit,
 ) *API {
-	return &API{
+	api := &API{
 		app:             app,
 		singleUserToken: singleUserToken,
 		authService:     authService,
 		permissions:     permissions,
 		logger:          logger,
 		audit:           audit,
+		avatarCache:     newAvatarCache(app.GetConfig().AvatarCacheSize),
 	}
+
+	// avatars uploaded or deleted on other nodes of a cluster must not be served from cache
+	app.OnAvatarChanged(api.avatarCache.Remove)
+
+	return api
 }
 
 func (a *API) RegisterRoutes(r *mux.Router) {
@@ -189,6 +198,10 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 		errorResponse.ErrorCode = http.StatusForbidden
 	case model.IsErrNotFound(err):
 		errorResponse.ErrorCode = http.StatusNotFound
+	case model.IsErrConflict(err):
+		errorResponse.ErrorCode = http.StatusConflict
+	case model.IsErrTooManyRequests(err):
+		errorResponse.ErrorCode = http.StatusTooManyRequests
 	case model.IsErrRequestEntityTooLarge(err):
 		errorResponse.ErrorCode = http.StatusRequestEntityTooLarge
 	case model.IsErrNotImplemented(err):
@@ -213,6 +226,37 @@ func (a *API) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	_, _ = w.Write(data)
 }
 
+// invalidPayloadError turns the error decoding a JSON request body into a bad request
+// naming the payload and, when the decoder knows it, the offending field or position. The
+// raw decoder message is logged but not sent back, as it names internal Go types.
+func (a *API) invalidPayloadError(payload string, err error) error {
+	a.logger.Debug("Cannot decode request payload", mlog.String("payload", payload), mlog.Err(err))
+
+	message := "invalid " + payload + " payload"
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		message += fmt.Sprintf(": field %q can't be %s", typeErr.Field, jsonValueKind(typeErr.Value))
+	case errors.As(err, &syntaxErr):
+		message += fmt.Sprintf(": malformed JSON at offset %d", syntaxErr.Offset)
+	}
+	return model.NewErrBadRequest(message)
+}
+
+// jsonValueKind returns the kind of JSON value an UnmarshalTypeError reports, with its
+// article.
+func jsonValueKind(value string) string {
+	switch value {
+	case "array", "object":
+		return "an " + value
+	case "":
+		return "this value"
+	default:
+		return "a " + value
+	}
+}
+
 func stringResponse(w http.ResponseWriter, message string) {
 	setResponseHeader(w, "Content-Type", "text/plain")
 	_, _ = fmt.Fprint(w, message)
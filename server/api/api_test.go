@@ -1,14 +1,18 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 	"github.com/stretchr/testify/require"
 )
@@ -75,3 +79,64 @@ func TestErrorResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidPayloadError(t *testing.T) {
+	testAPI := API{logger: mlog.CreateConsoleTestLogger(t)}
+
+	testCases := []struct {
+		Name    string
+		Body    string
+		Message string
+	}{
+		{"malformed JSON", `{"targetUserId":`, "invalid notification payload: malformed JSON at offset"},
+		{"wrong field type", `{"targetUserId":42}`, `invalid notification payload: field "targetUserId" can't be a number`},
+		{"wrong payload type", `[]`, "invalid notification payload"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var notification model.UserNotification
+			err := testAPI.invalidPayloadError("notification", json.Unmarshal([]byte(tc.Body), &notification))
+			require.True(t, model.IsErrBadRequest(err))
+			require.Contains(t, err.Error(), tc.Message)
+			require.NotContains(t, err.Error(), "model.UserNotification")
+		})
+	}
+}
+
+func TestMalformedPayloads(t *testing.T) {
+	logger := mlog.CreateConsoleTestLogger(t)
+	testAPI := API{
+		logger:      logger,
+		permissions: localpermissions.New(nil, false, []string{"admin-1"}, logger),
+	}
+
+	testCases := []struct {
+		Name    string
+		Handler func(w http.ResponseWriter, r *http.Request)
+		Message string
+	}{
+		{"createNotification", testAPI.handleCreateNotification, "invalid notification payload"},
+		{"createNotifications", testAPI.handleCreateNotifications, "invalid notification payload"},
+		{"adminSetPassword", testAPI.handleAdminSetPassword, "invalid password payload"},
+		{"adminUpdateUser", testAPI.handleAdminUpdateUser, "invalid user payload"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"bogus`))
+			r = r.WithContext(context.WithValue(r.Context(), sessionContextKey, &model.Session{UserID: "admin-1"}))
+			w := httptest.NewRecorder()
+
+			tc.Handler(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, http.StatusBadRequest, res.StatusCode)
+			var body model.ErrorResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			require.Contains(t, body.Error, tc.Message)
+			require.NotContains(t, body.Error, "unexpected end of JSON input")
+		})
+	}
+}
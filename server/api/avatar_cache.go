@@ -0,0 +1,96 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// avatarCacheEntry is an avatar held in memory.
+type avatarCacheEntry struct {
+	userID      string
+	name        string
+	contentType string
+	modTime     time.Time
+	data        []byte
+}
+
+// avatarCache is a least recently used cache of small avatars bounded by the total size
+// of the cached images. A nil cache never holds anything, which disables caching.
+type avatarCache struct {
+	mux      sync.Mutex
+	maxBytes int64
+	size     int64
+	lru      *list.List
+	entries  map[string]*list.Element
+}
+
+func newAvatarCache(maxBytes int64) *avatarCache {
+	if maxBytes <= 0 {
+		return nil
+	}
+	return &avatarCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get returns the cached avatar of the user, marking it as recently used.
+func (c *avatarCache) Get(userID string) (*avatarCacheEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	elem, ok := c.entries[userID]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*avatarCacheEntry), true
+}
+
+// Add caches an avatar, evicting the least recently used ones until the cache fits its
+// size. Avatars larger than the whole cache are not cached.
+func (c *avatarCache) Add(entry *avatarCacheEntry) {
+	if c == nil || int64(len(entry.data)) > c.maxBytes {
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if elem, ok := c.entries[entry.userID]; ok {
+		c.removeElement(elem)
+	}
+
+	c.entries[entry.userID] = c.lru.PushFront(entry)
+	c.size += int64(len(entry.data))
+
+	for c.size > c.maxBytes {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// Remove drops the cached avatar of the user, if any.
+func (c *avatarCache) Remove(userID string) {
+	if c == nil {
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if elem, ok := c.entries[userID]; ok {
+		c.removeElement(elem)
+	}
+}
+
+func (c *avatarCache) removeElement(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*avatarCacheEntry)
+	delete(c.entries, entry.userID)
+	c.size -= int64(len(entry.data))
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvatarCache(t *testing.T) {
+	newEntry := func(userID string, size int) *avatarCacheEntry {
+		return &avatarCacheEntry{userID: userID, data: make([]byte, size)}
+	}
+
+	t.Run("disabled cache", func(t *testing.T) {
+		cache := newAvatarCache(0)
+		require.Nil(t, cache)
+
+		cache.Add(newEntry("user-1", 10))
+		_, ok := cache.Get("user-1")
+		require.False(t, ok)
+		cache.Remove("user-1")
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := newAvatarCache(30)
+		cache.Add(newEntry("user-1", 10))
+		cache.Add(newEntry("user-2", 10))
+		cache.Add(newEntry("user-3", 10))
+
+		// touch user-1 so user-2 becomes the least recently used
+		_, ok := cache.Get("user-1")
+		require.True(t, ok)
+
+		cache.Add(newEntry("user-4", 10))
+
+		_, ok = cache.Get("user-2")
+		require.False(t, ok)
+		for _, userID := range []string{"user-1", "user-3", "user-4"} {
+			_, ok = cache.Get(userID)
+			require.True(t, ok, userID)
+		}
+		require.Equal(t, int64(30), cache.size)
+	})
+
+	t.Run("replaces and removes entries", func(t *testing.T) {
+		cache := newAvatarCache(30)
+		cache.Add(newEntry("user-1", 10))
+		cache.Add(newEntry("user-1", 20))
+		require.Equal(t, int64(20), cache.size)
+
+		cache.Remove("user-1")
+		_, ok := cache.Get("user-1")
+		require.False(t, ok)
+		require.Equal(t, int64(0), cache.size)
+	})
+
+	t.Run("skips avatars larger than the cache", func(t *testing.T) {
+		cache := newAvatarCache(30)
+		cache.Add(newEntry("user-1", 31))
+		_, ok := cache.Get("user-1")
+		require.False(t, ok)
+	})
+}
@@ -20,9 +20,9 @@ const (
 
 func (a *API) registerComplianceRoutes(r *mux.Router) {
 	// Compliance APIs
-	r.HandleFunc("/admin/boards", a.sessionRequired(a.handleGetBoardsForCompliance)).Methods("GET")
-	r.HandleFunc("/admin/boards_history", a.sessionRequired(a.handleGetBoardsComplianceHistory)).Methods("GET")
-	r.HandleFunc("/admin/blocks_history", a.sessionRequired(a.handleGetBlocksComplianceHistory)).Methods("GET")
+	r.HandleFunc("/admin/boards", a.compressed(a.sessionRequired(a.handleGetBoardsForCompliance))).Methods("GET")
+	r.HandleFunc("/admin/boards_history", a.compressed(a.sessionRequired(a.handleGetBoardsComplianceHistory))).Methods("GET")
+	r.HandleFunc("/admin/blocks_history", a.compressed(a.sessionRequired(a.handleGetBlocksComplianceHistory))).Methods("GET")
 }
 
 func (a *API) handleGetBoardsForCompliance(w http.ResponseWriter, r *http.Request) {
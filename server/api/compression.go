@@ -0,0 +1,173 @@
+package api
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+
+	// compressMinSize is the response size below which compressing is not worth it
+	compressMinSize = 1024
+)
+
+// compressed compresses the response of a handler with gzip or deflate if the client
+// accepts it and the response is large enough. Responses that are already encoded or are
+// images, e.g. avatars, are sent as is.
+func (a *API) compressed(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			handler(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer func() {
+			if err := cw.close(); err != nil {
+				a.logger.Warn("Cannot finish compressed response", mlog.String("uri", r.URL.Path), mlog.Err(err))
+			}
+		}()
+		handler(cw, r)
+	}
+}
+
+// negotiateEncoding returns the preferred encoding the Accept-Encoding header allows,
+// gzip over deflate, or an empty string if the response should not be compressed.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers the start of a response until it knows whether the response is
+// large enough to be compressed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+	buf      []byte
+	writer   io.WriteCloser // the compressing writer once the response is compressed
+	decided  bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if cw.decided {
+		if cw.writer != nil {
+			return cw.writer.Write(data)
+		}
+		return cw.ResponseWriter.Write(data)
+	}
+
+	cw.buf = append(cw.buf, data...)
+	if len(cw.buf) >= compressMinSize {
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// Flush sends what is buffered so far, compressing it if the response allows it.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if err := cw.decide(true); err != nil {
+			return
+		}
+	}
+	if flusher, ok := cw.writer.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// decide sends the headers and the buffered data, compressed if large is set and the
+// response is not already encoded or an image.
+func (cw *compressWriter) decide(large bool) error {
+	cw.decided = true
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	header := cw.Header()
+	compress := large &&
+		header.Get("Content-Encoding") == "" &&
+		!strings.HasPrefix(header.Get("Content-Type"), "image/") &&
+		cw.status != http.StatusNoContent && cw.status != http.StatusNotModified
+
+	if compress {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		if cw.encoding == encodingGzip {
+			cw.writer = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			writer, err := flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+			if err != nil {
+				return err
+			}
+			cw.writer = writer
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if cw.writer != nil {
+		_, err = cw.writer.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// close sends a small response as is, or finishes the compressed stream.
+func (cw *compressWriter) close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			// the handler wrote nothing, let the server send its default response
+			return nil
+		}
+		return cw.decide(false)
+	}
+	if cw.writer != nil {
+		return cw.writer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	require.Equal(t, "", negotiateEncoding(""))
+	require.Equal(t, "gzip", negotiateEncoding("deflate, gzip"))
+	require.Equal(t, "deflate", negotiateEncoding("gzip;q=0, deflate"))
+	require.Equal(t, "", negotiateEncoding("br"))
+}
+
+func TestCompressed(t *testing.T) {
+	testAPI := API{logger: mlog.CreateConsoleTestLogger(t)}
+	large := strings.Repeat("notification ", compressMinSize)
+
+	serve := func(body, contentType string) *httptest.ResponseRecorder {
+		handler := testAPI.compressed(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(body))
+		})
+		request, _ := http.NewRequest(http.MethodGet, "/notifications", nil)
+		request.Header.Set("Accept-Encoding", "gzip")
+		response := httptest.NewRecorder()
+		handler(response, request)
+		return response
+	}
+
+	t.Run("large responses are compressed", func(t *testing.T) {
+		response := serve(large, "application/json")
+		require.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+		require.Equal(t, "Accept-Encoding", response.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(response.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, large, string(body))
+	})
+
+	t.Run("small responses are sent as is", func(t *testing.T) {
+		response := serve("[]", "application/json")
+		require.Empty(t, response.Header().Get("Content-Encoding"))
+		require.Equal(t, "[]", response.Body.String())
+	})
+
+	t.Run("images are sent as is", func(t *testing.T) {
+		response := serve(large, "image/png")
+		require.Empty(t, response.Header().Get("Content-Encoding"))
+		require.Equal(t, large, response.Body.String())
+	})
+}
@@ -14,6 +14,7 @@ import (
 func (a *API) registerMembersRoutes(r *mux.Router) {
 	// Member APIs
 	r.HandleFunc("/boards/{boardID}/members", a.sessionRequired(a.handleGetMembersForBoard)).Methods("GET")
+	r.HandleFunc("/boards/{boardID}/admins", a.sessionRequired(a.handleGetBoardAdmins)).Methods("GET")
 	r.HandleFunc("/boards/{boardID}/members", a.sessionRequired(a.handleAddMember)).Methods("POST")
 	r.HandleFunc("/boards/{boardID}/members/{userID}", a.sessionRequired(a.handleUpdateMember)).Methods("PUT")
 	r.HandleFunc("/boards/{boardID}/members/{userID}", a.sessionRequired(a.handleDeleteMember)).Methods("DELETE")
@@ -84,6 +85,69 @@ func (a *API) handleGetMembersForBoard(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleGetBoardAdmins(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/admins getBoardAdmins
+	//
+	// Returns the members who can administer the board
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/BoardAdmin"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board members"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardAdmins", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	admins, err := a.app.GetBoardAdmins(boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetBoardAdmins",
+		mlog.String("boardID", boardID),
+		mlog.Int("adminsCount", len(admins)),
+	)
+
+	data, err := json.Marshal(admins)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	// response
+	jsonBytesResponse(w, http.StatusOK, data)
+
+	auditRec.Success()
+}
+
 func (a *API) handleAddMember(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation POST /boards/{boardID}/members addMember
 	//
@@ -163,6 +227,14 @@ func (a *API) handleAddMember(w http.ResponseWriter, r *http.Request) {
 		SchemeViewer:    reqBoardMember.SchemeViewer,
 		SchemeCommenter: reqBoardMember.SchemeCommenter,
 	}
+	// members added without a role get the one joining the board would give them
+	if !newBoardMember.SchemeAdmin && !newBoardMember.SchemeEditor && !newBoardMember.SchemeCommenter && !newBoardMember.SchemeViewer {
+		role := a.app.DefaultBoardMemberRole(board)
+		newBoardMember.SchemeAdmin = role == model.BoardRoleAdmin
+		newBoardMember.SchemeEditor = role == model.BoardRoleEditor
+		newBoardMember.SchemeCommenter = role == model.BoardRoleCommenter
+		newBoardMember.SchemeViewer = role == model.BoardRoleViewer
+	}
 
 	auditRec := a.makeAuditRecord(r, "addMember", audit.Fail)
 	defer a.audit.LogRecord(audit.LevelModify, auditRec)
@@ -267,13 +339,14 @@ func (a *API) handleJoinBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	role := a.app.DefaultBoardMemberRole(board)
 	newBoardMember := &model.BoardMember{
 		UserID:          userID,
 		BoardID:         boardID,
-		SchemeAdmin:     board.MinimumRole == model.BoardRoleAdmin || isAdmin,
-		SchemeEditor:    board.MinimumRole == model.BoardRoleNone || board.MinimumRole == model.BoardRoleEditor,
-		SchemeCommenter: board.MinimumRole == model.BoardRoleCommenter,
-		SchemeViewer:    board.MinimumRole == model.BoardRoleViewer,
+		SchemeAdmin:     role == model.BoardRoleAdmin || isAdmin,
+		SchemeEditor:    role == model.BoardRoleEditor,
+		SchemeCommenter: role == model.BoardRoleCommenter,
+		SchemeViewer:    role == model.BoardRoleViewer,
 	}
 
 	auditRec := a.makeAuditRecord(r, "joinBoard", audit.Fail)
@@ -2,24 +2,66 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+const (
+	// notificationBoardFilterMax is the maximum number of boards notifications can be filtered by
+	notificationBoardFilterMax = 50
+)
+
 func (a *API) registerNotificationsRoutes(r *mux.Router) {
 	// Notifications APIs
-	r.HandleFunc("/notifications", a.sessionRequired(a.handleGetNotifications)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications", a.compressed(a.sessionRequired(a.handleGetNotifications))).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/types", a.sessionRequired(a.handleGetNotificationTypes)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/capabilities", a.sessionRequired(a.handleGetNotificationCapabilities)).Methods(http.MethodGet)
 	r.HandleFunc("/notifications/unread-count", a.sessionRequired(a.handleGetUnreadCount)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/oldest-unread", a.sessionRequired(a.handleGetOldestUnread)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/unread-by-board", a.sessionRequired(a.handleGetUnreadCountByBoard)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/threads", a.compressed(a.sessionRequired(a.handleGetNotificationThreads))).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/summary", a.sessionRequired(a.handleGetNotificationSummary)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/last-seen", a.sessionRequired(a.handleGetLastSeen)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/last-seen", a.sessionRequired(a.handleSetLastSeen)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/settings", a.sessionRequired(a.handleGetNotificationSettings)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/settings", a.sessionRequired(a.handleUpdateNotificationSettings)).Methods(http.MethodPut)
+	r.HandleFunc("/teams/{teamID}/notifications/defaults", a.sessionRequired(a.handleGetNotificationTeamDefaults)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/{teamID}/notifications/defaults", a.sessionRequired(a.handleUpdateNotificationTeamDefaults)).Methods(http.MethodPut)
+	r.HandleFunc("/boards/{boardID}/notifications/settings", a.sessionRequired(a.handleGetNotificationBoardSettings)).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{boardID}/notifications/settings", a.sessionRequired(a.handleUpdateNotificationBoardSettings)).Methods(http.MethodPut)
+	r.HandleFunc("/notifications/by-card/{cardID}", a.sessionRequired(a.handleGetCardNotifications)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/by-board/{boardID}", a.sessionRequired(a.handleGetBoardNotifications)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/blocked-actors", a.sessionRequired(a.handleGetNotificationActorBlocks)).Methods(http.MethodGet)
+	r.HandleFunc("/notifications/blocked-actors/{actorID}", a.sessionRequired(a.handleBlockNotificationActor)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/blocked-actors/{actorID}", a.sessionRequired(a.handleUnblockNotificationActor)).Methods(http.MethodDelete)
+	r.HandleFunc("/notifications/test", a.sessionRequired(a.handleSendTestNotification)).Methods(http.MethodPost)
 	r.HandleFunc("/notifications", a.sessionRequired(a.handleCreateNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/batch", a.sessionRequired(a.handleCreateNotifications)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/query", a.compressed(a.sessionRequired(a.handleQueryNotifications))).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/preview", a.sessionRequired(a.handlePreviewNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/read", a.sessionRequired(a.handleMarkNotificationsAsRead)).Methods(http.MethodPost)
 	r.HandleFunc("/notifications/{notificationID}/read", a.sessionRequired(a.handleMarkAsRead)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/unread", a.sessionRequired(a.handleMarkAsUnread)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/open", a.sessionRequired(a.handleOpenNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/action", a.sessionRequired(a.handleNotificationAction)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/archive", a.sessionRequired(a.handleArchiveNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/unarchive", a.sessionRequired(a.handleUnarchiveNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/pin", a.sessionRequired(a.handlePinNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}/unpin", a.sessionRequired(a.handleUnpinNotification)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/sync", a.sessionRequired(a.handleSyncNotificationReadStates)).Methods(http.MethodPost)
 	r.HandleFunc("/notifications/read-all", a.sessionRequired(a.handleMarkAllAsRead)).Methods(http.MethodPost)
+	r.HandleFunc("/notifications/{notificationID}", a.sessionRequired(a.handleGetNotification)).Methods(http.MethodGet)
 	r.HandleFunc("/notifications/{notificationID}", a.sessionRequired(a.handleDeleteNotification)).Methods(http.MethodDelete)
 }
 
@@ -37,11 +79,85 @@ func (a *API) handleGetNotifications(w http.ResponseWriter, r *http.Request) {
 	//   description: Maximum number of notifications to return
 	//   required: false
 	//   type: integer
+	// - name: category
+	//   in: query
+	//   description: Only return notifications in this category (mentions, tasks, system)
+	//   required: false
+	//   type: string
+	// - name: includeArchived
+	//   in: query
+	//   description: Also return archived notifications
+	//   required: false
+	//   type: boolean
+	// - name: boardId
+	//   in: query
+	//   description: Only return notifications of these boards, as a comma-separated list of up to 50 board IDs
+	//   required: false
+	//   type: string
+	// - name: excludeBoardId
+	//   in: query
+	//   description: Do not return notifications of this board, can't be combined with boardId
+	//   required: false
+	//   type: string
+	// - name: teamId
+	//   in: query
+	//   description: Only return notifications of this team
+	//   required: false
+	//   type: string
+	// - name: from
+	//   in: query
+	//   description: Only return notifications created at or after this time, in milliseconds since epoch
+	//   required: false
+	//   type: integer
+	// - name: to
+	//   in: query
+	//   description: Only return notifications created at or before this time, in milliseconds since epoch
+	//   required: false
+	//   type: integer
+	// - name: unresolvedOnly
+	//   in: query
+	//   description: Only return notifications still waiting for an action, e.g. assignments that were not undone nor dismissed
+	//   required: false
+	//   type: boolean
+	// - name: includeCard
+	//   in: query
+	//   description: Embed the current title and status of the card of each notification
+	//   required: false
+	//   type: boolean
+	// - name: orderBy
+	//   in: query
+	//   description: Sort by creation time (createAt, the default) or by last update time (updateAt), e.g. to list recently read notifications first. Pinned notifications always come first.
+	//   required: false
+	//   type: string
+	// - name: order
+	//   in: query
+	//   description: Sort direction, asc or desc (the default)
+	//   required: false
+	//   type: string
+	// - name: markReadOnFetch
+	//   in: query
+	//   description: Mark the returned notifications as read in the same transaction, leaving the ones outside of the page alone. They are returned with the read state they had.
+	//   required: false
+	//   type: boolean
+	// - name: cursor
+	//   in: query
+	//   description: List a page of notifications, returned as a NotificationsPage instead of an array. Empty for the first page, then the nextCursor of the previous page.
+	//   required: false
+	//   type: string
+	// - name: before
+	//   in: query
+	//   description: List a page of the notifications created before this time in milliseconds since epoch, which needs the default order, or listed after the notification of this ID in the requested order, returned as a NotificationsPage instead of an array. The nextBefore of a page selects the next one. Cannot be combined with cursor.
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success, a NotificationsPage if the cursor or before parameter is set
+	//     headers:
+	//       X-Unread-Count:
+	//         type: integer
+	//         description: the number of unread notifications of the user, whatever the filters
 	//     schema:
 	//       type: array
 	//       items:
@@ -60,75 +176,149 @@ func (a *API) handleGetNotifications(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	auditRec := a.makeAuditRecord(r, "getNotifications", audit.Fail)
-	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	category := r.URL.Query().Get("category")
+	if category != "" && !model.IsValidNotificationCategory(category) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid notification category: "+category))
+		return
+	}
 
-	notifications, err := a.app.GetUserNotifications(userID, limit)
+	boardIDs, err := a.parseNotificationBoardIDs(r.URL.Query().Get("boardId"))
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
+	excludeBoardID := r.URL.Query().Get("excludeBoardId")
+	if len(boardIDs) > 0 && excludeBoardID != "" {
+		a.errorResponse(w, r, model.NewErrBadRequest("boardId and excludeBoardId can't be used together"))
+		return
+	}
+	if excludeBoardID != "" {
+		if err = a.checkNotificationBoardsExist([]string{excludeBoardID}); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
 
-	a.logger.Debug("GetNotifications",
-		mlog.String("userID", userID),
-		mlog.Int("count", len(notifications)),
-	)
+	from, err := parseNotificationTime(r.URL.Query().Get("from"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid from: "+err.Error()))
+		return
+	}
+	to, err := parseNotificationTime(r.URL.Query().Get("to"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid to: "+err.Error()))
+		return
+	}
+	if from > 0 && to > 0 && from > to {
+		a.errorResponse(w, r, model.NewErrBadRequest("from must not be after to"))
+		return
+	}
 
-	data, err := json.Marshal(notifications)
+	orderBy := r.URL.Query().Get("orderBy")
+	if !model.IsValidNotificationOrderBy(orderBy) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid orderBy: "+orderBy))
+		return
+	}
+	order := r.URL.Query().Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid order: "+order))
+		return
+	}
+
+	markRead := r.URL.Query().Get("markReadOnFetch") == True
+	level := audit.LevelRead
+	if markRead {
+		level = audit.LevelModify
+	}
+
+	auditRec := a.makeAuditRecord(r, "getNotifications", audit.Fail)
+	defer a.audit.LogRecord(level, auditRec)
+	auditRec.AddMeta("markReadOnFetch", markRead)
+
+	opts := model.QueryUserNotificationsOptions{
+		Category:        category,
+		Limit:           limit,
+		IncludeArchived: r.URL.Query().Get("includeArchived") == True,
+		BoardIDs:        boardIDs,
+		ExcludeBoardID:  excludeBoardID,
+		TeamID:          r.URL.Query().Get("teamId"),
+		From:            from,
+		To:              to,
+		UnresolvedOnly:  r.URL.Query().Get("unresolvedOnly") == True,
+		OrderBy:         orderBy,
+		Ascending:       order == "asc",
+	}
+	paged, err := readNotificationPage(r.URL.Query(), &opts)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
+	if before := r.URL.Query().Get("before"); before != "" {
+		if err = a.readNotificationsBefore(userID, before, &opts); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		paged = true
+	}
 
-	jsonBytesResponse(w, http.StatusOK, data)
-	auditRec.Success()
-}
-
-func (a *API) handleGetUnreadCount(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /notifications/unread-count getUnreadCount
-	//
-	// Returns unread notification count
-	//
-	// ---
-	// produces:
-	// - application/json
-	// security:
-	// - BearerAuth: []
-	// responses:
-	//   '200':
-	//     description: success
-	//     schema:
-	//       type: object
-	//       properties:
-	//         count:
-	//           type: integer
-	//   default:
-	//     description: internal error
-	//     schema:
-	//       "$ref": "#/definitions/ErrorResponse"
+	var notifications []*model.UserNotification
+	var page *model.NotificationsPage
+	switch {
+	case paged:
+		page, err = a.app.GetUserNotificationsPage(userID, opts, markRead)
+		if page != nil {
+			notifications = page.Results
+		}
+	case markRead:
+		notifications, err = a.app.GetUserNotificationsMarkingRead(userID, opts)
+	default:
+		notifications, err = a.app.GetUserNotifications(userID, opts)
+	}
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
-	userID := getUserID(r)
+	if r.URL.Query().Get("includeCard") == True {
+		if err = a.app.AttachNotificationCards(userID, notifications); err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+	}
 
-	count, err := a.app.GetUnreadNotificationCount(userID)
+	// the global count, read after marking so the badge matches the list
+	unreadCount, err := a.app.GetUnreadNotificationCount(userID)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	response := map[string]int{"count": count}
+	a.logger.Debug("GetNotifications",
+		mlog.String("userID", userID),
+		mlog.Int("count", len(notifications)),
+	)
+
+	var response interface{} = notifications
+	if page != nil {
+		response = page
+	}
 	data, err := json.Marshal(response)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
+	setResponseHeader(w, HeaderUnreadCount, strconv.Itoa(unreadCount))
 	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
 }
 
-func (a *API) handleCreateNotification(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation POST /notifications createNotification
+func (a *API) handleQueryNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/query queryNotifications
 	//
-	// Creates a notification for a user
+	// Returns the notifications of the current user matching a query, which combines
+	// filters on types, boards, cards, actors, read state and dates, e.g. the unread
+	// assignments or mentions of a board.
 	//
 	// ---
 	// produces:
@@ -136,128 +326,2309 @@ func (a *API) handleCreateNotification(w http.ResponseWriter, r *http.Request) {
 	// parameters:
 	// - name: Body
 	//   in: body
-	//   description: notification to create
+	//   description: the query and how to page and sort its results
 	//   required: true
 	//   schema:
-	//     "$ref": "#/definitions/UserNotification"
+	//     "$ref": "#/definitions/NotificationQueryRequest"
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
 	//     description: success
 	//     schema:
-	//       "$ref": "#/definitions/UserNotification"
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/UserNotification"
+	//   '400':
+	//     description: invalid query
 	//   default:
 	//     description: internal error
 	//     schema:
 	//       "$ref": "#/definitions/ErrorResponse"
 
+	userID := getUserID(r)
+
 	requestBody, err := io.ReadAll(r.Body)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	var notification model.UserNotification
-	if err = json.Unmarshal(requestBody, &notification); err != nil {
-		a.errorResponse(w, r, err)
+	var request model.NotificationQueryRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("notification query", err))
 		return
 	}
 
-	auditRec := a.makeAuditRecord(r, "createNotification", audit.Fail)
-	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	if err = request.Query.IsValid(); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid notification query: "+err.Error()))
+		return
+	}
+	if !model.IsValidNotificationOrderBy(request.OrderBy) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid orderBy: "+request.OrderBy))
+		return
+	}
 
-	// Create and broadcast notification
-	created, err := a.app.CreateAndBroadcastNotification(&notification)
+	limit := request.Limit
+	if limit <= 0 {
+		limit = 50 // default
+	}
+
+	auditRec := a.makeAuditRecord(r, "queryNotifications", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	opts := model.QueryUserNotificationsOptions{
+		Limit:           limit,
+		IncludeArchived: request.IncludeArchived,
+		OrderBy:         request.OrderBy,
+		Ascending:       request.Ascending,
+		Query:           &request.Query,
+	}
+
+	notifications, err := a.app.GetUserNotifications(userID, opts)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	a.logger.Debug("CreateNotification",
-		mlog.String("targetUserID", notification.TargetUserID),
-		mlog.String("type", notification.Type),
+	a.logger.Debug("QueryNotifications",
+		mlog.String("userID", userID),
+		mlog.Int("count", len(notifications)),
 	)
 
-	data, err := json.Marshal(created)
+	data, err := json.Marshal(notifications)
 	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
 	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.AddMeta("count", len(notifications))
 	auditRec.Success()
 }
 
-func (a *API) handleMarkAsRead(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation POST /notifications/{notificationID}/read markNotificationAsRead
+func (a *API) handleGetCardNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/by-card/{cardID} getCardNotifications
 	//
-	// Marks a notification as read
+	// Returns the current user's notifications for a card, newest first, optionally marking
+	// them as read in the same call
 	//
 	// ---
 	// produces:
 	// - application/json
 	// parameters:
-	// - name: notificationID
+	// - name: cardID
 	//   in: path
-	//   description: Notification ID
+	//   description: Card ID
 	//   required: true
 	//   type: string
+	// - name: markRead
+	//   in: query
+	//   description: Mark the unread notifications of the card as read
+	//   required: false
+	//   type: boolean
+	// - name: limit
+	//   in: query
+	//   description: Maximum number of notifications to return, all of them if not set, 50 if not set when listing a page
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: List a page of notifications, returned as a NotificationsPage instead of an array. Empty for the first page, then the nextCursor of the previous page.
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success, a NotificationsPage if the cursor parameter is set
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/UserNotification"
 	//   default:
 	//     description: internal error
 	//     schema:
 	//       "$ref": "#/definitions/ErrorResponse"
 
-	vars := mux.Vars(r)
-	notificationID := vars["notificationID"]
+	cardID := mux.Vars(r)["cardID"]
 	userID := getUserID(r)
+	markRead := r.URL.Query().Get("markRead") == True
 
-	auditRec := a.makeAuditRecord(r, "markNotificationAsRead", audit.Fail)
-	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	limit, err := parseNotificationLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid limit: "+err.Error()))
+		return
+	}
+	opts := model.QueryUserNotificationsOptions{Limit: limit}
+	paged, err := readNotificationPage(r.URL.Query(), &opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getCardNotifications", audit.Fail)
+	level := audit.LevelRead
+	if markRead {
+		level = audit.LevelModify
+	}
+	defer a.audit.LogRecord(level, auditRec)
+	auditRec.AddMeta("cardID", cardID)
+	auditRec.AddMeta("markRead", markRead)
 
-	if err := a.app.MarkNotificationAsRead(notificationID, userID); err != nil {
+	page, err := a.app.GetCardNotifications(userID, cardID, opts, markRead)
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	jsonStringResponse(w, http.StatusOK, "{}")
+	a.writeNotificationPage(w, r, page, paged)
 	auditRec.Success()
 }
 
-func (a *API) handleMarkAllAsRead(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation POST /notifications/read-all markAllNotificationsAsRead
+func (a *API) handleGetBoardNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/by-board/{boardID} getBoardNotifications
 	//
-	// Marks all notifications as read
+	// Returns the current user's notifications for a board, pinned first then newest first,
+	// optionally marking them as read in the same call
 	//
 	// ---
 	// produces:
 	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: markRead
+	//   in: query
+	//   description: Mark the unread notifications of the board as read
+	//   required: false
+	//   type: boolean
+	// - name: limit
+	//   in: query
+	//   description: Maximum number of notifications to return, all of them if not set, 50 if not set when listing a page
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: List a page of notifications, returned as a NotificationsPage instead of an array. Empty for the first page, then the nextCursor of the previous page.
+	//   required: false
+	//   type: string
 	// security:
 	// - BearerAuth: []
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success, a NotificationsPage if the cursor parameter is set
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/UserNotification"
 	//   default:
 	//     description: internal error
 	//     schema:
 	//       "$ref": "#/definitions/ErrorResponse"
 
+	boardID := mux.Vars(r)["boardID"]
 	userID := getUserID(r)
+	markRead := r.URL.Query().Get("markRead") == True
 
-	auditRec := a.makeAuditRecord(r, "markAllNotificationsAsRead", audit.Fail)
-	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	if !utils.IsValidID(boardID, utils.IDTypeBoard) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid board id: "+boardID))
+		return
+	}
+
+	limit, err := parseNotificationLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid limit: "+err.Error()))
+		return
+	}
+	opts := model.QueryUserNotificationsOptions{Limit: limit}
+	paged, err := readNotificationPage(r.URL.Query(), &opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getBoardNotifications", audit.Fail)
+	level := audit.LevelRead
+	if markRead {
+		level = audit.LevelModify
+	}
+	defer a.audit.LogRecord(level, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+	auditRec.AddMeta("markRead", markRead)
 
-	if err := a.app.MarkAllNotificationsAsRead(userID); err != nil {
+	page, err := a.app.GetBoardNotifications(userID, boardID, opts, markRead)
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	jsonStringResponse(w, http.StatusOK, "{}")
+	a.writeNotificationPage(w, r, page, paged)
+	auditRec.Success()
+}
+
+// readNotificationPage reads the cursor of a notification list request into opts. Returns
+// true if the cursor parameter is set, even empty for the first page, in which case the
+// list is answered with a NotificationsPage of 50 notifications unless opts sets a limit.
+func readNotificationPage(query url.Values, opts *model.QueryUserNotificationsOptions) (bool, error) {
+	if !query.Has("cursor") {
+		return false, nil
+	}
+	opts.Cursor = query.Get("cursor")
+	if opts.Cursor != "" {
+		if _, err := model.ParseNotificationsCursor(opts.Cursor); err != nil {
+			return false, err
+		}
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 50 // default
+	}
+	return true, nil
+}
+
+// writeNotificationPage answers a notification list request with the page, or only its
+// notifications if the request did not ask for a page.
+func (a *API) writeNotificationPage(w http.ResponseWriter, r *http.Request, page *model.NotificationsPage, paged bool) {
+	var response interface{} = page.Results
+	if paged {
+		response = page
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+// parseNotificationLimit parses an optional limit of a notification list, returning 0 if
+// it is empty.
+func parseNotificationLimit(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if limit < 0 {
+		return 0, fmt.Errorf("negative limit %d", limit)
+	}
+	return limit, nil
+}
+
+// parseNotificationBoardIDs parses the optional comma-separated list of boards a
+// notification list is filtered by, checking there are at most notificationBoardFilterMax
+// and that they all exist.
+func (a *API) parseNotificationBoardIDs(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	boardIDs := strings.Split(value, ",")
+	if len(boardIDs) > notificationBoardFilterMax {
+		return nil, model.NewErrBadRequest(fmt.Sprintf("boardId accepts at most %d boards", notificationBoardFilterMax))
+	}
+	if err := a.checkNotificationBoardsExist(boardIDs); err != nil {
+		return nil, err
+	}
+	return boardIDs, nil
+}
+
+// checkNotificationBoardsExist returns a bad request error if one of the boards a
+// notification list is filtered by is invalid or doesn't exist. The boards are loaded in
+// a single query.
+func (a *API) checkNotificationBoardsExist(boardIDs []string) error {
+	for _, id := range boardIDs {
+		if !utils.IsValidID(id, utils.IDTypeBoard) {
+			return model.NewErrBadRequest("invalid board id: " + id)
+		}
+	}
+
+	boards, err := a.app.GetBoardsByIDs(boardIDs)
+	if err != nil {
+		return err
+	}
+	found := make(map[string]bool, len(boards))
+	for _, board := range boards {
+		found[board.ID] = true
+	}
+	for _, id := range boardIDs {
+		if !found[id] {
+			return model.NewErrBadRequest("invalid board id: " + id)
+		}
+	}
+	return nil
+}
+
+// readNotificationsBefore selects the notifications listed after the before parameter of
+// a notification list. A time in milliseconds since epoch selects the ones created before
+// it, so it needs the default order, newest created first. The ID of one of the user's
+// notifications selects the ones after it in the order of the list, the same as its cursor.
+func (a *API) readNotificationsBefore(userID, before string, opts *model.QueryUserNotificationsOptions) error {
+	if opts.Cursor != "" {
+		return model.NewErrBadRequest("before and cursor cannot be combined")
+	}
+
+	if millis, err := strconv.ParseInt(before, 10, 64); err == nil {
+		if millis <= 0 {
+			return model.NewErrBadRequest("invalid before: " + before)
+		}
+		if opts.OrderBy == model.NotificationOrderByUpdateAt || opts.Ascending {
+			return model.NewErrBadRequest("a before time needs the default order, newest created first")
+		}
+		opts.Before = millis
+		return nil
+	}
+
+	notification, err := a.app.GetUserNotification(before, userID)
+	if model.IsErrNotFound(err) {
+		return model.NewErrBadRequest("invalid before, unknown notification: " + before)
+	}
+	if err != nil {
+		return err
+	}
+	opts.Cursor = model.NewNotificationsCursor(notification, opts.OrderBy)
+	return nil
+}
+
+// parseNotificationTime parses an optional time in milliseconds since epoch, returning 0
+// if it is empty.
+func parseNotificationTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if millis < 0 {
+		return 0, fmt.Errorf("negative time %d", millis)
+	}
+	return millis, nil
+}
+
+func (a *API) handleGetNotificationThreads(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/threads getNotificationThreads
+	//
+	// Returns user notifications grouped by card, the most recently active cards first
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: limit
+	//   in: query
+	//   description: Maximum number of threads to return
+	//   required: false
+	//   type: integer
+	// - name: category
+	//   in: query
+	//   description: Only return notifications in this category (mentions, tasks, system)
+	//   required: false
+	//   type: string
+	// - name: includeArchived
+	//   in: query
+	//   description: Also return archived notifications
+	//   required: false
+	//   type: boolean
+	// - name: boardId
+	//   in: query
+	//   description: Only return notifications of these boards, as a comma-separated list of up to 50 board IDs
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationThread"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	limit := 20 // default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	category := r.URL.Query().Get("category")
+	if category != "" && !model.IsValidNotificationCategory(category) {
+		a.errorResponse(w, r, model.NewErrBadRequest("invalid notification category: "+category))
+		return
+	}
+
+	boardIDs, err := a.parseNotificationBoardIDs(r.URL.Query().Get("boardId"))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "getNotificationThreads", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+
+	opts := model.QueryUserNotificationsOptions{
+		Category:        category,
+		IncludeArchived: r.URL.Query().Get("includeArchived") == True,
+		BoardIDs:        boardIDs,
+	}
+
+	threads, err := a.app.GetUserNotificationThreads(userID, opts, limit)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("GetNotificationThreads",
+		mlog.String("userID", userID),
+		mlog.Int("count", len(threads)),
+	)
+
+	data, err := json.Marshal(threads)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleGetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/unread-count getUnreadCount
+	//
+	// Returns unread notification count. With a cap, larger counts are returned as the cap
+	// and flagged as truncated, so badges can show e.g. "99+".
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: cap
+	//   in: query
+	//   description: Maximum count to return, uncapped if not set
+	//   required: false
+	//   type: integer
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UnreadNotificationCount"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	countCap := 0
+	if capStr := r.URL.Query().Get("cap"); capStr != "" {
+		c, err := strconv.Atoi(capStr)
+		if err != nil || c <= 0 {
+			a.errorResponse(w, r, model.NewErrBadRequest("invalid `cap` parameter: "+capStr))
+			return
+		}
+		countCap = c
+	}
+
+	count, err := a.app.GetUnreadNotificationCount(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	response := model.NewUnreadNotificationCount(count, countCap)
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetOldestUnread(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/oldest-unread getOldestUnread
+	//
+	// Returns the create time of the oldest unread notification, or null if nothing is
+	// unread
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/OldestUnreadNotification"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	oldest, err := a.app.GetOldestUnreadNotification(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(oldest)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetUnreadCountByBoard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/unread-by-board getUnreadCountByBoard
+	//
+	// Returns the unread notification count of each board the user is a member of. Boards
+	// without unread notifications, and boards the user left, are not listed.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationBoardUnreadCount"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	counts, err := a.app.GetUnreadCountByBoardForMember(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(counts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetNotificationSummary(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/summary getNotificationSummary
+	//
+	// Returns the total, unread and read notification counts
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationSummary"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	summary, err := a.app.GetNotificationSummary(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetNotificationTypes(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/types getNotificationTypes
+	//
+	// Returns the notification types the server accepts, including registered custom types
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         type: string
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	data, err := json.Marshal(a.app.GetNotificationTypes())
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetNotificationCapabilities(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/capabilities getNotificationCapabilities
+	//
+	// Returns the notification types, categories, features and limits the server supports
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationCapabilities"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	capabilities := a.app.GetNotificationCapabilities()
+	capabilities.Limits.BoardFilterMax = notificationBoardFilterMax
+
+	data, err := json.Marshal(capabilities)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleGetLastSeen(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/last-seen getNotificationLastSeen
+	//
+	// Returns the time the user last opened the notification center
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationLastSeen"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	lastSeen, err := a.app.GetNotificationLastSeen(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(model.NotificationLastSeen{LastSeen: lastSeen})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleSetLastSeen(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/last-seen setNotificationLastSeen
+	//
+	// Sets the time the user last opened the notification center. Defaults to now.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: last seen time, defaults to now if omitted
+	//   required: false
+	//   schema:
+	//     "$ref": "#/definitions/NotificationLastSeen"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationLastSeen"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var lastSeen model.NotificationLastSeen
+	if len(requestBody) > 0 {
+		if err = json.Unmarshal(requestBody, &lastSeen); err != nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+			return
+		}
+	}
+
+	now := utils.GetMillis()
+	if lastSeen.LastSeen <= 0 || lastSeen.LastSeen > now {
+		lastSeen.LastSeen = now
+	}
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "setNotificationLastSeen", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err = a.app.SetNotificationLastSeen(userID, lastSeen.LastSeen); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(lastSeen)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleGetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/settings getNotificationSettings
+	//
+	// Returns the effective notification preferences of the user, including where each
+	// value is inherited from
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamId
+	//   in: query
+	//   description: Team whose default notification preferences the user inherits
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationPreferences"
+	//   '403':
+	//     description: access denied to the team
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := r.URL.Query().Get("teamId")
+
+	if teamID != "" && !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	preferences, err := a.app.GetNotificationPreferences(userID, teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleUpdateNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /notifications/settings updateNotificationSettings
+	//
+	// Replaces the notification preferences the user overrides. Omitted preferences are
+	// inherited from the team defaults.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: notification preferences to override
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/NotificationPreferencesOverrides"
+	// - name: teamId
+	//   in: query
+	//   description: Team whose default notification preferences the returned preferences inherit
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationPreferences"
+	//   '403':
+	//     description: access denied to the team
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var overrides model.NotificationPreferencesOverrides
+	if err = json.Unmarshal(requestBody, &overrides); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	userID := getUserID(r)
+	teamID := r.URL.Query().Get("teamId")
+
+	if teamID != "" && !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateNotificationSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	preferences, err := a.app.SetNotificationPreferences(userID, teamID, &overrides)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleGetNotificationTeamDefaults(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /teams/{teamID}/notifications/defaults getNotificationTeamDefaults
+	//
+	// Returns the notification preferences the members of the team inherit unless they
+	// override them
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationPreferencesOverrides"
+	//   '403':
+	//     description: access denied to the team
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionViewTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to team"))
+		return
+	}
+
+	defaults, err := a.app.GetNotificationTeamDefaults(teamID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleUpdateNotificationTeamDefaults(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /teams/{teamID}/notifications/defaults updateNotificationTeamDefaults
+	//
+	// Replaces the notification preferences the members of the team inherit. Omitted
+	// preferences fall back to the built-in defaults. Only team admins can set them.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: path
+	//   description: Team ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: default notification preferences of the team
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/NotificationPreferencesOverrides"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationPreferencesOverrides"
+	//   '403':
+	//     description: access denied to manage the team
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	teamID := mux.Vars(r)["teamID"]
+
+	if !a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to manage team"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var defaults model.NotificationPreferencesOverrides
+	if err = json.Unmarshal(requestBody, &defaults); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("notification team defaults", err))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateNotificationTeamDefaults", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("teamID", teamID)
+
+	saved, err := a.app.SetNotificationTeamDefaults(teamID, &defaults)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleGetNotificationBoardSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /boards/{boardID}/notifications/settings getNotificationBoardSettings
+	//
+	// Returns the notification types the user enabled or disabled on the board. Types
+	// without an override follow the user's global notification preferences.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationBoardPreference"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	preferences, err := a.app.GetNotificationBoardPreferences(userID, boardID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleUpdateNotificationBoardSettings(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation PUT /boards/{boardID}/notifications/settings updateNotificationBoardSettings
+	//
+	// Replaces the notification types the user enables or disables on the board. Omitted
+	// types follow the user's global notification preferences.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardID
+	//   in: path
+	//   description: Board ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: the board overrides, only type and enabled are used
+	//   required: true
+	//   schema:
+	//     type: array
+	//     items:
+	//       "$ref": "#/definitions/NotificationBoardPreference"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationBoardPreference"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	boardID := mux.Vars(r)["boardID"]
+	userID := getUserID(r)
+
+	if !a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to board"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var preferences []*model.NotificationBoardPreference
+	if err = json.Unmarshal(requestBody, &preferences); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "updateNotificationBoardSettings", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", boardID)
+
+	preferences, err = a.app.SetNotificationBoardPreferences(userID, boardID, preferences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleSendTestNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/test sendTestNotification
+	//
+	// Creates and broadcasts a sample notification to the current user. Returns null if
+	// the user's notification preferences suppress it.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: bypass
+	//   in: query
+	//   description: Deliver the notification even if the user's preferences suppress it
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserNotification"
+	//   '429':
+	//     description: too many test notifications
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	bypass := r.URL.Query().Get("bypass") == True
+
+	auditRec := a.makeAuditRecord(r, "sendTestNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("bypass", bypass)
+
+	notification, err := a.app.SendTestNotification(userID, bypass)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("SendTestNotification",
+		mlog.String("userID", userID),
+		mlog.Bool("bypass", bypass),
+		mlog.Bool("delivered", notification != nil),
+	)
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handlePreviewNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/preview previewNotification
+	//
+	// Validates a notification and returns it resolved the way it would be delivered and
+	// rendered, with its link, actor avatar and message key, without storing or
+	// broadcasting it. The link is left empty unless the user can view the board.
+	// Returns a 404 unless the server enables notification previews.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: notification to preview
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UserNotification"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/NotificationPreview"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	if !a.app.GetConfig().EnableNotificationPreview {
+		a.errorResponse(w, r, model.NewErrNotFound("notification preview"))
+		return
+	}
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var notification model.UserNotification
+	if err = json.Unmarshal(requestBody, &notification); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("notification", err))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "previewNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("type", notification.Type)
+
+	preview, err := a.app.PreviewNotification(getUserID(r), &notification)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(preview)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications createNotification
+	//
+	// Creates a notification for a user. Returns a 204 without a body if the user's
+	// notification preferences suppress it. When the server queues notifications the request is
+	// accepted with a 202 and the notification is stored and broadcast shortly after,
+	// unless wait is set. A full queue returns a 429.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: notification to create
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UserNotification"
+	// - name: skipAssigneeCheck
+	//   in: query
+	//   description: Skip checking that assignment notifications match the card's assignees
+	//   required: false
+	//   type: boolean
+	// - name: wait
+	//   in: query
+	//   description: Store the notification right away and return it instead of queuing it
+	//   required: false
+	//   type: boolean
+	// - name: ephemeral
+	//   in: query
+	//   description: Only broadcast the notification, without storing it
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserNotification"
+	//   '202':
+	//     description: queued
+	//   '204':
+	//     description: suppressed by the target user's notification preferences
+	//   '403':
+	//     description: access denied to notify the target user
+	//   '429':
+	//     description: the notification queue is full
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var notification model.UserNotification
+	if err = json.Unmarshal(requestBody, &notification); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("notification", err))
+		return
+	}
+	// the server decides why the target is notified
+	notification.Reason = ""
+
+	if !a.app.CanCreateNotification(userID, &notification) {
+		a.errorResponse(w, r, model.NewErrPermission("access denied to create notification"))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	opts := model.CreateUserNotificationOptions{
+		SkipAssigneeCheck: r.URL.Query().Get("skipAssigneeCheck") == True,
+		Synchronous:       r.URL.Query().Get("wait") == True,
+		Ephemeral:         r.URL.Query().Get("ephemeral") == True,
+		Source:            model.NotificationSourceAPI,
+	}
+
+	// Create and broadcast notification
+	created, err := a.app.CreateAndBroadcastNotification(&notification, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateNotification",
+		mlog.String("targetUserID", notification.TargetUserID),
+		mlog.String("type", notification.Type),
+	)
+
+	if a.app.NotificationQueueEnabled() && !opts.Synchronous && !opts.Ephemeral {
+		jsonStringResponse(w, http.StatusAccepted, "{}")
+		auditRec.Success()
+		return
+	}
+
+	if created == nil {
+		w.WriteHeader(http.StatusNoContent)
+		auditRec.Success()
+		return
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleCreateNotifications(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/batch createNotifications
+	//
+	// Creates a batch of notifications at once and returns the ones that were created.
+	// Notifications the target users' preferences suppress are left out. Invalid
+	// notifications, e.g. for a target user that doesn't exist, are skipped and returned by
+	// their index in the batch. Batches larger than the configured maximum are rejected with
+	// a 400. The batch is never queued.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: notifications to create
+	//   required: true
+	//   schema:
+	//     type: array
+	//     items:
+	//       "$ref": "#/definitions/UserNotification"
+	// - name: skipAssigneeCheck
+	//   in: query
+	//   description: Skip checking that assignment notifications match the card's assignees
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/CreateNotificationsResponse"
+	//   '400':
+	//     description: null notification or too many notifications
+	//   '403':
+	//     description: access denied to notify a target user
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var notifications []*model.UserNotification
+	if err = json.Unmarshal(requestBody, &notifications); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("notification", err))
+		return
+	}
+
+	if err = a.app.CheckNotificationBatchSize(len(notifications)); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	for i, notification := range notifications {
+		if notification == nil {
+			a.errorResponse(w, r, model.NewErrBadRequest(fmt.Sprintf("notification %d cannot be null", i)))
+			return
+		}
+		notification.Reason = ""
+	}
+	if i, ok := a.app.CanCreateNotifications(userID, notifications); !ok {
+		a.errorResponse(w, r, model.NewErrPermission(fmt.Sprintf("access denied to create notification %d", i)))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "createNotifications", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("count", len(notifications))
+
+	opts := model.CreateUserNotificationOptions{
+		SkipAssigneeCheck: r.URL.Query().Get("skipAssigneeCheck") == True,
+		Source:            model.NotificationSourceAPI,
+	}
+
+	created, skipped, err := a.app.CreateAndBroadcastNotifications(notifications, opts)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	a.logger.Debug("CreateNotifications",
+		mlog.Int("count", len(notifications)),
+		mlog.Int("created", len(created)),
+		mlog.Int("skipped", len(skipped)),
+	)
+
+	data, err := json.Marshal(model.CreateNotificationsResponse{Created: created, Skipped: skipped})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleGetNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/{notificationID} getNotification
+	//
+	// Returns a notification of the current user, with the devices it was read on
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserNotification"
+	//   '404':
+	//     description: notification not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "getNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("notificationID", notificationID)
+
+	notification, err := a.app.GetUserNotification(notificationID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleMarkAsRead(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/read markNotificationAsRead
+	//
+	// Marks a notification as read
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// - name: X-Device-Id
+	//   in: header
+	//   description: ID of the device the notification was read on, to track reads per device
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+	deviceID := r.Header.Get(HeaderDeviceID)
+
+	auditRec := a.makeAuditRecord(r, "markNotificationAsRead", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	if deviceID != "" {
+		auditRec.AddMeta("deviceID", deviceID)
+	}
+
+	if err := a.app.MarkNotificationAsReadOnDevice(notificationID, userID, deviceID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleMarkAsUnread(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/unread markNotificationAsUnread
+	//
+	// Marks a notification as unread again
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "markNotificationAsUnread", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("notificationID", notificationID)
+
+	if err := a.app.MarkNotificationAsUnread(notificationID, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleOpenNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/open openNotification
+	//
+	// Marks a notification as read and returns where to navigate to
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/OpenedNotification"
+	//   '404':
+	//     description: notification not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "openNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("notificationID", notificationID)
+
+	opened, err := a.app.OpenNotification(notificationID, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(opened)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleSyncNotificationReadStates(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/sync syncNotificationReadStates
+	//
+	// Applies read state changes made offline, the latest change winning over the stored
+	// state, and returns the current state of the notifications. Notifications the user
+	// doesn't own are skipped.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: The read state changes, up to 200
+	//   required: true
+	//   schema:
+	//     type: array
+	//     items:
+	//       "$ref": "#/definitions/NotificationReadSync"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/UserNotification"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var changes []*model.NotificationReadSync
+	if err = json.Unmarshal(requestBody, &changes); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "syncNotificationReadStates", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("changesCount", len(changes))
+
+	notifications, err := a.app.SyncNotificationReadStates(userID, changes)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(notifications)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleNotificationAction(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/action notificationAction
+	//
+	// Performs one of the actions declared on a notification and marks it as resolved
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// - name: Body
+	//   in: body
+	//   description: The action to perform
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/NotificationActionRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserNotification"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.NotificationActionRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, model.NewErrBadRequest(err.Error()))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "notificationAction", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("notificationID", notificationID)
+	auditRec.AddMeta("action", request.Action)
+
+	notification, err := a.app.PerformNotificationAction(notificationID, userID, request.Action)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleArchiveNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/archive archiveNotification
+	//
+	// Archives a notification, hiding it from the default list without deleting it
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: notification not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "archiveNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.ArchiveNotification(notificationID, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleUnarchiveNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/unarchive unarchiveNotification
+	//
+	// Restores an archived notification to the default list
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   '404':
+	//     description: notification not found
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "unarchiveNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.UnarchiveNotification(notificationID, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handlePinNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/pin pinNotification
+	//
+	// Pins a notification, keeping it at the top of the list regardless of its age and
+	// exempting it from retention
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "pinNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.PinNotification(notificationID, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleUnpinNotification(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/{notificationID}/unpin unpinNotification
+	//
+	// Unpins a notification, returning it to its place in the list
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: notificationID
+	//   in: path
+	//   description: Notification ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	notificationID := vars["notificationID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "unpinNotification", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.UnpinNotification(notificationID, userID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetNotificationActorBlocks(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /notifications/blocked-actors getNotificationActorBlocks
+	//
+	// Returns the users the current user blocked notifications from
+	//
+	// ---
+	// produces:
+	// - application/json
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: array
+	//       items:
+	//         "$ref": "#/definitions/NotificationActorBlock"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	blocks, err := a.app.GetNotificationActorBlocks(userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+}
+
+func (a *API) handleBlockNotificationActor(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/blocked-actors/{actorID} blockNotificationActor
+	//
+	// Blocks the notifications the current user gets from another user. System
+	// notifications and notifications from other users are still delivered.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: actorID
+	//   in: path
+	//   description: ID of the user to block
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	actorID := mux.Vars(r)["actorID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "blockNotificationActor", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("actorID", actorID)
+
+	if err := a.app.BlockNotificationActor(userID, actorID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleUnblockNotificationActor(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /notifications/blocked-actors/{actorID} unblockNotificationActor
+	//
+	// Delivers the notifications the current user gets from another user again
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: actorID
+	//   in: path
+	//   description: ID of the user to unblock
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	actorID := mux.Vars(r)["actorID"]
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "unblockNotificationActor", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("actorID", actorID)
+
+	if err := a.app.UnblockNotificationActor(userID, actorID); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleMarkNotificationsAsRead(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/read markNotificationsAsRead
+	//
+	// Marks a batch of notifications as read and returns how many were unread.
+	// Notifications the user doesn't own are skipped.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: Body
+	//   in: body
+	//   description: The IDs of the notifications, up to 200
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/MarkNotificationsAsReadRequest"
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: object
+	//       properties:
+	//         count:
+	//           type: integer
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	var request model.MarkNotificationsAsReadRequest
+	if err = json.Unmarshal(requestBody, &request); err != nil {
+		a.errorResponse(w, r, a.invalidPayloadError("mark notifications as read", err))
+		return
+	}
+
+	auditRec := a.makeAuditRecord(r, "markNotificationsAsRead", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("idsCount", len(request.IDs))
+
+	count, err := a.app.MarkNotificationsAsRead(request.IDs, userID)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(map[string]int64{"count": count})
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
+func (a *API) handleMarkAllAsRead(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation POST /notifications/read-all markAllNotificationsAsRead
+	//
+	// Marks all notifications as read, optionally only those for a board and/or of a type
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: boardId
+	//   in: query
+	//   description: Only mark notifications for this board
+	//   required: false
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: Only mark notifications of this type
+	//   required: false
+	//   type: string
+	// - name: returnIds
+	//   in: query
+	//   description: Also return the IDs of the notifications marked as read. Requires boardId or type.
+	//   required: false
+	//   type: boolean
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       type: object
+	//       properties:
+	//         count:
+	//           type: integer
+	//         ids:
+	//           type: array
+	//           items:
+	//             type: string
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := getUserID(r)
+	query := r.URL.Query()
+
+	opts := model.MarkNotificationsAsReadOptions{
+		BoardID: query.Get("boardId"),
+		Type:    query.Get("type"),
+	}
+
+	auditRec := a.makeAuditRecord(r, "markAllNotificationsAsRead", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+	auditRec.AddMeta("boardID", opts.BoardID)
+	auditRec.AddMeta("type", opts.Type)
+
+	var response interface{}
+	if query.Get("returnIds") == True {
+		ids, err := a.app.MarkNotificationsAsReadReturningIDs(userID, opts)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		response = map[string]interface{}{"count": len(ids), "ids": ids}
+	} else {
+		count, err := a.app.MarkAllNotificationsAsRead(userID, opts)
+		if err != nil {
+			a.errorResponse(w, r, err)
+			return
+		}
+		response = map[string]int64{"count": count}
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
 	auditRec.Success()
 }
 
@@ -1,14 +1,23 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/focalboard/server/assets"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+// defaultAvatarContentType is the content type of assets.DefaultAvatar.
+const defaultAvatarContentType = "image/svg+xml"
+
 func (a *API) registerSystemRoutes(r *mux.Router) {
 	// System APIs
 	r.HandleFunc("/hello", a.handleHello).Methods("GET")
@@ -42,7 +51,13 @@ func (a *API) handlePing(w http.ResponseWriter, r *http.Request) {
 	// responses:
 	//   '200':
 	//     description: success
-	serverMetadata := a.app.GetServerMetadata()
+	//   '500':
+	//     description: server metadata unavailable
+	serverMetadata, err := a.app.GetServerMetadata()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
 
 	if a.singleUserToken != "" {
 		serverMetadata.SKU = "personal_desktop"
@@ -52,12 +67,19 @@ func (a *API) handlePing(w http.ResponseWriter, r *http.Request) {
 		serverMetadata.SKU = "suite"
 	}
 
-	bytes, err := json.Marshal(serverMetadata)
+	a.writePingResponse(w, r, serverMetadata)
+}
+
+// writePingResponse responds with the server metadata, or only with an error if it can't
+// be marshaled.
+func (a *API) writePingResponse(w http.ResponseWriter, r *http.Request, metadata interface{}) {
+	data, err := json.Marshal(metadata)
 	if err != nil {
 		a.errorResponse(w, r, err)
+		return
 	}
 
-	jsonStringResponse(w, 200, string(bytes))
+	jsonBytesResponse(w, http.StatusOK, data)
 }
 
 func (a *API) handleGetAvatar(w http.ResponseWriter, r *http.Request) {
@@ -70,44 +92,137 @@ func (a *API) handleGetAvatar(w http.ResponseWriter, r *http.Request) {
 	// - image/jpeg
 	// - image/png
 	// - image/gif
+	// - image/svg+xml
 	// parameters:
 	// - name: userID
 	//   in: path
 	//   description: User ID
 	//   required: true
 	//   type: string
+	// - name: t
+	//   in: query
+	//   description: Signed URL token, required when avatars are private
+	//   required: false
+	//   type: string
 	// responses:
 	//   '200':
-	//     description: success
+	//     description: success, the default avatar if the user's avatar is too large to serve
+	//   '403':
+	//     description: missing, invalid or expired token while avatars are private
 	//   '404':
 	//     description: avatar not found
 
 	vars := mux.Vars(r)
 	userID := vars["userID"]
 
-	avatarsDir := filepath.Join(a.app.GetConfig().FilesPath, "avatars")
-
-	// Try different extensions
-	for _, ext := range []string{".jpg", ".png", ".gif", ".webp"} {
-		avatarPath := filepath.Join(avatarsDir, userID+ext)
-		if _, err := os.Stat(avatarPath); err == nil {
-			// File exists, serve it
-			contentType := "image/jpeg"
-			switch ext {
-			case ".png":
-				contentType = "image/png"
-			case ".gif":
-				contentType = "image/gif"
-			case ".webp":
-				contentType = "image/webp"
+	cacheControl := "public, max-age=86400" // Cache for 24 hours
+	if a.app.AvatarsPrivate() {
+		if !a.app.VerifyAvatarToken(userID, r.URL.Query().Get("t")) {
+			a.errorResponse(w, r, model.NewErrPermission("invalid or expired avatar token"))
+			return
+		}
+		// shared caches must not serve a private avatar, and browsers not past the token expiry
+		cacheControl = fmt.Sprintf("private, max-age=%d", a.app.GetConfig().AvatarURLExpirySeconds)
+	}
+
+	if entry, ok := a.avatarCache.Get(userID); ok {
+		a.app.GetMetrics().IncrementAvatarCacheHits(1)
+		setAvatarHeaders(w, entry.contentType, cacheControl)
+		if !avatarNotModified(w, r, entry.modTime) {
+			http.ServeContent(w, r, entry.name, entry.modTime, bytes.NewReader(entry.data))
+		}
+		return
+	}
+	if a.avatarCache != nil {
+		a.app.GetMetrics().IncrementAvatarCacheMisses(1)
+	}
+
+	avatar := a.app.ResolveAvatar(userID)
+	if avatar == nil {
+		http.NotFound(w, r)
+		return
+	}
+	info := avatar.Info
+	config := a.app.GetConfig()
+
+	// Legacy avatars predating the upload limit can be huge. There is no downscaled
+	// variant to fall back to, so serve the default avatar instead.
+	if config.AvatarMaxServeSize > 0 && info.Size() > config.AvatarMaxServeSize {
+		a.logger.Warn("Refusing to serve oversized avatar",
+			mlog.String("userID", userID),
+			mlog.String("source", avatar.Source),
+			mlog.Int("size", info.Size()),
+			mlog.Int("maxSize", config.AvatarMaxServeSize),
+		)
+		serveDefaultAvatar(w, r, cacheControl)
+		return
+	}
+
+	setAvatarHeaders(w, avatar.ContentType, cacheControl)
+
+	// Small avatars are served from memory from now on, large ones always stream from disk
+	if a.avatarCache != nil && info.Size() <= config.AvatarCacheMaxItemSize {
+		if data, err := os.ReadFile(avatar.Path); err == nil {
+			entry := &avatarCacheEntry{
+				userID:      userID,
+				name:        info.Name(),
+				contentType: avatar.ContentType,
+				modTime:     info.ModTime().UTC().Truncate(time.Second),
+				data:        data,
+			}
+			a.avatarCache.Add(entry)
+			if !avatarNotModified(w, r, entry.modTime) {
+				http.ServeContent(w, r, entry.name, entry.modTime, bytes.NewReader(entry.data))
 			}
-			w.Header().Set("Content-Type", contentType)
-			w.Header().Set("Cache-Control", "public, max-age=86400") // Cache for 24 hours
-			http.ServeFile(w, r, avatarPath)
 			return
 		}
 	}
 
-	// No avatar found, return 404
-	http.NotFound(w, r)
+	serveAvatarFile(w, r, avatar.Path, info)
+}
+
+// serveDefaultAvatar serves the default avatar, for the users whose own avatar can't be
+// served.
+func serveDefaultAvatar(w http.ResponseWriter, r *http.Request, cacheControl string) {
+	setAvatarHeaders(w, defaultAvatarContentType, cacheControl)
+	http.ServeContent(w, r, "default-avatar.svg", time.Time{}, bytes.NewReader(assets.DefaultAvatar))
+}
+
+func setAvatarHeaders(w http.ResponseWriter, contentType, cacheControl string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", cacheControl)
+}
+
+// avatarNotModified sets Last-Modified and, if the request's If-Modified-Since is not older
+// than the avatar, answers it with a 304 and returns true.
+func avatarNotModified(w http.ResponseWriter, r *http.Request, modTime time.Time) bool {
+	w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil && !modTime.After(t) {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// serveAvatarFile serves the avatar described by info, which the caller already has from
+// locating the file. Requests whose If-Modified-Since is not older than the avatar get a
+// 304 without the file being opened.
+func serveAvatarFile(w http.ResponseWriter, r *http.Request, avatarPath string, info os.FileInfo) {
+	modTime := info.ModTime().UTC().Truncate(time.Second)
+	if avatarNotModified(w, r, modTime) {
+		return
+	}
+
+	file, err := os.Open(avatarPath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	// ServeContent sizes the response by seeking, so the file is not stat'ed again
+	http.ServeContent(w, r, info.Name(), modTime, file)
 }
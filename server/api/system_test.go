@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/mattermost/focalboard/server/app"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHello(t *testing.T) {
@@ -141,3 +145,65 @@ func TestPing(t *testing.T) {
 		}
 	})
 }
+
+func TestWritePingResponse(t *testing.T) {
+	testAPI := API{logger: mlog.CreateConsoleTestLogger(t)}
+
+	t.Run("Responds with an error only when marshaling fails", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/ping", nil)
+		response := httptest.NewRecorder()
+
+		testAPI.writePingResponse(response, request, map[string]interface{}{"version": make(chan int)})
+
+		require.Equal(t, http.StatusInternalServerError, response.Code)
+
+		var got model.ErrorResponse
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		require.Equal(t, http.StatusInternalServerError, got.ErrorCode)
+		// nothing was written after the error
+		require.False(t, json.NewDecoder(response.Body).More())
+	})
+}
+
+func TestServeAvatarFile(t *testing.T) {
+	dir := t.TempDir()
+	avatarPath := filepath.Join(dir, "user.png")
+	require.NoError(t, os.WriteFile(avatarPath, []byte("avatar"), 0600))
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(avatarPath, modTime, modTime))
+	info, err := os.Stat(avatarPath)
+	require.NoError(t, err)
+
+	t.Run("serves the file with Last-Modified", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/api/v2/users/user/avatar", nil)
+		response := httptest.NewRecorder()
+
+		serveAvatarFile(response, request, avatarPath, info)
+
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "avatar", response.Body.String())
+		require.Equal(t, modTime.Format(http.TimeFormat), response.Header().Get("Last-Modified"))
+	})
+
+	t.Run("not modified since", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/api/v2/users/user/avatar", nil)
+		request.Header.Set("If-Modified-Since", modTime.Format(http.TimeFormat))
+		response := httptest.NewRecorder()
+
+		serveAvatarFile(response, request, filepath.Join(dir, "missing.png"), info)
+
+		require.Equal(t, http.StatusNotModified, response.Code)
+		require.Empty(t, response.Body.String())
+	})
+
+	t.Run("modified since", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/api/v2/users/user/avatar", nil)
+		request.Header.Set("If-Modified-Since", modTime.Add(-time.Hour).Format(http.TimeFormat))
+		response := httptest.NewRecorder()
+
+		serveAvatarFile(response, request, avatarPath, info)
+
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "avatar", response.Body.String())
+	})
+}
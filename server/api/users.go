@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/audit"
 	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
 func (a *API) registerUsersRoutes(r *mux.Router) {
@@ -20,11 +23,14 @@ func (a *API) registerUsersRoutes(r *mux.Router) {
 	r.HandleFunc("/users", a.sessionRequired(a.handleGetUsersList)).Methods("POST")
 	r.HandleFunc("/users/me", a.sessionRequired(a.handleGetMe)).Methods("GET")
 	r.HandleFunc("/users/me/memberships", a.sessionRequired(a.handleGetMyMemberships)).Methods("GET")
+	r.HandleFunc("/users/me/permissions", a.sessionRequired(a.handleGetMyPermissions)).Methods("GET")
 	r.HandleFunc("/users/{userID}", a.sessionRequired(a.handleGetUser)).Methods("GET")
 	r.HandleFunc("/users/{userID}/config", a.sessionRequired(a.handleUpdateUserConfig)).Methods(http.MethodPut)
 	r.HandleFunc("/users/me/config", a.sessionRequired(a.handleGetUserPreferences)).Methods(http.MethodGet)
 	// Avatar upload endpoint (requires session)
 	r.HandleFunc("/users/{userID}/avatar", a.sessionRequired(a.handleUploadAvatar)).Methods(http.MethodPost)
+	r.HandleFunc("/users/{userID}/avatar", a.sessionRequired(a.handleDeleteAvatar)).Methods(http.MethodDelete)
+	r.HandleFunc("/users/{userID}/avatar/url", a.sessionRequired(a.handleGetAvatarURL)).Methods(http.MethodGet)
 	// Note: Avatar GET is registered in system.go to bypass CSRF for img src loading
 }
 
@@ -256,6 +262,59 @@ func (a *API) handleGetMyMemberships(w http.ResponseWriter, r *http.Request) {
 	auditRec.Success()
 }
 
+func (a *API) handleGetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/me/permissions getMyPermissions
+	//
+	// Returns the capabilities of the currently logged-in user
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: teamID
+	//   in: query
+	//   description: Team ID to include team capabilities for
+	//   required: false
+	//   type: string
+	// - name: boardID
+	//   in: query
+	//   description: Board ID to include board permissions for
+	//   required: false
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/UserPermissions"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	query := r.URL.Query()
+	teamID := query.Get("teamID")
+	boardID := query.Get("boardID")
+
+	userID := getUserID(r)
+
+	auditRec := a.makeAuditRecord(r, "getMyPermissions", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelRead, auditRec)
+	auditRec.AddMeta("userID", userID)
+
+	permissions := a.app.GetUserPermissions(userID, teamID, boardID)
+
+	data, err := json.Marshal(permissions)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+
+	jsonBytesResponse(w, http.StatusOK, data)
+	auditRec.Success()
+}
+
 func (a *API) handleGetUser(w http.ResponseWriter, r *http.Request) {
 	// swagger:operation GET /users/{userID} getUser
 	//
@@ -465,6 +524,8 @@ func (a *API) handleUploadAvatar(w http.ResponseWriter, r *http.Request) {
 	// responses:
 	//   '200':
 	//     description: success
+	//   '429':
+	//     description: the user uploaded too many avatars within the last minute
 	//   default:
 	//     description: internal error
 
@@ -480,6 +541,11 @@ func (a *API) handleUploadAvatar(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !a.app.AllowAvatarUpload(userID) {
+		a.errorResponse(w, r, model.NewErrTooManyRequests("too many avatar uploads, try again in a minute"))
+		return
+	}
+
 	// Parse multipart form (max 5MB)
 	err := r.ParseMultipartForm(5 << 20)
 	if err != nil {
@@ -494,57 +560,168 @@ func (a *API) handleUploadAvatar(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	// Validate file type
-	contentType := handler.Header.Get("Content-Type")
-	if !strings.HasPrefix(contentType, "image/") {
-		a.errorResponse(w, r, model.NewErrBadRequest("file must be an image"))
+	// Validate file type from the content, not only the declared type
+	contentType, err := a.app.CheckAvatarUpload(file, handler.Header.Get("Content-Type"))
+	if err != nil {
+		a.errorResponse(w, r, err)
 		return
 	}
 
-	// Create avatars directory
-	avatarsDir := filepath.Join(a.app.GetConfig().FilesPath, "avatars")
-	if err := os.MkdirAll(avatarsDir, 0755); err != nil {
+	// Save file as the user's local avatar, which takes precedence over synced ones
+	avatarPath, err := a.app.LocalAvatarPath(userID, contentType)
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	if err := writeAvatarFile(avatarPath, file, handler.Size); err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	// Determine file extension
-	ext := ".jpg"
-	if strings.Contains(contentType, "png") {
-		ext = ".png"
-	} else if strings.Contains(contentType, "gif") {
-		ext = ".gif"
-	} else if strings.Contains(contentType, "webp") {
-		ext = ".webp"
+	// Remove old avatar files with different extensions
+	if err := a.app.DeleteLocalAvatar(userID, avatarPath); err != nil {
+		a.logger.Warn("Cannot remove previous avatar", mlog.String("userID", userID), mlog.Err(err))
 	}
+	a.avatarCache.Remove(userID)
+	a.app.NotifyAvatarChanged(userID)
 
-	// Save file
-	avatarPath := filepath.Join(avatarsDir, userID+ext)
+	// Return success with avatar URL
+	response := map[string]string{
+		"url": fmt.Sprintf("/api/v2/users/%s/avatar", userID),
+	}
+	data, _ := json.Marshal(response)
+	jsonBytesResponse(w, http.StatusOK, data)
+}
 
-	// Remove old avatar files with different extensions
-	for _, oldExt := range []string{".jpg", ".png", ".gif", ".webp"} {
-		oldPath := filepath.Join(avatarsDir, userID+oldExt)
-		os.Remove(oldPath) // Ignore error if file doesn't exist
+func (a *API) handleDeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation DELETE /users/{userID}/avatar deleteAvatar
+	//
+	// Delete the avatar the user uploaded. The user's avatar falls back to the next
+	// configured source, e.g. one synced from an identity provider.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	vars := mux.Vars(r)
+	userID := vars["userID"]
+
+	ctx := r.Context()
+	session := ctx.Value(sessionContextKey).(*model.Session)
+
+	// User can only delete their own avatar
+	if userID != session.UserID {
+		a.errorResponse(w, r, model.NewErrForbidden("cannot delete avatar for another user"))
+		return
 	}
 
-	dst, err := os.Create(avatarPath)
-	if err != nil {
+	auditRec := a.makeAuditRecord(r, "deleteAvatar", audit.Fail)
+	defer a.audit.LogRecord(audit.LevelModify, auditRec)
+
+	if err := a.app.DeleteLocalAvatar(userID, ""); err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
-	defer dst.Close()
+	a.avatarCache.Remove(userID)
+	a.app.NotifyAvatarChanged(userID)
+
+	jsonStringResponse(w, http.StatusOK, "{}")
+	auditRec.Success()
+}
+
+func (a *API) handleGetAvatarURL(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /users/{userID}/avatar/url getAvatarURL
+	//
+	// Returns the URL of a user's avatar. When avatars are private the URL carries a
+	// short-lived signed token, so it can be used in img tags until it expires.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: userID
+	//   in: path
+	//   description: User ID
+	//   required: true
+	//   type: string
+	// security:
+	// - BearerAuth: []
+	// responses:
+	//   '200':
+	//     description: success
+	//     schema:
+	//       "$ref": "#/definitions/AvatarURL"
+	//   default:
+	//     description: internal error
+	//     schema:
+	//       "$ref": "#/definitions/ErrorResponse"
+
+	userID := mux.Vars(r)["userID"]
 
-	if _, err := io.Copy(dst, file); err != nil {
+	data, err := json.Marshal(a.app.GetAvatarURL(userID))
+	if err != nil {
 		a.errorResponse(w, r, err)
 		return
 	}
 
-	// Return success with avatar URL
-	response := map[string]string{
-		"url": fmt.Sprintf("/api/v2/users/%s/avatar", userID),
-	}
-	data, _ := json.Marshal(response)
 	jsonBytesResponse(w, http.StatusOK, data)
 }
 
+// writeAvatarFile writes an uploaded avatar to a temp file next to avatarPath and renames
+// it into place only once the whole upload has been received and looks like an image,
+// so the avatar handler never serves a partially written file.
+func writeAvatarFile(avatarPath string, src io.Reader, expectedSize int64) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(avatarPath), filepath.Base(avatarPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	written, err := io.Copy(tmp, src)
+	if err != nil {
+		return err
+	}
+	if written == 0 || (expectedSize > 0 && written != expectedSize) {
+		return model.NewErrBadRequest("incomplete avatar upload")
+	}
+
+	header := make([]byte, 512)
+	n, err := tmp.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if !strings.HasPrefix(http.DetectContentType(header[:n]), "image/") {
+		return model.NewErrBadRequest("file must be an image")
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), avatarPath)
+}
+
 // handleGetAvatar is defined in system.go to bypass CSRF check for img src loading
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG file for content sniffing to recognize it.
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+func TestWriteAvatarFile(t *testing.T) {
+	t.Run("writes the avatar and leaves no temp files", func(t *testing.T) {
+		dir := t.TempDir()
+		avatarPath := filepath.Join(dir, "user.png")
+
+		err := writeAvatarFile(avatarPath, bytes.NewReader(pngHeader), int64(len(pngHeader)))
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(avatarPath)
+		require.NoError(t, err)
+		require.Equal(t, pngHeader, data)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("incomplete upload keeps the previous avatar", func(t *testing.T) {
+		dir := t.TempDir()
+		avatarPath := filepath.Join(dir, "user.png")
+		require.NoError(t, os.WriteFile(avatarPath, []byte("previous"), 0600))
+
+		err := writeAvatarFile(avatarPath, bytes.NewReader(pngHeader), int64(len(pngHeader))+10)
+		require.True(t, model.IsErrBadRequest(err))
+
+		data, err := os.ReadFile(avatarPath)
+		require.NoError(t, err)
+		require.Equal(t, []byte("previous"), data)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("rejects content that is not an image", func(t *testing.T) {
+		dir := t.TempDir()
+		avatarPath := filepath.Join(dir, "user.png")
+		content := []byte("<html><body>not an image</body></html>")
+
+		err := writeAvatarFile(avatarPath, bytes.NewReader(content), int64(len(content)))
+		require.True(t, model.IsErrBadRequest(err))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
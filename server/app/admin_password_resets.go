@@ -0,0 +1,48 @@
+package app
+
+import (
+	"time"
+)
+
+// adminPasswordResetWindow is the window AdminPasswordResetsPerMinute applies to.
+const adminPasswordResetWindow = time.Minute
+
+// AllowAdminPasswordReset returns true, and records the reset, if the admin session made
+// fewer than AdminPasswordResetsPerMinute password resets within the last minute. It also
+// returns how many resets the session made within that minute, before this one.
+func (a *App) AllowAdminPasswordReset(sessionID string) (bool, int) {
+	limit := a.config.AdminPasswordResetsPerMinute
+	if limit <= 0 {
+		return true, 0
+	}
+
+	a.passwordResetMux.Lock()
+	defer a.passwordResetMux.Unlock()
+
+	if a.passwordResets == nil {
+		a.passwordResets = map[string][]time.Time{}
+	}
+
+	now := time.Now()
+	// forget the resets that left the window, of every session so ended ones don't pile up
+	for id, resets := range a.passwordResets {
+		recent := resets[:0]
+		for _, reset := range resets {
+			if now.Sub(reset) < adminPasswordResetWindow {
+				recent = append(recent, reset)
+			}
+		}
+		if len(recent) == 0 {
+			delete(a.passwordResets, id)
+		} else {
+			a.passwordResets[id] = recent
+		}
+	}
+
+	count := len(a.passwordResets[sessionID])
+	if count >= limit {
+		return false, count
+	}
+	a.passwordResets[sessionID] = append(a.passwordResets[sessionID], now)
+	return true, count
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowAdminPasswordReset(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.AdminPasswordResetsPerMinute = 2
+	defer func() { th.App.config.AdminPasswordResetsPerMinute = 0 }()
+
+	t.Run("limited per session", func(t *testing.T) {
+		allowed, _ := th.App.AllowAdminPasswordReset("session-1")
+		require.True(t, allowed)
+		allowed, _ = th.App.AllowAdminPasswordReset("session-1")
+		require.True(t, allowed)
+
+		allowed, recent := th.App.AllowAdminPasswordReset("session-1")
+		require.False(t, allowed)
+		require.Equal(t, 2, recent)
+
+		allowed, _ = th.App.AllowAdminPasswordReset("session-2")
+		require.True(t, allowed)
+	})
+
+	t.Run("resets leave the window", func(t *testing.T) {
+		th.App.passwordResetMux.Lock()
+		th.App.passwordResets["session-1"] = []time.Time{time.Now().Add(-2 * adminPasswordResetWindow)}
+		th.App.passwordResetMux.Unlock()
+
+		allowed, recent := th.App.AllowAdminPasswordReset("session-1")
+		require.True(t, allowed)
+		require.Equal(t, 0, recent)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		th.App.config.AdminPasswordResetsPerMinute = 0
+		for i := 0; i < 5; i++ {
+			allowed, _ := th.App.AllowAdminPasswordReset("session-3")
+			require.True(t, allowed)
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/mattermost/focalboard/server/auth"
+	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/config"
 	"github.com/mattermost/focalboard/server/services/metrics"
 	"github.com/mattermost/focalboard/server/services/notify"
@@ -66,10 +67,30 @@ type App struct {
 	logger              mlog.LoggerIFace
 	permissions         permissions.PermissionsService
 	blockChangeNotifier *utils.CallbackQueue
+	notificationQueue   *notificationQueue
 	servicesAPI         servicesAPI
 
 	cardLimitMux sync.RWMutex
 	cardLimit    int
+
+	testNotificationMux  sync.Mutex
+	testNotificationSent map[string]time.Time
+
+	statusChangeMux      sync.Mutex
+	pendingStatusChanges map[string]*pendingStatusChange
+
+	suppressedNotificationsMux sync.Mutex
+	suppressedNotifications    map[string]map[string]int
+	suppressedBoardsCreatedAs  map[string]string
+
+	passwordResetMux sync.Mutex
+	passwordResets   map[string][]time.Time
+
+	avatarUploadLimiter *rateLimiter
+
+	notificationTypes *model.NotificationTypeRegistry
+
+	avatarSigningKey []byte
 }
 
 func (a *App) SetConfig(config *config.Configuration) {
@@ -80,6 +101,10 @@ func (a *App) GetConfig() *config.Configuration {
 	return a.config
 }
 
+func (a *App) GetMetrics() *metrics.Metrics {
+	return a.metrics
+}
+
 func New(config *config.Configuration, wsAdapter ws.Adapter, services Services) *App {
 	app := &App{
 		config:              config,
@@ -94,6 +119,13 @@ func New(config *config.Configuration, wsAdapter ws.Adapter, services Services)
 		permissions:         services.Permissions,
 		blockChangeNotifier: utils.NewCallbackQueue("blockChangeNotifier", blockChangeNotifierQueueSize, blockChangeNotifierPoolSize, services.Logger),
 		servicesAPI:         services.ServicesAPI,
+		notificationTypes:   model.NewNotificationTypeRegistry(config.NotificationCustomTypes),
+		avatarSigningKey:    newAvatarSigningKey(config.Secret),
+
+		avatarUploadLimiter: newRateLimiter(),
+	}
+	if config.NotificationQueueSize > 0 && config.NotificationQueueWorkers > 0 {
+		app.notificationQueue = newNotificationQueue(app, config.NotificationQueueSize, config.NotificationQueueWorkers)
 	}
 	app.initialize(services.SkipTemplateInit)
 	return app
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const (
+	auditRetentionBatchSize = 1000
+	auditExportPageSize     = 500
+)
+
+// GetAuditRecords returns a page of persisted audit records matching the given filters.
+func (a *App) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	return a.store.GetAuditRecords(opts)
+}
+
+// ExportAuditRecords calls fn for every audit record matching the filters, newest first.
+// The pagination options are ignored. Records created after the export started are not
+// included, so they can't shift the pages being read.
+func (a *App) ExportAuditRecords(opts model.QueryAuditRecordsOptions, fn func(record *model.AuditRecord) error) error {
+	if opts.Until == 0 {
+		opts.Until = utils.GetMillis()
+	}
+	opts.PerPage = auditExportPageSize
+
+	for opts.Page = 0; ; opts.Page++ {
+		records, more, err := a.store.GetAuditRecords(opts)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// PurgeAuditRecords deletes the audit records older than the given number of days and
+// returns how many were deleted.
+func (a *App) PurgeAuditRecords(retentionDays int) (int64, error) {
+	return a.store.DeleteAuditRecordsBefore(retentionCutoff(retentionDays), auditRetentionBatchSize)
+}
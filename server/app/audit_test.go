@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAuditRecords(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	first := []*model.AuditRecord{{ID: "r-1"}, {ID: "r-2"}}
+	second := []*model.AuditRecord{{ID: "r-3"}}
+
+	var until int64
+	gomock.InOrder(
+		th.Store.EXPECT().GetAuditRecords(gomock.Any()).DoAndReturn(
+			func(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+				require.Equal(t, 0, opts.Page)
+				require.Equal(t, auditExportPageSize, opts.PerPage)
+				require.NotZero(t, opts.Until)
+				until = opts.Until
+				return first, true, nil
+			}),
+		th.Store.EXPECT().GetAuditRecords(gomock.Any()).DoAndReturn(
+			func(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+				require.Equal(t, 1, opts.Page)
+				require.Equal(t, until, opts.Until)
+				return second, false, nil
+			}),
+	)
+
+	exported := []string{}
+	err := th.App.ExportAuditRecords(model.QueryAuditRecordsOptions{Level: "auth", Page: 3, PerPage: 10}, func(record *model.AuditRecord) error {
+		exported = append(exported, record.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"r-1", "r-2", "r-3"}, exported)
+}
+
+func TestPurgeAuditRecords(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.Store.EXPECT().DeleteAuditRecordsBefore(gomock.Any(), auditRetentionBatchSize).Return(int64(42), nil)
+
+	deleted, err := th.App.PurgeAuditRecords(90)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), deleted)
+}
@@ -90,7 +90,7 @@ func (a *App) Login(username, email, password, mfaToken string) (string, error)
 
 	if user == nil && email != "" {
 		var err error
-		user, err = a.store.GetUserByEmail(email)
+		user, err = a.store.GetUserByEmail(model.NormalizeEmail(email))
 		if err != nil && model.IsErrNotFound(err) {
 			a.metrics.IncrementLoginFailCount(1)
 			return "", errors.Wrap(err, "invalid username or password")
@@ -145,6 +145,13 @@ func (a *App) Logout(sessionID string) error {
 
 // RegisterUser creates a new user if the provided data is valid.
 func (a *App) RegisterUser(username, email, password string) error {
+	email = model.NormalizeEmail(email)
+	if email != "" {
+		if err := a.CheckEmailDomainAllowed(email); err != nil {
+			return err
+		}
+	}
+
 	var user *model.User
 	if username != "" {
 		var err error
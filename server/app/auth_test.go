@@ -39,9 +39,9 @@ func TestLogin(t *testing.T) {
 	}
 
 	th.Store.EXPECT().GetUserByUsername("badUsername").Return(nil, errors.New("Bad Username"))
-	th.Store.EXPECT().GetUserByEmail("badEmail").Return(nil, errors.New("Bad Email"))
+	th.Store.EXPECT().GetUserByEmail("bademail").Return(nil, errors.New("Bad Email"))
 	th.Store.EXPECT().GetUserByUsername("testUsername").Return(mockUser, nil).Times(2)
-	th.Store.EXPECT().GetUserByEmail("testEmail").Return(mockUser, nil)
+	th.Store.EXPECT().GetUserByEmail("testemail").Return(mockUser, nil)
 	th.Store.EXPECT().CreateSession(gomock.Any()).Return(nil).Times(2)
 
 	for _, test := range testcases {
@@ -100,16 +100,20 @@ func TestRegisterUser(t *testing.T) {
 	}{
 		{"fail, missing login information", "", "", "", true},
 		{"fail, username exists", "existingUsername", "", "", true},
-		{"fail, email exists", "", "existingEmail", "", true},
+		{"fail, email exists", "", "existing@example.com", "", true},
+		{"fail, email exists with other casing", "", " Existing@Example.COM ", "", true},
 		{"fail, invalid password", "newUsername", "", "test", true},
-		{"success, using email", "", "newEmail", "testPassword", false},
+		{"success, using email", "", "New@Example.com", "testPassword", false},
 	}
 
 	th.Store.EXPECT().GetUserByUsername("existingUsername").Return(mockUser, nil)
 	th.Store.EXPECT().GetUserByUsername("newUsername").Return(mockUser, errors.New("user not found"))
-	th.Store.EXPECT().GetUserByEmail("existingEmail").Return(mockUser, nil)
-	th.Store.EXPECT().GetUserByEmail("newEmail").Return(nil, model.NewErrNotFound("user"))
-	th.Store.EXPECT().CreateUser(gomock.Any()).Return(nil, nil)
+	th.Store.EXPECT().GetUserByEmail("existing@example.com").Return(mockUser, nil).Times(2)
+	th.Store.EXPECT().GetUserByEmail("new@example.com").Return(nil, model.NewErrNotFound("user"))
+	th.Store.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(user *model.User) (*model.User, error) {
+		require.Equal(t, "new@example.com", user.Email)
+		return user, nil
+	})
 
 	for _, test := range testcases {
 		t.Run(test.title, func(t *testing.T) {
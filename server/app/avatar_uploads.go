@@ -0,0 +1,123 @@
+package app
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+
+	// decoders for the dimensions of uploaded avatars
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// avatarSniffLength is how much of an upload is read to detect its content type.
+const avatarSniffLength = 512
+
+// CheckAvatarUpload verifies that an uploaded avatar is an image of a type allowed by the
+// AvatarAllowedTypes setting, detected from its content, and that it matches the content
+// type the client declared. Images wider or taller than the AvatarMaxDimension setting are
+// rejected before anything decodes them. Returns the detected content type, with src
+// rewound to its start.
+func (a *App) CheckAvatarUpload(src io.ReadSeeker, declaredType string) (string, error) {
+	header := make([]byte, avatarSniffLength)
+	n, err := io.ReadFull(src, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(header[:n])
+	if !a.isAvatarTypeAllowed(contentType) {
+		return "", model.NewErrBadRequest("avatar type " + contentType + " is not allowed")
+	}
+	if declared, _, err := mime.ParseMediaType(declaredType); err != nil || declared != contentType {
+		return "", model.NewErrBadRequest("avatar content is " + contentType + " but was uploaded as " + declaredType)
+	}
+
+	if maxDimension := a.config.AvatarMaxDimension; maxDimension > 0 {
+		if _, err = src.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		width, height, err := avatarDimensions(src, contentType)
+		if err != nil {
+			return "", model.NewErrBadRequest("invalid avatar image: " + err.Error())
+		}
+		if width > maxDimension || height > maxDimension {
+			return "", model.NewErrBadRequest(fmt.Sprintf("avatar is %dx%d, the maximum is %dx%d", width, height, maxDimension, maxDimension))
+		}
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// isAvatarTypeAllowed returns true if avatars can be uploaded with the content type. Only
+// the types avatars are stored as can be allowed, all of them if AvatarAllowedTypes is
+// empty.
+func (a *App) isAvatarTypeAllowed(contentType string) bool {
+	stored := false
+	for _, ct := range avatarContentTypes {
+		if ct.contentType == contentType {
+			stored = true
+			break
+		}
+	}
+	if !stored {
+		return false
+	}
+
+	if len(a.config.AvatarAllowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range a.config.AvatarAllowedTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// avatarDimensions reads the width and height of an image from its header, without
+// decoding its pixels.
+func avatarDimensions(src io.Reader, contentType string) (int, int, error) {
+	if contentType == "image/webp" {
+		return webpDimensions(src)
+	}
+	config, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+// webpDimensions reads the canvas size of a WebP image from the header of its first chunk,
+// which is either a lossy (VP8), lossless (VP8L) or extended (VP8X) one.
+func webpDimensions(src io.Reader) (int, int, error) {
+	header := make([]byte, 30)
+	if _, err := io.ReadFull(src, header); err != nil {
+		return 0, 0, errors.New("truncated webp header")
+	}
+
+	switch string(header[12:16]) {
+	case "VP8 ":
+		width := binary.LittleEndian.Uint16(header[26:28]) & 0x3fff
+		height := binary.LittleEndian.Uint16(header[28:30]) & 0x3fff
+		return int(width), int(height), nil
+	case "VP8L":
+		bits := binary.LittleEndian.Uint32(header[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		width := uint32(header[24]) | uint32(header[25])<<8 | uint32(header[26])<<16
+		height := uint32(header[27]) | uint32(header[28])<<8 | uint32(header[29])<<16
+		return int(width) + 1, int(height) + 1, nil
+	}
+	return 0, 0, errors.New("unknown webp chunk " + string(header[12:16]))
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestCheckAvatarUpload(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.AvatarMaxDimension = 50
+	defer func() {
+		th.App.config.AvatarMaxDimension = 0
+		th.App.config.AvatarAllowedTypes = nil
+	}()
+
+	t.Run("image of an allowed type", func(t *testing.T) {
+		data := encodeTestPNG(t, 20, 10)
+		src := bytes.NewReader(data)
+
+		contentType, err := th.App.CheckAvatarUpload(src, "image/png")
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", contentType)
+
+		// rewound for the upload to be written
+		read, err := io.ReadAll(src)
+		require.NoError(t, err)
+		assert.Equal(t, data, read)
+	})
+
+	t.Run("renamed non-image", func(t *testing.T) {
+		src := bytes.NewReader([]byte("#!/bin/sh\necho not an image\n"))
+
+		_, err := th.App.CheckAvatarUpload(src, "image/png")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("content not matching the declared type", func(t *testing.T) {
+		src := bytes.NewReader(encodeTestPNG(t, 20, 10))
+
+		_, err := th.App.CheckAvatarUpload(src, "image/jpeg")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("type not allowed", func(t *testing.T) {
+		th.App.config.AvatarAllowedTypes = []string{"image/jpeg"}
+		defer func() { th.App.config.AvatarAllowedTypes = nil }()
+
+		src := bytes.NewReader(encodeTestPNG(t, 20, 10))
+
+		_, err := th.App.CheckAvatarUpload(src, "image/png")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("oversized dimensions", func(t *testing.T) {
+		src := bytes.NewReader(encodeTestPNG(t, 100, 10))
+
+		_, err := th.App.CheckAvatarUpload(src, "image/png")
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestWebpDimensions(t *testing.T) {
+	header := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00")
+	// canvas of 5000x20, stored minus one on 24 bits
+	header = append(header, 0x87, 0x13, 0x00, 0x13, 0x00, 0x00)
+
+	width, height, err := webpDimensions(bytes.NewReader(header))
+	require.NoError(t, err)
+	assert.Equal(t, 5000, width)
+	assert.Equal(t, 20, height)
+
+	_, _, err = webpDimensions(bytes.NewReader(header[:20]))
+	require.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// Avatar sources, in the order they are checked by default.
+const (
+	AvatarSourceLocal   = "local"
+	AvatarSourceSynced  = "synced"
+	AvatarSourceDefault = "default"
+)
+
+// avatarContentTypes maps the avatar file extensions to their content type, in the order
+// they are looked for.
+var avatarContentTypes = []struct {
+	ext         string
+	contentType string
+}{
+	{".jpg", "image/jpeg"},
+	{".png", "image/png"},
+	{".gif", "image/gif"},
+	{".webp", "image/webp"},
+}
+
+// AvatarFile is an avatar image on disk.
+type AvatarFile struct {
+	Path        string
+	Info        os.FileInfo
+	ContentType string
+	Source      string
+}
+
+// ResolveAvatar returns the avatar to serve for a user, or nil if there is none and clients
+// should show their default one. The sources are checked in the order of the AvatarSources
+// setting and the first one holding an avatar wins. The default order is:
+//
+//  1. local: an avatar the user uploaded, which overrides any other source.
+//  2. synced: an avatar synced from an external identity provider into AvatarSyncedPath.
+//  3. default: no avatar file, clients generate one from the user's initials.
+//
+// Sources listed after default are never checked. Unknown sources are ignored.
+func (a *App) ResolveAvatar(userID string) *AvatarFile {
+	for _, source := range a.config.AvatarSources {
+		if source == AvatarSourceDefault {
+			return nil
+		}
+
+		dir := a.avatarSourceDir(source)
+		if dir == "" {
+			continue
+		}
+
+		for _, ct := range avatarContentTypes {
+			path := filepath.Join(dir, userID+ct.ext)
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			return &AvatarFile{Path: path, Info: info, ContentType: ct.contentType, Source: source}
+		}
+	}
+	return nil
+}
+
+// LocalAvatarPath returns where to store the avatar a user uploads with the given content
+// type, creating the local avatars directory if needed.
+func (a *App) LocalAvatarPath(userID, contentType string) (string, error) {
+	dir := a.avatarSourceDir(AvatarSourceLocal)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	ext := ".jpg"
+	for _, ct := range avatarContentTypes {
+		if strings.Contains(contentType, strings.TrimPrefix(ct.ext, ".")) {
+			ext = ct.ext
+		}
+	}
+	return filepath.Join(dir, userID+ext), nil
+}
+
+// DeleteLocalAvatar removes the avatars a user uploaded except the one at keepPath, if
+// any, so the next source in the chain applies again once none is left.
+func (a *App) DeleteLocalAvatar(userID, keepPath string) error {
+	dir := a.avatarSourceDir(AvatarSourceLocal)
+	for _, ct := range avatarContentTypes {
+		path := filepath.Join(dir, userID+ct.ext)
+		if path == keepPath {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyAvatarChanged tells the other nodes of the cluster that the avatar of a user was
+// uploaded or deleted, so they stop serving a stale copy. It does nothing on a standalone
+// server.
+func (a *App) NotifyAvatarChanged(userID string) {
+	a.wsAdapter.BroadcastAvatarChange(userID)
+}
+
+// OnAvatarChanged registers a handler called when another node of the cluster changes the
+// avatar of a user.
+func (a *App) OnAvatarChanged(handler func(userID string)) {
+	a.wsAdapter.OnAvatarChange(handler)
+}
+
+// avatarSigningKeyLabel is the label the avatar signing key is derived from the secret
+// with, so the key can't be used to forge anything else signed with the secret.
+const avatarSigningKeyLabel = "focalboard-avatar-url"
+
+// newAvatarSigningKey returns the key signing avatar URLs: a key derived from the
+// configured secret, or a random key if there is none. A random key is only valid on this
+// node until it restarts, so clustered deployments with private avatars must configure
+// the secret.
+func newAvatarSigningKey(secret string) []byte {
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(avatarSigningKeyLabel))
+		return mac.Sum(nil)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// AvatarsPrivate returns true if avatars are only served with a signed URL token.
+func (a *App) AvatarsPrivate() bool {
+	return a.config.PrivateAvatars
+}
+
+// SignAvatarURL returns a token granting access to the avatar of a user until the
+// returned expiry, in milliseconds since epoch. The token is the expiry followed by the
+// signature of the user ID and the expiry.
+func (a *App) SignAvatarURL(userID string) (string, int64) {
+	expiry := time.Duration(a.config.AvatarURLExpirySeconds) * time.Second
+	expiresAt := utils.GetMillis() + expiry.Milliseconds()
+	return strconv.FormatInt(expiresAt, 10) + "." + a.avatarSignature(userID, expiresAt), expiresAt
+}
+
+// GetAvatarURL returns the URL of the avatar of a user, signed if avatars are private.
+func (a *App) GetAvatarURL(userID string) *model.AvatarURL {
+	avatarURL := &model.AvatarURL{
+		URL: utils.MakeAvatarLink(a.config.ServerRoot, userID),
+	}
+	if a.AvatarsPrivate() {
+		var token string
+		token, avatarURL.ExpiresAt = a.SignAvatarURL(userID)
+		avatarURL.URL += "?t=" + url.QueryEscape(token)
+	}
+	return avatarURL
+}
+
+// VerifyAvatarToken returns true if the token was signed for the avatar of the user and
+// hasn't expired yet.
+func (a *App) VerifyAvatarToken(userID, token string) bool {
+	expiresAtStr, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiresAtStr, 10, 64)
+	if err != nil || expiresAt < utils.GetMillis() {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(a.avatarSignature(userID, expiresAt)))
+}
+
+func (a *App) avatarSignature(userID string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, a.avatarSigningKey)
+	mac.Write([]byte(userID + ":" + strconv.FormatInt(expiresAt, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// avatarSourceDir returns the directory holding the avatars of a source, or an empty
+// string if the source has no files.
+func (a *App) avatarSourceDir(source string) string {
+	switch source {
+	case AvatarSourceLocal:
+		return filepath.Join(a.config.FilesPath, "avatars")
+	case AvatarSourceSynced:
+		return a.config.AvatarSyncedPath
+	default:
+		a.logger.Warn("Unknown avatar source", mlog.String("source", source))
+		return ""
+	}
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAvatar(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.FilesPath = t.TempDir()
+	th.App.config.AvatarSyncedPath = t.TempDir()
+	th.App.config.AvatarSources = []string{AvatarSourceLocal, AvatarSourceSynced, AvatarSourceDefault}
+
+	localDir := filepath.Join(th.App.config.FilesPath, "avatars")
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(th.App.config.AvatarSyncedPath, "user-1.png"), []byte("synced"), 0600))
+
+	t.Run("synced avatar without a local one", func(t *testing.T) {
+		avatar := th.App.ResolveAvatar("user-1")
+		require.NotNil(t, avatar)
+		assert.Equal(t, AvatarSourceSynced, avatar.Source)
+		assert.Equal(t, "image/png", avatar.ContentType)
+	})
+
+	t.Run("local avatar overrides the synced one", func(t *testing.T) {
+		path, err := th.App.LocalAvatarPath("user-1", "image/gif")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte("local"), 0600))
+
+		avatar := th.App.ResolveAvatar("user-1")
+		require.NotNil(t, avatar)
+		assert.Equal(t, AvatarSourceLocal, avatar.Source)
+		assert.Equal(t, path, avatar.Path)
+	})
+
+	t.Run("configured order wins", func(t *testing.T) {
+		th.App.config.AvatarSources = []string{AvatarSourceSynced, AvatarSourceLocal}
+		defer func() {
+			th.App.config.AvatarSources = []string{AvatarSourceLocal, AvatarSourceSynced, AvatarSourceDefault}
+		}()
+
+		avatar := th.App.ResolveAvatar("user-1")
+		require.NotNil(t, avatar)
+		assert.Equal(t, AvatarSourceSynced, avatar.Source)
+	})
+
+	t.Run("deleting the local avatar falls back to the synced one", func(t *testing.T) {
+		require.NoError(t, th.App.DeleteLocalAvatar("user-1", ""))
+
+		avatar := th.App.ResolveAvatar("user-1")
+		require.NotNil(t, avatar)
+		assert.Equal(t, AvatarSourceSynced, avatar.Source)
+	})
+
+	t.Run("sources after default are not checked", func(t *testing.T) {
+		th.App.config.AvatarSources = []string{AvatarSourceDefault, AvatarSourceSynced}
+		defer func() {
+			th.App.config.AvatarSources = []string{AvatarSourceLocal, AvatarSourceSynced, AvatarSourceDefault}
+		}()
+
+		assert.Nil(t, th.App.ResolveAvatar("user-1"))
+	})
+
+	t.Run("no avatar", func(t *testing.T) {
+		assert.Nil(t, th.App.ResolveAvatar("user-2"))
+	})
+}
+
+func TestSignAvatarURL(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.AvatarURLExpirySeconds = 60
+
+	t.Run("valid token", func(t *testing.T) {
+		token, expiresAt := th.App.SignAvatarURL("user-1")
+		assert.Greater(t, expiresAt, int64(0))
+		assert.True(t, th.App.VerifyAvatarToken("user-1", token))
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		token, _ := th.App.SignAvatarURL("user-1")
+		assert.False(t, th.App.VerifyAvatarToken("user-2", token))
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		th.App.config.AvatarURLExpirySeconds = -1
+		defer func() { th.App.config.AvatarURLExpirySeconds = 60 }()
+
+		token, _ := th.App.SignAvatarURL("user-1")
+		assert.False(t, th.App.VerifyAvatarToken("user-1", token))
+	})
+
+	t.Run("malformed tokens", func(t *testing.T) {
+		assert.False(t, th.App.VerifyAvatarToken("user-1", ""))
+		assert.False(t, th.App.VerifyAvatarToken("user-1", "not-a-token"))
+		assert.False(t, th.App.VerifyAvatarToken("user-1", "99999999999999.bad"))
+	})
+
+	t.Run("URLs are only signed when avatars are private", func(t *testing.T) {
+		assert.Zero(t, th.App.GetAvatarURL("user-1").ExpiresAt)
+		assert.NotContains(t, th.App.GetAvatarURL("user-1").URL, "?t=")
+
+		th.App.config.PrivateAvatars = true
+		defer func() { th.App.config.PrivateAvatars = false }()
+
+		avatarURL := th.App.GetAvatarURL("user-1")
+		assert.NotZero(t, avatarURL.ExpiresAt)
+		assert.Contains(t, avatarURL.URL, "?t=")
+	})
+}
+
+func TestNewAvatarSigningKey(t *testing.T) {
+	t.Run("derived from the secret", func(t *testing.T) {
+		key := newAvatarSigningKey("secret")
+		assert.Equal(t, key, newAvatarSigningKey("secret"))
+		assert.NotEqual(t, []byte("secret"), key)
+		assert.NotEqual(t, key, newAvatarSigningKey("other-secret"))
+	})
+
+	t.Run("random without a secret", func(t *testing.T) {
+		assert.NotEqual(t, newAvatarSigningKey(""), newAvatarSigningKey(""))
+	})
+}
@@ -307,8 +307,25 @@ func (a *App) GetBlocksForBoard(boardID string) ([]*model.Block, error) {
 }
 
 func (a *App) notifyBlockChanged(action notify.Action, block *model.Block, oldBlock *model.Block, modifiedByID string) {
-	// don't notify if notifications service disabled, or block change is generated via system user.
-	if a.notifications == nil || modifiedByID == model.SystemUserID {
+	// don't notify if block change is generated via system user.
+	if modifiedByID == model.SystemUserID {
+		return
+	}
+
+	if a.holdBackBlockChange(block, modifiedByID) {
+		return
+	}
+
+	switch action {
+	case notify.Update:
+		a.notifyCardPropertiesChanged(block, oldBlock, modifiedByID)
+	case notify.Add:
+		a.notifyCardCommented(block, modifiedByID)
+	}
+	a.notifyBoardMentions(action, block, oldBlock, modifiedByID)
+
+	// don't notify if notifications service disabled.
+	if a.notifications == nil {
 		return
 	}
 
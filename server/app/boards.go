@@ -6,6 +6,7 @@ package app
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/notify"
@@ -222,6 +223,11 @@ func (a *App) DuplicateBoard(boardID, userID, toTeam string, asTemplate bool) (*
 	return bab, members, err
 }
 
+// GetBoardsByIDs returns the boards among the given IDs that exist.
+func (a *App) GetBoardsByIDs(boardIDs []string) ([]*model.Board, error) {
+	return a.store.GetBoardsByIDs(boardIDs)
+}
+
 func (a *App) GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error) {
 	return a.store.GetBoardsForUserAndTeam(userID, teamID, includePublicBoards)
 }
@@ -453,8 +459,13 @@ func (a *App) DeleteBoard(boardID, userID string) error {
 		return err
 	}
 
+	notifiedUserIDs := a.setNotificationsHiddenForBoards(true, boardID)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBoardDelete(board.TeamID, boardID)
+		for _, notifiedUserID := range notifiedUserIDs {
+			a.broadcastUnreadNotificationCount(notifiedUserID)
+		}
 		return nil
 	})
 
@@ -483,6 +494,66 @@ func (a *App) GetMembersForBoard(boardID string) ([]*model.BoardMember, error) {
 	return members, nil
 }
 
+// GetBoardAdmins returns the members whose effective role on the board lets them manage
+// the board's roles.
+func (a *App) GetBoardAdmins(boardID string) ([]*model.BoardAdmin, error) {
+	members, err := a.GetMembersForBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, m := range members {
+		if m.SchemeAdmin || a.permissions.HasPermissionToBoard(m.UserID, boardID, model.PermissionManageBoardRoles) {
+			userIDs = append(userIDs, m.UserID)
+		}
+	}
+
+	admins := make([]*model.BoardAdmin, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return admins, nil
+	}
+
+	users, err := a.GetUsersList(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, userID := range userIDs {
+		admin := &model.BoardAdmin{UserID: userID}
+		if user, ok := usersByID[userID]; ok {
+			admin.DisplayName = a.userDisplayName(user)
+		}
+		admins = append(admins, admin)
+	}
+	return admins, nil
+}
+
+// userDisplayName returns the name to show for a user according to the teammate name
+// display setting, falling back to the username when the preferred name is not set.
+func (a *App) userDisplayName(user *model.User) string {
+	fullName := strings.TrimSpace(user.FirstName + " " + user.LastName)
+
+	switch a.config.TeammateNameDisplay {
+	case "nickname_full_name":
+		if user.Nickname != "" {
+			return user.Nickname
+		}
+		if fullName != "" {
+			return fullName
+		}
+	case "full_name":
+		if fullName != "" {
+			return fullName
+		}
+	}
+	return user.Username
+}
+
 func (a *App) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
 	members, err := a.store.GetMembersForUser(userID)
 	if err != nil {
@@ -506,6 +577,29 @@ func (a *App) GetMembersForUser(userID string) ([]*model.BoardMember, error) {
 	return members, nil
 }
 
+// GetBoardMembershipsForUser returns a page of the memberships of a user, sorted by board
+// ID, with the role each of them grants. Team admins are admins of the boards of their
+// teams, like for permission checks. Returns a not found error if the user doesn't exist.
+func (a *App) GetBoardMembershipsForUser(userID string, page, perPage int) ([]*model.UserBoardMembership, bool, error) {
+	if _, err := a.store.GetUserByID(userID); err != nil {
+		return nil, false, err
+	}
+
+	memberships, hasMore, err := a.store.GetBoardMembershipsForUser(userID, page, perPage)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, membership := range memberships {
+		membership.Role = membership.Member.EffectiveRole()
+		if membership.Role != model.BoardRoleAdmin && membership.TeamID != "" &&
+			a.permissions.HasPermissionToTeam(userID, membership.TeamID, model.PermissionManageTeam) {
+			membership.Role = model.BoardRoleAdmin
+		}
+	}
+	return memberships, hasMore, nil
+}
+
 func (a *App) GetMemberForBoard(boardID string, userID string) (*model.BoardMember, error) {
 	return a.store.GetMemberForBoard(boardID, userID)
 }
@@ -597,6 +691,66 @@ func (a *App) UpdateBoardMember(member *model.BoardMember) (*model.BoardMember,
 	return newMember, nil
 }
 
+// SetBoardMemberRole creates or updates the membership of a user on a board so that it
+// grants exactly the given role. The user must exist and be a member of the team of the
+// board. Returns nil if the board does not exist.
+func (a *App) SetBoardMemberRole(boardID, userID string, role model.BoardRole) (*model.BoardMember, error) {
+	if !model.IsBoardMemberRoleValid(role) {
+		return nil, model.NewErrBadRequest("invalid board role: " + string(role))
+	}
+
+	board, err := a.store.GetBoard(boardID)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = a.store.GetUserByID(userID); err != nil {
+		if model.IsErrNotFound(err) {
+			return nil, model.NewErrBadRequest("user not found: " + userID)
+		}
+		return nil, err
+	}
+	if board.TeamID != model.GlobalTeamID && !a.permissions.HasPermissionToTeam(userID, board.TeamID, model.PermissionViewTeam) {
+		return nil, model.NewErrBadRequest("user is not a member of the team: " + userID)
+	}
+
+	member := model.NewBoardMemberWithRole(boardID, userID, role)
+
+	existingMembership, err := a.store.GetMemberForBoard(boardID, userID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, err
+	}
+
+	if existingMembership == nil || existingMembership.Synthetic {
+		return a.AddMemberToBoard(member)
+	}
+	return a.UpdateBoardMember(member)
+}
+
+// DefaultBoardMemberRole returns the role given to the users joining the board: its
+// minimum role, or the DefaultBoardMemberRole setting if the board sets none. An empty or
+// unknown setting falls back to editor.
+func (a *App) DefaultBoardMemberRole(board *model.Board) model.BoardRole {
+	if board.MinimumRole != model.BoardRoleNone {
+		return board.MinimumRole
+	}
+
+	role := model.BoardRole(a.config.DefaultBoardMemberRole)
+	if role == model.BoardRoleNone {
+		return model.BoardRoleEditor
+	}
+	if !model.IsBoardMemberRoleValid(role) {
+		a.logger.Warn("Unknown default board member role, ignoring it",
+			mlog.String("role", string(role)),
+		)
+		return model.BoardRoleEditor
+	}
+	return role
+}
+
 func (a *App) isLastAdmin(userID, boardID string) (bool, error) {
 	members, err := a.store.GetMembersForBoard(boardID)
 	if err != nil {
@@ -690,8 +844,13 @@ func (a *App) UndeleteBoard(boardID string, modifiedBy string) error {
 		return nil
 	}
 
+	notifiedUserIDs := a.setNotificationsHiddenForBoards(false, boardID)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		a.wsAdapter.BroadcastBoardChange(board.TeamID, board)
+		for _, notifiedUserID := range notifiedUserIDs {
+			a.broadcastUnreadNotificationCount(notifiedUserID)
+		}
 		return nil
 	})
 
@@ -22,6 +22,10 @@ func (a *App) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string, a
 		return nil, err
 	}
 
+	// notifications held back for the boards, e.g. while they are imported, stay held back
+	// for the created boards
+	a.suppressCreatedBoardNotifications(bab.Boards, newBab.Boards)
+
 	// all new boards should belong to the same team
 	teamID := newBab.Boards[0].TeamID
 
@@ -118,6 +122,8 @@ func (a *App) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID st
 		return err
 	}
 
+	notifiedUserIDs := a.setNotificationsHiddenForBoards(true, dbab.Boards...)
+
 	a.blockChangeNotifier.Enqueue(func() error {
 		for _, block := range blocks {
 			a.wsAdapter.BroadcastBlockDelete(firstBoard.TeamID, block.ID, block.BoardID)
@@ -128,6 +134,10 @@ func (a *App) DeleteBoardsAndBlocks(dbab *model.DeleteBoardsAndBlocks, userID st
 		for _, boardID := range dbab.Boards {
 			a.wsAdapter.BroadcastBoardDelete(firstBoard.TeamID, boardID)
 		}
+
+		for _, notifiedUserID := range notifiedUserIDs {
+			a.broadcastUnreadNotificationCount(notifiedUserID)
+		}
 		return nil
 	})
 
@@ -1,8 +1,13 @@
 package app
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
 	"github.com/mattermost/focalboard/server/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -135,6 +140,76 @@ func TestAddMemberToBoard(t *testing.T) {
 	})
 }
 
+func TestSetBoardMemberRole(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	const boardID = "board_id_1"
+	const userID = "user_id_1"
+
+	t.Run("invalid role", func(t *testing.T) {
+		member, err := th.App.SetBoardMemberRole(boardID, userID, model.BoardRole("owner"))
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, member)
+	})
+
+	t.Run("updates an existing membership", func(t *testing.T) {
+		existing := &model.BoardMember{
+			BoardID:         boardID,
+			UserID:          userID,
+			SchemeEditor:    true,
+			SchemeCommenter: true,
+			SchemeViewer:    true,
+		}
+
+		th.Store.EXPECT().GetMemberForBoard(boardID, userID).Return(existing, nil).Times(2)
+		th.Store.EXPECT().GetBoard(boardID).Return(&model.Board{
+			ID:     boardID,
+			TeamID: "team_id_1",
+		}, nil).Times(2)
+		th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+		th.API.EXPECT().HasPermissionToTeam(userID, "team_id_1", model.PermissionViewTeam).Return(true)
+		th.Store.EXPECT().SaveMember(mock.MatchedBy(func(i interface{}) bool {
+			p := i.(*model.BoardMember)
+			return p.BoardID == boardID && p.UserID == userID &&
+				p.MinimumRole == string(model.BoardRoleViewer) &&
+				!p.SchemeAdmin && !p.SchemeEditor && !p.SchemeCommenter && p.SchemeViewer
+		})).Return(&model.BoardMember{
+			BoardID:      boardID,
+			UserID:       userID,
+			MinimumRole:  string(model.BoardRoleViewer),
+			SchemeViewer: true,
+		}, nil)
+
+		// for WS change broadcast
+		th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{}, nil)
+
+		member, err := th.App.SetBoardMemberRole(boardID, userID, model.BoardRoleViewer)
+		require.NoError(t, err)
+		require.True(t, member.SchemeViewer)
+		require.False(t, member.SchemeEditor)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(boardID).Return(&model.Board{ID: boardID, TeamID: "team_id_1"}, nil)
+		th.Store.EXPECT().GetUserByID("missing").Return(nil, model.NewErrNotFound("user"))
+
+		member, err := th.App.SetBoardMemberRole(boardID, "missing", model.BoardRoleViewer)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, member)
+	})
+
+	t.Run("user outside of the team", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard(boardID).Return(&model.Board{ID: boardID, TeamID: "team_id_1"}, nil)
+		th.Store.EXPECT().GetUserByID("outsider").Return(&model.User{ID: "outsider"}, nil)
+		th.API.EXPECT().HasPermissionToTeam("outsider", "team_id_1", model.PermissionViewTeam).Return(false)
+
+		member, err := th.App.SetBoardMemberRole(boardID, "outsider", model.BoardRoleViewer)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, member)
+	})
+}
+
 func TestPatchBoard(t *testing.T) {
 	th, tearDown := SetupTestHelper(t)
 	defer tearDown()
@@ -722,6 +797,40 @@ func TestGetMembersForBoard(t *testing.T) {
 	})
 }
 
+func TestGetBoardAdmins(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	const boardID = "board_id_1"
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+	th.App.config.TeammateNameDisplay = "full_name"
+	defer func() { th.App.config.TeammateNameDisplay = "" }()
+
+	th.Store.EXPECT().GetMembersForBoard(boardID).Return([]*model.BoardMember{
+		{BoardID: boardID, UserID: "user_admin", SchemeAdmin: true},
+		{BoardID: boardID, UserID: "user_minimum_admin", MinimumRole: "admin", SchemeViewer: true},
+		{BoardID: boardID, UserID: "user_editor", SchemeEditor: true},
+	}, nil)
+	th.Store.EXPECT().GetBoard(boardID).Return(nil, nil)
+	permissionsStore.EXPECT().GetMemberForBoard(boardID, "user_minimum_admin").
+		Return(&model.BoardMember{BoardID: boardID, UserID: "user_minimum_admin", MinimumRole: "admin", SchemeViewer: true}, nil)
+	permissionsStore.EXPECT().GetMemberForBoard(boardID, "user_editor").
+		Return(&model.BoardMember{BoardID: boardID, UserID: "user_editor", SchemeEditor: true}, nil)
+	th.Store.EXPECT().GetUsersList([]string{"user_admin", "user_minimum_admin"}, false, false).Return([]*model.User{
+		{ID: "user_admin", Username: "admin", FirstName: "Ada", LastName: "Admin"},
+		{ID: "user_minimum_admin", Username: "minimum"},
+	}, nil)
+
+	admins, err := th.App.GetBoardAdmins(boardID)
+	require.NoError(t, err)
+	require.Equal(t, []*model.BoardAdmin{
+		{UserID: "user_admin", DisplayName: "Ada Admin"},
+		{UserID: "user_minimum_admin", DisplayName: "minimum"},
+	}, admins)
+}
+
 func TestGetMembersForUser(t *testing.T) {
 	th, tearDown := SetupTestHelper(t)
 	defer tearDown()
@@ -769,3 +878,106 @@ func TestGetMembersForUser(t *testing.T) {
 		assert.True(t, members[0].SchemeAdmin)
 	})
 }
+
+func TestDeleteBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("hides the notifications of the board", func(t *testing.T) {
+		board := &model.Board{ID: "board-1", TeamID: "team-1"}
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().DeleteBoard("board-1", "user-1").Return(nil)
+		th.Store.EXPECT().SetNotificationsHiddenForBoard("board-1", true).Return([]string{"user-2"}, nil)
+		// for the board delete and unread count broadcasts, sent asynchronously
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetUnreadNotificationCount("user-2").Return(0, nil).MaxTimes(1)
+
+		require.NoError(t, th.App.DeleteBoard("board-1", "user-1"))
+	})
+
+	t.Run("deleted even if its notifications can't be hidden", func(t *testing.T) {
+		board := &model.Board{ID: "board-2", TeamID: "team-1"}
+		th.Store.EXPECT().GetBoard("board-2").Return(board, nil)
+		th.Store.EXPECT().DeleteBoard("board-2", "user-1").Return(nil)
+		th.Store.EXPECT().SetNotificationsHiddenForBoard("board-2", true).Return(nil, errors.New("database error"))
+		th.Store.EXPECT().GetMembersForBoard("board-2").Return([]*model.BoardMember{}, nil).AnyTimes()
+
+		require.NoError(t, th.App.DeleteBoard("board-2", "user-1"))
+	})
+}
+
+func TestUndeleteBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("shows the notifications of the board again", func(t *testing.T) {
+		board := &model.Board{ID: "board-1", TeamID: "team-1"}
+		th.Store.EXPECT().GetBoardHistory("board-1", gomock.Any()).Return([]*model.Board{board}, nil)
+		th.Store.EXPECT().UndeleteBoard("board-1", "user-1").Return(nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().SetNotificationsHiddenForBoard("board-1", false).Return([]string{"user-2"}, nil)
+		// for the board change and unread count broadcasts, sent asynchronously
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{}, nil).AnyTimes()
+		th.Store.EXPECT().GetUnreadNotificationCount("user-2").Return(1, nil).MaxTimes(1)
+
+		require.NoError(t, th.App.UndeleteBoard("board-1", "user-1"))
+	})
+}
+
+func TestDefaultBoardMemberRole(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+	defer func() { th.App.config.DefaultBoardMemberRole = "" }()
+
+	testCases := []struct {
+		name        string
+		minimumRole model.BoardRole
+		setting     string
+		expected    model.BoardRole
+	}{
+		{"board minimum role wins", model.BoardRoleViewer, "commenter", model.BoardRoleViewer},
+		{"setting used without minimum role", model.BoardRoleNone, "commenter", model.BoardRoleCommenter},
+		{"empty setting", model.BoardRoleNone, "", model.BoardRoleEditor},
+		{"unknown setting", model.BoardRoleNone, "owner", model.BoardRoleEditor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			th.App.config.DefaultBoardMemberRole = tc.setting
+			board := &model.Board{ID: "board-1", MinimumRole: tc.minimumRole}
+			require.Equal(t, tc.expected, th.App.DefaultBoardMemberRole(board))
+		})
+	}
+}
+
+func TestGetBoardMembershipsForUser(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("unknown user", func(t *testing.T) {
+		th.Store.EXPECT().GetUserByID("user-2").Return(nil, model.NewErrNotFound("user ID=user-2"))
+
+		memberships, _, err := th.App.GetBoardMembershipsForUser("user-2", 0, 10)
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, memberships)
+	})
+
+	t.Run("resolves the effective roles", func(t *testing.T) {
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetBoardMembershipsForUser("user-1", 1, 10).Return([]*model.UserBoardMembership{
+			{BoardID: "board-1", TeamID: "team-1", Member: &model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeViewer: true, MinimumRole: "editor"}},
+			{BoardID: "board-2", TeamID: "team-1", Member: &model.BoardMember{BoardID: "board-2", UserID: "user-1", SchemeAdmin: true, SchemeEditor: true}},
+			{BoardID: "board-3", TeamID: "team-2", Member: &model.BoardMember{BoardID: "board-3", UserID: "user-1", SchemeCommenter: true}},
+		}, true, nil)
+		th.API.EXPECT().HasPermissionToTeam("user-1", "team-1", model.PermissionManageTeam).Return(false)
+		th.API.EXPECT().HasPermissionToTeam("user-1", "team-2", model.PermissionManageTeam).Return(true)
+
+		memberships, hasMore, err := th.App.GetBoardMembershipsForUser("user-1", 1, 10)
+		require.NoError(t, err)
+		require.True(t, hasMore)
+		require.Len(t, memberships, 3)
+		assert.Equal(t, model.BoardRoleEditor, memberships[0].Role)
+		assert.Equal(t, model.BoardRoleAdmin, memberships[1].Role)
+		assert.Equal(t, model.BoardRoleAdmin, memberships[2].Role)
+	})
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// WatchCard makes a user follow a card, so they get its notifications like its assignees.
+// Watching a card twice does nothing.
+func (a *App) WatchCard(cardID, userID string) error {
+	return a.store.AddCardWatcher(cardID, userID)
+}
+
+// UnwatchCard stops a user from following a card.
+func (a *App) UnwatchCard(cardID, userID string) error {
+	return a.store.RemoveCardWatcher(cardID, userID)
+}
+
+// GetCardWatchers returns the users following a card, in the order they started to.
+func (a *App) GetCardWatchers(cardID string) ([]*model.CardWatcher, error) {
+	return a.store.GetCardWatchers(cardID)
+}
+
+// cardNotificationTarget is a user notified about the changes to a card, with the reason
+// they are.
+type cardNotificationTarget struct {
+	userID string
+	reason string
+}
+
+// cardNotificationTargets returns the users notified about the changes to a card: its
+// assignees, then its watchers who can still view its board, other than the actor and
+// each only once.
+func (a *App) cardNotificationTargets(card *model.Block, schema model.PropSchema, actorID string) []cardNotificationTarget {
+	seen := map[string]bool{actorID: true}
+	targets := []cardNotificationTarget{}
+	for _, userID := range model.GetPersonPropertyUserIDs(card, schema) {
+		if !seen[userID] {
+			seen[userID] = true
+			targets = append(targets, cardNotificationTarget{userID, model.NotificationReasonAssignee})
+		}
+	}
+
+	watchers, err := a.store.GetCardWatchers(card.ID)
+	if err != nil {
+		a.logger.Warn("Cannot read the watchers of a card", mlog.String("cardID", card.ID), mlog.Err(err))
+		return targets
+	}
+	for _, watcher := range watchers {
+		if seen[watcher.UserID] {
+			continue
+		}
+		seen[watcher.UserID] = true
+		// watchers keep watching after they lose access to the board, don't leak its changes
+		if !a.permissions.HasPermissionToBoard(watcher.UserID, card.BoardID, model.PermissionViewBoard) {
+			continue
+		}
+		targets = append(targets, cardNotificationTarget{watcher.UserID, model.NotificationReasonWatcher})
+	}
+	return targets
+}
+
+// sendCardNotifications creates the notifications about a change to a card as a single
+// batch.
+func (a *App) sendCardNotifications(cardID string, notifications []*model.UserNotification) {
+	if len(notifications) == 0 {
+		return
+	}
+	if _, _, err := a.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{}); err != nil {
+		a.logger.Error("Cannot notify card change",
+			mlog.String("cardID", cardID),
+			mlog.Int("count", len(notifications)),
+			mlog.Err(err),
+		)
+	}
+}
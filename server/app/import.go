@@ -261,6 +261,21 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 		return nil, fmt.Errorf("error generating archive block IDs: %w", err)
 	}
 
+	// the import creates every card at once, hold back the notifications it triggers and
+	// only tell each user how many there were once the boards are imported
+	boardIDs := make([]string, 0, len(boardsAndBlocks.Boards))
+	for _, board := range boardsAndBlocks.Boards {
+		boardIDs = append(boardIDs, board.ID)
+	}
+	a.suppressBoardNotifications(boardIDs)
+	imported := false
+	defer func() {
+		held := a.releaseBoardNotifications(boardIDs)
+		if imported {
+			a.notifyBoardsImported(boardsAndBlocks.Boards, opt.ModifiedBy, held)
+		}
+	}()
+
 	boardsAndBlocks, err = a.CreateBoardsAndBlocks(boardsAndBlocks, opt.ModifiedBy, false)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting archive blocks: %w", err)
@@ -295,6 +310,8 @@ func (a *App) ImportBoardJSONL(r io.Reader, opt model.ImportArchiveOptions) (*mo
 		}
 	}
 
+	imported = true
+
 	// find new board id
 	for _, board := range boardsAndBlocks.Boards {
 		return board, nil
@@ -23,4 +23,12 @@ func (a *App) Shutdown() {
 			a.logger.Warn("blockChangeNotifier shutdown timed out")
 		}
 	}
+
+	if a.notificationQueue != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationQueueShutdownTimeout)
+		defer cancel()
+		if !a.notificationQueue.shutdown(ctx) {
+			a.logger.Warn("notificationQueue shutdown timed out")
+		}
+	}
 }
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// inaccessibleNotificationBoards returns the boards the user has notifications of but can
+// no longer view, e.g. since they were removed from them, along with the number of unread
+// notifications of these boards. Returns nothing unless the
+// NotificationHideInaccessibleBoards setting is on. Notifications without a board are
+// always accessible. The memberships of the user are read at once, a board is accessible
+// if they grant at least the viewer role.
+func (a *App) inaccessibleNotificationBoards(userID string) ([]string, int, error) {
+	if !a.config.NotificationHideInaccessibleBoards {
+		return nil, 0, nil
+	}
+
+	counts, err := a.store.GetUnreadCountByBoard(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(counts) == 0 {
+		return nil, 0, nil
+	}
+
+	members, err := a.store.GetMembersForUser(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	viewable := make(map[string]bool, len(members))
+	for _, member := range members {
+		if member.EffectiveRole() != model.BoardRoleNone {
+			viewable[member.BoardID] = true
+		}
+	}
+
+	var boardIDs []string
+	unread := 0
+	for _, count := range counts {
+		if !viewable[count.BoardID] {
+			boardIDs = append(boardIDs, count.BoardID)
+			unread += count.Count
+		}
+	}
+	return boardIDs, unread, nil
+}
+
+// excludeInaccessibleNotificationBoards adds the boards the user can no longer view to the
+// boards filtered out of a notification list.
+func (a *App) excludeInaccessibleNotificationBoards(userID string, opts *model.QueryUserNotificationsOptions) error {
+	boardIDs, _, err := a.inaccessibleNotificationBoards(userID)
+	if err != nil {
+		return err
+	}
+	opts.ExcludeBoardIDs = append(opts.ExcludeBoardIDs, boardIDs...)
+	return nil
+}
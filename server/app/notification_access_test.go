@@ -0,0 +1,78 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestInaccessibleNotificationBoards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationHideInaccessibleBoards = true
+	defer func() { th.App.config.NotificationHideInaccessibleBoards = false }()
+
+	// user-1 was notified on the three boards, then removed from board-2 and left on
+	// board-3 without a role
+	expectBoards := func() {
+		th.Store.EXPECT().GetUnreadCountByBoard("user-1").Return([]*model.NotificationBoardUnreadCount{
+			{BoardID: "board-1", Count: 2},
+			{BoardID: "board-2", Count: 3},
+			{BoardID: "board-3", Count: 1},
+		}, nil)
+		th.Store.EXPECT().GetMembersForUser("user-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "user-1", SchemeViewer: true},
+			{BoardID: "board-3", UserID: "user-1"},
+			{BoardID: "board-4", UserID: "user-1", MinimumRole: string(model.BoardRoleEditor)},
+		}, nil)
+	}
+
+	t.Run("the list leaves out revoked boards", func(t *testing.T) {
+		expectBoards()
+		th.Store.EXPECT().GetUserNotifications("user-1", gomock.Any()).DoAndReturn(
+			func(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+				assert.Equal(t, []string{"board-2", "board-3"}, opts.Filter().ExcludeBoardIDs)
+				return []*model.UserNotification{{ID: "notification-1", TargetUserID: "user-1", BoardID: "board-1"}}, nil
+			},
+		)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		notifications, err := th.App.GetUserNotifications("user-1", model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+	})
+
+	t.Run("the unread count leaves out revoked boards", func(t *testing.T) {
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(6, nil)
+		expectBoards()
+
+		count, err := th.App.GetUnreadNotificationCount("user-1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("memberships are not read without notifications", func(t *testing.T) {
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(0, nil)
+		th.Store.EXPECT().GetUnreadCountByBoard("user-1").Return([]*model.NotificationBoardUnreadCount{}, nil)
+
+		count, err := th.App.GetUnreadNotificationCount("user-1")
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("nothing is hidden when disabled", func(t *testing.T) {
+		th.App.config.NotificationHideInaccessibleBoards = false
+		defer func() { th.App.config.NotificationHideInaccessibleBoards = true }()
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(6, nil)
+
+		count, err := th.App.GetUnreadNotificationCount("user-1")
+		require.NoError(t, err)
+		assert.Equal(t, 6, count)
+	})
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// notifyBoardMentions notifies every member of the board when a block's text mentions a
+// board mention alias, such as @board, that it didn't mention before. Only users that can
+// comment on the board may mention all of it, and an alias mentioned several times, or
+// several aliases, notify the members once.
+func (a *App) notifyBoardMentions(action notify.Action, block *model.Block, oldBlock *model.Block, modifiedByID string) {
+	if action == notify.Delete {
+		return
+	}
+
+	switch block.Type {
+	case model.TypeText, model.TypeComment, model.TypeImage:
+	default:
+		return
+	}
+
+	alias := a.newBoardMentionAlias(block, oldBlock)
+	if alias == "" {
+		return
+	}
+
+	if !a.permissions.HasPermissionToBoard(modifiedByID, block.BoardID, model.PermissionCommentBoardCards) {
+		a.logger.Debug("Board mention ignored, user cannot comment on the board",
+			mlog.String("boardID", block.BoardID),
+			mlog.String("userID", modifiedByID),
+		)
+		return
+	}
+
+	_, card, err := a.getBoardAndCard(block)
+	if err != nil {
+		a.logger.Error("Cannot notify board mention, card not found", mlog.String("blockID", block.ID), mlog.Err(err))
+		return
+	}
+
+	template := &model.UserNotification{
+		ActorUserID: modifiedByID,
+		ActorName:   a.notificationActorName(modifiedByID),
+	}
+	if card != nil {
+		template.CardID = card.ID
+		template.CardTitle = card.Title
+	}
+
+	if _, _, err := a.CreateBoardMentionNotifications(block.BoardID, alias, template); err != nil {
+		a.logger.Error("Cannot notify board mention",
+			mlog.String("boardID", block.BoardID),
+			mlog.String("alias", alias),
+			mlog.Err(err),
+		)
+	}
+}
+
+// boardMentionRegexp matches the at-mentions of a block's text, like the mentions of users.
+var boardMentionRegexp = regexp.MustCompile(`\B@[[:alnum:]][[:alnum:]\.\-_:]*`)
+
+// newBoardMentionAlias returns a board mention alias the block mentions and the old
+// version of the block didn't, or an empty string if there is none.
+func (a *App) newBoardMentionAlias(block *model.Block, oldBlock *model.Block) string {
+	oldAliases := a.extractBoardMentionAliases(oldBlock)
+	for alias := range a.extractBoardMentionAliases(block) {
+		if _, existed := oldAliases[alias]; !existed {
+			return alias
+		}
+	}
+	return ""
+}
+
+// extractBoardMentionAliases returns the board mention aliases the block's text mentions.
+// They are parsed here rather than with the mentions of users, as aliases such as all are
+// restricted usernames that the mentions of users leave out.
+func (a *App) extractBoardMentionAliases(block *model.Block) map[string]struct{} {
+	aliases := make(map[string]struct{})
+	if block == nil || !strings.Contains(block.Title, "@") {
+		return aliases
+	}
+
+	for _, match := range boardMentionRegexp.FindAllString(block.Title, -1) {
+		// punctuation ending a sentence is not part of the mention
+		alias := strings.ToLower(strings.TrimRight(match[1:], ".-_:"))
+		if a.IsBoardMentionAlias(alias) {
+			aliases[alias] = struct{}{}
+		}
+	}
+	return aliases
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/notify"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
+	"github.com/stretchr/testify/assert"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotifyBoardMentions(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	card := &model.Block{ID: "card-1", BoardID: "board-1", Type: model.TypeCard, Title: "Launch"}
+	comment := &model.Block{ID: "comment-1", ParentID: "card-1", BoardID: "board-1", Type: model.TypeComment, Title: "Heads up @all"}
+
+	t.Run("members are notified of a new alias mention", func(t *testing.T) {
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "actor").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "actor", SchemeCommenter: true}, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1"}, nil)
+		th.Store.EXPECT().GetBlock("card-1").Return(card, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "actor"},
+			{BoardID: "board-1", UserID: "user-1"},
+		}, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1"}, false, false).Return([]*model.User{{ID: "user-1"}}, nil)
+		th.Store.EXPECT().GetUsersNotificationBoardPreferences([]string{"user-1"}, "board-1").
+			Return(map[string][]*model.NotificationBoardPreference{}, nil)
+		th.Store.EXPECT().GetUsersPreferences([]string{"user-1"}).Return(map[string]mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(false, nil)
+		th.Store.EXPECT().CreateUserNotification(gomock.Any()).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				assert.Equal(t, "user-1", notification.TargetUserID)
+				assert.Equal(t, model.NotificationReasonBoardMention, notification.Reason)
+				assert.Equal(t, "card-1", notification.CardID)
+				assert.Equal(t, "Launch", notification.CardTitle)
+				assert.Equal(t, "alice", notification.ActorName)
+				return notification, nil
+			},
+		)
+
+		th.App.notifyBoardMentions(notify.Add, comment, nil, "actor")
+	})
+
+	t.Run("an alias that was already mentioned is not notified again", func(t *testing.T) {
+		edited := &model.Block{ID: "comment-1", ParentID: "card-1", BoardID: "board-1", Type: model.TypeComment, Title: "Heads up @all, edited"}
+		th.App.notifyBoardMentions(notify.Update, edited, comment, "actor")
+	})
+
+	t.Run("users who cannot comment cannot mention the board", func(t *testing.T) {
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "viewer").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "viewer", SchemeViewer: true}, nil)
+		th.App.notifyBoardMentions(notify.Add, comment, nil, "viewer")
+	})
+
+	t.Run("mentions of users are left to the mentions backend", func(t *testing.T) {
+		mention := &model.Block{ID: "comment-2", ParentID: "card-1", BoardID: "board-1", Type: model.TypeComment, Title: "Thanks @bob"}
+		th.App.notifyBoardMentions(notify.Add, mention, nil, "actor")
+	})
+
+	t.Run("aliases are parsed from the text", func(t *testing.T) {
+		block := &model.Block{Title: "@All, @board. and @bob or me@board"}
+		assert.Equal(t, map[string]struct{}{"all": {}, "board": {}}, th.App.extractBoardMentionAliases(block))
+	})
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// notifyBoardShared notifies the admins of a board, but the one who shared it, that the
+// board was shared. The notifications go through the admins' preferences and mutes like
+// any other, failures are only logged as the board is shared already.
+func (a *App) notifyBoardShared(boardID, actorID string) {
+	board, err := a.store.GetBoard(boardID)
+	if err != nil {
+		a.logger.Error("Cannot notify board share, board not found", mlog.String("boardID", boardID), mlog.Err(err))
+		return
+	}
+
+	members, err := a.store.GetMembersForBoard(boardID)
+	if err != nil {
+		a.logger.Error("Cannot notify board share, cannot get the board members", mlog.String("boardID", boardID), mlog.Err(err))
+		return
+	}
+
+	var actorName string
+	if actorID != "" {
+		actorName = a.notificationActorName(actorID)
+	}
+
+	notifications := []*model.UserNotification{}
+	for _, member := range members {
+		if !member.SchemeAdmin || member.UserID == actorID {
+			continue
+		}
+		notifications = append(notifications, &model.UserNotification{
+			TargetUserID: member.UserID,
+			ActorUserID:  actorID,
+			ActorName:    actorName,
+			Type:         model.NotificationTypeBoardShared,
+			CardTitle:    board.Title,
+			BoardID:      board.ID,
+			Reason:       model.NotificationReasonBoardAdmin,
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	if _, _, err := a.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{}); err != nil {
+		a.logger.Error("Cannot notify board share",
+			mlog.String("boardID", boardID),
+			mlog.Int("count", len(notifications)),
+			mlog.Err(err),
+		)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// notifyCardCommented notifies the assignees and watchers of a card when a comment is
+// added to it. The user who commented is not notified. The notifications are created as
+// a batch with CreateAndBroadcastNotifications, so the preferences, mutes and blocks of
+// their targets apply.
+func (a *App) notifyCardCommented(comment *model.Block, modifiedByID string) {
+	if comment.Type != model.TypeComment || comment.ParentID == "" {
+		return
+	}
+
+	card, err := a.store.GetBlock(comment.ParentID)
+	if err != nil {
+		a.logger.Error("Cannot notify comment, card not found", mlog.String("commentID", comment.ID), mlog.Err(err))
+		return
+	}
+	if card.Type != model.TypeCard {
+		return
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		a.logger.Error("Cannot notify comment, board not found", mlog.String("cardID", card.ID), mlog.Err(err))
+		return
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Error("Cannot notify comment, invalid card properties", mlog.String("boardID", board.ID), mlog.Err(err))
+		return
+	}
+
+	targets := a.cardNotificationTargets(card, schema, modifiedByID)
+	if len(targets) == 0 {
+		return
+	}
+
+	actorName := a.notificationActorName(modifiedByID)
+	notifications := make([]*model.UserNotification, 0, len(targets))
+	for _, target := range targets {
+		notifications = append(notifications, &model.UserNotification{
+			TargetUserID: target.userID,
+			ActorUserID:  modifiedByID,
+			ActorName:    actorName,
+			Type:         model.NotificationTypeCommented,
+			CardID:       card.ID,
+			CardTitle:    card.Title,
+			BoardID:      card.BoardID,
+			Reason:       target.reason,
+		})
+	}
+	a.sendCardNotifications(card.ID, notifications)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
+	"github.com/stretchr/testify/assert"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotifyCardCommented(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	board := &model.Board{
+		ID: "board-1",
+		CardProperties: []map[string]interface{}{
+			{"id": "assignees", "name": "Assignees", "type": "multiPerson"},
+		},
+	}
+	card := &model.Block{ID: "card-1", BoardID: "board-1", Type: model.TypeCard, Title: "Launch",
+		Fields: map[string]interface{}{"properties": map[string]interface{}{"assignees": []interface{}{"actor", "user-1"}}}}
+	comment := &model.Block{ID: "comment-1", ParentID: "card-1", BoardID: "board-1", Type: model.TypeComment, Title: "Looks good"}
+
+	t.Run("assignees and watchers are notified", func(t *testing.T) {
+		th.Store.EXPECT().GetBlock("card-1").Return(card, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return([]*model.CardWatcher{{CardID: "card-1", UserID: "watcher"}}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "watcher").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "watcher", SchemeViewer: true}, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		for _, userID := range []string{"user-1", "watcher"} {
+			th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+			th.Store.EXPECT().IsNotificationActorBlocked(userID, "actor").Return(false, nil)
+			th.Store.EXPECT().GetNotificationBoardPreferences(userID, "board-1").Return(nil, nil)
+			th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+			th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+		}
+		reasons := map[string]string{"user-1": model.NotificationReasonAssignee, "watcher": model.NotificationReasonWatcher}
+		th.Store.EXPECT().CreateUserNotifications(gomock.Any()).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				assert.Len(t, notifications, 2)
+				for _, notification := range notifications {
+					assert.Equal(t, model.NotificationTypeCommented, notification.Type)
+					assert.Equal(t, reasons[notification.TargetUserID], notification.Reason)
+					assert.Equal(t, "card-1", notification.CardID)
+					assert.Equal(t, "alice", notification.ActorName)
+				}
+				return notifications, nil
+			},
+		)
+
+		th.App.notifyCardCommented(comment, "actor")
+	})
+
+	t.Run("blocks other than comments are ignored", func(t *testing.T) {
+		th.App.notifyCardCommented(card, "actor")
+	})
+}
@@ -0,0 +1,28 @@
+package app
+
+import "github.com/mattermost/focalboard/server/model"
+
+// SetNotificationDeliveryStatus records the outcome of delivering a notification through
+// an out-of-band channel, creating the record on the first update. deliveryErr is kept
+// as the last error of failed deliveries.
+func (a *App) SetNotificationDeliveryStatus(notificationID, channel, status string, deliveryErr error) error {
+	delivery := &model.NotificationDelivery{
+		NotificationID: notificationID,
+		Channel:        channel,
+		Status:         status,
+	}
+	if err := delivery.IsValid(); err != nil {
+		return model.NewErrBadRequest(err.Error())
+	}
+
+	errMsg := ""
+	if deliveryErr != nil && status == model.NotificationDeliveryStatusFailed {
+		errMsg = deliveryErr.Error()
+	}
+	return a.store.UpdateNotificationDeliveryStatus(notificationID, channel, status, errMsg)
+}
+
+// GetNotificationDeliveries returns a page of notification deliveries matching the given filters.
+func (a *App) GetNotificationDeliveries(opts model.QueryNotificationDeliveriesOptions) ([]*model.NotificationDelivery, bool, error) {
+	return a.store.GetNotificationDeliveries(opts)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetNotificationDeliveryStatus(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("failed delivery keeps the error", func(t *testing.T) {
+		th.Store.EXPECT().UpdateNotificationDeliveryStatus("notification-1", model.NotificationChannelEmail, model.NotificationDeliveryStatusFailed, "smtp timeout").Return(nil)
+
+		err := th.App.SetNotificationDeliveryStatus("notification-1", model.NotificationChannelEmail, model.NotificationDeliveryStatusFailed, errors.New("smtp timeout"))
+		require.NoError(t, err)
+	})
+
+	t.Run("sent delivery clears the error", func(t *testing.T) {
+		th.Store.EXPECT().UpdateNotificationDeliveryStatus("notification-1", model.NotificationChannelWebhook, model.NotificationDeliveryStatusSent, "").Return(nil)
+
+		err := th.App.SetNotificationDeliveryStatus("notification-1", model.NotificationChannelWebhook, model.NotificationDeliveryStatusSent, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("in-app deliveries can't be failed", func(t *testing.T) {
+		err := th.App.SetNotificationDeliveryStatus("notification-1", model.NotificationChannelInApp, model.NotificationDeliveryStatusFailed, nil)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		err := th.App.SetNotificationDeliveryStatus("notification-1", "sms", model.NotificationDeliveryStatusSent, nil)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
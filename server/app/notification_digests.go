@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// SendNotificationDigests turns the board activity that accumulated for longer than the
+// interval into one summary notification per user and board, linking to the board. It
+// returns the number of digests sent.
+func (a *App) SendNotificationDigests(interval time.Duration) (int, error) {
+	digests, err := a.store.GetDueNotificationDigests(utils.GetMillis() - interval.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, digest := range digests {
+		board, err := a.store.GetBoard(digest.BoardID)
+		if model.IsErrNotFound(err) {
+			if _, err := a.store.DeleteNotificationDigest(digest.UserID, digest.BoardID, digest.LastAt); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if err != nil {
+			return sent, err
+		}
+
+		notification := &model.UserNotification{
+			TargetUserID: digest.UserID,
+			Type:         model.NotificationTypeBoardDigest,
+			CardTitle:    notificationDigestSummary(digest.PendingCount, board.Title),
+			BoardID:      digest.BoardID,
+		}
+
+		created, err := a.sendNotificationDigest(digest, notification)
+		if err != nil {
+			a.logger.Error("Cannot send notification digest",
+				mlog.String("userID", digest.UserID),
+				mlog.String("boardID", digest.BoardID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		if created == nil {
+			continue
+		}
+
+		a.broadcastUserNotification(created)
+		sent++
+	}
+	return sent, nil
+}
+
+// sendNotificationDigest creates the notification of a digest and removes the digest in
+// one transaction, so that a failure leaves the digest to be sent next time. It returns
+// nil if there is nothing to send, because more activity came in or the target cannot
+// get the notification anymore.
+func (a *App) sendNotificationDigest(digest *model.NotificationDigest, notification *model.UserNotification) (*model.UserNotification, error) {
+	opts := model.CreateUserNotificationOptions{SkipPreferences: true, SkipAssigneeCheck: true, Synchronous: true, Source: model.NotificationSourceJob}
+	deliver, err := a.prepareNotification(notification, opts, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !deliver {
+		_, err := a.store.DeleteNotificationDigest(digest.UserID, digest.BoardID, digest.LastAt)
+		return nil, err
+	}
+	return a.store.SendNotificationDigest(digest, notification)
+}
+
+// notificationDigestSummary describes the activity rolled up in a digest.
+func notificationDigestSummary(count int, boardTitle string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 update on %s", boardTitle)
+	}
+	return fmt.Sprintf("%d updates on %s", count, boardTitle)
+}
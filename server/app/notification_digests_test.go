@@ -0,0 +1,77 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreateAndBroadcastNotificationDigest(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+	th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+	th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+	th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{
+		{UserId: "user-1", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationPreferences, Value: `{"digestBoardIds":["board-1"]}`},
+	}, nil)
+	th.Store.EXPECT().AddNotificationDigestActivity("user-1", "board-1", utils.Anything).Return(nil)
+
+	created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+	require.NoError(t, err)
+	require.Nil(t, created)
+}
+
+func TestSendNotificationDigests(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	digests := []*model.NotificationDigest{
+		{UserID: "user-1", BoardID: "board-1", PendingCount: 5, FirstAt: 100, LastAt: 200},
+		{UserID: "user-2", BoardID: "board-1", PendingCount: 2, FirstAt: 100, LastAt: 300},
+		{UserID: "user-3", BoardID: "board-1", PendingCount: 1, FirstAt: 100, LastAt: 400},
+	}
+	th.Store.EXPECT().GetDueNotificationDigests(utils.Anything).Return(digests, nil)
+	th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1", Title: "Roadmap"}, nil).Times(3)
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+	th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+	th.Store.EXPECT().GetUserByID("user-3").Return(&model.User{ID: "user-3"}, nil)
+	th.Store.EXPECT().SendNotificationDigest(digests[0], utils.Anything).DoAndReturn(
+		func(_ *model.NotificationDigest, notification *model.UserNotification) (*model.UserNotification, error) {
+			require.Equal(t, model.NotificationTypeBoardDigest, notification.Type)
+			require.Equal(t, "5 updates on Roadmap", notification.CardTitle)
+			return notification, nil
+		},
+	)
+	// more activity came in since the digest was read
+	th.Store.EXPECT().SendNotificationDigest(digests[1], utils.Anything).Return(nil, nil)
+	// the digest is kept and sent next time
+	th.Store.EXPECT().SendNotificationDigest(digests[2], utils.Anything).Return(nil, errors.New("insert failed"))
+
+	sent, err := th.App.SendNotificationDigests(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+}
+
+func TestSendNotificationDigestsDeactivatedTarget(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	digest := &model.NotificationDigest{UserID: "user-1", BoardID: "board-1", PendingCount: 5, FirstAt: 100, LastAt: 200}
+	th.Store.EXPECT().GetDueNotificationDigests(utils.Anything).Return([]*model.NotificationDigest{digest}, nil)
+	th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1", Title: "Roadmap"}, nil)
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1", DeleteAt: 100}, nil)
+	th.Store.EXPECT().DeleteNotificationDigest("user-1", "board-1", int64(200)).Return(true, nil)
+
+	sent, err := th.App.SendNotificationDigests(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 0, sent)
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+)
+
+// dueDateUnset is how a due date that is not set renders in notification messages.
+const dueDateUnset = "none"
+
+// notifyDueDateChanged notifies the assignees and watchers of a card when one of its due
+// dates is set, changed or cleared. The user who made the change is not notified, so
+// nothing is sent if they are the only one following the card. The notifications are
+// created as a batch with CreateAndBroadcastNotifications, so the preferences, mutes and
+// blocks of their targets apply.
+func (a *App) notifyDueDateChanged(card, oldCard *model.Block, schema model.PropSchema, modifiedByID string) {
+	var targets []cardNotificationTarget
+	var actorName string
+	var notifications []*model.UserNotification
+	for _, propID := range schema.DueDatePropertyIDs() {
+		oldValue, newValue := cardPropertyString(oldCard, propID), cardPropertyString(card, propID)
+		if oldValue == newValue {
+			continue
+		}
+
+		if targets == nil {
+			targets = a.cardNotificationTargets(card, schema, modifiedByID)
+			if len(targets) == 0 {
+				return
+			}
+			actorName = a.notificationActorName(modifiedByID)
+		}
+
+		def := schema[propID]
+		params := map[string]string{
+			"property": def.Name,
+			"oldDate":  formatDueDate(def, oldValue),
+			"newDate":  formatDueDate(def, newValue),
+		}
+		for _, target := range targets {
+			notifications = append(notifications, &model.UserNotification{
+				TargetUserID: target.userID,
+				ActorUserID:  modifiedByID,
+				ActorName:    actorName,
+				Type:         model.NotificationTypeDueDateChanged,
+				CardID:       card.ID,
+				CardTitle:    card.Title,
+				BoardID:      card.BoardID,
+				Params:       params,
+				Reason:       target.reason,
+			})
+		}
+	}
+	a.sendCardNotifications(card.ID, notifications)
+}
+
+// notificationActorName returns the username of the user who triggered a notification,
+// or an empty string if they can't be found.
+func (a *App) notificationActorName(userID string) string {
+	user, err := a.store.GetUserByID(userID)
+	if err != nil || user == nil {
+		return ""
+	}
+	return user.Username
+}
+
+// cardPropertyString returns the value of a card property holding a string, e.g. a date,
+// or an empty string if it is not set.
+func cardPropertyString(card *model.Block, propID string) string {
+	props, _ := card.Fields["properties"].(map[string]interface{})
+	value, _ := props[propID].(string)
+	return value
+}
+
+// formatDueDate renders the value of a date property for notification messages.
+func formatDueDate(def model.PropDef, value string) string {
+	if value == "" {
+		return dueDateUnset
+	}
+	date, err := def.ParseDate(value)
+	if err != nil {
+		return value
+	}
+	return date
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
+	"github.com/stretchr/testify/assert"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotifyDueDateChanged(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	board := &model.Board{
+		ID: "board-1",
+		CardProperties: []map[string]interface{}{
+			{"id": "due", "name": "Due date", "type": "date"},
+			{"id": "started", "name": "Started", "type": "date"},
+			{"id": "assignees", "name": "Assignees", "type": "multiPerson"},
+		},
+	}
+	makeCard := func(props map[string]interface{}) *model.Block {
+		return &model.Block{ID: "card-1", BoardID: "board-1", Type: model.TypeCard, Title: "Launch",
+			Fields: map[string]interface{}{"properties": props}}
+	}
+	// January 2, 2006 at noon UTC
+	newDate := `{"from":1136203200000}`
+
+	t.Run("assignees other than the actor are notified", func(t *testing.T) {
+		oldCard := makeCard(map[string]interface{}{"assignees": []interface{}{"actor", "user-1"}})
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"actor", "user-1"}, "due": newDate})
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(false, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotifications(gomock.Any()).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				assert.Len(t, notifications, 1)
+				notification := notifications[0]
+				assert.Equal(t, "user-1", notification.TargetUserID)
+				assert.Equal(t, model.NotificationTypeDueDateChanged, notification.Type)
+				assert.Equal(t, "alice", notification.ActorName)
+				assert.Equal(t, map[string]string{"property": "Due date", "oldDate": "none", "newDate": "January 02, 2006"}, notification.Params)
+				return notifications, nil
+			},
+		)
+
+		th.App.notifyCardPropertiesChanged(card, oldCard, "actor")
+	})
+
+	t.Run("the actor as only assignee is not notified", func(t *testing.T) {
+		oldCard := makeCard(map[string]interface{}{"assignees": []interface{}{"actor"}, "due": newDate})
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"actor"}})
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return([]*model.CardWatcher{{CardID: "card-1", UserID: "actor"}}, nil)
+
+		th.App.notifyCardPropertiesChanged(card, oldCard, "actor")
+	})
+
+	t.Run("watchers are notified along with the assignees", func(t *testing.T) {
+		oldCard := makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}})
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}, "due": newDate})
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return([]*model.CardWatcher{
+			{CardID: "card-1", UserID: "watcher"},
+			{CardID: "card-1", UserID: "user-1"},
+		}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "watcher").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "watcher", SchemeViewer: true}, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		for _, userID := range []string{"user-1", "watcher"} {
+			th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+			th.Store.EXPECT().IsNotificationActorBlocked(userID, "actor").Return(false, nil)
+			th.Store.EXPECT().GetNotificationBoardPreferences(userID, "board-1").Return(nil, nil)
+			th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+			th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+		}
+		th.Store.EXPECT().CreateUserNotifications(gomock.Any()).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				targetIDs := []string{}
+				for _, notification := range notifications {
+					targetIDs = append(targetIDs, notification.TargetUserID)
+				}
+				assert.Equal(t, []string{"user-1", "watcher"}, targetIDs)
+				return notifications, nil
+			},
+		)
+
+		th.App.notifyCardPropertiesChanged(card, oldCard, "actor")
+	})
+
+	t.Run("watchers who can no longer view the board are not notified", func(t *testing.T) {
+		oldCard := makeCard(map[string]interface{}{"assignees": []interface{}{"actor"}})
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"actor"}, "due": newDate})
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return([]*model.CardWatcher{{CardID: "card-1", UserID: "former"}}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "former").Return(nil, model.NewErrNotFound("member"))
+
+		th.App.notifyCardPropertiesChanged(card, oldCard, "actor")
+	})
+
+	t.Run("other date properties are ignored", func(t *testing.T) {
+		oldCard := makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}})
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}, "started": newDate})
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+
+		th.App.notifyCardPropertiesChanged(card, oldCard, "actor")
+	})
+
+	t.Run("unchanged properties don't read the board", func(t *testing.T) {
+		card := makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}, "due": newDate})
+
+		th.App.notifyCardPropertiesChanged(card, makeCard(map[string]interface{}{"assignees": []interface{}{"user-1"}, "due": newDate}), "actor")
+	})
+}
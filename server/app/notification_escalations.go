@@ -0,0 +1,74 @@
+package app
+
+import (
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// notificationEscalationBatchSize caps the notifications escalated per run, the rest are
+// escalated by the next runs.
+const notificationEscalationBatchSize = 100
+
+// EscalateNotifications escalates the high urgency notifications left unread for longer
+// than the threshold: each one gets a pending delivery through the first channel of the
+// NotificationEscalationChannels setting it has no delivery for yet, even if the target
+// turned that channel off, and is never escalated again. It returns the number of
+// notifications escalated.
+func (a *App) EscalateNotifications(threshold time.Duration) (int, error) {
+	channels := a.notificationEscalationChannels()
+	if len(channels) == 0 {
+		return 0, nil
+	}
+
+	notifications, err := a.store.GetNotificationsToEscalate(utils.GetMillis()-threshold.Milliseconds(), notificationEscalationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, notification := range notifications {
+		channel, err := a.store.EscalateNotification(notification.ID, channels)
+		if err != nil {
+			a.logger.Error("Cannot escalate notification",
+				mlog.String("notificationID", notification.ID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		if channel == "" {
+			continue
+		}
+		a.logger.Debug("Notification escalated",
+			mlog.String("notificationID", notification.ID),
+			mlog.String("targetUserID", notification.TargetUserID),
+			mlog.String("channel", channel),
+		)
+		escalated++
+	}
+	return escalated, nil
+}
+
+// notificationEscalationChannels returns the out-of-band channels of the
+// NotificationEscalationChannels setting, in order and each only once. Unknown channels
+// are logged and skipped.
+func (a *App) notificationEscalationChannels() []string {
+	channels := []string{}
+	seen := map[string]bool{}
+	for _, channel := range a.config.NotificationEscalationChannels {
+		switch channel {
+		case model.NotificationChannelEmail, model.NotificationChannelWebhook:
+		default:
+			a.logger.Warn("Invalid notification escalation channel, skipped", mlog.String("channel", channel))
+			continue
+		}
+		if !seen[channel] {
+			seen[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalateNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationEscalationChannels = []string{"email", "sms", "webhook", "email"}
+	defer func() { th.App.config.NotificationEscalationChannels = nil }()
+
+	t.Run("escalates through the next channel", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{ID: "n-1", TargetUserID: "user-1", Urgency: model.NotificationUrgencyHigh},
+			{ID: "n-2", TargetUserID: "user-2", Urgency: model.NotificationUrgencyHigh},
+			{ID: "n-3", TargetUserID: "user-3", Urgency: model.NotificationUrgencyHigh},
+		}
+		channels := []string{model.NotificationChannelEmail, model.NotificationChannelWebhook}
+		th.Store.EXPECT().GetNotificationsToEscalate(utils.Anything, notificationEscalationBatchSize).Return(notifications, nil)
+		th.Store.EXPECT().EscalateNotification("n-1", channels).Return(model.NotificationChannelEmail, nil)
+		// every channel was already used
+		th.Store.EXPECT().EscalateNotification("n-2", channels).Return("", nil)
+		th.Store.EXPECT().EscalateNotification("n-3", channels).Return("", errors.New("database error"))
+
+		escalated, err := th.App.EscalateNotifications(time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, 1, escalated)
+	})
+
+	t.Run("nothing to escalate through", func(t *testing.T) {
+		th.App.config.NotificationEscalationChannels = []string{model.NotificationChannelInApp}
+
+		escalated, err := th.App.EscalateNotifications(time.Hour)
+		require.NoError(t, err)
+		require.Zero(t, escalated)
+	})
+}
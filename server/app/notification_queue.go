@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	notificationQueueBatchSize       = 50
+	notificationQueueShutdownTimeout = time.Second * 10
+)
+
+// notificationQueue is a bounded queue of notifications waiting to be stored and
+// broadcast. Workers drain it in batches so a burst of notifications, e.g. from a bulk
+// import, turns into a few multi-row inserts instead of one insert per notification.
+type notificationQueue struct {
+	app     *App
+	queue   chan *model.UserNotification
+	done    chan struct{}
+	wg      sync.WaitGroup
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// newNotificationQueue starts the workers of a queue holding up to size notifications.
+func newNotificationQueue(app *App, size, workers int) *notificationQueue {
+	nq := &notificationQueue{
+		app:   app,
+		queue: make(chan *model.UserNotification, size),
+		done:  make(chan struct{}),
+	}
+
+	for i := 0; i < workers; i++ {
+		nq.wg.Add(1)
+		go nq.loop()
+	}
+	return nq
+}
+
+// enqueue adds a notification to the queue without blocking. It returns false if the
+// queue is full or shut down.
+func (nq *notificationQueue) enqueue(notification *model.UserNotification) bool {
+	nq.closeMu.RLock()
+	defer nq.closeMu.RUnlock()
+
+	if nq.closed {
+		return false
+	}
+
+	select {
+	case nq.queue <- notification:
+		nq.app.metrics.ObserveNotificationQueueDepth(len(nq.queue))
+		return true
+	default:
+		return false
+	}
+}
+
+// depth returns the number of notifications waiting in the queue.
+func (nq *notificationQueue) depth() int {
+	return len(nq.queue)
+}
+
+// shutdown stops the workers and stores whatever is left in the queue. Returns false if
+// the context expires first.
+func (nq *notificationQueue) shutdown(ctx context.Context) bool {
+	nq.closeMu.Lock()
+	if nq.closed {
+		nq.closeMu.Unlock()
+		return true
+	}
+	nq.closed = true
+	close(nq.done)
+	nq.closeMu.Unlock()
+
+	exited := make(chan struct{})
+	go func() {
+		nq.wg.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		return false
+	}
+
+	for {
+		batch := nq.nextBatch(nil)
+		if len(batch) == 0 {
+			return true
+		}
+		nq.process(batch)
+		if ctx.Err() != nil {
+			return false
+		}
+	}
+}
+
+func (nq *notificationQueue) loop() {
+	defer nq.wg.Done()
+
+	for {
+		select {
+		case notification := <-nq.queue:
+			nq.process(nq.nextBatch(notification))
+		case <-nq.done:
+			return
+		}
+	}
+}
+
+// nextBatch returns first, if not nil, followed by the notifications already waiting in
+// the queue, up to notificationQueueBatchSize.
+func (nq *notificationQueue) nextBatch(first *model.UserNotification) []*model.UserNotification {
+	batch := make([]*model.UserNotification, 0, notificationQueueBatchSize)
+	if first != nil {
+		batch = append(batch, first)
+	}
+
+	for len(batch) < notificationQueueBatchSize {
+		select {
+		case notification := <-nq.queue:
+			batch = append(batch, notification)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+func (nq *notificationQueue) process(batch []*model.UserNotification) {
+	nq.app.metrics.ObserveNotificationQueueDepth(len(nq.queue))
+
+	created, err := nq.app.store.CreateUserNotifications(batch)
+	if err != nil {
+		nq.app.logger.Warn("Cannot store queued notifications in a batch, storing them one by one",
+			mlog.Int("count", len(batch)),
+			mlog.Err(err),
+		)
+		created = nq.createEach(batch)
+	}
+
+	for _, notification := range created {
+		nq.app.broadcastUserNotification(notification)
+	}
+}
+
+// createEach stores the notifications of a batch that failed to store one by one, so a
+// single bad notification doesn't drop the others. It returns the stored notifications.
+func (nq *notificationQueue) createEach(batch []*model.UserNotification) []*model.UserNotification {
+	created := make([]*model.UserNotification, 0, len(batch))
+	for _, notification := range batch {
+		stored, err := nq.app.store.CreateUserNotification(notification)
+		if err != nil {
+			nq.app.logger.Error("Cannot store queued notification",
+				mlog.String("notificationID", notification.ID),
+				mlog.String("targetUserID", notification.TargetUserID),
+				mlog.Err(err),
+			)
+			continue
+		}
+		created = append(created, stored)
+	}
+	return created
+}
+
+// NotificationQueueDepth returns the number of notifications waiting to be stored, or 0
+// if notifications are stored synchronously.
+func (a *App) NotificationQueueDepth() int {
+	if a.notificationQueue == nil {
+		return 0
+	}
+	return a.notificationQueue.depth()
+}
+
+// NotificationQueueEnabled returns true if notifications are queued instead of being
+// stored synchronously.
+func (a *App) NotificationQueueEnabled() bool {
+	return a.notificationQueue != nil
+}
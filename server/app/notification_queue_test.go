@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreateAndBroadcastNotificationQueued(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("queued notifications are stored in a batch", func(t *testing.T) {
+		// no workers, the queue is drained on shutdown
+		th.App.notificationQueue = newNotificationQueue(th.App, 10, 0)
+		defer func() { th.App.notificationQueue = nil }()
+
+		for _, userID := range []string{"user-1", "user-2"} {
+			notification := &model.UserNotification{TargetUserID: userID, Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+			th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+			th.Store.EXPECT().GetNotificationBoardPreferences(userID, "board-1").Return(nil, nil)
+			th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+			th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+
+			created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+			require.NoError(t, err)
+			require.Nil(t, created)
+		}
+		require.Equal(t, 2, th.App.NotificationQueueDepth())
+
+		th.Store.EXPECT().CreateUserNotifications(gomock.Len(2)).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				return notifications, nil
+			},
+		)
+		require.True(t, th.App.notificationQueue.shutdown(context.Background()))
+		require.Equal(t, 0, th.App.NotificationQueueDepth())
+	})
+
+	t.Run("a failed batch is stored one by one", func(t *testing.T) {
+		th.App.notificationQueue = newNotificationQueue(th.App, 10, 0)
+		defer func() { th.App.notificationQueue = nil }()
+
+		good := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		bad := &model.UserNotification{ID: "n-2", TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		require.True(t, th.App.notificationQueue.enqueue(good))
+		require.True(t, th.App.notificationQueue.enqueue(bad))
+
+		th.Store.EXPECT().CreateUserNotifications(gomock.Len(2)).Return(nil, errors.New("duplicate key"))
+		th.Store.EXPECT().CreateUserNotification(good).Return(good, nil)
+		th.Store.EXPECT().CreateUserNotification(bad).Return(nil, errors.New("duplicate key"))
+
+		require.True(t, th.App.notificationQueue.shutdown(context.Background()))
+	})
+
+	t.Run("full queue applies backpressure", func(t *testing.T) {
+		th.App.notificationQueue = newNotificationQueue(th.App, 1, 0)
+		defer func() { th.App.notificationQueue = nil }()
+
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil).Times(2)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil).Times(2)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil).Times(2)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil).Times(2)
+
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		_, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+
+		notification = &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-2", BoardID: "board-1"}
+		_, err = th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrTooManyRequests(err))
+	})
+
+	t.Run("synchronous callers get the stored notification", func(t *testing.T) {
+		th.App.notificationQueue = newNotificationQueue(th.App, 10, 0)
+		defer func() { th.App.notificationQueue = nil }()
+
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		stored := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(stored, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, stored, created)
+		require.Equal(t, 0, th.App.NotificationQueueDepth())
+	})
+}
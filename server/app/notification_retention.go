@@ -0,0 +1,60 @@
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+const notificationRetentionBatchSize = 1000
+
+// PurgeUserNotifications deletes the notifications older than their retention window and
+// returns how many were deleted per type, with the ones deleted by the global window under
+// an empty type. Types in typeRetentionDays use their own window instead of retentionDays.
+// A window of 0 days keeps the notifications forever.
+func (a *App) PurgeUserNotifications(retentionDays int, typeRetentionDays map[string]int) (map[string]int64, error) {
+	overridden := make([]string, 0, len(typeRetentionDays))
+	for notifType := range typeRetentionDays {
+		overridden = append(overridden, notifType)
+	}
+	sort.Strings(overridden)
+
+	deleted := map[string]int64{}
+	for _, notifType := range overridden {
+		days := typeRetentionDays[notifType]
+		if days <= 0 {
+			continue
+		}
+
+		opts := model.PurgeUserNotificationsOptions{
+			Before: retentionCutoff(days),
+			Types:  []string{notifType},
+		}
+		count, err := a.store.DeleteUserNotificationsBefore(opts, notificationRetentionBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+		deleted[notifType] = count
+	}
+
+	if retentionDays > 0 {
+		opts := model.PurgeUserNotificationsOptions{
+			Before:       retentionCutoff(retentionDays),
+			ExcludeTypes: overridden,
+		}
+		count, err := a.store.DeleteUserNotificationsBefore(opts, notificationRetentionBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+		deleted[""] = count
+	}
+	return deleted, nil
+}
+
+// retentionCutoff returns the time before which data kept for the given number of days is
+// deleted.
+func retentionCutoff(days int) int64 {
+	return utils.GetMillisForTime(time.Now().AddDate(0, 0, -days))
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeUserNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("types use their own window", func(t *testing.T) {
+		typeRetentionDays := map[string]int{
+			model.NotificationTypeMentioned: 7,
+			model.NotificationTypeTest:      0, // kept forever
+		}
+
+		th.Store.EXPECT().DeleteUserNotificationsBefore(gomock.Any(), notificationRetentionBatchSize).DoAndReturn(
+			func(opts model.PurgeUserNotificationsOptions, _ int) (int64, error) {
+				require.Equal(t, []string{model.NotificationTypeMentioned}, opts.Types)
+				require.InDelta(t, retentionCutoff(7), opts.Before, 1000)
+				return 3, nil
+			},
+		)
+		th.Store.EXPECT().DeleteUserNotificationsBefore(gomock.Any(), notificationRetentionBatchSize).DoAndReturn(
+			func(opts model.PurgeUserNotificationsOptions, _ int) (int64, error) {
+				require.Empty(t, opts.Types)
+				require.Equal(t, []string{model.NotificationTypeMentioned, model.NotificationTypeTest}, opts.ExcludeTypes)
+				require.InDelta(t, retentionCutoff(90), opts.Before, 1000)
+				return 5, nil
+			},
+		)
+
+		deleted, err := th.App.PurgeUserNotifications(90, typeRetentionDays)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{model.NotificationTypeMentioned: 3, "": 5}, deleted)
+	})
+
+	t.Run("no global window keeps the other types", func(t *testing.T) {
+		th.Store.EXPECT().DeleteUserNotificationsBefore(gomock.Any(), notificationRetentionBatchSize).Return(int64(1), nil)
+
+		deleted, err := th.App.PurgeUserNotifications(0, map[string]int{model.NotificationTypeMentioned: 7})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{model.NotificationTypeMentioned: 1}, deleted)
+	})
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// statusUnset is how a card without a status renders in notification messages.
+const statusUnset = "none"
+
+// pendingStatusChange is a status change waiting for the card to stay put before it is
+// notified. It keeps the status the card had before the first move and the latest card,
+// so dragging a card through several columns notifies a single move.
+type pendingStatusChange struct {
+	oldOptionID string
+	card        *model.Block
+	schema      model.PropSchema
+	actorID     string
+	timer       *time.Timer
+}
+
+// notifyCardPropertiesChanged notifies the users following a card about the changes to its
+// properties that matter to them: due dates and status. The board is only read if the
+// properties changed.
+func (a *App) notifyCardPropertiesChanged(card, oldCard *model.Block, modifiedByID string) {
+	if card.Type != model.TypeCard || oldCard == nil {
+		return
+	}
+	// most updates leave the properties alone, skip reading the board for them
+	if reflect.DeepEqual(card.Fields["properties"], oldCard.Fields["properties"]) {
+		return
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		a.logger.Error("Cannot notify card property changes, board not found", mlog.String("cardID", card.ID), mlog.Err(err))
+		return
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Error("Cannot notify card property changes, invalid card properties", mlog.String("boardID", board.ID), mlog.Err(err))
+		return
+	}
+
+	a.notifyDueDateChanged(card, oldCard, schema, modifiedByID)
+	a.notifyStatusChanged(card, oldCard, schema, modifiedByID)
+}
+
+// notifyStatusChanged notifies the assignees and watchers of a card when it moves to
+// another status. Moves are debounced by the NotificationStatusDebounceSeconds setting:
+// the notification is sent once the card stayed in a status that long, from the status it
+// had before the first move, and nothing is sent if it ends up where it started. If the
+// NotificationStatuses setting is not empty, only moves to these statuses are notified.
+func (a *App) notifyStatusChanged(card, oldCard *model.Block, schema model.PropSchema, modifiedByID string) {
+	propID := schema.StatusPropertyID()
+	if propID == "" {
+		return
+	}
+	oldOptionID := cardPropertyString(oldCard, propID)
+	if oldOptionID == cardPropertyString(card, propID) {
+		return
+	}
+
+	change := &pendingStatusChange{
+		oldOptionID: oldOptionID,
+		card:        card,
+		schema:      schema,
+		actorID:     modifiedByID,
+	}
+	a.queueStatusChange(change, time.Duration(a.config.NotificationStatusDebounceSeconds)*time.Second)
+}
+
+// queueStatusChange sends the notifications of a status change after delay, merging it
+// with the change of the same card already waiting, if any. A zero delay sends them
+// right away.
+func (a *App) queueStatusChange(change *pendingStatusChange, delay time.Duration) {
+	if delay <= 0 {
+		a.sendStatusChangeNotifications(change)
+		return
+	}
+
+	a.statusChangeMux.Lock()
+	defer a.statusChangeMux.Unlock()
+
+	if a.pendingStatusChanges == nil {
+		a.pendingStatusChanges = map[string]*pendingStatusChange{}
+	}
+
+	cardID := change.card.ID
+	if pending, ok := a.pendingStatusChanges[cardID]; ok {
+		pending.card = change.card
+		pending.schema = change.schema
+		pending.actorID = change.actorID
+		pending.timer.Reset(delay)
+		return
+	}
+
+	a.pendingStatusChanges[cardID] = change
+	change.timer = time.AfterFunc(delay, func() {
+		a.statusChangeMux.Lock()
+		pending := a.pendingStatusChanges[cardID]
+		delete(a.pendingStatusChanges, cardID)
+		a.statusChangeMux.Unlock()
+
+		if pending != nil {
+			a.sendStatusChangeNotifications(pending)
+		}
+	})
+}
+
+func (a *App) sendStatusChangeNotifications(change *pendingStatusChange) {
+	card, schema := change.card, change.schema
+	propID := schema.StatusPropertyID()
+	def := schema[propID]
+
+	newOptionID := cardPropertyString(card, propID)
+	if newOptionID == change.oldOptionID {
+		return
+	}
+	newStatus := statusLabel(def, newOptionID)
+	if !a.isNotifiedStatus(newStatus) {
+		return
+	}
+
+	targets := a.cardNotificationTargets(card, schema, change.actorID)
+	if len(targets) == 0 {
+		return
+	}
+
+	actorName := a.notificationActorName(change.actorID)
+	params := map[string]string{
+		"property":  def.Name,
+		"oldStatus": statusLabel(def, change.oldOptionID),
+		"newStatus": newStatus,
+	}
+	notifications := make([]*model.UserNotification, 0, len(targets))
+	for _, target := range targets {
+		notifications = append(notifications, &model.UserNotification{
+			TargetUserID: target.userID,
+			ActorUserID:  change.actorID,
+			ActorName:    actorName,
+			Type:         model.NotificationTypeStatusChanged,
+			CardID:       card.ID,
+			CardTitle:    card.Title,
+			BoardID:      card.BoardID,
+			Params:       params,
+			Reason:       target.reason,
+		})
+	}
+	a.sendCardNotifications(card.ID, notifications)
+}
+
+// isNotifiedStatus returns true if moving a card to the status is notified.
+func (a *App) isNotifiedStatus(status string) bool {
+	if len(a.config.NotificationStatuses) == 0 {
+		return true
+	}
+	for _, notified := range a.config.NotificationStatuses {
+		if strings.EqualFold(notified, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusLabel returns the label of a status option for notification messages.
+func statusLabel(def model.PropDef, optionID string) string {
+	if optionID == "" {
+		return statusUnset
+	}
+	if option, ok := def.Options[optionID]; ok {
+		return option.Value
+	}
+	return optionID
+}
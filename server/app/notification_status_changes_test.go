@@ -0,0 +1,124 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotifyStatusChanged(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	board := &model.Board{
+		ID: "board-1",
+		CardProperties: []map[string]interface{}{
+			{"id": "status", "name": "Status", "type": "select", "options": []interface{}{
+				map[string]interface{}{"id": "todo", "value": "To Do"},
+				map[string]interface{}{"id": "doing", "value": "In Progress"},
+				map[string]interface{}{"id": "done", "value": "Done"},
+			}},
+			{"id": "assignees", "name": "Assignees", "type": "multiPerson"},
+		},
+	}
+	schema, err := model.ParsePropertySchema(board)
+	require.NoError(t, err)
+
+	makeCard := func(status string) *model.Block {
+		return &model.Block{ID: "card-1", BoardID: "board-1", Type: model.TypeCard, Title: "Launch",
+			Fields: map[string]interface{}{"properties": map[string]interface{}{"assignees": []interface{}{"actor", "user-1"}, "status": status}}}
+	}
+	expectNotified := func(userIDs []string, check func(notification *model.UserNotification)) {
+		for _, userID := range userIDs {
+			th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+			th.Store.EXPECT().IsNotificationActorBlocked(userID, "actor").Return(false, nil)
+			th.Store.EXPECT().GetNotificationBoardPreferences(userID, "board-1").Return(nil, nil)
+			th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+			th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+		}
+		th.Store.EXPECT().CreateUserNotifications(gomock.Any()).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				assert.Len(t, notifications, len(userIDs))
+				for i, notification := range notifications {
+					assert.Equal(t, userIDs[i], notification.TargetUserID)
+					check(notification)
+				}
+				return notifications, nil
+			},
+		)
+	}
+
+	t.Run("assignees and watchers are notified", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetCardWatchers("card-1").Return([]*model.CardWatcher{
+			{CardID: "card-1", UserID: "user-1"},
+			{CardID: "card-1", UserID: "watcher"},
+			{CardID: "card-1", UserID: "actor"},
+		}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "watcher").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "watcher", SchemeViewer: true}, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		reasons := map[string]string{"user-1": model.NotificationReasonAssignee, "watcher": model.NotificationReasonWatcher}
+		expectNotified([]string{"user-1", "watcher"}, func(notification *model.UserNotification) {
+			assert.Equal(t, model.NotificationTypeStatusChanged, notification.Type)
+			assert.Equal(t, reasons[notification.TargetUserID], notification.Reason)
+			assert.Equal(t, model.NotificationSourceServer, notification.Source)
+			assert.Equal(t, "alice", notification.ActorName)
+			assert.Equal(t, map[string]string{"property": "Status", "oldStatus": "To Do", "newStatus": "Done"}, notification.Params)
+		})
+
+		th.App.notifyCardPropertiesChanged(makeCard("done"), makeCard("todo"), "actor")
+	})
+
+	t.Run("only the configured statuses are notified", func(t *testing.T) {
+		th.App.config.NotificationStatuses = []string{"done"}
+		defer func() { th.App.config.NotificationStatuses = nil }()
+
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+
+		th.App.notifyCardPropertiesChanged(makeCard("doing"), makeCard("todo"), "actor")
+	})
+
+	t.Run("rapid moves are notified once", func(t *testing.T) {
+		th.Store.EXPECT().GetCardWatchers("card-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		notified := make(chan struct{})
+		expectNotified([]string{"user-1"}, func(notification *model.UserNotification) {
+			assert.Equal(t, map[string]string{"property": "Status", "oldStatus": "To Do", "newStatus": "Done"}, notification.Params)
+			close(notified)
+		})
+
+		delay := 50 * time.Millisecond
+		th.App.queueStatusChange(&pendingStatusChange{oldOptionID: "todo", card: makeCard("doing"), schema: schema, actorID: "actor"}, delay)
+		th.App.queueStatusChange(&pendingStatusChange{oldOptionID: "doing", card: makeCard("done"), schema: schema, actorID: "actor"}, delay)
+
+		select {
+		case <-notified:
+		case <-time.After(time.Second):
+			require.Fail(t, "status change not notified")
+		}
+	})
+
+	t.Run("moving back to the original status notifies nothing", func(t *testing.T) {
+		delay := 10 * time.Millisecond
+		th.App.queueStatusChange(&pendingStatusChange{oldOptionID: "todo", card: makeCard("doing"), schema: schema, actorID: "actor"}, delay)
+		th.App.queueStatusChange(&pendingStatusChange{oldOptionID: "doing", card: makeCard("todo"), schema: schema, actorID: "actor"}, delay)
+
+		require.Eventually(t, func() bool {
+			th.App.statusChangeMux.Lock()
+			defer th.App.statusChangeMux.Unlock()
+			return len(th.App.pendingStatusChanges) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"strconv"
+
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// suppressBoardNotifications holds back the notifications of the boards until they are
+// released, e.g. while the boards are imported and every card of them is created at once.
+// Only the notifications of these boards are held back, the other boards are unaffected.
+func (a *App) suppressBoardNotifications(boardIDs []string) {
+	a.suppressedNotificationsMux.Lock()
+	defer a.suppressedNotificationsMux.Unlock()
+
+	if a.suppressedNotifications == nil {
+		a.suppressedNotifications = map[string]map[string]int{}
+	}
+	for _, boardID := range boardIDs {
+		if _, ok := a.suppressedNotifications[boardID]; !ok {
+			a.suppressedNotifications[boardID] = map[string]int{}
+		}
+	}
+}
+
+// suppressCreatedBoardNotifications keeps holding back the notifications of boards that
+// were held back before they were created, if the store created them with other IDs. The
+// created boards are in the same order as the requested ones and share their held back
+// notifications, which are released with the requested boards.
+func (a *App) suppressCreatedBoardNotifications(requested []*model.Board, created []*model.Board) {
+	a.suppressedNotificationsMux.Lock()
+	defer a.suppressedNotificationsMux.Unlock()
+
+	for i, board := range created {
+		if i >= len(requested) || requested[i].ID == board.ID {
+			continue
+		}
+		counts, ok := a.suppressedNotifications[requested[i].ID]
+		if !ok {
+			continue
+		}
+		if a.suppressedBoardsCreatedAs == nil {
+			a.suppressedBoardsCreatedAs = map[string]string{}
+		}
+		a.suppressedNotifications[board.ID] = counts
+		a.suppressedBoardsCreatedAs[requested[i].ID] = board.ID
+	}
+}
+
+// releaseBoardNotifications stops holding back the notifications of the boards, and of the
+// boards they were created as. Returns how many notifications each user had held back, by
+// board, under both IDs.
+func (a *App) releaseBoardNotifications(boardIDs []string) map[string]map[string]int {
+	a.suppressedNotificationsMux.Lock()
+	defer a.suppressedNotificationsMux.Unlock()
+
+	held := map[string]map[string]int{}
+	for _, boardID := range boardIDs {
+		counts := a.suppressedNotifications[boardID]
+		if len(counts) > 0 {
+			held[boardID] = counts
+		}
+		delete(a.suppressedNotifications, boardID)
+
+		if createdID, ok := a.suppressedBoardsCreatedAs[boardID]; ok {
+			if len(counts) > 0 {
+				held[createdID] = counts
+			}
+			delete(a.suppressedNotifications, createdID)
+			delete(a.suppressedBoardsCreatedAs, boardID)
+		}
+	}
+	return held
+}
+
+// holdBackNotification returns true, and counts the notification for its target, if the
+// notifications of its board are held back.
+func (a *App) holdBackNotification(notification *model.UserNotification) bool {
+	if notification.BoardID == "" {
+		return false
+	}
+
+	a.suppressedNotificationsMux.Lock()
+	defer a.suppressedNotificationsMux.Unlock()
+
+	counts, ok := a.suppressedNotifications[notification.BoardID]
+	if !ok {
+		return false
+	}
+	counts[notification.TargetUserID]++
+	return true
+}
+
+// holdBackBlockChange returns true if the notifications of the board of the block are held
+// back, so that the change is neither passed to the notification backends nor starts the
+// debounced notifications of card property changes, which would otherwise go out once
+// the board is released. A held back card counts one notification for each of its
+// assignees but the actor.
+func (a *App) holdBackBlockChange(block *model.Block, modifiedByID string) bool {
+	a.suppressedNotificationsMux.Lock()
+	_, ok := a.suppressedNotifications[block.BoardID]
+	a.suppressedNotificationsMux.Unlock()
+	if !ok {
+		return false
+	}
+	if block.Type != model.TypeCard {
+		return true
+	}
+
+	board, err := a.store.GetBoard(block.BoardID)
+	if err != nil {
+		a.logger.Warn("Cannot count the held back notifications of a card", mlog.String("cardID", block.ID), mlog.Err(err))
+		return true
+	}
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		a.logger.Warn("Cannot count the held back notifications of a card", mlog.String("boardID", board.ID), mlog.Err(err))
+		return true
+	}
+
+	for _, userID := range model.GetPersonPropertyUserIDs(block, schema) {
+		if userID != modifiedByID {
+			a.holdBackNotification(&model.UserNotification{TargetUserID: userID, BoardID: block.BoardID})
+		}
+	}
+	return true
+}
+
+// notifyBoardsImported sends the users who had notifications held back while the boards
+// were imported one notification per board telling how many there were. Nothing is sent
+// without the NotificationImportSummary setting. Failures are only logged, the boards are
+// imported already.
+func (a *App) notifyBoardsImported(boards []*model.Board, actorID string, held map[string]map[string]int) {
+	if !a.config.NotificationImportSummary || len(held) == 0 {
+		return
+	}
+
+	actorName := a.notificationActorName(actorID)
+	for _, board := range boards {
+		for userID, count := range held[board.ID] {
+			if userID == actorID {
+				continue
+			}
+			notification := &model.UserNotification{
+				TargetUserID: userID,
+				ActorUserID:  actorID,
+				ActorName:    actorName,
+				Type:         model.NotificationTypeBoardImported,
+				CardTitle:    board.Title,
+				BoardID:      board.ID,
+				Params:       map[string]string{"count": strconv.Itoa(count)},
+			}
+			if _, err := a.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{}); err != nil {
+				a.logger.Error("Cannot notify the import of a board",
+					mlog.String("boardID", board.ID),
+					mlog.String("targetUserID", userID),
+					mlog.Err(err),
+				)
+			}
+		}
+	}
+}
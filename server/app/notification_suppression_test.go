@@ -0,0 +1,128 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSuppressBoardNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	expectDelivered := func(userID, boardID string) {
+		th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences(userID, boardID).Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard(boardID).Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+	}
+
+	th.App.suppressBoardNotifications([]string{"board-1"})
+
+	t.Run("notifications of the board are held back", func(t *testing.T) {
+		expectDelivered("user-1", "board-1")
+		created, err := th.App.CreateAndBroadcastNotification(&model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, BoardID: "board-1"}, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+
+		expectDelivered("user-1", "board-1")
+		created, err = th.App.CreateAndBroadcastNotification(&model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, BoardID: "board-1"}, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("other boards are unaffected", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, BoardID: "board-2"}
+		expectDelivered("user-1", "board-2")
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("released with a summary per user", func(t *testing.T) {
+		th.App.config.NotificationImportSummary = true
+		defer func() { th.App.config.NotificationImportSummary = false }()
+
+		held := th.App.releaseBoardNotifications([]string{"board-1"})
+		require.Equal(t, map[string]map[string]int{"board-1": {"user-1": 2}}, held)
+
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		expectDelivered("user-1", "board-1")
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(false, nil)
+		th.Store.EXPECT().CreateUserNotification(gomock.Any()).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				assert.Equal(t, model.NotificationTypeBoardImported, notification.Type)
+				assert.Equal(t, "Roadmap", notification.CardTitle)
+				assert.Equal(t, map[string]string{"count": "2"}, notification.Params)
+				return notification, nil
+			},
+		)
+
+		th.App.notifyBoardsImported([]*model.Board{{ID: "board-1", Title: "Roadmap"}}, "actor", held)
+	})
+
+	t.Run("no longer held back once released", func(t *testing.T) {
+		require.False(t, th.App.holdBackNotification(&model.UserNotification{TargetUserID: "user-1", BoardID: "board-1"}))
+	})
+}
+
+func TestImportArchiveHoldsBackNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationImportSummary = true
+	defer func() { th.App.config.NotificationImportSummary = false }()
+
+	board := &model.Board{
+		ID:         "board-1",
+		TeamID:     "test-team",
+		Title:      "Roadmap",
+		IsTemplate: true,
+		CardProperties: []map[string]interface{}{
+			{"id": "assignee", "name": "Assignee", "type": "person"},
+		},
+	}
+	babs := &model.BoardsAndBlocks{
+		Boards: []*model.Board{board},
+		Blocks: []*model.Block{
+			{ID: "card-1", ParentID: board.ID, BoardID: board.ID, Type: model.TypeCard, Fields: map[string]interface{}{"properties": map[string]interface{}{"assignee": "user-2"}}},
+			{ID: "card-2", ParentID: board.ID, BoardID: board.ID, Type: model.TypeCard, Fields: map[string]interface{}{"properties": map[string]interface{}{"assignee": "user"}}},
+			{ID: "view-1", ParentID: board.ID, BoardID: board.ID, Type: model.TypeView},
+		},
+	}
+	admin := &model.BoardMember{BoardID: board.ID, UserID: "user", SchemeAdmin: true}
+
+	th.Store.EXPECT().CreateBoardsAndBlocks(gomock.AssignableToTypeOf(&model.BoardsAndBlocks{}), "user").Return(babs, nil)
+	th.Store.EXPECT().GetMembersForBoard(board.ID).AnyTimes().Return([]*model.BoardMember{admin}, nil)
+	// read to count the assignees of the two cards, then to add the admin
+	th.Store.EXPECT().GetBoard(board.ID).Times(3).Return(board, nil)
+	th.Store.EXPECT().GetMemberForBoard(board.ID, "user").Return(admin, nil)
+
+	// only the summary goes out, once the board is imported
+	th.Store.EXPECT().GetUserByID("user").Return(&model.User{ID: "user", Username: "alice"}, nil)
+	th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+	th.Store.EXPECT().GetNotificationBoardPreferences("user-2", board.ID).Return(nil, nil)
+	th.Store.EXPECT().GetNotificationTeamDefaultsForBoard(board.ID).Return(nil, nil)
+	th.Store.EXPECT().GetUserPreferences("user-2").Return(mmModel.Preferences{}, nil)
+	th.Store.EXPECT().IsNotificationActorBlocked("user-2", "user").Return(false, nil)
+	th.Store.EXPECT().CreateUserNotification(gomock.Any()).DoAndReturn(
+		func(notification *model.UserNotification) (*model.UserNotification, error) {
+			assert.Equal(t, "user-2", notification.TargetUserID)
+			assert.Equal(t, model.NotificationTypeBoardImported, notification.Type)
+			assert.Equal(t, map[string]string{"count": "1"}, notification.Params)
+			return notification, nil
+		},
+	)
+
+	opts := model.ImportArchiveOptions{TeamID: "test-team", ModifiedBy: "user"}
+	require.NoError(t, th.App.ImportArchive(bytes.NewReader([]byte(asana)), opts))
+	require.False(t, th.App.holdBackNotification(&model.UserNotification{TargetUserID: "user-2", BoardID: board.ID}))
+}
@@ -1,9 +1,36 @@
 package app
 
 import (
+	"github.com/mattermost/focalboard/server/model"
+
 	mm_model "github.com/mattermost/mattermost/server/public/model"
 )
 
 func (a *App) HasPermissionToBoard(userID, boardID string, permission *mm_model.Permission) bool {
 	return a.permissions.HasPermissionToBoard(userID, boardID, permission)
 }
+
+// GetUserPermissions computes the capabilities of a user, including the team and board
+// capabilities when a teamID or boardID is given.
+func (a *App) GetUserPermissions(userID, teamID, boardID string) *model.UserPermissions {
+	permissions := &model.UserPermissions{
+		SystemAdmin: a.permissions.HasPermissionTo(userID, model.PermissionManageSystem),
+	}
+
+	if teamID != "" {
+		permissions.TeamID = teamID
+		permissions.TeamAdmin = a.permissions.HasPermissionToTeam(userID, teamID, model.PermissionManageTeam)
+	}
+
+	if boardID != "" {
+		permissions.BoardID = boardID
+		permissions.BoardPermissions = []string{}
+		for _, permission := range model.BoardPermissions {
+			if a.permissions.HasPermissionToBoard(userID, boardID, permission) {
+				permissions.BoardPermissions = append(permissions.BoardPermissions, permission.Id)
+			}
+		}
+	}
+
+	return permissions
+}
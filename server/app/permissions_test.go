@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserPermissions(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("system admin without team or board", func(t *testing.T) {
+		th.API.EXPECT().HasPermissionTo("user_id_1", model.PermissionManageSystem).Return(true).Times(1)
+
+		permissions := th.App.GetUserPermissions("user_id_1", "", "")
+		assert.True(t, permissions.SystemAdmin)
+		assert.Empty(t, permissions.TeamID)
+		assert.False(t, permissions.TeamAdmin)
+		assert.Empty(t, permissions.BoardID)
+		assert.Nil(t, permissions.BoardPermissions)
+	})
+
+	t.Run("regular user with team", func(t *testing.T) {
+		th.API.EXPECT().HasPermissionTo("user_id_2", model.PermissionManageSystem).Return(false).Times(1)
+		th.API.EXPECT().HasPermissionToTeam("user_id_2", "team_id_1", model.PermissionManageTeam).Return(true).Times(1)
+
+		permissions := th.App.GetUserPermissions("user_id_2", "team_id_1", "")
+		assert.False(t, permissions.SystemAdmin)
+		assert.Equal(t, "team_id_1", permissions.TeamID)
+		assert.True(t, permissions.TeamAdmin)
+	})
+}
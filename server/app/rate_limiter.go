@@ -0,0 +1,63 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimitWindow is the window the per minute rate limits apply to.
+const rateLimitWindow = time.Minute
+
+// rateLimiter counts the requests of each user within a sliding window of a minute.
+type rateLimiter struct {
+	mux       sync.Mutex
+	requests  map[string][]time.Time
+	lastPrune time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{requests: map[string][]time.Time{}}
+}
+
+// allow returns true, and records the request, if the user made fewer than limit requests
+// within the last minute. A limit of 0 or less disables the limit.
+func (l *rateLimiter) allow(userID string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+	// forget the users that made no request within the window, once per window so the
+	// map doesn't grow with every user that ever made a request
+	if now.Sub(l.lastPrune) >= rateLimitWindow {
+		for id, requests := range l.requests {
+			if now.Sub(requests[len(requests)-1]) >= rateLimitWindow {
+				delete(l.requests, id)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	recent := l.requests[userID][:0]
+	for _, request := range l.requests[userID] {
+		if now.Sub(request) < rateLimitWindow {
+			recent = append(recent, request)
+		}
+	}
+
+	if len(recent) >= limit {
+		l.requests[userID] = recent
+		return false
+	}
+	l.requests[userID] = append(recent, now)
+	return true
+}
+
+// AllowAvatarUpload returns true, and records the upload, if the user made fewer than
+// AvatarUploadsPerMinute avatar uploads within the last minute.
+func (a *App) AllowAvatarUpload(userID string) bool {
+	return a.avatarUploadLimiter.allow(userID, a.config.AvatarUploadsPerMinute)
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("limited per user", func(t *testing.T) {
+		limiter := newRateLimiter()
+		require.True(t, limiter.allow("user-1", 2))
+		require.True(t, limiter.allow("user-1", 2))
+		require.False(t, limiter.allow("user-1", 2))
+		require.True(t, limiter.allow("user-2", 2))
+	})
+
+	t.Run("disabled limit", func(t *testing.T) {
+		limiter := newRateLimiter()
+		for i := 0; i < 10; i++ {
+			require.True(t, limiter.allow("user-1", 0))
+		}
+		require.Empty(t, limiter.requests)
+	})
+
+	t.Run("requests leave the window", func(t *testing.T) {
+		limiter := newRateLimiter()
+		limiter.requests["user-1"] = []time.Time{time.Now().Add(-2 * rateLimitWindow)}
+
+		require.True(t, limiter.allow("user-1", 1))
+		require.Len(t, limiter.requests["user-1"], 1)
+	})
+
+	t.Run("idle users are forgotten", func(t *testing.T) {
+		limiter := newRateLimiter()
+		limiter.requests["user-1"] = []time.Time{time.Now().Add(-2 * rateLimitWindow)}
+
+		require.True(t, limiter.allow("user-2", 1))
+		require.NotContains(t, limiter.requests, "user-1")
+	})
+}
+
+func TestAllowAvatarUpload(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.AvatarUploadsPerMinute = 1
+	defer func() { th.App.config.AvatarUploadsPerMinute = 0 }()
+
+	require.True(t, th.App.AllowAvatarUpload("user-1"))
+	require.False(t, th.App.AllowAvatarUpload("user-1"))
+	require.True(t, th.App.AllowAvatarUpload("user-2"))
+}
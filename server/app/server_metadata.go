@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"runtime"
 
 	"github.com/mattermost/focalboard/server/model"
@@ -19,7 +20,14 @@ type ServerMetadata struct {
 	SKU         string `json:"sku"`
 }
 
-func (a *App) GetServerMetadata() *ServerMetadata {
+// GetServerMetadata returns the version and environment of the server. The database
+// fields are optional and left empty when there is no store, but metadata without a
+// server version is useless to clients and returned as an error.
+func (a *App) GetServerMetadata() (*ServerMetadata, error) {
+	if model.CurrentVersion == "" {
+		return nil, errors.New("server version is not set")
+	}
+
 	var dbType string
 	var dbVersion string
 	if a != nil && a.store != nil {
@@ -38,5 +46,5 @@ func (a *App) GetServerMetadata() *ServerMetadata {
 		OSType:      runtime.GOOS,
 		OSArch:      runtime.GOARCH,
 		SKU:         "personal_server",
-	}
+	}, nil
 }
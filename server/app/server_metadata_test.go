@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetServerMetadata(t *testing.T) {
@@ -16,7 +17,8 @@ func TestGetServerMetadata(t *testing.T) {
 	th.Store.EXPECT().DBVersion().Return("TEST_DB_VERSION")
 
 	t.Run("Get Server Metadata", func(t *testing.T) {
-		got := th.App.GetServerMetadata()
+		got, err := th.App.GetServerMetadata()
+		require.NoError(t, err)
 		want := &ServerMetadata{
 			Version:     model.CurrentVersion,
 			BuildNumber: model.BuildNumber,
@@ -34,4 +36,14 @@ func TestGetServerMetadata(t *testing.T) {
 			t.Errorf("got: %q, want: %q", got, want)
 		}
 	})
+
+	t.Run("Fails without a server version", func(t *testing.T) {
+		version := model.CurrentVersion
+		model.CurrentVersion = ""
+		defer func() { model.CurrentVersion = version }()
+
+		got, err := th.App.GetServerMetadata()
+		require.Error(t, err)
+		require.Nil(t, got)
+	})
 }
@@ -12,6 +12,20 @@ func (a *App) GetSharing(boardID string) (*model.Sharing, error) {
 	return sharing, nil
 }
 
+// UpsertSharing saves the sharing of a board and notifies the board admins if the board
+// was shared, i.e. if sharing was turned on or the share link changed.
 func (a *App) UpsertSharing(sharing model.Sharing) error {
-	return a.store.UpsertSharing(sharing)
+	previous, err := a.store.GetSharing(sharing.ID)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+
+	if err := a.store.UpsertSharing(sharing); err != nil {
+		return err
+	}
+
+	if sharing.Enabled && (previous == nil || !previous.Enabled || previous.Token != sharing.Token) {
+		a.notifyBoardShared(sharing.ID, sharing.ModifiedBy)
+	}
+	return nil
 }
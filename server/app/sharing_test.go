@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
 )
 
 func TestGetSharing(t *testing.T) {
@@ -68,6 +71,7 @@ func TestUpsertSharing(t *testing.T) {
 	}
 
 	t.Run("should success to upsert sharing", func(t *testing.T) {
+		th.Store.EXPECT().GetSharing(sharing.ID).Return(&sharing, nil)
 		th.Store.EXPECT().UpsertSharing(sharing).Return(nil)
 		err := th.App.UpsertSharing(sharing)
 
@@ -75,6 +79,7 @@ func TestUpsertSharing(t *testing.T) {
 	})
 
 	t.Run("should fail to upsert a sharing", func(t *testing.T) {
+		th.Store.EXPECT().GetSharing(sharing.ID).Return(&sharing, nil)
 		th.Store.EXPECT().UpsertSharing(sharing).Return(errors.New("sharing not found"))
 		err := th.App.UpsertSharing(sharing)
 
@@ -82,3 +87,56 @@ func TestUpsertSharing(t *testing.T) {
 		require.Equal(t, "sharing not found", err.Error())
 	})
 }
+
+func TestUpsertSharingNotifiesBoardShared(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{ID: "board-1", TeamID: "team-1", Title: "Roadmap"}
+	sharing := model.Sharing{ID: "board-1", Enabled: true, Token: "token", ModifiedBy: "actor"}
+
+	t.Run("sharing a board notifies its admins", func(t *testing.T) {
+		th.Store.EXPECT().GetSharing("board-1").Return(nil, sql.ErrNoRows)
+		th.Store.EXPECT().UpsertSharing(sharing).Return(nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "actor", SchemeAdmin: true},
+			{BoardID: "board-1", UserID: "admin", SchemeAdmin: true},
+			{BoardID: "board-1", UserID: "editor", SchemeEditor: true},
+		}, nil)
+		th.Store.EXPECT().GetUserByID("actor").Return(&model.User{ID: "actor", Username: "alice"}, nil)
+		th.Store.EXPECT().GetUserByID("admin").Return(&model.User{ID: "admin"}, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("admin", "actor").Return(false, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("admin", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("admin").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotifications(gomock.Any()).DoAndReturn(
+			func(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+				require.Len(t, notifications, 1)
+				require.Equal(t, "admin", notifications[0].TargetUserID)
+				require.Equal(t, model.NotificationTypeBoardShared, notifications[0].Type)
+				require.Equal(t, "alice", notifications[0].ActorName)
+				require.Equal(t, "Roadmap", notifications[0].CardTitle)
+				return notifications, nil
+			},
+		)
+
+		require.NoError(t, th.App.UpsertSharing(sharing))
+	})
+
+	t.Run("saving an unchanged share notifies nothing", func(t *testing.T) {
+		th.Store.EXPECT().GetSharing("board-1").Return(&sharing, nil)
+		th.Store.EXPECT().UpsertSharing(sharing).Return(nil)
+
+		require.NoError(t, th.App.UpsertSharing(sharing))
+	})
+
+	t.Run("turning sharing off notifies nothing", func(t *testing.T) {
+		disabled := sharing
+		disabled.Enabled = false
+		th.Store.EXPECT().GetSharing("board-1").Return(&sharing, nil)
+		th.Store.EXPECT().UpsertSharing(disabled).Return(nil)
+
+		require.NoError(t, th.App.UpsertSharing(disabled))
+	})
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"strings"
+
 	"github.com/mattermost/focalboard/server/model"
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 )
@@ -98,17 +100,71 @@ func (a *App) GetAllUsers() ([]*model.User, error) {
 	return a.store.GetAllUsers()
 }
 
-// UpdateUser updates a user
+// GetUsersPage returns a page of registered users, newest first, and whether there is a
+// next page (for admin panel)
+func (a *App) GetUsersPage(opts model.QueryUsersOptions) ([]*model.User, bool, error) {
+	return a.store.GetUsersPage(opts)
+}
+
+// UpdateUser updates a user, normalizing the email and making sure no other user has it.
+// A new email must belong to one of the allowed domains, an unchanged one is kept even if
+// its domain is no longer allowed.
 func (a *App) UpdateUser(user *model.User) (*model.User, error) {
+	user.Email = model.NormalizeEmail(user.Email)
+	if user.Email != "" {
+		existing, err := a.store.GetUserByEmail(user.Email)
+		if err != nil && !model.IsErrNotFound(err) {
+			return nil, err
+		}
+		if existing != nil && existing.ID != user.ID {
+			return nil, model.NewErrConflict("the email already exists")
+		}
+		if existing == nil {
+			if err := a.CheckEmailDomainAllowed(user.Email); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return a.store.UpdateUser(user)
 }
 
+// CheckEmailDomainAllowed returns a bad request error if the AllowedEmailDomains setting
+// is not empty and the domain of the email is not in it. Domains are compared ignoring
+// their casing and subdomains must be listed on their own.
+func (a *App) CheckEmailDomainAllowed(email string) error {
+	if len(a.config.AllowedEmailDomains) == 0 {
+		return nil
+	}
+
+	domain := ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		domain = strings.ToLower(strings.TrimSpace(email[at+1:]))
+	}
+
+	allowed := make([]string, 0, len(a.config.AllowedEmailDomains))
+	for _, allowedDomain := range a.config.AllowedEmailDomains {
+		allowedDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(allowedDomain), "@"))
+		if domain != "" && domain == allowedDomain {
+			return nil
+		}
+		allowed = append(allowed, allowedDomain)
+	}
+	return model.NewErrBadRequest("the email domain " + domain + " is not allowed, the allowed domains are: " + strings.Join(allowed, ", "))
+}
+
 // UpdateUserPasswordByID updates a user's password by user ID
 func (a *App) UpdateUserPasswordByID(userID string, password string) error {
 	return a.store.UpdateUserPasswordByID(userID, password)
 }
 
-// DeleteUser soft deletes a user
+// DeleteUser soft deletes a user, removing their board memberships and received
+// notifications and anonymizing the notifications they authored
 func (a *App) DeleteUser(userID string) error {
 	return a.store.DeleteUser(userID)
 }
+
+// GetUserDeletionSummary returns what DeleteUser would remove or anonymize for a user,
+// without deleting anything.
+func (a *App) GetUserDeletionSummary(userID string) (*model.UserDeletionSummary, error) {
+	return a.store.GetUserDeletionSummary(userID)
+}
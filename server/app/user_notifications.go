@@ -4,7 +4,31 @@
 package app
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+const (
+	testNotificationInterval = 10 * time.Second
+
+	// notificationReadSyncMax caps the read state changes a client can sync at once
+	notificationReadSyncMax = 200
+
+	// notificationsMarkReadMax caps the notifications a client can mark as read at once
+	notificationsMarkReadMax = 200
+
+	// notificationCoRecipientsMax caps the co-recipients stored on a mention notification,
+	// so mentioning a large board doesn't store every member on every notification
+	notificationCoRecipientsMax = 50
 )
 
 // CreateUserNotification creates a new user notification
@@ -12,40 +36,1855 @@ func (a *App) CreateUserNotification(notification *model.UserNotification) (*mod
 	return a.store.CreateUserNotification(notification)
 }
 
-// GetUserNotifications retrieves notifications for a user
-func (a *App) GetUserNotifications(userID string, limit int) ([]*model.UserNotification, error) {
-	return a.store.GetUserNotifications(userID, limit)
+// GetUserNotifications retrieves notifications for a user, flagging the ones created
+// since the user last opened the notification center as new. The notifications of boards
+// the user can no longer view are left out, see inaccessibleNotificationBoards.
+func (a *App) GetUserNotifications(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	if err := a.excludeInaccessibleNotificationBoards(userID, &opts); err != nil {
+		return nil, err
+	}
+
+	notifications, err := a.store.GetUserNotifications(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.prepareFetchedNotifications(userID, notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// GetUserNotificationsMarkingRead returns the notifications of a user like
+// GetUserNotifications and marks the unread ones it returns as read in the same
+// transaction, broadcasting the new unread count. Notifications outside of the returned
+// page are left unread. The notifications keep the read state they had when fetched.
+func (a *App) GetUserNotificationsMarkingRead(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	if err := a.excludeInaccessibleNotificationBoards(userID, &opts); err != nil {
+		return nil, err
+	}
+
+	notifications, err := a.store.GetUserNotificationsMarkingRead(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, notification := range notifications {
+		if !notification.Read {
+			a.broadcastUnreadNotificationCount(userID)
+			break
+		}
+	}
+	if err := a.prepareFetchedNotifications(userID, notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// GetUserNotificationsPage returns a page of the notifications of a user, like
+// GetUserNotifications or GetUserNotificationsMarkingRead if markRead is set, along with
+// whether more follow and the cursor and the last notification ID of the next page. A full page looks one notification
+// ahead to tell, pages without a limit hold every notification left.
+func (a *App) GetUserNotificationsPage(userID string, opts model.QueryUserNotificationsOptions, markRead bool) (*model.NotificationsPage, error) {
+	var notifications []*model.UserNotification
+	var err error
+	if markRead {
+		notifications, err = a.GetUserNotificationsMarkingRead(userID, opts)
+	} else {
+		notifications, err = a.GetUserNotifications(userID, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.NotificationsPage{Results: notifications}
+	if opts.Limit <= 0 || len(notifications) < opts.Limit {
+		return page, nil
+	}
+
+	next := opts
+	next.Cursor = model.NewNotificationsCursor(notifications[len(notifications)-1], opts.OrderBy)
+	next.Limit = 1
+	more, err := a.GetUserNotifications(userID, next)
+	if err != nil {
+		return nil, err
+	}
+	if len(more) > 0 {
+		page.HasMore = true
+		page.NextCursor = next.Cursor
+		page.NextBefore = notifications[len(notifications)-1].ID
+	}
+	return page, nil
+}
+
+// prepareFetchedNotifications flags the notifications created since the user last looked
+// at them as new and renders their messages.
+func (a *App) prepareFetchedNotifications(userID string, notifications []*model.UserNotification) error {
+	lastSeen, err := a.GetNotificationLastSeen(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		notification.New = notification.CreateAt > lastSeen
+		a.renderNotificationMessage(notification)
+	}
+	return nil
+}
+
+// AttachNotificationCards embeds the current state of their card into the notifications
+// of a user, so clients can show cards renamed since with their current title. The cards
+// are fetched in a single query and the boards, to resolve the card status, once per
+// board. Only cards of the notification's board that the user can view are embedded, so a
+// notification can't reveal a block of another board; the others are marked as deleted,
+// like cards that no longer exist.
+func (a *App) AttachNotificationCards(userID string, notifications []*model.UserNotification) error {
+	cardIDs := make([]string, 0, len(notifications))
+	seen := map[string]bool{}
+	for _, notification := range notifications {
+		if notification.CardID != "" && !seen[notification.CardID] {
+			seen[notification.CardID] = true
+			cardIDs = append(cardIDs, notification.CardID)
+		}
+	}
+	if len(cardIDs) == 0 {
+		return nil
+	}
+
+	// a not all found error still returns the cards that exist
+	blocks, err := a.store.GetBlocksByIDs(cardIDs)
+	if err != nil && !model.IsErrNotFound(err) {
+		return err
+	}
+
+	snapshots := make(map[string]*model.NotificationCardSnapshot, len(blocks))
+	boardOfCard := make(map[string]string, len(blocks))
+	schemas := map[string]model.PropSchema{}
+	canView := map[string]bool{}
+	for _, block := range blocks {
+		if block.Type != model.TypeCard {
+			continue
+		}
+		viewable, ok := canView[block.BoardID]
+		if !ok {
+			viewable = a.permissions.HasPermissionToBoard(userID, block.BoardID, model.PermissionViewBoard)
+			canView[block.BoardID] = viewable
+		}
+		if !viewable {
+			continue
+		}
+		card, err := model.Block2Card(block)
+		if err != nil {
+			a.logger.Warn("Cannot read the card of a notification", mlog.String("cardID", block.ID), mlog.Err(err))
+			continue
+		}
+		snapshots[card.ID] = &model.NotificationCardSnapshot{
+			ID:       card.ID,
+			Title:    card.Title,
+			Icon:     card.Icon,
+			Status:   a.notificationCardStatus(card, schemas),
+			UpdateAt: block.UpdateAt,
+		}
+		boardOfCard[card.ID] = block.BoardID
+	}
+
+	for _, notification := range notifications {
+		if notification.CardID == "" {
+			continue
+		}
+		if snapshot, ok := snapshots[notification.CardID]; ok && boardOfCard[notification.CardID] == notification.BoardID {
+			notification.Card = snapshot
+		} else {
+			notification.Card = &model.NotificationCardSnapshot{ID: notification.CardID, Deleted: true}
+		}
+	}
+	return nil
+}
+
+// notificationCardStatus returns the label of the option set on the Status property of a
+// card, or an empty string if its board has no such property. The property schemas are
+// cached by board in schemas.
+func (a *App) notificationCardStatus(card *model.Card, schemas map[string]model.PropSchema) string {
+	schema, ok := schemas[card.BoardID]
+	if !ok {
+		board, err := a.store.GetBoard(card.BoardID)
+		if err == nil {
+			schema, err = model.ParsePropertySchema(board)
+		}
+		if err != nil {
+			a.logger.Debug("Cannot read the card properties of a board", mlog.String("boardID", card.BoardID), mlog.Err(err))
+		}
+		// failures are cached too, so a broken board is only read once
+		schemas[card.BoardID] = schema
+	}
+
+	propID := schema.StatusPropertyID()
+	if propID == "" {
+		return ""
+	}
+	optionID, _ := card.Properties[propID].(string)
+	return schema[propID].Options[optionID].Value
+}
+
+// GetUserNotificationThreads retrieves the notifications of a user grouped by card, the
+// most recently active cards first. Limit caps the number of threads, not notifications.
+// Each notification that is not about a card is a thread of its own.
+func (a *App) GetUserNotificationThreads(userID string, opts model.QueryUserNotificationsOptions, limit int) ([]*model.NotificationThread, error) {
+	if err := a.excludeInaccessibleNotificationBoards(userID, &opts); err != nil {
+		return nil, err
+	}
+
+	notifications, err := a.store.GetUserNotificationThreads(userID, opts, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.prepareFetchedNotifications(userID, notifications); err != nil {
+		return nil, err
+	}
+
+	return model.GroupNotificationsByCard(notifications), nil
+}
+
+// GetNotificationLastSeen returns the time the user last opened the notification center,
+// or 0 if they never did
+func (a *App) GetNotificationLastSeen(userID string) (int64, error) {
+	preferences, err := a.store.GetUserPreferences(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Category != model.PreferencesCategoryFocalboard || preference.Name != model.PreferenceNameNotificationLastSeen {
+			continue
+		}
+		lastSeen, err := strconv.ParseInt(preference.Value, 10, 64)
+		if err != nil {
+			a.logger.Warn("invalid notification last seen preference",
+				mlog.String("userID", userID),
+				mlog.String("value", preference.Value),
+			)
+			return 0, nil
+		}
+		return lastSeen, nil
+	}
+	return 0, nil
+}
+
+// SetNotificationLastSeen saves the time the user last opened the notification center
+func (a *App) SetNotificationLastSeen(userID string, lastSeen int64) error {
+	patch := model.UserPreferencesPatch{
+		UpdatedFields: map[string]string{
+			model.PreferenceNameNotificationLastSeen: strconv.FormatInt(lastSeen, 10),
+		},
+	}
+	_, err := a.store.PatchUserPreferences(userID, patch)
+	return err
 }
 
-// GetUnreadNotificationCount gets the count of unread notifications
+// GetUnreadNotificationCount gets the count of unread notifications, leaving out the ones
+// of boards the user can no longer view
 func (a *App) GetUnreadNotificationCount(userID string) (int, error) {
-	return a.store.GetUnreadNotificationCount(userID)
+	count, err := a.store.GetUnreadNotificationCount(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, inaccessible, err := a.inaccessibleNotificationBoards(userID)
+	if err != nil {
+		return 0, err
+	}
+	return count - inaccessible, nil
+}
+
+// GetOldestUnreadNotification returns the create time of the oldest unread notification of
+// the user, with a nil timestamp if none is unread.
+func (a *App) GetOldestUnreadNotification(userID string) (*model.OldestUnreadNotification, error) {
+	oldest, err := a.store.GetOldestUnreadNotificationTime(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.OldestUnreadNotification{}
+	if oldest > 0 {
+		result.Timestamp = &oldest
+	}
+	return result, nil
+}
+
+// GetUnreadCountByBoardForMember returns the unread notification counts of the boards the
+// user is a member of.
+func (a *App) GetUnreadCountByBoardForMember(userID string) ([]*model.NotificationBoardUnreadCount, error) {
+	return a.store.GetUnreadCountByBoardForMember(userID)
+}
+
+// GetNotificationSummary counts the notifications of a user by read state
+func (a *App) GetNotificationSummary(userID string) (*model.NotificationSummary, error) {
+	return a.store.GetNotificationSummary(userID)
+}
+
+// CountNotificationsByActor returns the actors that generated the most notifications
+// since the given time, e.g. to spot a spamming integration.
+func (a *App) CountNotificationsByActor(since int64, limit int) ([]*model.NotificationActorCount, error) {
+	return a.store.CountNotificationsByActor(since, limit)
+}
+
+// SearchUserNotifications returns a page of the notifications of every user matching the
+// given filters, newest first, e.g. to trace the notifications created from a source.
+func (a *App) SearchUserNotifications(opts model.SearchUserNotificationsOptions) ([]*model.UserNotification, bool, error) {
+	return a.store.SearchUserNotifications(opts)
 }
 
 // MarkNotificationAsRead marks a notification as read
 func (a *App) MarkNotificationAsRead(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
 	return a.store.MarkNotificationAsRead(notificationID, userID)
 }
 
-// MarkAllNotificationsAsRead marks all notifications for a user as read
-func (a *App) MarkAllNotificationsAsRead(userID string) error {
-	return a.store.MarkAllNotificationsAsRead(userID)
+// MarkNotificationAsUnread marks a notification of the user as unread again, e.g. after
+// it was read by accident.
+func (a *App) MarkNotificationAsUnread(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.MarkNotificationAsUnread(notificationID, userID)
 }
 
-// DeleteUserNotification deletes a notification
-func (a *App) DeleteUserNotification(notificationID, userID string) error {
-	return a.store.DeleteUserNotification(notificationID, userID)
+// MarkNotificationAsReadOnDevice marks a notification of the user as read and records the
+// device it was read on. Without a device ID only the read state of the notification is
+// set, as MarkNotificationAsRead does.
+func (a *App) MarkNotificationAsReadOnDevice(notificationID, userID, deviceID string) error {
+	if deviceID == "" {
+		return a.MarkNotificationAsRead(notificationID, userID)
+	}
+	if !model.IsNotificationDeviceIDValid(deviceID) {
+		return model.NewErrBadRequest("invalid device id, the maximum length is " + strconv.Itoa(model.NotificationDeviceIDMaxLength))
+	}
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.MarkNotificationAsReadOnDevice(notificationID, userID, deviceID)
 }
 
-// CreateAndBroadcastNotification creates a notification and broadcasts it via WebSocket
-func (a *App) CreateAndBroadcastNotification(notification *model.UserNotification) (*model.UserNotification, error) {
-	created, err := a.store.CreateUserNotification(notification)
+// GetUserNotification returns a notification of the user along with the devices it was
+// read on. Returns a not found error if the user does not own the notification.
+func (a *App) GetUserNotification(notificationID, userID string) (*model.UserNotification, error) {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return nil, err
+	}
+
+	notification, err := a.store.GetUserNotification(notificationID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err = a.prepareFetchedNotifications(userID, []*model.UserNotification{notification}); err != nil {
+		return nil, err
+	}
 
-	// Broadcast to the target user via WebSocket
-	a.wsAdapter.BroadcastUserNotification(notification.TargetUserID, created)
+	notification.DeviceReads, err = a.store.GetNotificationDeviceReads(notificationID)
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
 
-	return created, nil
+// SyncNotificationReadStates applies the read state changes a client made while offline,
+// the latest change winning over the stored state, and returns the current state of the
+// notifications. Notifications the user doesn't own and ephemeral ones are skipped. Change
+// times in the future are clamped to now so a skewed client clock can't win forever.
+func (a *App) SyncNotificationReadStates(userID string, changes []*model.NotificationReadSync) ([]*model.UserNotification, error) {
+	if len(changes) > notificationReadSyncMax {
+		return nil, model.NewErrBadRequest("too many read state changes, the maximum is " + strconv.Itoa(notificationReadSyncMax))
+	}
+
+	now := utils.GetMillis()
+	stored := make([]*model.NotificationReadSync, 0, len(changes))
+	for _, change := range changes {
+		if change == nil || change.ID == "" {
+			return nil, model.NewErrBadRequest("missing notification id")
+		}
+		if change.ReadAt <= 0 {
+			return nil, model.NewErrBadRequest("missing readAt for notification ID=" + change.ID)
+		}
+		if model.IsEphemeralNotificationID(change.ID) {
+			continue
+		}
+		if change.ReadAt > now {
+			change.ReadAt = now
+		}
+		stored = append(stored, change)
+	}
+
+	return a.store.SyncNotificationReadStates(userID, stored)
+}
+
+// OpenNotification marks a notification of the user as read and resolves where the client
+// should navigate to. Returns a not found error if the user does not own the notification.
+func (a *App) OpenNotification(notificationID, userID string) (*model.OpenedNotification, error) {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return nil, err
+	}
+
+	notification, err := a.store.GetUserNotification(notificationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !notification.Read {
+		if err = a.store.MarkNotificationAsRead(notificationID, userID); err != nil {
+			return nil, err
+		}
+		notification.Read = true
+	}
+	a.renderNotificationMessage(notification)
+
+	target, err := a.resolveNotificationTarget(userID, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OpenedNotification{
+		Notification: notification,
+		Target:       target,
+	}, nil
+}
+
+// resolveNotificationTarget returns the board or card a notification points to. The link is
+// left empty if the board no longer exists or the user cannot view it, in which case the
+// board is not read at all.
+func (a *App) resolveNotificationTarget(userID string, notification *model.UserNotification) (*model.NotificationTarget, error) {
+	target := &model.NotificationTarget{
+		BoardID: notification.BoardID,
+		CardID:  notification.CardID,
+	}
+	if notification.BoardID == "" {
+		return target, nil
+	}
+	if !a.permissions.HasPermissionToBoard(userID, notification.BoardID, model.PermissionViewBoard) {
+		return target, nil
+	}
+
+	board, err := a.store.GetBoard(notification.BoardID)
+	if model.IsErrNotFound(err) {
+		return target, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	target.TeamID = board.TeamID
+	if notification.CardID != "" {
+		target.Link = utils.MakeCardLink(a.config.ServerRoot, board.TeamID, board.ID, notification.CardID)
+	} else {
+		target.Link = utils.MakeBoardLink(a.config.ServerRoot, board.TeamID, board.ID)
+	}
+	return target, nil
+}
+
+// PreviewNotification validates a notification and resolves it the way it would be
+// delivered and rendered for the user previewing it, without storing or broadcasting it.
+func (a *App) PreviewNotification(userID string, notification *model.UserNotification) (*model.NotificationPreview, error) {
+	if err := notification.IsValid(a.notificationTypes); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	active, err := a.checkNotificationTarget(notification.TargetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, model.NewErrBadRequest("target user ID=" + notification.TargetUserID + " is deactivated")
+	}
+
+	now := utils.GetMillis()
+	notification.CreateAt = now
+	notification.UpdateAt = now
+	notification.Category = model.NotificationCategoryForType(notification.Type)
+	notification.Silent, notification.Urgency = model.NotificationAlertForCategory(notification.Category)
+	notification.SetActions()
+	a.renderNotificationMessage(notification)
+
+	target, err := a.resolveNotificationTarget(userID, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	actorName := notification.ActorName
+	if actorName == "" {
+		actorName = "Someone"
+	}
+
+	preview := &model.NotificationPreview{
+		Notification: notification,
+		Target:       target,
+		MessageKey:   model.NotificationMessageKey(notification.Type),
+		MessageParams: map[string]string{
+			"actorName": actorName,
+			"cardTitle": notification.CardTitle,
+		},
+	}
+	if notification.ActorUserID != "" {
+		preview.ActorAvatarURL = a.GetAvatarURL(notification.ActorUserID).URL
+	}
+	return preview, nil
+}
+
+// MarkNotificationsAsRead marks a batch of notifications of a user as read and returns how
+// many were unread. IDs of notifications the user doesn't own or that were never stored
+// are skipped.
+func (a *App) MarkNotificationsAsRead(ids []string, userID string) (int64, error) {
+	if len(ids) > notificationsMarkReadMax {
+		return 0, model.NewErrBadRequest("too many notifications, the maximum is " + strconv.Itoa(notificationsMarkReadMax))
+	}
+
+	stored := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return 0, model.NewErrBadRequest("missing notification id")
+		}
+		if model.IsEphemeralNotificationID(id) {
+			continue
+		}
+		stored = append(stored, id)
+	}
+
+	count, err := a.store.MarkNotificationsAsRead(stored, userID)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		a.broadcastUnreadNotificationCount(userID)
+	}
+	return count, nil
+}
+
+// MarkAllNotificationsAsRead marks all notifications for a user matching the options as read
+// and returns how many were marked
+func (a *App) MarkAllNotificationsAsRead(userID string, opts model.MarkNotificationsAsReadOptions) (int64, error) {
+	count, err := a.store.MarkAllNotificationsAsRead(userID, opts)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		a.broadcastUnreadNotificationCount(userID)
+	}
+	return count, nil
+}
+
+// GetCardNotifications returns a page of the notifications of a user for a card, newest
+// first, selected by the limit and cursor of opts. If markRead is set, the unread ones are
+// marked as read first, so opening a card clears its notifications in a single call.
+func (a *App) GetCardNotifications(userID, cardID string, opts model.QueryUserNotificationsOptions, markRead bool) (*model.NotificationsPage, error) {
+	if cardID == "" {
+		return nil, model.NewErrBadRequest("missing card ID")
+	}
+
+	if markRead {
+		if _, err := a.store.MarkAllNotificationsAsRead(userID, model.MarkNotificationsAsReadOptions{CardID: cardID}); err != nil {
+			return nil, err
+		}
+	}
+	opts.CardID = cardID
+	return a.GetUserNotificationsPage(userID, opts, false)
+}
+
+// GetBoardNotifications returns a page of the notifications of a user for a board, pinned
+// first then newest first, like GetCardNotifications for a card.
+func (a *App) GetBoardNotifications(userID, boardID string, opts model.QueryUserNotificationsOptions, markRead bool) (*model.NotificationsPage, error) {
+	if boardID == "" {
+		return nil, model.NewErrBadRequest("missing board ID")
+	}
+
+	if markRead {
+		if _, err := a.store.MarkAllNotificationsAsRead(userID, model.MarkNotificationsAsReadOptions{BoardID: boardID}); err != nil {
+			return nil, err
+		}
+	}
+	opts.BoardIDs = []string{boardID}
+	return a.GetUserNotificationsPage(userID, opts, false)
+}
+
+// MarkNotificationsAsReadReturningIDs marks the notifications of a user matching the
+// options as read and returns the IDs of the ones that were unread. The options must be
+// scoped to a board, a type or a card, as an unscoped sweep could return every
+// notification of the user.
+func (a *App) MarkNotificationsAsReadReturningIDs(userID string, opts model.MarkNotificationsAsReadOptions) ([]string, error) {
+	if !opts.IsScoped() {
+		return nil, model.NewErrBadRequest("returning the marked notification IDs requires a board, a type or a card")
+	}
+	ids, err := a.store.MarkNotificationsAsReadReturningIDs(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		a.broadcastUnreadNotificationCount(userID)
+	}
+	return ids, nil
+}
+
+// PatchUserNotification corrects the content of a notification and pushes the updated
+// notification to its target again.
+func (a *App) PatchUserNotification(notificationID string, patch *model.UserNotificationPatch) (*model.UserNotification, error) {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return nil, err
+	}
+
+	notification, err := a.store.GetUserNotificationByID(notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := patch.IsValid(notification); err != nil {
+		return nil, model.NewErrBadRequest(err.Error())
+	}
+
+	notification = patch.Patch(notification)
+	if err := a.store.UpdateUserNotification(notification); err != nil {
+		return nil, err
+	}
+
+	a.broadcastUserNotification(notification)
+	return notification, nil
+}
+
+// PurgeNotificationsForUser deletes the notifications a user received, e.g. when they are
+// deactivated, and returns how many were deleted. The notifications are gone for good, so
+// reactivating the user doesn't bring them back.
+func (a *App) PurgeNotificationsForUser(userID string) (int64, error) {
+	count, err := a.store.DeleteNotificationsForUser(userID)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		a.broadcastUnreadNotificationCount(userID)
+	}
+	return count, nil
+}
+
+// ReassignNotificationsBoard moves the notifications of a board to another board and
+// returns how many were moved. Both boards must exist unless force is set. The moved
+// notifications get the team of the new board, if it exists.
+func (a *App) ReassignNotificationsBoard(reassign *model.NotificationBoardReassign) (int64, error) {
+	if reassign.From == "" || reassign.To == "" {
+		return 0, model.NewErrBadRequest("both the from and to boards are required")
+	}
+	if reassign.From == reassign.To {
+		return 0, model.NewErrBadRequest("the from and to boards must be different")
+	}
+
+	var toTeamID string
+	for _, boardID := range []string{reassign.From, reassign.To} {
+		board, err := a.store.GetBoard(boardID)
+		if model.IsErrNotFound(err) {
+			if reassign.Force {
+				continue
+			}
+			return 0, model.NewErrNotFound("board ID=" + boardID)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if boardID == reassign.To {
+			toTeamID = board.TeamID
+		}
+	}
+
+	return a.store.ReassignNotificationsBoard(reassign.From, reassign.To, toTeamID)
+}
+
+// ArchiveNotification hides a notification from the default list without deleting it
+func (a *App) ArchiveNotification(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.SetNotificationArchived(notificationID, userID, true)
+}
+
+// UnarchiveNotification restores an archived notification to the default list
+func (a *App) UnarchiveNotification(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.SetNotificationArchived(notificationID, userID, false)
+}
+
+// PinNotification keeps a notification at the top of the list and out of retention
+func (a *App) PinNotification(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.SetNotificationPinned(notificationID, userID, true)
+}
+
+// UnpinNotification returns a pinned notification to its place in the list
+func (a *App) UnpinNotification(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.SetNotificationPinned(notificationID, userID, false)
+}
+
+// PerformNotificationAction performs one of the actions declared on a notification. The
+// notification is marked as resolved and read first, so concurrent requests can't both
+// take the action, and is unresolved again if the action fails.
+func (a *App) PerformNotificationAction(notificationID, userID, action string) (*model.UserNotification, error) {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return nil, err
+	}
+
+	notification, err := a.store.GetUserNotification(notificationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if notification.ResolvedAction != "" {
+		return nil, model.NewErrConflict("notification already resolved with action " + notification.ResolvedAction)
+	}
+	if !notification.HasAction(action) {
+		return nil, model.NewErrBadRequest("invalid action " + action + " for notification of type " + notification.Type)
+	}
+
+	err = a.store.ResolveUserNotification(notificationID, userID, action)
+	if model.IsErrNotFound(err) {
+		return nil, model.NewErrConflict("notification already resolved")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.takeNotificationAction(notification, userID, action); err != nil {
+		if unresolveErr := a.store.UnresolveUserNotification(notificationID, userID, action, notification.Read); unresolveErr != nil {
+			a.logger.Error("Cannot unresolve notification after its action failed",
+				mlog.String("notificationID", notificationID),
+				mlog.String("action", action),
+				mlog.Err(unresolveErr),
+			)
+		}
+		return nil, err
+	}
+
+	notification.ResolvedAction = action
+	notification.Read = true
+	notification.SetActions()
+	a.broadcastUserNotification(notification)
+	return notification, nil
+}
+
+// takeNotificationAction applies the side effect of an action on a notification.
+func (a *App) takeNotificationAction(notification *model.UserNotification, userID, action string) error {
+	switch action {
+	case model.NotificationActionDismiss:
+		if err := a.store.SetNotificationArchived(notification.ID, userID, true); err != nil {
+			return err
+		}
+		notification.Archived = true
+	case model.NotificationActionUnassign:
+		return a.unassignFromCard(notification.CardID, userID)
+	}
+	return nil
+}
+
+// unassignFromCard removes the user from the person properties of a card.
+func (a *App) unassignFromCard(cardID, userID string) error {
+	card, err := a.store.GetBlock(cardID)
+	if err != nil {
+		return err
+	}
+
+	if !a.permissions.HasPermissionToBoard(userID, card.BoardID, model.PermissionManageBoardCards) {
+		return model.NewErrPermission("access denied to modify card")
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	props, found := model.RemovePersonPropertyUserID(card, schema, userID)
+	if !found {
+		return model.NewErrConflict("user is not assigned to the card")
+	}
+
+	patch := &model.BlockPatch{UpdatedFields: map[string]interface{}{"properties": props}}
+	_, err = a.PatchBlock(cardID, patch, userID)
+	return err
+}
+
+// DeleteUserNotification deletes a notification
+func (a *App) DeleteUserNotification(notificationID, userID string) error {
+	if err := checkNotificationStored(notificationID); err != nil {
+		return err
+	}
+	return a.store.DeleteUserNotification(notificationID, userID)
+}
+
+// CreateAndBroadcastNotification creates a notification and broadcasts it via WebSocket.
+// Returns nil without error if the target user's preferences suppress the notification,
+// roll it up in the digest of its board, or if the notifications of its board are held
+// back, e.g. while the board is imported.
+// When the notification queue is enabled the notification is checked, then queued to be
+// stored and broadcast by the queue workers, and nil is returned. A full queue returns a
+// too many requests error. Set opts.Synchronous to store the notification right away and
+// get it back. Set opts.Ephemeral for real-time signals that are broadcast but never
+// stored: the returned notification gets an ID that can't be used with other endpoints.
+// A notification with a collapse key replaces the unread notification of its target with
+// the same key, if any, which is updated in place, broadcast again and returned.
+func (a *App) CreateAndBroadcastNotification(notification *model.UserNotification, opts model.CreateUserNotificationOptions) (*model.UserNotification, error) {
+	deliver, err := a.prepareNotification(notification, opts, nil, nil)
+	if err != nil || !deliver {
+		return nil, err
+	}
+	if a.holdBackNotification(notification) {
+		return nil, nil
+	}
+	return a.deliverNotification(notification, opts)
+}
+
+// deliverNotification broadcasts, collapses, queues or stores a notification that was
+// already prepared, as described in CreateAndBroadcastNotification.
+func (a *App) deliverNotification(notification *model.UserNotification, opts model.CreateUserNotificationOptions) (*model.UserNotification, error) {
+	if opts.Ephemeral {
+		ephemeral := newEphemeralNotification(notification)
+		a.broadcastUserNotification(ephemeral)
+		return ephemeral, nil
+	}
+
+	if notification.CollapseKey != "" {
+		replaced, err := a.collapseNotification(notification)
+		if err != nil || replaced != nil {
+			return replaced, err
+		}
+	}
+
+	if a.notificationQueue != nil && !opts.Synchronous {
+		if !a.notificationQueue.enqueue(notification) {
+			return nil, model.NewErrTooManyRequests("the notification queue is full")
+		}
+		return nil, nil
+	}
+
+	created, err := a.store.CreateUserNotification(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	// Broadcast to the target user via WebSocket
+	a.broadcastUserNotification(created)
+
+	return created, nil
+}
+
+// prepareNotification checks a notification before it is created, resets the state the
+// server owns, defaults its reason to the one of its type, records its source and applies
+// the target user's preferences.
+// Returns false if the notification should not be delivered, because the target is
+// deactivated or below the minimum board role, suppressed it or rolls it up in a digest.
+//
+// In a batch, targets caches whether the target users are active so each one is only
+// looked up once. It is nil for a single notification. In a fan-out to the members of a
+// board, preferences holds the preferences of every target user, read at once. It is nil
+// otherwise.
+func (a *App) prepareNotification(notification *model.UserNotification, opts model.CreateUserNotificationOptions, targets notificationTargets, preferences *notificationPreferences) (bool, error) {
+	if err := notification.IsValid(a.notificationTypes); err != nil {
+		return false, model.NewErrBadRequest(err.Error())
+	}
+	// a new notification is unread and unresolved whatever its creator set, and the server
+	// decides who else it was sent to
+	notification.Read = false
+	notification.Archived = false
+	notification.Pinned = false
+	notification.ResolvedAction = ""
+	notification.CoRecipients = nil
+	if notification.Reason == "" {
+		notification.Reason = model.NotificationReasonForType(notification.Type)
+	}
+	notification.Source = opts.Source
+	if notification.Source == "" {
+		notification.Source = model.NotificationSourceServer
+	}
+
+	active, err := a.isActiveNotificationTarget(notification.TargetUserID, targets)
+	if err != nil {
+		return false, err
+	}
+	if !active {
+		a.logger.Debug("Notification skipped, target user is deactivated",
+			mlog.String("targetUserID", notification.TargetUserID),
+		)
+		return false, nil
+	}
+
+	if !opts.SkipAssigneeCheck {
+		if err := a.checkNotificationAssignee(notification); err != nil {
+			return false, err
+		}
+	}
+
+	if a.belowNotificationMinBoardRole(notification) {
+		a.logger.Debug("Notification skipped, target user is below the minimum board role",
+			mlog.String("targetUserID", notification.TargetUserID),
+			mlog.String("boardID", notification.BoardID),
+		)
+		return false, nil
+	}
+
+	if opts.SkipPreferences {
+		return true, nil
+	}
+
+	blocked, err := a.isNotificationActorBlocked(notification)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
+		a.logger.Debug("Notification suppressed, target user blocked the actor",
+			mlog.String("targetUserID", notification.TargetUserID),
+			mlog.String("actorUserID", notification.ActorUserID),
+		)
+		return false, nil
+	}
+
+	mode, err := a.getNotificationMode(notification, preferences)
+	if err != nil {
+		return false, err
+	}
+	switch mode {
+	case notificationModeSuppressed:
+		a.logger.Debug("Notification suppressed by notification preferences",
+			mlog.String("targetUserID", notification.TargetUserID),
+			mlog.String("boardID", notification.BoardID),
+		)
+		return false, nil
+	case notificationModeDigest:
+		return false, a.store.AddNotificationDigestActivity(notification.TargetUserID, notification.BoardID, utils.GetMillis())
+	}
+	return true, nil
+}
+
+// CreateAndBroadcastNotifications creates a batch of notifications with a single store
+// call and broadcasts them. Each notification is checked like in
+// CreateAndBroadcastNotification and the ones the target users' preferences don't deliver,
+// or that are held back, are left out of the result. Invalid notifications, e.g. for a
+// target user that doesn't exist, are skipped and returned by their index in the batch
+// rather than failing the others. Notifications with a collapse key that replace an unread
+// one are returned updated instead of created. Batches larger than the
+// NotificationBatchMaxSize setting are rejected with a bad request error. The batch is
+// never queued and opts.Synchronous and opts.Ephemeral are ignored.
+func (a *App) CreateAndBroadcastNotifications(notifications []*model.UserNotification, opts model.CreateUserNotificationOptions) ([]*model.UserNotification, []*model.SkippedNotification, error) {
+	if err := a.CheckNotificationBatchSize(len(notifications)); err != nil {
+		return nil, nil, err
+	}
+
+	toCreate := make([]*model.UserNotification, 0, len(notifications))
+	skipped := []*model.SkippedNotification{}
+	targets := notificationTargets{}
+	for i, notification := range notifications {
+		deliver, err := a.prepareNotification(notification, opts, targets, nil)
+		if model.IsErrBadRequest(err) || model.IsErrNotFound(err) {
+			// skipped notifications are named by their index in the batch the client sent
+			skipped = append(skipped, &model.SkippedNotification{Index: i, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if deliver && !a.holdBackNotification(notification) {
+			toCreate = append(toCreate, notification)
+		}
+	}
+	if len(skipped) > 0 {
+		a.logger.Debug("CreateAndBroadcastNotifications skipped invalid notifications",
+			mlog.Int("count", len(skipped)),
+		)
+	}
+
+	collapsed, toCreate, err := a.collapseNotifications(toCreate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(toCreate) == 0 {
+		return collapsed, skipped, nil
+	}
+	setMentionCoRecipients(toCreate)
+
+	created, err := a.store.CreateUserNotifications(toCreate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, notification := range created {
+		a.broadcastUserNotification(notification)
+	}
+	return append(collapsed, created...), skipped, nil
+}
+
+// CheckNotificationBatchSize returns a bad request error if a batch of notifications is
+// larger than the NotificationBatchMaxSize setting.
+func (a *App) CheckNotificationBatchSize(size int) error {
+	if maxSize := a.config.NotificationBatchMaxSize; maxSize > 0 && size > maxSize {
+		return model.NewErrBadRequest(fmt.Sprintf("too many notifications in batch: %d, the maximum is %d", size, maxSize))
+	}
+	return nil
+}
+
+// collapseNotifications applies the collapse keys of a batch of notifications to create:
+// the last notification of the batch for a target, key, actor and source replaces the
+// others with the same ones, then the matching unread notification of the target, if any.
+// Returns the replaced notifications, already updated and broadcast, and the ones left to
+// create.
+func (a *App) collapseNotifications(notifications []*model.UserNotification) ([]*model.UserNotification, []*model.UserNotification, error) {
+	type collapseKey struct{ targetUserID, key, actorUserID, source string }
+
+	deduped := make([]*model.UserNotification, 0, len(notifications))
+	positions := map[collapseKey]int{}
+	for _, notification := range notifications {
+		if notification.CollapseKey == "" {
+			deduped = append(deduped, notification)
+			continue
+		}
+		key := collapseKey{notification.TargetUserID, notification.CollapseKey, notification.ActorUserID, notification.Source}
+		if i, ok := positions[key]; ok {
+			deduped[i] = notification
+			continue
+		}
+		positions[key] = len(deduped)
+		deduped = append(deduped, notification)
+	}
+
+	collapsed := []*model.UserNotification{}
+	toCreate := make([]*model.UserNotification, 0, len(deduped))
+	for _, notification := range deduped {
+		if notification.CollapseKey != "" {
+			replaced, err := a.collapseNotification(notification)
+			if err != nil {
+				return nil, nil, err
+			}
+			if replaced != nil {
+				collapsed = append(collapsed, replaced)
+				continue
+			}
+		}
+		toCreate = append(toCreate, notification)
+	}
+	return collapsed, toCreate, nil
+}
+
+// collapseNotification updates the unread notification of the target user with the
+// collapse key, actor and source of notification, if any, with the content of
+// notification and broadcasts it again. Returns nil if there is none and notification is
+// to be created.
+func (a *App) collapseNotification(notification *model.UserNotification) (*model.UserNotification, error) {
+	existing, err := a.store.GetUnreadNotificationByCollapseKey(notification.TargetUserID, notification.CollapseKey, notification.ActorUserID, notification.Source)
+	if model.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing.ActorName = notification.ActorName
+	existing.CardTitle = notification.CardTitle
+	existing.Params = notification.Params
+	if err := a.store.UpdateUserNotification(existing); err != nil {
+		return nil, err
+	}
+
+	a.broadcastUserNotification(existing)
+	return existing, nil
+}
+
+// setMentionCoRecipients sets the co-recipients of the mention notifications of a fan-out
+// that are delivered: the targets of the other mention notifications about the same card
+// by the same actor, capped at notificationCoRecipientsMax. Mentions with a single target
+// get none.
+func setMentionCoRecipients(notifications []*model.UserNotification) {
+	type mentionKey struct{ actorUserID, cardID string }
+
+	targets := map[mentionKey][]string{}
+	for _, notification := range notifications {
+		if notification.Type != model.NotificationTypeMentioned {
+			continue
+		}
+		key := mentionKey{notification.ActorUserID, notification.CardID}
+		targets[key] = append(targets[key], notification.TargetUserID)
+	}
+
+	for _, notification := range notifications {
+		if notification.Type != model.NotificationTypeMentioned {
+			continue
+		}
+		coRecipients := []string{}
+		for _, userID := range targets[mentionKey{notification.ActorUserID, notification.CardID}] {
+			if userID != notification.TargetUserID && len(coRecipients) < notificationCoRecipientsMax {
+				coRecipients = append(coRecipients, userID)
+			}
+		}
+		if len(coRecipients) > 0 {
+			notification.CoRecipients = coRecipients
+		}
+	}
+}
+
+// newEphemeralNotification fills in the fields the store would set for a notification that
+// is only broadcast.
+func newEphemeralNotification(notification *model.UserNotification) *model.UserNotification {
+	now := utils.GetMillis()
+	notification.ID = utils.NewID(utils.IDTypeEphemeral)
+	notification.CreateAt = now
+	notification.UpdateAt = now
+	notification.Category = model.NotificationCategoryForType(notification.Type)
+	notification.Silent, notification.Urgency = model.NotificationAlertForCategory(notification.Category)
+	notification.Ephemeral = true
+	return notification
+}
+
+// checkNotificationStored returns a bad request error if the ID belongs to an ephemeral
+// notification, which can't be read, archived or deleted as it was never stored.
+func checkNotificationStored(notificationID string) error {
+	if model.IsEphemeralNotificationID(notificationID) {
+		return model.NewErrBadRequest("notification ID=" + notificationID + " is ephemeral and was never stored")
+	}
+	return nil
+}
+
+// SendTestNotification creates and broadcasts a sample notification to the user so they
+// can verify delivery. Unless bypass is set the user's notification preferences apply, so
+// a suppressed test notification returns nil. Users can send one test notification every
+// testNotificationInterval.
+func (a *App) SendTestNotification(userID string, bypass bool) (*model.UserNotification, error) {
+	if !a.allowTestNotification(userID) {
+		return nil, model.NewErrTooManyRequests("test notifications are limited to one every " + testNotificationInterval.String())
+	}
+
+	notification := &model.UserNotification{
+		TargetUserID: userID,
+		ActorUserID:  userID,
+		Type:         model.NotificationTypeTest,
+		CardTitle:    "Test notification",
+	}
+
+	opts := model.CreateUserNotificationOptions{
+		SkipPreferences: bypass,
+		Synchronous:     true,
+		Source:          model.NotificationSourceAPI,
+	}
+	return a.CreateAndBroadcastNotification(notification, opts)
+}
+
+// allowTestNotification returns true, and records the attempt, if the user has not sent a
+// test notification within testNotificationInterval.
+func (a *App) allowTestNotification(userID string) bool {
+	a.testNotificationMux.Lock()
+	defer a.testNotificationMux.Unlock()
+
+	now := time.Now()
+	// forget the attempts that left the interval so the map only holds recent senders
+	for id, last := range a.testNotificationSent {
+		if now.Sub(last) >= testNotificationInterval {
+			delete(a.testNotificationSent, id)
+		}
+	}
+
+	if _, ok := a.testNotificationSent[userID]; ok {
+		return false
+	}
+	if a.testNotificationSent == nil {
+		a.testNotificationSent = map[string]time.Time{}
+	}
+	a.testNotificationSent[userID] = now
+	return true
+}
+
+// broadcastUserNotification sends a persisted notification to its target user. Delivery
+// is best effort: a failing broadcast is logged and never affects the stored notification,
+// which the client picks up on its next fetch.
+func (a *App) broadcastUserNotification(notification *model.UserNotification) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Error("broadcast user notification panic",
+				mlog.String("notificationID", notification.ID),
+				mlog.String("targetUserID", notification.TargetUserID),
+				mlog.Any("panic", r),
+			)
+		}
+	}()
+
+	a.renderNotificationMessage(notification)
+	a.wsAdapter.BroadcastUserNotification(notification.TargetUserID, notification)
+}
+
+// setNotificationsHiddenForBoards hides the notifications of deleted boards, which would
+// only lead users nowhere, or shows them again once the boards are undeleted. They are
+// kept meanwhile so that undeleting a board restores them. Returns the users who had any,
+// whose unread count may have changed. Failures are logged and don't fail the change of
+// the boards.
+func (a *App) setNotificationsHiddenForBoards(hidden bool, boardIDs ...string) []string {
+	userIDs := []string{}
+	seen := map[string]bool{}
+	for _, boardID := range boardIDs {
+		notifiedUserIDs, err := a.store.SetNotificationsHiddenForBoard(boardID, hidden)
+		if err != nil {
+			a.logger.Error("Cannot change the visibility of the notifications of a board",
+				mlog.String("boardID", boardID),
+				mlog.Bool("hidden", hidden),
+				mlog.Err(err),
+			)
+			continue
+		}
+		for _, userID := range notifiedUserIDs {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+	return userIDs
+}
+
+// broadcastUnreadNotificationCount sends the current number of unread notifications of a
+// user to their clients, after notifications were removed or read without a broadcast of
+// their own. With the NotificationUnreadCountsByType setting the count of each type is
+// sent too, so clients can update per type badges without fetching them.
+func (a *App) broadcastUnreadNotificationCount(userID string) {
+	if err := a.sendUnreadNotificationCount(userID); err != nil {
+		a.logger.Error("Cannot get the unread notification count to broadcast",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+	}
+}
+
+// sendUnreadNotificationCount counts the unread notifications of a user and broadcasts
+// the result, see broadcastUnreadNotificationCount.
+func (a *App) sendUnreadNotificationCount(userID string) error {
+	count, err := a.GetUnreadNotificationCount(userID)
+	if err != nil {
+		return err
+	}
+
+	var countsByType map[string]int
+	if a.config.NotificationUnreadCountsByType {
+		countsByType, err = a.store.GetUnreadNotificationCountByType(userID)
+		if err != nil {
+			return err
+		}
+	}
+	a.wsAdapter.BroadcastUnreadNotificationCount(userID, count, countsByType)
+	return nil
+}
+
+// RecomputeUnreadNotificationCounts counts the unread notifications of a user again and
+// broadcasts the result to their clients, repairing badges that went out of sync with the
+// read flags. Without a user, every user connected to this server is recomputed. Returns
+// the number of users whose count was broadcast.
+func (a *App) RecomputeUnreadNotificationCounts(userID string) (int, error) {
+	userIDs := []string{userID}
+	if userID == "" {
+		userIDs = a.wsAdapter.ConnectedUserIDs()
+	} else if _, err := a.store.GetUserByID(userID); err != nil {
+		return 0, err
+	}
+
+	for i, id := range userIDs {
+		if err := a.sendUnreadNotificationCount(id); err != nil {
+			return i, err
+		}
+	}
+	return len(userIDs), nil
+}
+
+// renderNotificationMessage sets the message of a notification from the template of its
+// type. Templates from the NotificationTemplates setting override the built-in ones.
+func (a *App) renderNotificationMessage(notification *model.UserNotification) {
+	template := a.config.NotificationTemplates[notification.Type]
+	if template == "" {
+		template = model.DefaultNotificationTemplate(notification.Type)
+	}
+	notification.Message = model.RenderNotificationMessage(template, notification)
+}
+
+// RegisterNotificationType makes the server accept notifications of a custom type, e.g.
+// for plugins and integrations
+func (a *App) RegisterNotificationType(notifType string) error {
+	return a.notificationTypes.Register(notifType)
+}
+
+// GetNotificationTypes returns the notification types the server accepts
+func (a *App) GetNotificationTypes() []string {
+	return a.notificationTypes.Types()
+}
+
+// GetNotificationCapabilities returns the notification types the server accepts and the
+// notification features and limits of its configuration. Do not disturb and snoozing are
+// not supported yet and are always reported as disabled.
+func (a *App) GetNotificationCapabilities() *model.NotificationCapabilities {
+	types := a.notificationTypes.Types()
+	typeCapabilities := make([]model.NotificationTypeCapability, 0, len(types))
+	for _, notifType := range types {
+		typeCapabilities = append(typeCapabilities, model.NotificationTypeCapability{
+			Type:     notifType,
+			Category: model.NotificationCategoryForType(notifType),
+			Actions:  model.NotificationActionsForType(notifType),
+		})
+	}
+
+	return &model.NotificationCapabilities{
+		Types:            typeCapabilities,
+		Categories:       model.NotificationCategories(),
+		Preferences:      true,
+		BoardPreferences: true,
+		Digests:          a.config.NotificationDigestIntervalMinutes > 0,
+		Preview:          a.config.EnableNotificationPreview,
+		Queued:           a.NotificationQueueEnabled(),
+		Limits: model.NotificationLimits{
+			BatchMaxSize: a.config.NotificationBatchMaxSize,
+			ReadSyncMax:  notificationReadSyncMax,
+		},
+	}
+}
+
+// GetNotificationPreferences returns the effective notification preferences of a user in
+// a team: their own overrides on top of the team defaults on top of the built-in defaults.
+// An empty team ID leaves the team defaults out.
+func (a *App) GetNotificationPreferences(userID, teamID string) (*model.NotificationPreferences, error) {
+	teamDefaults, err := a.teamNotificationDefaults(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return a.resolveNotificationPreferences(userID, teamDefaults)
+}
+
+// resolveNotificationPreferences returns the effective notification preferences of a user
+// given the defaults of the team they are resolved in.
+func (a *App) resolveNotificationPreferences(userID string, teamDefaults *model.NotificationPreferencesOverrides) (*model.NotificationPreferences, error) {
+	preferences, err := a.store.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := model.NotificationPreferencesOverridesFromPreferences(preferences)
+	if err != nil {
+		return nil, err
+	}
+	return model.ResolveNotificationPreferences(teamDefaults, overrides), nil
+}
+
+// SetNotificationPreferences saves the notification preferences a user overrides and
+// returns the resulting effective preferences in the team
+func (a *App) SetNotificationPreferences(userID, teamID string, overrides *model.NotificationPreferencesOverrides) (*model.NotificationPreferences, error) {
+	value, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := model.UserPreferencesPatch{
+		UpdatedFields: map[string]string{
+			model.PreferenceNameNotificationPreferences: string(value),
+		},
+	}
+	if _, err = a.store.PatchUserPreferences(userID, patch); err != nil {
+		return nil, err
+	}
+
+	teamDefaults, err := a.teamNotificationDefaults(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return model.ResolveNotificationPreferences(teamDefaults, overrides), nil
+}
+
+// CanCreateNotification returns true if userID may create the notification through the
+// API. Unless RestrictNotificationCreate is off, only admins may notify anyone: other users
+// must be the actor and may only notify themselves or users they share the board of the
+// notification with.
+func (a *App) CanCreateNotification(userID string, notification *model.UserNotification) bool {
+	if !a.config.RestrictNotificationCreate || a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) {
+		return true
+	}
+	return canCreateNotification(userID, notification, func(userID, boardID string) bool {
+		return a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+	})
+}
+
+// CanCreateNotifications returns true if userID may create every notification of a batch
+// through the API, else false and the index of the first one it may not create. The
+// checks are the ones of CanCreateNotification, made once per distinct user and board.
+func (a *App) CanCreateNotifications(userID string, notifications []*model.UserNotification) (int, bool) {
+	if !a.config.RestrictNotificationCreate || a.permissions.HasPermissionTo(userID, model.PermissionManageSystem) {
+		return 0, true
+	}
+
+	type boardAccess struct{ userID, boardID string }
+	canView := map[boardAccess]bool{}
+	canViewBoard := func(userID, boardID string) bool {
+		access := boardAccess{userID, boardID}
+		viewable, ok := canView[access]
+		if !ok {
+			viewable = a.permissions.HasPermissionToBoard(userID, boardID, model.PermissionViewBoard)
+			canView[access] = viewable
+		}
+		return viewable
+	}
+
+	for i, notification := range notifications {
+		if !canCreateNotification(userID, notification, canViewBoard) {
+			return i, false
+		}
+	}
+	return 0, true
+}
+
+// canCreateNotification returns true if a user who isn't an admin may create the
+// notification: they must be the actor and may only notify themselves or users they share
+// the board of the notification with.
+func canCreateNotification(userID string, notification *model.UserNotification, canViewBoard func(userID, boardID string) bool) bool {
+	if notification.ActorUserID != userID {
+		return false
+	}
+	if notification.TargetUserID == userID {
+		return true
+	}
+
+	return notification.BoardID != "" &&
+		canViewBoard(userID, notification.BoardID) &&
+		canViewBoard(notification.TargetUserID, notification.BoardID)
+}
+
+// belowNotificationMinBoardRole returns true if the target user's role on the board of the
+// notification is below the NotificationMinBoardRole setting. The default, viewer, lets
+// every notification through so the check is skipped.
+func (a *App) belowNotificationMinBoardRole(notification *model.UserNotification) bool {
+	role := model.BoardRole(a.config.NotificationMinBoardRole)
+	if notification.BoardID == "" || role == model.BoardRoleNone || role == model.BoardRoleViewer {
+		return false
+	}
+
+	permission := model.PermissionForBoardRole(role)
+	if permission == nil {
+		a.logger.Warn("Unknown minimum board role for notifications, ignoring it",
+			mlog.String("role", string(role)),
+		)
+		return false
+	}
+	return !a.permissions.HasPermissionToBoard(notification.TargetUserID, notification.BoardID, permission)
+}
+
+// notificationMode is how a notification reaches its target user.
+type notificationMode int
+
+const (
+	notificationModeImmediate notificationMode = iota
+	notificationModeDigest
+	notificationModeSuppressed
+)
+
+// getNotificationMode returns how the target user's preferences deliver the notification.
+// A board override of the notification type wins and delivers it right away or suppresses
+// it, otherwise the user's global preferences apply. The preferences are read from
+// preloaded if it holds the ones of the notification's board.
+func (a *App) getNotificationMode(notification *model.UserNotification, preloaded *notificationPreferences) (notificationMode, error) {
+	if preloaded != nil && preloaded.boardID == notification.BoardID {
+		return preloaded.modeFor(notification)
+	}
+
+	var boardPreferences []*model.NotificationBoardPreference
+	var teamDefaults *model.NotificationPreferencesOverrides
+	if notification.BoardID != "" {
+		var err error
+		boardPreferences, err = a.store.GetNotificationBoardPreferences(notification.TargetUserID, notification.BoardID)
+		if err != nil {
+			return notificationModeImmediate, err
+		}
+		if model.NotificationBoardPreferenceFor(boardPreferences, notification.Type) != nil {
+			return notificationModeFor(notification, boardPreferences, nil), nil
+		}
+
+		// the defaults of the team the board belongs to apply to its notifications
+		teamDefaults, err = a.store.GetNotificationTeamDefaultsForBoard(notification.BoardID)
+		if err != nil {
+			return notificationModeImmediate, err
+		}
+	}
+
+	preferences, err := a.resolveNotificationPreferences(notification.TargetUserID, teamDefaults)
+	if err != nil {
+		return notificationModeImmediate, err
+	}
+	return notificationModeFor(notification, boardPreferences, preferences), nil
+}
+
+// notificationModeFor returns how the already loaded board overrides and global
+// preferences of the target user deliver the notification.
+func notificationModeFor(notification *model.UserNotification, boardPreferences []*model.NotificationBoardPreference, preferences *model.NotificationPreferences) notificationMode {
+	if preference := model.NotificationBoardPreferenceFor(boardPreferences, notification.Type); preference != nil {
+		if !preference.Enabled {
+			return notificationModeSuppressed
+		}
+		return notificationModeImmediate
+	}
+
+	if preferences.Suppresses(notification) {
+		return notificationModeSuppressed
+	}
+	if preferences.Digests(notification) {
+		return notificationModeDigest
+	}
+	return notificationModeImmediate
+}
+
+// notificationPreferences are the preferences of several users for the notifications of a
+// board.
+type notificationPreferences struct {
+	boardID          string
+	boardPreferences map[string][]*model.NotificationBoardPreference
+	userPreferences  map[string]mmModel.Preferences
+	teamDefaults     *model.NotificationPreferencesOverrides
+}
+
+// loadNotificationPreferences reads the preferences of the given users for the
+// notifications of a board at once rather than one user at a time.
+func (a *App) loadNotificationPreferences(boardID string, userIDs []string) (*notificationPreferences, error) {
+	boardPreferences, err := a.store.GetUsersNotificationBoardPreferences(userIDs, boardID)
+	if err != nil {
+		return nil, err
+	}
+	userPreferences, err := a.store.GetUsersPreferences(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	teamDefaults, err := a.store.GetNotificationTeamDefaultsForBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notificationPreferences{
+		boardID:          boardID,
+		boardPreferences: boardPreferences,
+		userPreferences:  userPreferences,
+		teamDefaults:     teamDefaults,
+	}, nil
+}
+
+// modeFor is getNotificationMode for a notification of the board the preferences were
+// loaded for.
+func (p *notificationPreferences) modeFor(notification *model.UserNotification) (notificationMode, error) {
+	overrides, err := model.NotificationPreferencesOverridesFromPreferences(p.userPreferences[notification.TargetUserID])
+	if err != nil {
+		return notificationModeImmediate, err
+	}
+	preferences := model.ResolveNotificationPreferences(p.teamDefaults, overrides)
+	return notificationModeFor(notification, p.boardPreferences[notification.TargetUserID], preferences), nil
+}
+
+// GetNotificationBoardPreferences returns the notification types a user enabled or
+// disabled on a board, overriding their global preferences
+func (a *App) GetNotificationBoardPreferences(userID, boardID string) ([]*model.NotificationBoardPreference, error) {
+	return a.store.GetNotificationBoardPreferences(userID, boardID)
+}
+
+// SetNotificationBoardPreferences replaces the board overrides of a user. Notification
+// types without an override follow the user's global preferences.
+func (a *App) SetNotificationBoardPreferences(userID, boardID string, preferences []*model.NotificationBoardPreference) ([]*model.NotificationBoardPreference, error) {
+	seen := map[string]bool{}
+	for _, preference := range preferences {
+		if preference == nil {
+			return nil, model.NewErrBadRequest("missing notification board preference")
+		}
+		if err := preference.IsValid(a.notificationTypes); err != nil {
+			return nil, model.NewErrBadRequest(err.Error())
+		}
+		if seen[preference.Type] {
+			return nil, model.NewErrBadRequest("duplicate override for notification type: " + preference.Type)
+		}
+		seen[preference.Type] = true
+	}
+
+	if err := a.store.SetNotificationBoardPreferences(userID, boardID, preferences); err != nil {
+		return nil, err
+	}
+	return a.store.GetNotificationBoardPreferences(userID, boardID)
+}
+
+// isNotificationActorBlocked returns true if the target user blocked the actor of the
+// notification. Notifications without an actor, from the system or from the target user
+// themselves are never blocked.
+func (a *App) isNotificationActorBlocked(notification *model.UserNotification) (bool, error) {
+	actorID := notification.ActorUserID
+	if actorID == "" || actorID == model.SystemUserID || actorID == notification.TargetUserID {
+		return false, nil
+	}
+	return a.store.IsNotificationActorBlocked(notification.TargetUserID, actorID)
+}
+
+// GetNotificationActorBlocks returns the actors a user blocked notifications from.
+func (a *App) GetNotificationActorBlocks(userID string) ([]*model.NotificationActorBlock, error) {
+	return a.store.GetNotificationActorBlocks(userID)
+}
+
+// BlockNotificationActor stops the notifications a user gets from the actor. Blocking an
+// actor already blocked does nothing.
+func (a *App) BlockNotificationActor(userID, actorID string) error {
+	if actorID == "" {
+		return model.NewErrBadRequest("missing actor ID")
+	}
+	if actorID == userID {
+		return model.NewErrBadRequest("users can't block themselves")
+	}
+	if actorID == model.SystemUserID {
+		return model.NewErrBadRequest("system notifications can't be blocked")
+	}
+	return a.store.AddNotificationActorBlock(userID, actorID)
+}
+
+// UnblockNotificationActor delivers the notifications a user gets from the actor again.
+func (a *App) UnblockNotificationActor(userID, actorID string) error {
+	return a.store.RemoveNotificationActorBlock(userID, actorID)
+}
+
+// teamNotificationDefaults returns the default notification preferences of a team's
+// members, or nil for an empty team ID.
+func (a *App) teamNotificationDefaults(teamID string) (*model.NotificationPreferencesOverrides, error) {
+	if teamID == "" {
+		return nil, nil
+	}
+	return a.store.GetNotificationTeamDefaults(teamID)
+}
+
+// GetNotificationTeamDefaults returns the notification preferences the members of a team
+// inherit unless they override them.
+func (a *App) GetNotificationTeamDefaults(teamID string) (*model.NotificationPreferencesOverrides, error) {
+	return a.store.GetNotificationTeamDefaults(teamID)
+}
+
+// SetNotificationTeamDefaults replaces the notification preferences the members of a team
+// inherit. Omitted preferences fall back to the built-in defaults.
+func (a *App) SetNotificationTeamDefaults(teamID string, defaults *model.NotificationPreferencesOverrides) (*model.NotificationPreferencesOverrides, error) {
+	if err := a.store.SetNotificationTeamDefaults(teamID, defaults); err != nil {
+		return nil, err
+	}
+	return a.store.GetNotificationTeamDefaults(teamID)
+}
+
+// defaultBoardMentionAliases are the board mention aliases used when the
+// BoardMentionAliases setting is missing.
+var defaultBoardMentionAliases = []string{"board", "all"}
+
+// boardMentionAliases returns the aliases that address every member of a board. A missing
+// setting falls back to the defaults while an empty list disables board mentions.
+func (a *App) boardMentionAliases() []string {
+	if a.config.BoardMentionAliases == nil {
+		return defaultBoardMentionAliases
+	}
+	return a.config.BoardMentionAliases
+}
+
+// IsBoardMentionAlias returns true if the mention (with or without the leading @) is one
+// of the configured aliases that address every member of a board.
+func (a *App) IsBoardMentionAlias(mention string) bool {
+	mention = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(mention)), "@")
+	for _, alias := range a.boardMentionAliases() {
+		if strings.ToLower(alias) == mention {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateBoardMentionNotifications notifies every member of a board that they were
+// mentioned through a board-wide alias. The actor is skipped and each notification is
+// checked, held back, queued and delivered like in CreateAndBroadcastNotification, the
+// preferences of the members being read at once. The template provides the actor and card
+// of the mention. Returns the notifications that were stored, and the members that are
+// not existing users, which are skipped.
+func (a *App) CreateBoardMentionNotifications(boardID, alias string, template *model.UserNotification) ([]*model.UserNotification, []string, error) {
+	if !a.IsBoardMentionAlias(alias) {
+		return nil, nil, model.NewErrBadRequest("not a board mention alias: " + alias)
+	}
+
+	members, err := a.store.GetMembersForBoard(boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.UserID != template.ActorUserID {
+			targetIDs = append(targetIDs, member.UserID)
+		}
+	}
+
+	existing, invalidTargets, err := a.splitNotificationTargets(targetIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(invalidTargets) > 0 {
+		a.logger.Warn("CreateBoardMentionNotifications skipped targets that are not users",
+			mlog.String("boardID", boardID),
+			mlog.Array("userIDs", invalidTargets),
+		)
+	}
+
+	// the existing users are the active ones, so they are not looked up again
+	recipientIDs := make([]string, 0, len(existing))
+	targets := notificationTargets{}
+	for _, userID := range targetIDs {
+		if existing[userID] {
+			recipientIDs = append(recipientIDs, userID)
+			targets[userID] = true
+		}
+	}
+
+	preferences, err := a.loadNotificationPreferences(boardID, recipientIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := model.CreateUserNotificationOptions{Source: model.NotificationSourceServer}
+	toDeliver := make([]*model.UserNotification, 0, len(recipientIDs))
+	for _, userID := range recipientIDs {
+		notification := &model.UserNotification{
+			TargetUserID: userID,
+			ActorUserID:  template.ActorUserID,
+			ActorName:    template.ActorName,
+			Type:         model.NotificationTypeMentioned,
+			CardID:       template.CardID,
+			CardTitle:    template.CardTitle,
+			BoardID:      boardID,
+			Reason:       model.NotificationReasonBoardMention,
+		}
+
+		deliver, err := a.prepareNotification(notification, opts, targets, preferences)
+		if err != nil {
+			return nil, nil, err
+		}
+		if deliver && !a.holdBackNotification(notification) {
+			toDeliver = append(toDeliver, notification)
+		}
+	}
+	setMentionCoRecipients(toDeliver)
+
+	created := make([]*model.UserNotification, 0, len(toDeliver))
+	for _, notification := range toDeliver {
+		delivered, err := a.deliverNotification(notification, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if delivered != nil {
+			created = append(created, delivered)
+		}
+	}
+
+	a.logger.Debug("CreateBoardMentionNotifications",
+		mlog.String("boardID", boardID),
+		mlog.String("alias", alias),
+		mlog.Int("count", len(toDeliver)),
+	)
+
+	return created, invalidTargets, nil
+}
+
+// checkNotificationTarget makes sure a notification is addressed to an existing user, as
+// nobody could ever read one stored for any other ID. Returns false if the user is
+// deactivated, as they would only find the notification if they are reactivated.
+func (a *App) checkNotificationTarget(userID string) (bool, error) {
+	user, err := a.store.GetUserByID(userID)
+	if err != nil {
+		if model.IsErrNotFound(err) {
+			return false, model.NewErrNotFound("target user ID=" + userID)
+		}
+		return false, err
+	}
+	return user.DeleteAt == 0, nil
+}
+
+// notificationTargets caches whether the target users of a batch of notifications are
+// active, by user ID.
+type notificationTargets map[string]bool
+
+// isActiveNotificationTarget is checkNotificationTarget, reading and filling the cache of
+// a batch if there is one.
+func (a *App) isActiveNotificationTarget(userID string, targets notificationTargets) (bool, error) {
+	if active, ok := targets[userID]; ok {
+		return active, nil
+	}
+	active, err := a.checkNotificationTarget(userID)
+	if err != nil {
+		return false, err
+	}
+	if targets != nil {
+		targets[userID] = active
+	}
+	return active, nil
+}
+
+// splitNotificationTargets looks up the given user IDs at once, returning the set of the
+// ones that are existing users and the list of the ones that are not.
+func (a *App) splitNotificationTargets(userIDs []string) (map[string]bool, []string, error) {
+	existing := map[string]bool{}
+	if len(userIDs) == 0 {
+		return existing, nil, nil
+	}
+
+	users, err := a.store.GetUsersList(userIDs, false, false)
+	if err != nil && !model.IsErrNotFound(err) {
+		return nil, nil, err
+	}
+
+	for _, user := range users {
+		existing[user.ID] = true
+	}
+
+	invalid := []string{}
+	for _, userID := range userIDs {
+		if !existing[userID] {
+			invalid = append(invalid, userID)
+		}
+	}
+	return existing, invalid, nil
+}
+
+// checkNotificationAssignee makes sure assignment notifications agree with the card: the
+// target of an "assigned" notification must currently be assigned to the card and the
+// target of an "unassigned" notification must not be.
+func (a *App) checkNotificationAssignee(notification *model.UserNotification) error {
+	if notification.Type != model.NotificationTypeAssigned && notification.Type != model.NotificationTypeUnassigned {
+		return nil
+	}
+
+	card, err := a.store.GetBlock(notification.CardID)
+	if err != nil {
+		return err
+	}
+
+	board, err := a.store.GetBoard(card.BoardID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := model.ParsePropertySchema(board)
+	if err != nil {
+		return err
+	}
+
+	isAssignee := false
+	for _, userID := range model.GetPersonPropertyUserIDs(card, schema) {
+		if userID == notification.TargetUserID {
+			isAssignee = true
+			break
+		}
+	}
+
+	if notification.Type == model.NotificationTypeAssigned && !isAssignee {
+		return model.NewErrConflict("target user is not assigned to the card")
+	}
+	if notification.Type == model.NotificationTypeUnassigned && isAssignee {
+		return model.NewErrConflict("target user is still assigned to the card")
+	}
+	return nil
 }
@@ -0,0 +1,1674 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/permissions/localpermissions"
+	permissionsMocks "github.com/mattermost/focalboard/server/services/permissions/mocks"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/mattermost/focalboard/server/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestCreateBoardMentionNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.BoardMentionAliases = []string{"board", "all"}
+
+	template := &model.UserNotification{
+		ActorUserID: "actor",
+		ActorName:   "Actor",
+		CardID:      "card-1",
+		CardTitle:   "Card 1",
+	}
+
+	t.Run("unknown alias", func(t *testing.T) {
+		notifications, invalidTargets, err := th.App.CreateBoardMentionNotifications("board-1", "@someone", template)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, notifications)
+		require.Nil(t, invalidTargets)
+	})
+
+	t.Run("notifies members except the actor and muted users", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "actor"},
+			{BoardID: "board-1", UserID: "user-1"},
+			{BoardID: "board-1", UserID: "user-2"},
+		}, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1", "user-2"}, false, false).Return([]*model.User{{ID: "user-1"}, {ID: "user-2"}}, nil)
+		th.Store.EXPECT().GetUsersNotificationBoardPreferences([]string{"user-1", "user-2"}, "board-1").
+			Return(map[string][]*model.NotificationBoardPreference{}, nil)
+		th.Store.EXPECT().GetUsersPreferences([]string{"user-1", "user-2"}).Return(map[string]mmModel.Preferences{
+			"user-2": {
+				{
+					UserId:   "user-2",
+					Category: model.PreferencesCategoryFocalboard,
+					Name:     model.PreferenceNameNotificationPreferences,
+					Value:    `{"mutedBoardIds":["board-1"]}`,
+				},
+			},
+		}, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(false, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-2", "actor").Return(false, nil)
+		th.Store.EXPECT().CreateUserNotification(utils.Anything).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				return notification, nil
+			})
+
+		notifications, invalidTargets, err := th.App.CreateBoardMentionNotifications("board-1", "@ALL", template)
+		require.NoError(t, err)
+		require.Empty(t, invalidTargets)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "user-1", notifications[0].TargetUserID)
+		assert.Equal(t, model.NotificationTypeMentioned, notifications[0].Type)
+		assert.Equal(t, "board-1", notifications[0].BoardID)
+		assert.Equal(t, "card-1", notifications[0].CardID)
+		assert.Equal(t, model.NotificationReasonBoardMention, notifications[0].Reason)
+		assert.Empty(t, notifications[0].CoRecipients)
+	})
+
+	t.Run("members know who else was mentioned", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "user-1"},
+			{BoardID: "board-1", UserID: "user-2"},
+			{BoardID: "board-1", UserID: "user-3"},
+		}, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1", "user-2", "user-3"}, false, false).
+			Return([]*model.User{{ID: "user-1"}, {ID: "user-2"}, {ID: "user-3"}}, nil)
+		th.Store.EXPECT().GetUsersNotificationBoardPreferences([]string{"user-1", "user-2", "user-3"}, "board-1").
+			Return(map[string][]*model.NotificationBoardPreference{}, nil)
+		th.Store.EXPECT().GetUsersPreferences([]string{"user-1", "user-2", "user-3"}).
+			Return(map[string]mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked(utils.Anything, "actor").Return(false, nil).Times(3)
+		th.Store.EXPECT().CreateUserNotification(utils.Anything).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				return notification, nil
+			}).Times(3)
+
+		notifications, _, err := th.App.CreateBoardMentionNotifications("board-1", "@board", template)
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+		assert.Equal(t, []string{"user-2", "user-3"}, notifications[0].CoRecipients)
+		assert.Equal(t, []string{"user-1", "user-3"}, notifications[1].CoRecipients)
+		assert.Equal(t, []string{"user-1", "user-2"}, notifications[2].CoRecipients)
+	})
+
+	t.Run("skips members that are not users", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "user-1"},
+			{BoardID: "board-1", UserID: "bogus-user"},
+		}, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1", "bogus-user"}, false, false).
+			Return([]*model.User{{ID: "user-1"}}, model.NewErrNotAllFound("user", []string{"user-1", "bogus-user"}))
+		th.Store.EXPECT().GetUsersNotificationBoardPreferences([]string{"user-1"}, "board-1").
+			Return(map[string][]*model.NotificationBoardPreference{}, nil)
+		th.Store.EXPECT().GetUsersPreferences([]string{"user-1"}).Return(map[string]mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(false, nil)
+		th.Store.EXPECT().CreateUserNotification(utils.Anything).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				return notification, nil
+			})
+
+		notifications, invalidTargets, err := th.App.CreateBoardMentionNotifications("board-1", "@board", template)
+		require.NoError(t, err)
+		require.Equal(t, []string{"bogus-user"}, invalidTargets)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "user-1", notifications[0].TargetUserID)
+	})
+
+	t.Run("skips members that blocked the actor", func(t *testing.T) {
+		th.Store.EXPECT().GetMembersForBoard("board-1").Return([]*model.BoardMember{
+			{BoardID: "board-1", UserID: "user-1"},
+		}, nil)
+		th.Store.EXPECT().GetUsersList([]string{"user-1"}, false, false).Return([]*model.User{{ID: "user-1"}}, nil)
+		th.Store.EXPECT().GetUsersNotificationBoardPreferences([]string{"user-1"}, "board-1").
+			Return(map[string][]*model.NotificationBoardPreference{}, nil)
+		th.Store.EXPECT().GetUsersPreferences([]string{"user-1"}).Return(map[string]mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "actor").Return(true, nil)
+
+		notifications, _, err := th.App.CreateBoardMentionNotifications("board-1", "@board", template)
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+}
+
+func TestIsBoardMentionAlias(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("a missing setting falls back to the default aliases", func(t *testing.T) {
+		th.App.config.BoardMentionAliases = nil
+		assert.True(t, th.App.IsBoardMentionAlias("@board"))
+		assert.True(t, th.App.IsBoardMentionAlias("all"))
+		assert.False(t, th.App.IsBoardMentionAlias("@here"))
+	})
+
+	t.Run("configured aliases replace the default ones", func(t *testing.T) {
+		th.App.config.BoardMentionAliases = []string{"here"}
+		assert.True(t, th.App.IsBoardMentionAlias("@HERE"))
+		assert.False(t, th.App.IsBoardMentionAlias("@board"))
+	})
+
+	t.Run("an empty list disables board mentions", func(t *testing.T) {
+		th.App.config.BoardMentionAliases = []string{}
+		assert.False(t, th.App.IsBoardMentionAlias("@board"))
+	})
+}
+
+func TestCreateAndBroadcastNotificationUnknownTarget(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notification := &model.UserNotification{TargetUserID: "bogus-user", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+	th.Store.EXPECT().GetUserByID("bogus-user").Return(nil, model.NewErrNotFound("user"))
+
+	created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+	require.True(t, model.IsErrNotFound(err))
+	require.Nil(t, created)
+}
+
+func TestCreateAndBroadcastNotificationServerState(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notification := &model.UserNotification{
+		TargetUserID:   "user-1",
+		Type:           model.NotificationTypeMentioned,
+		CardID:         "card-1",
+		Read:           true,
+		Archived:       true,
+		Pinned:         true,
+		ResolvedAction: model.NotificationActionDismiss,
+		CoRecipients:   []string{"user-2"},
+	}
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+	th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+	th.Store.EXPECT().CreateUserNotification(gomock.Any()).DoAndReturn(
+		func(notification *model.UserNotification) (*model.UserNotification, error) {
+			return notification, nil
+		},
+	)
+
+	created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+	require.NoError(t, err)
+	assert.False(t, created.Read)
+	assert.False(t, created.Archived)
+	assert.False(t, created.Pinned)
+	assert.Empty(t, created.ResolvedAction)
+	assert.Empty(t, created.CoRecipients)
+}
+
+func TestCreateAndBroadcastNotificationAssigneeCheck(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-1",
+		CardProperties: []map[string]interface{}{
+			{"id": "assignee", "name": "Assignee", "type": "person"},
+			{"id": "reviewers", "name": "Reviewers", "type": "multiPerson"},
+		},
+	}
+	card := &model.Block{
+		ID:      "card-1",
+		BoardID: "board-1",
+		Type:    model.TypeCard,
+		Fields: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"assignee":  "user-1",
+				"reviewers": []interface{}{"user-2"},
+			},
+		},
+	}
+
+	t.Run("assigned target is an assignee", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-2", Type: model.NotificationTypeAssigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		th.Store.EXPECT().GetBlock("card-1").Return(card, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences(notification.TargetUserID, "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences(notification.TargetUserID).Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("assigned target is not an assignee", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-3", Type: model.NotificationTypeAssigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-3").Return(&model.User{ID: "user-3"}, nil)
+		th.Store.EXPECT().GetBlock("card-1").Return(card, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, created)
+	})
+
+	t.Run("unassigned target is still an assignee", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeUnassigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetBlock("card-1").Return(card, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, created)
+	})
+
+	t.Run("check skipped", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-3", Type: model.NotificationTypeAssigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-3").Return(&model.User{ID: "user-3"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences(notification.TargetUserID, "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences(notification.TargetUserID).Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{SkipAssigneeCheck: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("mentions are not checked", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-3", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-3").Return(&model.User{ID: "user-3"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences(notification.TargetUserID, "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences(notification.TargetUserID).Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+}
+
+func TestGetUserNotificationsNewFlag(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notifications := []*model.UserNotification{
+		{ID: "n-1", TargetUserID: "user-1", CreateAt: 300},
+		{ID: "n-2", TargetUserID: "user-1", CreateAt: 100},
+	}
+
+	t.Run("everything is new without last seen", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", model.QueryUserNotificationsOptions{}).Return(notifications, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		result, err := th.App.GetUserNotifications("user-1", model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		assert.True(t, result[0].New)
+		assert.True(t, result[1].New)
+	})
+
+	t.Run("only notifications after last seen are new", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", model.QueryUserNotificationsOptions{}).Return(notifications, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{
+			{UserId: "user-1", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationLastSeen, Value: "200"},
+		}, nil)
+
+		result, err := th.App.GetUserNotifications("user-1", model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		assert.True(t, result[0].New)
+		assert.False(t, result[1].New)
+	})
+}
+
+func TestGetUserNotificationsMarkingRead(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	opts := model.QueryUserNotificationsOptions{Limit: 2}
+
+	t.Run("broadcasts the unread count when unread notifications were marked", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{ID: "n-1", TargetUserID: "user-1", CreateAt: 300},
+			{ID: "n-2", TargetUserID: "user-1", Read: true, CreateAt: 100},
+		}
+		th.Store.EXPECT().GetUserNotificationsMarkingRead("user-1", opts).Return(notifications, nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(4, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		result, err := th.App.GetUserNotificationsMarkingRead("user-1", opts)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.False(t, result[0].Read)
+		assert.True(t, result[0].New)
+	})
+
+	t.Run("no broadcast if everything was read already", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{ID: "n-2", TargetUserID: "user-1", Read: true, CreateAt: 100},
+		}
+		th.Store.EXPECT().GetUserNotificationsMarkingRead("user-1", opts).Return(notifications, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		result, err := th.App.GetUserNotificationsMarkingRead("user-1", opts)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
+
+func TestNotificationDeviceReads(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("reads without a device only set the read state", func(t *testing.T) {
+		th.Store.EXPECT().MarkNotificationAsRead("n-1", "user-1").Return(nil)
+
+		require.NoError(t, th.App.MarkNotificationAsReadOnDevice("n-1", "user-1", ""))
+	})
+
+	t.Run("reads on a device are recorded", func(t *testing.T) {
+		th.Store.EXPECT().MarkNotificationAsReadOnDevice("n-1", "user-1", "phone").Return(nil)
+
+		require.NoError(t, th.App.MarkNotificationAsReadOnDevice("n-1", "user-1", "phone"))
+	})
+
+	t.Run("device IDs are limited in length", func(t *testing.T) {
+		err := th.App.MarkNotificationAsReadOnDevice("n-1", "user-1", strings.Repeat("d", model.NotificationDeviceIDMaxLength+1))
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("a single notification has its device reads", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Read: true, CreateAt: 100}
+		reads := []*model.NotificationDeviceRead{{NotificationID: "n-1", DeviceID: "phone", ReadAt: 200}}
+		th.Store.EXPECT().GetUserNotification("n-1", "user-1").Return(notification, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetNotificationDeviceReads("n-1").Return(reads, nil)
+
+		result, err := th.App.GetUserNotification("n-1", "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, reads, result.DeviceReads)
+	})
+}
+
+func TestGetOldestUnreadNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("timestamp of the oldest unread notification", func(t *testing.T) {
+		th.Store.EXPECT().GetOldestUnreadNotificationTime("user-1").Return(int64(1000), nil)
+
+		oldest, err := th.App.GetOldestUnreadNotification("user-1")
+		require.NoError(t, err)
+		require.NotNil(t, oldest.Timestamp)
+		assert.Equal(t, int64(1000), *oldest.Timestamp)
+	})
+
+	t.Run("no timestamp when nothing is unread", func(t *testing.T) {
+		th.Store.EXPECT().GetOldestUnreadNotificationTime("user-1").Return(int64(0), nil)
+
+		oldest, err := th.App.GetOldestUnreadNotification("user-1")
+		require.NoError(t, err)
+		assert.Nil(t, oldest.Timestamp)
+	})
+}
+
+func TestRenderNotificationMessage(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationTemplates = map[string]string{model.NotificationTypeMentioned: "{actorName} pinged you about {cardTitle}"}
+	defer func() { th.App.config.NotificationTemplates = nil }()
+
+	mentioned := &model.UserNotification{Type: model.NotificationTypeMentioned, ActorName: "Alice", CardTitle: "Release"}
+	th.App.renderNotificationMessage(mentioned)
+	require.Equal(t, "Alice pinged you about Release", mentioned.Message)
+
+	assigned := &model.UserNotification{Type: model.NotificationTypeAssigned, CardTitle: "Release"}
+	th.App.renderNotificationMessage(assigned)
+	require.Equal(t, `Someone added you to "Release"`, assigned.Message)
+}
+
+// panickingAdapter is a websocket adapter whose user notification broadcasts always fail.
+type panickingAdapter struct {
+	ws.Adapter
+}
+
+func (pa *panickingAdapter) BroadcastUserNotification(targetUserID string, notification *model.UserNotification) {
+	panic("broadcast failed")
+}
+
+// unreadCountAdapter is a websocket adapter recording the unread counts it broadcasts.
+type unreadCountAdapter struct {
+	ws.Adapter
+	count        int
+	countsByType map[string]int
+	userIDs      []string
+	connected    []string
+}
+
+func (ua *unreadCountAdapter) BroadcastUnreadNotificationCount(userID string, count int, countsByType map[string]int) {
+	ua.count = count
+	ua.countsByType = countsByType
+	ua.userIDs = append(ua.userIDs, userID)
+}
+
+func (ua *unreadCountAdapter) ConnectedUserIDs() []string {
+	return ua.connected
+}
+
+func TestBroadcastUnreadNotificationCount(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	adapter := &unreadCountAdapter{Adapter: th.App.wsAdapter}
+	th.App.wsAdapter = adapter
+	opts := model.MarkNotificationsAsReadOptions{Type: model.NotificationTypeMentioned}
+
+	t.Run("global count by default", func(t *testing.T) {
+		th.Store.EXPECT().MarkAllNotificationsAsRead("user-1", opts).Return(int64(2), nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(3, nil)
+
+		_, err := th.App.MarkAllNotificationsAsRead("user-1", opts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, adapter.count)
+		assert.Nil(t, adapter.countsByType)
+	})
+
+	t.Run("counts by type when configured", func(t *testing.T) {
+		th.App.config.NotificationUnreadCountsByType = true
+		defer func() { th.App.config.NotificationUnreadCountsByType = false }()
+
+		countsByType := map[string]int{model.NotificationTypeAssigned: 3}
+		th.Store.EXPECT().MarkAllNotificationsAsRead("user-1", opts).Return(int64(2), nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(3, nil)
+		th.Store.EXPECT().GetUnreadNotificationCountByType("user-1").Return(countsByType, nil)
+
+		_, err := th.App.MarkAllNotificationsAsRead("user-1", opts)
+		require.NoError(t, err)
+		assert.Equal(t, 3, adapter.count)
+		assert.Equal(t, countsByType, adapter.countsByType)
+	})
+
+	t.Run("nothing marked, nothing broadcast", func(t *testing.T) {
+		adapter.count = -1
+		th.Store.EXPECT().MarkAllNotificationsAsRead("user-1", opts).Return(int64(0), nil)
+
+		_, err := th.App.MarkAllNotificationsAsRead("user-1", opts)
+		require.NoError(t, err)
+		assert.Equal(t, -1, adapter.count)
+	})
+}
+
+func TestRecomputeUnreadNotificationCounts(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	adapter := &unreadCountAdapter{Adapter: th.App.wsAdapter, connected: []string{"user-1", "user-2"}}
+	th.App.wsAdapter = adapter
+
+	t.Run("every connected user", func(t *testing.T) {
+		adapter.userIDs = nil
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(3, nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-2").Return(0, nil)
+
+		count, err := th.App.RecomputeUnreadNotificationCounts("")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"user-1", "user-2"}, adapter.userIDs)
+	})
+
+	t.Run("a single user", func(t *testing.T) {
+		adapter.userIDs = nil
+		th.Store.EXPECT().GetUserByID("user-3").Return(&model.User{ID: "user-3"}, nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-3").Return(5, nil)
+
+		count, err := th.App.RecomputeUnreadNotificationCounts("user-3")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, []string{"user-3"}, adapter.userIDs)
+		assert.Equal(t, 5, adapter.count)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		adapter.userIDs = nil
+		th.Store.EXPECT().GetUserByID("missing").Return(nil, model.NewErrNotFound("user ID=missing"))
+
+		count, err := th.App.RecomputeUnreadNotificationCounts("missing")
+		require.True(t, model.IsErrNotFound(err))
+		assert.Zero(t, count)
+		assert.Empty(t, adapter.userIDs)
+	})
+}
+
+func TestCreateAndBroadcastNotificationBroadcastFailure(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.wsAdapter = &panickingAdapter{Adapter: th.App.wsAdapter}
+
+	notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+	stored := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+	th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+	th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+	th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+	th.Store.EXPECT().CreateUserNotification(notification).Return(stored, nil)
+
+	require.NotPanics(t, func() {
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, stored, created)
+	})
+}
+
+func TestCreateAndBroadcastNotificationPreferences(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	muted := true
+	teamDefaults := &model.NotificationPreferencesOverrides{Muted: &muted}
+
+	t.Run("suppressed by team defaults", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(teamDefaults, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("user override wins over team defaults", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-2", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(teamDefaults, nil)
+		th.Store.EXPECT().GetUserPreferences("user-2").Return(mmModel.Preferences{
+			{UserId: "user-2", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationPreferences, Value: `{"muted":false}`},
+		}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+}
+
+func TestGetNotificationPreferences(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	muted := true
+	teamDefaults := &model.NotificationPreferencesOverrides{Muted: &muted}
+
+	t.Run("inherits the team defaults", func(t *testing.T) {
+		th.Store.EXPECT().GetNotificationTeamDefaults("team-1").Return(teamDefaults, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		preferences, err := th.App.GetNotificationPreferences("user-1", "team-1")
+		require.NoError(t, err)
+		assert.True(t, preferences.Muted)
+		assert.Equal(t, model.NotificationPreferenceSourceTeam, preferences.Sources["muted"])
+	})
+
+	t.Run("no team leaves the team defaults out", func(t *testing.T) {
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		preferences, err := th.App.GetNotificationPreferences("user-1", "")
+		require.NoError(t, err)
+		assert.False(t, preferences.Muted)
+		assert.Equal(t, model.NotificationPreferenceSourceDefault, preferences.Sources["muted"])
+	})
+}
+
+func TestSendTestNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	muted := `{"muted":true}`
+	mutedPreferences := mmModel.Preferences{
+		{UserId: "user-1", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationPreferences, Value: muted},
+	}
+
+	t.Run("respects preferences", func(t *testing.T) {
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mutedPreferences, nil)
+
+		notification, err := th.App.SendTestNotification("user-1", false)
+		require.NoError(t, err)
+		require.Nil(t, notification)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		notification, err := th.App.SendTestNotification("user-1", false)
+		require.True(t, model.IsErrTooManyRequests(err))
+		require.Nil(t, notification)
+	})
+
+	t.Run("bypass ignores preferences", func(t *testing.T) {
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		th.Store.EXPECT().CreateUserNotification(utils.Anything).DoAndReturn(
+			func(notification *model.UserNotification) (*model.UserNotification, error) {
+				return notification, nil
+			})
+
+		notification, err := th.App.SendTestNotification("user-2", true)
+		require.NoError(t, err)
+		require.NotNil(t, notification)
+		assert.Equal(t, "user-2", notification.TargetUserID)
+		assert.Equal(t, model.NotificationTypeTest, notification.Type)
+		assert.Equal(t, model.NotificationReasonDirect, notification.Reason)
+		assert.Equal(t, model.NotificationSourceAPI, notification.Source)
+	})
+
+	t.Run("attempts that left the interval are forgotten", func(t *testing.T) {
+		th.App.testNotificationMux.Lock()
+		th.App.testNotificationSent["user-1"] = time.Now().Add(-2 * testNotificationInterval)
+		th.App.testNotificationMux.Unlock()
+
+		require.True(t, th.App.allowTestNotification("user-3"))
+
+		th.App.testNotificationMux.Lock()
+		defer th.App.testNotificationMux.Unlock()
+		assert.NotContains(t, th.App.testNotificationSent, "user-1")
+		assert.Contains(t, th.App.testNotificationSent, "user-3")
+	})
+}
+
+func TestOpenNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	t.Run("not owned by the user", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotification("n-1", "user-2").Return(nil, model.NewErrNotFound("notification ID=n-1"))
+
+		opened, err := th.App.OpenNotification("n-1", "user-2")
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, opened)
+	})
+
+	t.Run("marks read and resolves the card link", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserNotification("n-1", "user-1").Return(notification, nil)
+		th.Store.EXPECT().MarkNotificationAsRead("n-1", "user-1").Return(nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-1").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeViewer: true}, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1", TeamID: "team-1"}, nil)
+
+		opened, err := th.App.OpenNotification("n-1", "user-1")
+		require.NoError(t, err)
+		assert.True(t, opened.Notification.Read)
+		assert.Equal(t, "team-1", opened.Target.TeamID)
+		assert.Equal(t, "card-1", opened.Target.CardID)
+		assert.Equal(t, utils.MakeCardLink(th.App.config.ServerRoot, "team-1", "board-1", "card-1"), opened.Target.Link)
+	})
+
+	t.Run("deleted board leaves the link empty", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-2", TargetUserID: "user-1", CardID: "card-1", BoardID: "board-2", Read: true}
+		th.Store.EXPECT().GetUserNotification("n-2", "user-1").Return(notification, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-2", "user-1").
+			Return(&model.BoardMember{BoardID: "board-2", UserID: "user-1", SchemeViewer: true}, nil)
+		th.Store.EXPECT().GetBoard("board-2").Return(nil, model.NewErrNotFound("board ID=board-2"))
+
+		opened, err := th.App.OpenNotification("n-2", "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, "board-2", opened.Target.BoardID)
+		assert.Empty(t, opened.Target.Link)
+	})
+
+	t.Run("board the user can no longer view is not read", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-3", TargetUserID: "user-1", CardID: "card-1", BoardID: "board-3", Read: true}
+		th.Store.EXPECT().GetUserNotification("n-3", "user-1").Return(notification, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-3", "user-1").Return(nil, model.NewErrNotFound("member"))
+
+		opened, err := th.App.OpenNotification("n-3", "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, "board-3", opened.Target.BoardID)
+		assert.Empty(t, opened.Target.TeamID)
+		assert.Empty(t, opened.Target.Link)
+	})
+}
+
+func TestCreateAndBroadcastNotificationCustomTypes(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("unknown type is rejected", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: "deployment", BoardID: "board-1"}
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, created)
+	})
+
+	t.Run("registered type is accepted", func(t *testing.T) {
+		require.NoError(t, th.App.RegisterNotificationType("deployment"))
+
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: "deployment", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+}
+
+func TestCreateAndBroadcastNotificationMinBoardRole(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+	th.App.config.NotificationMinBoardRole = string(model.BoardRoleEditor)
+	defer func() { th.App.config.NotificationMinBoardRole = "" }()
+
+	t.Run("viewers are skipped", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-1").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeViewer: true}, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("editors are notified", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-2").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-2", SchemeEditor: true}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-2", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-2").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+}
+
+func TestCanCreateNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, []string{"admin-1"}, th.logger)
+	th.App.config.RestrictNotificationCreate = true
+	defer func() { th.App.config.RestrictNotificationCreate = false }()
+
+	t.Run("users can't notify users they share no board with", func(t *testing.T) {
+		notification := &model.UserNotification{ActorUserID: "user-1", TargetUserID: "user-2", BoardID: "board-2"}
+		permissionsStore.EXPECT().GetMemberForBoard("board-2", "user-1").Return(nil, model.NewErrNotFound("member"))
+
+		require.False(t, th.App.CanCreateNotification("user-1", notification))
+	})
+
+	t.Run("users can't notify as another actor", func(t *testing.T) {
+		notification := &model.UserNotification{ActorUserID: "user-3", TargetUserID: "user-2", BoardID: "board-1"}
+		require.False(t, th.App.CanCreateNotification("user-1", notification))
+	})
+
+	t.Run("users can't notify others without a board", func(t *testing.T) {
+		notification := &model.UserNotification{ActorUserID: "user-1", TargetUserID: "user-2"}
+		require.False(t, th.App.CanCreateNotification("user-1", notification))
+	})
+
+	t.Run("users can notify members of the same board", func(t *testing.T) {
+		notification := &model.UserNotification{ActorUserID: "user-1", TargetUserID: "user-2", BoardID: "board-1"}
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-1").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeEditor: true}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-2").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-2", SchemeViewer: true}, nil)
+
+		require.True(t, th.App.CanCreateNotification("user-1", notification))
+	})
+
+	t.Run("users can notify themselves", func(t *testing.T) {
+		notification := &model.UserNotification{ActorUserID: "user-1", TargetUserID: "user-1"}
+		require.True(t, th.App.CanCreateNotification("user-1", notification))
+	})
+
+	t.Run("admins can notify anyone", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-2"}
+		require.True(t, th.App.CanCreateNotification("admin-1", notification))
+	})
+
+	t.Run("a batch checks each user and board once", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{ActorUserID: "user-1", TargetUserID: "user-2", BoardID: "board-1"},
+			{ActorUserID: "user-1", TargetUserID: "user-2", BoardID: "board-1"},
+			{ActorUserID: "user-1", TargetUserID: "user-3", BoardID: "board-1"},
+			{ActorUserID: "user-1", TargetUserID: "user-2", BoardID: "board-1"},
+		}
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-1").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeEditor: true}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-2").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-2", SchemeViewer: true}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-3").Return(nil, model.NewErrNotFound("member"))
+
+		i, ok := th.App.CanCreateNotifications("user-1", notifications)
+		require.False(t, ok)
+		require.Equal(t, 2, i)
+	})
+}
+
+func TestCreateAndBroadcastNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationBatchMaxSize = 2
+	defer func() { th.App.config.NotificationBatchMaxSize = 0 }()
+
+	t.Run("batch larger than the maximum", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-3", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+		}
+
+		created, _, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, created)
+	})
+
+	t.Run("invalid notifications are skipped by their index", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-2", CardID: "card-1"},
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotifications(notifications[:1]).Return(notifications[:1], nil)
+
+		created, skipped, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.Len(t, skipped, 1)
+		require.Equal(t, 1, skipped[0].Index)
+	})
+
+	t.Run("unknown targets are skipped by their index", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "unknown", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+		}
+		th.Store.EXPECT().GetUserByID("unknown").Return(nil, model.NewErrNotFound("user"))
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotifications(notifications[1:]).Return(notifications[1:], nil)
+
+		created, skipped, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notifications[1:], created)
+		require.Equal(t, []*model.SkippedNotification{{Index: 0, Error: "{target user ID=unknown} not found"}}, skipped)
+	})
+
+	t.Run("each target is looked up once", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-2"},
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil).Times(2)
+		th.Store.EXPECT().CreateUserNotifications(notifications).Return(notifications, nil)
+
+		created, _, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+	})
+
+	t.Run("suppressed notifications are left out", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+			{TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1"},
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-2").Return(mmModel.Preferences{
+			{UserId: "user-2", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationPreferences, Value: `{"muted":true}`},
+		}, nil)
+		th.Store.EXPECT().CreateUserNotifications([]*model.UserNotification{notifications[0]}).Return([]*model.UserNotification{notifications[0]}, nil)
+
+		created, _, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []*model.UserNotification{notifications[0]}, created)
+	})
+}
+
+func TestGetNotificationCapabilities(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.NotificationBatchMaxSize = 500
+	th.App.config.NotificationDigestIntervalMinutes = 60
+	defer func() {
+		th.App.config.NotificationBatchMaxSize = 0
+		th.App.config.NotificationDigestIntervalMinutes = 0
+	}()
+
+	capabilities := th.App.GetNotificationCapabilities()
+	require.Len(t, capabilities.Types, len(th.App.GetNotificationTypes()))
+	for _, capability := range capabilities.Types {
+		if capability.Type == model.NotificationTypeAssigned {
+			require.Equal(t, model.NotificationCategoryTasks, capability.Category)
+			require.Len(t, capability.Actions, 2)
+		}
+	}
+	require.ElementsMatch(t, []string{model.NotificationCategoryMentions, model.NotificationCategoryTasks, model.NotificationCategorySystem}, capabilities.Categories)
+	require.True(t, capabilities.Digests)
+	require.False(t, capabilities.DoNotDisturb)
+	require.False(t, capabilities.Queued)
+	require.Equal(t, 500, capabilities.Limits.BatchMaxSize)
+	require.Equal(t, notificationReadSyncMax, capabilities.Limits.ReadSyncMax)
+}
+
+func TestPreviewNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	t.Run("resolves the notification without storing it", func(t *testing.T) {
+		notification := &model.UserNotification{
+			TargetUserID: "user-1",
+			ActorUserID:  "user-2",
+			ActorName:    "Jane",
+			Type:         model.NotificationTypeAssigned,
+			CardID:       "card-1",
+			CardTitle:    "Card",
+			BoardID:      "board-1",
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-2").
+			Return(&model.BoardMember{BoardID: "board-1", UserID: "user-2", SchemeEditor: true}, nil)
+		th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1", TeamID: "team-1"}, nil)
+
+		preview, err := th.App.PreviewNotification("user-2", notification)
+		require.NoError(t, err)
+		assert.Equal(t, utils.MakeCardLink(th.App.config.ServerRoot, "team-1", "board-1", "card-1"), preview.Target.Link)
+		assert.Equal(t, utils.MakeAvatarLink(th.App.config.ServerRoot, "user-2"), preview.ActorAvatarURL)
+		assert.Equal(t, "NotificationBell.youAssigned", preview.MessageKey)
+		assert.Equal(t, map[string]string{"actorName": "Jane", "cardTitle": "Card"}, preview.MessageParams)
+		assert.True(t, preview.Notification.HasAction(model.NotificationActionUnassign))
+		assert.Empty(t, preview.Notification.ID)
+	})
+
+	t.Run("board the user cannot view is not read", func(t *testing.T) {
+		notification := &model.UserNotification{
+			TargetUserID: "user-1",
+			Type:         model.NotificationTypeAssigned,
+			CardID:       "card-1",
+			BoardID:      "private-board",
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		permissionsStore.EXPECT().GetMemberForBoard("private-board", "user-2").Return(nil, model.NewErrNotFound("member"))
+
+		preview, err := th.App.PreviewNotification("user-2", notification)
+		require.NoError(t, err)
+		assert.Empty(t, preview.Target.TeamID)
+		assert.Empty(t, preview.Target.Link)
+	})
+
+	t.Run("invalid notifications are rejected", func(t *testing.T) {
+		_, err := th.App.PreviewNotification("user-2", &model.UserNotification{TargetUserID: "user-1", Type: "unknown"})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestSyncNotificationReadStates(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("clamps future changes and skips ephemeral notifications", func(t *testing.T) {
+		future := utils.GetMillis() + 60*60*1000
+		changes := []*model.NotificationReadSync{
+			{ID: "n-1", Read: true, ReadAt: future},
+			{ID: utils.NewID(utils.IDTypeEphemeral), Read: true, ReadAt: 1000},
+		}
+		th.Store.EXPECT().SyncNotificationReadStates("user-1", gomock.Len(1)).DoAndReturn(
+			func(userID string, changes []*model.NotificationReadSync) ([]*model.UserNotification, error) {
+				require.Equal(t, "n-1", changes[0].ID)
+				require.Less(t, changes[0].ReadAt, future)
+				return []*model.UserNotification{{ID: "n-1", Read: true}}, nil
+			},
+		)
+
+		notifications, err := th.App.SyncNotificationReadStates("user-1", changes)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+	})
+
+	t.Run("rejects changes without a time", func(t *testing.T) {
+		notifications, err := th.App.SyncNotificationReadStates("user-1", []*model.NotificationReadSync{{ID: "n-1", Read: true}})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, notifications)
+	})
+}
+
+func TestMarkNotificationsAsRead(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("skips ephemeral notifications", func(t *testing.T) {
+		ids := []string{"n-1", utils.NewID(utils.IDTypeEphemeral), "n-2"}
+		th.Store.EXPECT().MarkNotificationsAsRead([]string{"n-1", "n-2"}, "user-1").Return(int64(2), nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(0, nil)
+
+		count, err := th.App.MarkNotificationsAsRead(ids, "user-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+	})
+
+	t.Run("rejects too many notifications", func(t *testing.T) {
+		ids := make([]string, notificationsMarkReadMax+1)
+		for i := range ids {
+			ids[i] = utils.NewID(utils.IDTypeNone)
+		}
+
+		count, err := th.App.MarkNotificationsAsRead(ids, "user-1")
+		require.True(t, model.IsErrBadRequest(err))
+		require.Zero(t, count)
+	})
+}
+
+func TestPerformNotificationAction(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("dismiss archives and resolves", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Type: model.NotificationTypeMentioned}
+		notification.SetActions()
+		th.Store.EXPECT().GetUserNotification("n-1", "user-1").Return(notification, nil)
+		gomock.InOrder(
+			th.Store.EXPECT().ResolveUserNotification("n-1", "user-1", model.NotificationActionDismiss).Return(nil),
+			th.Store.EXPECT().SetNotificationArchived("n-1", "user-1", true).Return(nil),
+		)
+
+		resolved, err := th.App.PerformNotificationAction("n-1", "user-1", model.NotificationActionDismiss)
+		require.NoError(t, err)
+		assert.True(t, resolved.Archived)
+		assert.True(t, resolved.Read)
+		assert.Equal(t, model.NotificationActionDismiss, resolved.ResolvedAction)
+		assert.Empty(t, resolved.Actions)
+	})
+
+	t.Run("rejects undeclared actions", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-2", TargetUserID: "user-1", Type: model.NotificationTypeMentioned}
+		notification.SetActions()
+		th.Store.EXPECT().GetUserNotification("n-2", "user-1").Return(notification, nil)
+
+		resolved, err := th.App.PerformNotificationAction("n-2", "user-1", model.NotificationActionUnassign)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, resolved)
+	})
+
+	t.Run("rejects resolved notifications", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-3", TargetUserID: "user-1", Type: model.NotificationTypeMentioned, ResolvedAction: model.NotificationActionDismiss}
+		th.Store.EXPECT().GetUserNotification("n-3", "user-1").Return(notification, nil)
+
+		resolved, err := th.App.PerformNotificationAction("n-3", "user-1", model.NotificationActionDismiss)
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, resolved)
+	})
+
+	t.Run("a concurrent resolve wins", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-4", TargetUserID: "user-1", Type: model.NotificationTypeMentioned}
+		notification.SetActions()
+		th.Store.EXPECT().GetUserNotification("n-4", "user-1").Return(notification, nil)
+		th.Store.EXPECT().ResolveUserNotification("n-4", "user-1", model.NotificationActionDismiss).
+			Return(model.NewErrNotFound("unresolved notification ID=n-4"))
+
+		resolved, err := th.App.PerformNotificationAction("n-4", "user-1", model.NotificationActionDismiss)
+		require.True(t, model.IsErrConflict(err))
+		require.Nil(t, resolved)
+	})
+
+	t.Run("a failed action is unresolved", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-5", TargetUserID: "user-1", Type: model.NotificationTypeMentioned}
+		notification.SetActions()
+		th.Store.EXPECT().GetUserNotification("n-5", "user-1").Return(notification, nil)
+		th.Store.EXPECT().ResolveUserNotification("n-5", "user-1", model.NotificationActionDismiss).Return(nil)
+		th.Store.EXPECT().SetNotificationArchived("n-5", "user-1", true).Return(errors.New("store error"))
+		th.Store.EXPECT().UnresolveUserNotification("n-5", "user-1", model.NotificationActionDismiss, false).Return(nil)
+
+		resolved, err := th.App.PerformNotificationAction("n-5", "user-1", model.NotificationActionDismiss)
+		require.Error(t, err)
+		require.Nil(t, resolved)
+	})
+}
+
+func TestPatchUserNotification(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("updates the mutable fields", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardTitle: "Typo", ActorName: "bob", CreateAt: 1000}
+		th.Store.EXPECT().GetUserNotificationByID("n-1").Return(notification, nil)
+		th.Store.EXPECT().UpdateUserNotification(utils.Anything).Return(nil)
+
+		title := "Fixed"
+		createAt := int64(1000)
+		patched, err := th.App.PatchUserNotification("n-1", &model.UserNotificationPatch{CardTitle: &title, CreateAt: &createAt})
+		require.NoError(t, err)
+		assert.Equal(t, "Fixed", patched.CardTitle)
+		assert.Equal(t, "bob", patched.ActorName)
+	})
+
+	t.Run("rejects changing the target", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "n-2", TargetUserID: "user-1", Type: model.NotificationTypeMentioned}
+		th.Store.EXPECT().GetUserNotificationByID("n-2").Return(notification, nil)
+
+		target := "user-2"
+		patched, err := th.App.PatchUserNotification("n-2", &model.UserNotificationPatch{TargetUserID: &target})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, patched)
+	})
+
+	t.Run("unknown notification", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotificationByID("n-3").Return(nil, model.NewErrNotFound("notification ID=n-3"))
+
+		patched, err := th.App.PatchUserNotification("n-3", &model.UserNotificationPatch{})
+		require.True(t, model.IsErrNotFound(err))
+		require.Nil(t, patched)
+	})
+}
+
+func TestMarkNotificationsAsReadReturningIDs(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("scoped sweep returns the IDs", func(t *testing.T) {
+		opts := model.MarkNotificationsAsReadOptions{Type: model.NotificationTypeMentioned}
+		th.Store.EXPECT().MarkNotificationsAsReadReturningIDs("user-1", opts).Return([]string{"n-1", "n-2"}, nil)
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(3, nil)
+
+		ids, err := th.App.MarkNotificationsAsReadReturningIDs("user-1", opts)
+		require.NoError(t, err)
+		require.Equal(t, []string{"n-1", "n-2"}, ids)
+	})
+
+	t.Run("unscoped sweep is rejected", func(t *testing.T) {
+		ids, err := th.App.MarkNotificationsAsReadReturningIDs("user-1", model.MarkNotificationsAsReadOptions{})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, ids)
+	})
+}
+
+func TestAttachNotificationCards(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	board := &model.Board{
+		ID: "board-1",
+		CardProperties: []map[string]interface{}{
+			{
+				"id":   "status",
+				"name": "Status",
+				"type": "select",
+				"options": []interface{}{
+					map[string]interface{}{"id": "done", "value": "Done", "color": "propColorGreen"},
+				},
+			},
+		},
+	}
+	renamed := &model.Block{ID: "card-1", BoardID: "board-1", Type: model.TypeCard, Title: "Renamed", UpdateAt: 2000,
+		Fields: map[string]interface{}{"properties": map[string]interface{}{"status": "done"}}}
+	unset := &model.Block{ID: "card-2", BoardID: "board-1", Type: model.TypeCard, Title: "No status", UpdateAt: 1000}
+	comment := &model.Block{ID: "comment-1", BoardID: "board-1", Type: model.TypeComment, Title: "A comment"}
+	private := &model.Block{ID: "card-4", BoardID: "board-2", Type: model.TypeCard, Title: "Private"}
+
+	permissionsStore := permissionsMocks.NewMockStore(gomock.NewController(t))
+	th.App.permissions = localpermissions.New(permissionsStore, false, nil, th.logger)
+
+	notifications := []*model.UserNotification{
+		{ID: "n-1", CardID: "card-1", CardTitle: "Old title", BoardID: "board-1"},
+		{ID: "n-2", CardID: "card-1", CardTitle: "Old title", BoardID: "board-1"},
+		{ID: "n-3", CardID: "card-2", BoardID: "board-1"},
+		{ID: "n-4", CardID: "card-3", BoardID: "board-1"},
+		{ID: "n-5", Type: model.NotificationTypeTest},
+		// blocks that aren't cards, of boards the user can't view or of another board are not embedded
+		{ID: "n-6", CardID: "comment-1", BoardID: "board-1"},
+		{ID: "n-7", CardID: "card-4", BoardID: "board-2"},
+		{ID: "n-8", CardID: "card-1", BoardID: "board-3"},
+	}
+
+	// the cards are fetched at once and the board and its permissions only once
+	th.Store.EXPECT().GetBlocksByIDs([]string{"card-1", "card-2", "card-3", "comment-1", "card-4"}).
+		Return([]*model.Block{renamed, unset, comment, private}, model.NewErrNotAllFound("block", []string{"card-3"}))
+	th.Store.EXPECT().GetBoard("board-1").Return(board, nil)
+	permissionsStore.EXPECT().GetMemberForBoard("board-1", "user-1").
+		Return(&model.BoardMember{BoardID: "board-1", UserID: "user-1", SchemeViewer: true}, nil)
+	permissionsStore.EXPECT().GetMemberForBoard("board-2", "user-1").
+		Return(nil, model.NewErrNotFound("member"))
+
+	require.NoError(t, th.App.AttachNotificationCards("user-1", notifications))
+
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "card-1", Title: "Renamed", Status: "Done", UpdateAt: 2000}, notifications[0].Card)
+	assert.Same(t, notifications[0].Card, notifications[1].Card)
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "card-2", Title: "No status", UpdateAt: 1000}, notifications[2].Card)
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "card-3", Deleted: true}, notifications[3].Card)
+	assert.Nil(t, notifications[4].Card)
+	assert.Equal(t, "Old title", notifications[0].CardTitle)
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "comment-1", Deleted: true}, notifications[5].Card)
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "card-4", Deleted: true}, notifications[6].Card)
+	assert.Equal(t, &model.NotificationCardSnapshot{ID: "card-1", Deleted: true}, notifications[7].Card)
+}
+
+func TestReassignNotificationsBoard(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("moves the notifications to the team of the new board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-1").Return(&model.Board{ID: "board-1", TeamID: "team-1"}, nil)
+		th.Store.EXPECT().GetBoard("board-2").Return(&model.Board{ID: "board-2", TeamID: "team-2"}, nil)
+		th.Store.EXPECT().ReassignNotificationsBoard("board-1", "board-2", "team-2").Return(int64(3), nil)
+
+		count, err := th.App.ReassignNotificationsBoard(&model.NotificationBoardReassign{From: "board-1", To: "board-2"})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+
+	t.Run("same board", func(t *testing.T) {
+		count, err := th.App.ReassignNotificationsBoard(&model.NotificationBoardReassign{From: "board-1", To: "board-1"})
+		require.True(t, model.IsErrBadRequest(err))
+		require.Zero(t, count)
+	})
+
+	t.Run("unknown board", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-3").Return(nil, model.NewErrNotFound("board ID=board-3"))
+
+		count, err := th.App.ReassignNotificationsBoard(&model.NotificationBoardReassign{From: "board-3", To: "board-2"})
+		require.True(t, model.IsErrNotFound(err))
+		require.Zero(t, count)
+	})
+
+	t.Run("force skips missing boards", func(t *testing.T) {
+		th.Store.EXPECT().GetBoard("board-3").Return(nil, model.NewErrNotFound("board ID=board-3"))
+		th.Store.EXPECT().GetBoard("board-4").Return(nil, model.NewErrNotFound("board ID=board-4"))
+		th.Store.EXPECT().ReassignNotificationsBoard("board-3", "board-4", "").Return(int64(1), nil)
+
+		count, err := th.App.ReassignNotificationsBoard(&model.NotificationBoardReassign{From: "board-3", To: "board-4", Force: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+}
+
+func TestCreateAndBroadcastNotificationEphemeral(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+	th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+	th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+	th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+	th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+	created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Ephemeral: true})
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.True(t, created.Ephemeral)
+	assert.True(t, model.IsEphemeralNotificationID(created.ID))
+	assert.NotZero(t, created.CreateAt)
+
+	t.Run("can't be referenced later", func(t *testing.T) {
+		require.True(t, model.IsErrBadRequest(th.App.MarkNotificationAsRead(created.ID, "user-1")))
+		require.True(t, model.IsErrBadRequest(th.App.ArchiveNotification(created.ID, "user-1")))
+		require.True(t, model.IsErrBadRequest(th.App.DeleteUserNotification(created.ID, "user-1")))
+
+		opened, err := th.App.OpenNotification(created.ID, "user-1")
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, opened)
+	})
+}
+
+func TestCreateAndBroadcastNotificationBoardPreferences(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	boardPreferences := []*model.NotificationBoardPreference{
+		{UserID: "user-1", BoardID: "board-1", Type: model.NotificationTypeMentioned, Enabled: true},
+		{UserID: "user-1", BoardID: "board-1", Type: model.NotificationTypeAssigned, Enabled: false},
+	}
+
+	t.Run("board override enables a type the global preferences mute", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(boardPreferences, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("board override disables a type", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeAssigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(boardPreferences, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{SkipAssigneeCheck: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("types without an override fall back to global preferences", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeUnassigned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(boardPreferences, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{
+			{UserId: "user-1", Category: model.PreferencesCategoryFocalboard, Name: model.PreferenceNameNotificationPreferences, Value: `{"muted":true}`},
+		}, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{SkipAssigneeCheck: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+}
+
+func TestSetNotificationBoardPreferences(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("unknown type", func(t *testing.T) {
+		preferences := []*model.NotificationBoardPreference{{Type: "bogus", Enabled: true}}
+
+		result, err := th.App.SetNotificationBoardPreferences("user-1", "board-1", preferences)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+
+	t.Run("duplicate type", func(t *testing.T) {
+		preferences := []*model.NotificationBoardPreference{
+			{Type: model.NotificationTypeMentioned, Enabled: true},
+			{Type: model.NotificationTypeMentioned, Enabled: false},
+		}
+
+		result, err := th.App.SetNotificationBoardPreferences("user-1", "board-1", preferences)
+		require.True(t, model.IsErrBadRequest(err))
+		require.Nil(t, result)
+	})
+
+	t.Run("replaces the overrides", func(t *testing.T) {
+		preferences := []*model.NotificationBoardPreference{{Type: model.NotificationTypeMentioned, Enabled: false}}
+		stored := []*model.NotificationBoardPreference{{UserID: "user-1", BoardID: "board-1", Type: model.NotificationTypeMentioned, Enabled: false}}
+		th.Store.EXPECT().SetNotificationBoardPreferences("user-1", "board-1", preferences).Return(nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(stored, nil)
+
+		result, err := th.App.SetNotificationBoardPreferences("user-1", "board-1", preferences)
+		require.NoError(t, err)
+		require.Equal(t, stored, result)
+	})
+}
+
+func TestCreateAndBroadcastNotificationBlockedActor(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("notifications from a blocked actor are suppressed", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", ActorUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "user-2").Return(true, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("notifications from other actors are delivered", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", ActorUserID: "user-3", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "user-3").Return(false, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("system notifications are never blocked", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", ActorUserID: model.SystemUserID, Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-1", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("users can't block themselves", func(t *testing.T) {
+		require.True(t, model.IsErrBadRequest(th.App.BlockNotificationActor("user-1", "user-1")))
+		require.True(t, model.IsErrBadRequest(th.App.BlockNotificationActor("user-1", "")))
+	})
+}
+
+func TestGetCardNotifications(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notifications := []*model.UserNotification{
+		{ID: "n-1", TargetUserID: "user-1", CardID: "card-1", Read: true, CreateAt: 300},
+		{ID: "n-2", TargetUserID: "user-1", CardID: "card-1", Read: true, CreateAt: 100},
+	}
+	opts := model.QueryUserNotificationsOptions{CardID: "card-1"}
+
+	t.Run("marks the notifications of the card as read first", func(t *testing.T) {
+		gomock.InOrder(
+			th.Store.EXPECT().MarkAllNotificationsAsRead("user-1", model.MarkNotificationsAsReadOptions{CardID: "card-1"}).Return(int64(2), nil),
+			th.Store.EXPECT().GetUserNotifications("user-1", opts).Return(notifications, nil),
+		)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		page, err := th.App.GetCardNotifications("user-1", "card-1", model.QueryUserNotificationsOptions{}, true)
+		require.NoError(t, err)
+		require.Equal(t, notifications, page.Results)
+		require.False(t, page.HasMore)
+	})
+
+	t.Run("leaves the read state alone without markRead", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", opts).Return(notifications, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		page, err := th.App.GetCardNotifications("user-1", "card-1", model.QueryUserNotificationsOptions{}, false)
+		require.NoError(t, err)
+		require.Len(t, page.Results, 2)
+	})
+
+	t.Run("missing card", func(t *testing.T) {
+		_, err := th.App.GetCardNotifications("user-1", "", model.QueryUserNotificationsOptions{}, true)
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
+
+func TestGetUserNotificationsPage(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	notifications := []*model.UserNotification{
+		{ID: "n-1", TargetUserID: "user-1", CreateAt: 300},
+		{ID: "n-2", TargetUserID: "user-1", CreateAt: 100},
+	}
+	opts := model.QueryUserNotificationsOptions{BoardIDs: []string{"board-1"}, Limit: 2}
+	next := opts
+	next.Cursor = model.NewNotificationsCursor(notifications[1], "")
+	next.Limit = 1
+
+	t.Run("a full page looks ahead", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", opts).Return(notifications, nil)
+		th.Store.EXPECT().GetUserNotifications("user-1", next).Return([]*model.UserNotification{{ID: "n-3", TargetUserID: "user-1"}}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil).Times(2)
+
+		page, err := th.App.GetUserNotificationsPage("user-1", opts, false)
+		require.NoError(t, err)
+		assert.Equal(t, notifications, page.Results)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, next.Cursor, page.NextCursor)
+		assert.Equal(t, "n-2", page.NextBefore)
+	})
+
+	t.Run("the last full page has no next cursor", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", opts).Return(notifications, nil)
+		th.Store.EXPECT().GetUserNotifications("user-1", next).Return([]*model.UserNotification{}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil).Times(2)
+
+		page, err := th.App.GetUserNotificationsPage("user-1", opts, false)
+		require.NoError(t, err)
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+		assert.Empty(t, page.NextBefore)
+	})
+
+	t.Run("a short page is the last one", func(t *testing.T) {
+		th.Store.EXPECT().GetUserNotifications("user-1", opts).Return(notifications[:1], nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil)
+
+		page, err := th.App.GetUserNotificationsPage("user-1", opts, false)
+		require.NoError(t, err)
+		assert.Len(t, page.Results, 1)
+		assert.False(t, page.HasMore)
+	})
+}
+
+func TestNotificationsOfDeactivatedUsers(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("deactivated targets are not notified", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1", DeleteAt: 1000}, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Nil(t, created)
+	})
+
+	t.Run("deactivated targets are left out of batches", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"},
+			{TargetUserID: "user-2", Type: model.NotificationTypeMentioned, CardID: "card-1", BoardID: "board-1"},
+		}
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1", DeleteAt: 1000}, nil)
+		th.Store.EXPECT().GetUserByID("user-2").Return(&model.User{ID: "user-2"}, nil)
+		th.Store.EXPECT().GetNotificationBoardPreferences("user-2", "board-1").Return(nil, nil)
+		th.Store.EXPECT().GetNotificationTeamDefaultsForBoard("board-1").Return(nil, nil)
+		th.Store.EXPECT().GetUserPreferences("user-2").Return(mmModel.Preferences{}, nil)
+		th.Store.EXPECT().CreateUserNotifications([]*model.UserNotification{notifications[1]}).Return([]*model.UserNotification{notifications[1]}, nil)
+
+		created, _, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		require.Equal(t, "user-2", created[0].TargetUserID)
+	})
+
+	t.Run("purges the notifications of a user", func(t *testing.T) {
+		th.Store.EXPECT().DeleteNotificationsForUser("user-1").Return(int64(3), nil)
+		// for the unread count broadcast to the user
+		th.Store.EXPECT().GetUnreadNotificationCount("user-1").Return(0, nil).MaxTimes(1)
+
+		count, err := th.App.PurgeNotificationsForUser("user-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+}
+
+func TestCreateAndBroadcastNotificationCollapseKey(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	expectDelivered := func(userID string) {
+		th.Store.EXPECT().GetUserByID(userID).Return(&model.User{ID: userID}, nil)
+		th.Store.EXPECT().GetUserPreferences(userID).Return(mmModel.Preferences{}, nil)
+	}
+
+	t.Run("replaces the unread notification with the key", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", ActorName: "bob", Type: model.NotificationTypeMentioned, CardID: "card-1", CardTitle: "Launch v2", CollapseKey: "card-1-updates"}
+		existing := &model.UserNotification{ID: "n-1", TargetUserID: "user-1", ActorName: "alice", Type: model.NotificationTypeMentioned, CardID: "card-1", CardTitle: "Launch", CollapseKey: "card-1-updates"}
+		expectDelivered("user-1")
+		th.Store.EXPECT().GetUnreadNotificationByCollapseKey("user-1", "card-1-updates", "", model.NotificationSourceServer).Return(existing, nil)
+		th.Store.EXPECT().UpdateUserNotification(existing).Return(nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, "n-1", created.ID)
+		assert.Equal(t, "bob", created.ActorName)
+		assert.Equal(t, "Launch v2", created.CardTitle)
+	})
+
+	t.Run("created if there is none", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardID: "card-1", CollapseKey: "card-1-updates"}
+		expectDelivered("user-1")
+		th.Store.EXPECT().GetUnreadNotificationByCollapseKey("user-1", "card-1-updates", "", model.NotificationSourceServer).Return(nil, model.NewErrNotFound("notification collapseKey=card-1-updates"))
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CollapseKey: strings.Repeat("k", model.NotificationCollapseKeyMaxLength+1)}
+
+		_, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("notifications of other actors are not replaced", func(t *testing.T) {
+		notification := &model.UserNotification{TargetUserID: "user-1", ActorUserID: "bob", Type: model.NotificationTypeMentioned, CardID: "card-1", CollapseKey: "card-1-updates"}
+		expectDelivered("user-1")
+		th.Store.EXPECT().IsNotificationActorBlocked("user-1", "bob").Return(false, nil)
+		// alice's unread notification with the key is not returned for bob
+		th.Store.EXPECT().GetUnreadNotificationByCollapseKey("user-1", "card-1-updates", "bob", model.NotificationSourceServer).Return(nil, model.NewErrNotFound("notification collapseKey=card-1-updates"))
+		th.Store.EXPECT().CreateUserNotification(notification).Return(notification, nil)
+
+		created, err := th.App.CreateAndBroadcastNotification(notification, model.CreateUserNotificationOptions{Synchronous: true})
+		require.NoError(t, err)
+		require.Equal(t, notification, created)
+	})
+
+	t.Run("the last notification of a batch with the key wins", func(t *testing.T) {
+		notifications := []*model.UserNotification{
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardTitle: "first", CollapseKey: "card-1-updates"},
+			{TargetUserID: "user-2", Type: model.NotificationTypeMentioned},
+			{TargetUserID: "user-1", Type: model.NotificationTypeMentioned, CardTitle: "second", CollapseKey: "card-1-updates"},
+		}
+		// each target is only looked up once per batch
+		th.Store.EXPECT().GetUserByID("user-1").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().GetUserPreferences("user-1").Return(mmModel.Preferences{}, nil).Times(2)
+		expectDelivered("user-2")
+		th.Store.EXPECT().GetUnreadNotificationByCollapseKey("user-1", "card-1-updates", "", model.NotificationSourceServer).Return(nil, model.NewErrNotFound("notification collapseKey=card-1-updates"))
+		toCreate := []*model.UserNotification{notifications[2], notifications[1]}
+		th.Store.EXPECT().CreateUserNotifications(toCreate).Return(toCreate, nil)
+
+		created, _, err := th.App.CreateAndBroadcastNotifications(notifications, model.CreateUserNotificationOptions{})
+		require.NoError(t, err)
+		require.Equal(t, toCreate, created)
+	})
+}
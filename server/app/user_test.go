@@ -83,3 +83,73 @@ func TestSearchUsers(t *testing.T) {
 		assert.Equal(t, 0, len(channels))
 	})
 }
+
+func TestUpdateUserEmail(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	t.Run("email is normalized", func(t *testing.T) {
+		user := &model.User{ID: "user-1", Email: " User1@Example.COM "}
+		th.Store.EXPECT().GetUserByEmail("user1@example.com").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().UpdateUser(user).Return(user, nil)
+
+		updated, err := th.App.UpdateUser(user)
+		assert.NoError(t, err)
+		assert.Equal(t, "user1@example.com", updated.Email)
+	})
+
+	t.Run("mixed case email collides with another user", func(t *testing.T) {
+		user := &model.User{ID: "user-1", Email: "Existing@Example.com"}
+		th.Store.EXPECT().GetUserByEmail("existing@example.com").Return(&model.User{ID: "user-2"}, nil)
+
+		_, err := th.App.UpdateUser(user)
+		assert.True(t, model.IsErrConflict(err))
+	})
+}
+
+func TestAllowedEmailDomains(t *testing.T) {
+	th, tearDown := SetupTestHelper(t)
+	defer tearDown()
+
+	th.App.config.AllowedEmailDomains = []string{"example.com", "@Corp.Example.org"}
+	defer func() { th.App.config.AllowedEmailDomains = nil }()
+
+	t.Run("allowed domains", func(t *testing.T) {
+		assert.NoError(t, th.App.CheckEmailDomainAllowed("user@example.com"))
+		assert.NoError(t, th.App.CheckEmailDomainAllowed("user@corp.example.org"))
+	})
+
+	t.Run("disallowed domains", func(t *testing.T) {
+		for _, email := range []string{"user@other.com", "user@sub.example.com", "user@example.com.evil.org", "user"} {
+			err := th.App.CheckEmailDomainAllowed(email)
+			assert.True(t, model.IsErrBadRequest(err), email)
+		}
+	})
+
+	t.Run("registration with a disallowed domain", func(t *testing.T) {
+		err := th.App.RegisterUser("newUsername", "New@Other.com", "testPassword")
+		assert.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("update to a disallowed domain", func(t *testing.T) {
+		user := &model.User{ID: "user-1", Email: "user1@other.com"}
+		th.Store.EXPECT().GetUserByEmail("user1@other.com").Return(nil, model.NewErrNotFound("user"))
+
+		_, err := th.App.UpdateUser(user)
+		assert.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("unchanged email of a disallowed domain is kept", func(t *testing.T) {
+		user := &model.User{ID: "user-1", Email: "user1@other.com", Username: "renamed"}
+		th.Store.EXPECT().GetUserByEmail("user1@other.com").Return(&model.User{ID: "user-1"}, nil)
+		th.Store.EXPECT().UpdateUser(user).Return(user, nil)
+
+		_, err := th.App.UpdateUser(user)
+		assert.NoError(t, err)
+	})
+
+	t.Run("no restriction", func(t *testing.T) {
+		th.App.config.AllowedEmailDomains = nil
+		assert.NoError(t, th.App.CheckEmailDomainAllowed("user@other.com"))
+	})
+}
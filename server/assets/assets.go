@@ -10,3 +10,9 @@ import (
 //
 //go:embed templates.boardarchive
 var DefaultTemplatesArchive []byte
+
+// DefaultAvatar is the avatar served for the users whose avatar can't be served, e.g.
+// because it is too large.
+//
+//go:embed default-avatar.svg
+var DefaultAvatar []byte
@@ -38,7 +38,7 @@ func setupTestHelper(t *testing.T) *TestHelper {
 	mockPermissions := mockpermissions.NewMockStore(ctrlPermissions)
 	logger, err := mlog.NewLogger()
 	require.NoError(t, err)
-	newAuth := New(&cfg, mockStore, localpermissions.New(mockPermissions, logger))
+	newAuth := New(&cfg, mockStore, localpermissions.New(mockPermissions, true, nil, logger))
 
 	// called during default template setup for every test
 	mockStore.EXPECT().GetTemplateBoards("0", "").AnyTimes()
@@ -134,6 +134,8 @@ func getTestConfig() (*config.Configuration, error) {
 		LoggingCfgJSON:    logging,
 		SessionExpireTime: int64(30 * time.Second),
 		AuthMode:          "native",
+
+		EnableFirstUserAdmin: true,
 	}, nil
 }
 
@@ -170,7 +172,7 @@ func newTestServerWithLicense(singleUserToken string, licenseType LicenseType) *
 		db = innerStore
 	}
 
-	permissionsService := localpermissions.New(db, logger)
+	permissionsService := localpermissions.New(db, cfg.EnableFirstUserAdmin, cfg.AdminUserIDs, logger)
 
 	params := server.Params{
 		Cfg:                cfg,
@@ -241,7 +243,7 @@ func newTestServerLocalMode() *server.Server {
 		panic(err)
 	}
 
-	permissionsService := localpermissions.New(db, logger)
+	permissionsService := localpermissions.New(db, cfg.EnableFirstUserAdmin, cfg.AdminUserIDs, logger)
 
 	params := server.Params{
 		Cfg:                cfg,
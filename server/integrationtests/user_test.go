@@ -3,8 +3,14 @@ package integrationtests
 import (
 	"bytes"
 	"crypto/rand"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/mattermost/focalboard/server/app"
+	"github.com/mattermost/focalboard/server/assets"
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/utils"
 	"github.com/stretchr/testify/require"
@@ -338,3 +344,28 @@ func TestTeamUploadFile(t *testing.T) {
 		require.Nil(t, result)
 	})
 }
+
+func TestGetOversizedAvatar(t *testing.T) {
+	th := SetupTestHelper(t).Start()
+	defer th.TearDown()
+
+	cfg := th.Server.Config()
+	cfg.FilesPath = t.TempDir()
+	cfg.AvatarSources = []string{app.AvatarSourceLocal, app.AvatarSourceDefault}
+	cfg.AvatarMaxServeSize = 4
+
+	userID := utils.NewID(utils.IDTypeUser)
+	dir := filepath.Join(cfg.FilesPath, "avatars")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, userID+".png"), []byte("oversized"), 0600))
+
+	response, err := th.Client.DoAPIGet("/users/"+userID+"/avatar", "")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "image/svg+xml", response.Header.Get("Content-Type"))
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, assets.DefaultAvatar, body)
+}
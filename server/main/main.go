@@ -138,7 +138,7 @@ func main() {
 		logger.Fatal("server.NewStore ERROR", mlog.Err(err))
 	}
 
-	permissionsService := localpermissions.New(db, logger)
+	permissionsService := localpermissions.New(db, config.EnableFirstUserAdmin, config.AdminUserIDs, logger)
 
 	params := server.Params{
 		Cfg:                config,
@@ -232,7 +232,7 @@ func startServer(webPath string, filesPath string, port int, singleUserToken, db
 		logger.Fatal("server.NewStore ERROR", mlog.Err(err))
 	}
 
-	permissionsService := localpermissions.New(db, logger)
+	permissionsService := localpermissions.New(db, config.EnableFirstUserAdmin, config.AdminUserIDs, logger)
 
 	params := server.Params{
 		Cfg:                config,
@@ -134,7 +134,7 @@ func main() {
 		logger.Fatal("server.NewStore ERROR", mlog.Err(err))
 	}
 
-	permissionsService := localpermissions.New(db, logger)
+	permissionsService := localpermissions.New(db, config.EnableFirstUserAdmin, config.AdminUserIDs, logger)
 
 	params := server.Params{
 		Cfg:                config,
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package model
+
+// AuditRecord is a persisted audit log entry.
+// swagger:model
+type AuditRecord struct {
+	// The audit record ID
+	// required: true
+	ID string `json:"id"`
+
+	// The audit level (auth, mod, read)
+	// required: true
+	Level string `json:"level"`
+
+	// The event (action) that was audited
+	// required: true
+	Event string `json:"event"`
+
+	// The outcome of the event (success, attempt, fail)
+	// required: true
+	Status string `json:"status"`
+
+	// The ID of the user that performed the action
+	// required: false
+	UserID string `json:"userId"`
+
+	// The session ID used to perform the action
+	// required: false
+	SessionID string `json:"sessionId"`
+
+	// The API path that was called
+	// required: false
+	APIPath string `json:"apiPath"`
+
+	// The client (user agent) that performed the action
+	// required: false
+	Client string `json:"client"`
+
+	// The IP address of the client
+	// required: false
+	IPAddress string `json:"ipAddress"`
+
+	// Additional metadata serialized as JSON
+	// required: false
+	Meta string `json:"meta"`
+
+	// Created time in milliseconds since epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+}
+
+// AuditRecordsResponse is the response body to a request for audit records.
+// swagger:model
+type AuditRecordsResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The array of audit records.
+	// required: true
+	Results []*AuditRecord `json:"results"`
+}
+
+type QueryAuditRecordsOptions struct {
+	UserID  string // if not empty then filter for records created by this actor
+	Event   string // if not empty then filter for records of this event (action)
+	Level   string // if not empty then filter for records of this level
+	Since   int64  // if non-zero then filter for records with create_at greater than or equal to Since
+	Until   int64  // if non-zero then filter for records with create_at less than or equal to Until
+	Page    int    // page number to select when paginating
+	PerPage int    // number of records per page (default=60)
+}
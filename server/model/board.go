@@ -208,6 +208,118 @@ type BoardMember struct {
 	Synthetic bool `json:"synthetic"`
 }
 
+// BoardMemberRoleAssignment is a role to grant a user on a board
+// swagger:model
+type BoardMemberRoleAssignment struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The role to grant (admin, editor, commenter, viewer)
+	// required: true
+	Role BoardRole `json:"role"`
+}
+
+// BoardMemberRoleResult is the outcome of assigning a role to a user on a board
+// swagger:model
+type BoardMemberRoleResult struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The role that was requested
+	// required: true
+	Role BoardRole `json:"role"`
+
+	// The resulting membership, empty if the assignment failed
+	// required: false
+	Member *BoardMember `json:"member,omitempty"`
+
+	// The reason the assignment failed, empty on success
+	// required: false
+	Error string `json:"error,omitempty"`
+}
+
+// UserBoardMembership is a membership of a user on a board with the role it grants
+// swagger:model
+type UserBoardMembership struct {
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The ID of the team of the board
+	// required: true
+	TeamID string `json:"teamId"`
+
+	// The title of the board
+	// required: true
+	Title string `json:"title"`
+
+	// The effective role of the user on the board (admin, editor, commenter, viewer)
+	// required: true
+	Role BoardRole `json:"role"`
+
+	// The membership
+	// required: true
+	Member *BoardMember `json:"member"`
+}
+
+// UserBoardMembershipsResponse is a page of the board memberships of a user
+// swagger:model
+type UserBoardMembershipsResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The memberships, sorted by board ID
+	// required: true
+	Results []*UserBoardMembership `json:"results"`
+}
+
+// BoardAdmin is a user who can administer a board
+// swagger:model
+type BoardAdmin struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The name of the user to display, following the teammate name display setting
+	// required: true
+	DisplayName string `json:"displayName"`
+}
+
+// NewBoardMemberWithRole returns a membership of the user on the board granting the role
+// and every role below it.
+func NewBoardMemberWithRole(boardID, userID string, role BoardRole) *BoardMember {
+	return &BoardMember{
+		BoardID:         boardID,
+		UserID:          userID,
+		MinimumRole:     string(role),
+		SchemeAdmin:     role == BoardRoleAdmin,
+		SchemeEditor:    role == BoardRoleAdmin || role == BoardRoleEditor,
+		SchemeCommenter: role == BoardRoleAdmin || role == BoardRoleEditor || role == BoardRoleCommenter,
+		SchemeViewer:    role == BoardRoleAdmin || role == BoardRoleEditor || role == BoardRoleCommenter || role == BoardRoleViewer,
+	}
+}
+
+// EffectiveRole returns the highest role the membership grants once the minimum role of
+// the board is applied, like permission checks do.
+func (m *BoardMember) EffectiveRole() BoardRole {
+	minimumRole := BoardRole(m.MinimumRole)
+	switch {
+	case m.SchemeAdmin || minimumRole == BoardRoleAdmin:
+		return BoardRoleAdmin
+	case m.SchemeEditor || minimumRole == BoardRoleEditor:
+		return BoardRoleEditor
+	case m.SchemeCommenter || minimumRole == BoardRoleCommenter:
+		return BoardRoleCommenter
+	case m.SchemeViewer || minimumRole == BoardRoleViewer:
+		return BoardRoleViewer
+	default:
+		return BoardRoleNone
+	}
+}
+
 // BoardMetadata contains metadata for a Board
 // swagger:model
 type BoardMetadata struct {
@@ -359,6 +471,11 @@ func IsBoardMinimumRoleValid(r BoardRole) bool {
 	return r == BoardRoleNone || r == BoardRoleAdmin || r == BoardRoleEditor || r == BoardRoleCommenter || r == BoardRoleViewer
 }
 
+// IsBoardMemberRoleValid returns true if the role can be granted to a board member.
+func IsBoardMemberRoleValid(r BoardRole) bool {
+	return r == BoardRoleAdmin || r == BoardRoleEditor || r == BoardRoleCommenter || r == BoardRoleViewer
+}
+
 func (p *BoardPatch) IsValid() error {
 	if p.Type != nil && !IsBoardTypeValid(*p.Type) {
 		return InvalidBoardErr{"invalid-board-type"}
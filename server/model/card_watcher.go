@@ -0,0 +1,18 @@
+package model
+
+// CardWatcher is a user following a card they are not necessarily assigned to, so they
+// get its notifications too.
+// swagger:model
+type CardWatcher struct {
+	// The ID of the card
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The ID of the user watching the card
+	// required: true
+	UserID string `json:"userId"`
+
+	// Created time in milliseconds since epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+}
@@ -132,6 +132,40 @@ func (br *ErrForbidden) Error() string {
 	return br.reason
 }
 
+// ErrConflict can be returned when a request conflicts with the
+// current state of a resource.
+type ErrConflict struct {
+	reason string
+}
+
+// NewErrConflict creates a new ErrConflict instance.
+func NewErrConflict(reason string) *ErrConflict {
+	return &ErrConflict{
+		reason: reason,
+	}
+}
+
+func (c *ErrConflict) Error() string {
+	return c.reason
+}
+
+// ErrTooManyRequests can be returned when a user sends requests faster
+// than an endpoint allows.
+type ErrTooManyRequests struct {
+	reason string
+}
+
+// NewErrTooManyRequests creates a new ErrTooManyRequests instance.
+func NewErrTooManyRequests(reason string) *ErrTooManyRequests {
+	return &ErrTooManyRequests{
+		reason: reason,
+	}
+}
+
+func (tm *ErrTooManyRequests) Error() string {
+	return tm.reason
+}
+
 type ErrInvalidCategory struct {
 	msg string
 }
@@ -304,6 +338,30 @@ func IsErrNotFound(err error) bool {
 	return errors.Is(err, ErrCategoryDeleted)
 }
 
+// IsErrConflict returns true if `err` is or wraps one of:
+// - model.ErrConflict.
+func IsErrConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// check if this is a model.ErrConflict
+	var c *ErrConflict
+	return errors.As(err, &c)
+}
+
+// IsErrTooManyRequests returns true if `err` is or wraps one of:
+// - model.ErrTooManyRequests.
+func IsErrTooManyRequests(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// check if this is a model.ErrTooManyRequests
+	var tm *ErrTooManyRequests
+	return errors.As(err, &tm)
+}
+
 // IsErrRequestEntityTooLarge returns true if `err` is or wraps one of:
 // - model.ErrRequestEntityTooLarge.
 func IsErrRequestEntityTooLarge(err error) bool {
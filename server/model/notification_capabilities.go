@@ -0,0 +1,84 @@
+package model
+
+// NotificationTypeCapability describes a notification type the server accepts
+// swagger:model
+type NotificationTypeCapability struct {
+	// The notification type
+	// required: true
+	Type string `json:"type"`
+
+	// The category the type belongs to
+	// required: true
+	Category string `json:"category"`
+
+	// The actions users can take from notifications of this type
+	// required: true
+	Actions []NotificationAction `json:"actions"`
+}
+
+// NotificationLimits are the limits the server applies to notification requests. A limit
+// of 0 means there is none.
+// swagger:model
+type NotificationLimits struct {
+	// The maximum number of notifications created in one batch
+	// required: true
+	BatchMaxSize int `json:"batchMaxSize"`
+
+	// The maximum number of boards notifications can be filtered by
+	// required: true
+	BoardFilterMax int `json:"boardFilterMax"`
+
+	// The maximum number of read state changes synced at once
+	// required: true
+	ReadSyncMax int `json:"readSyncMax"`
+}
+
+// NotificationCapabilities describes the notification features of the server so clients
+// can adapt to its configuration
+// swagger:model
+type NotificationCapabilities struct {
+	// The notification types the server accepts, sorted
+	// required: true
+	Types []NotificationTypeCapability `json:"types"`
+
+	// The notification categories
+	// required: true
+	Categories []string `json:"categories"`
+
+	// Whether users can set notification preferences
+	// required: true
+	Preferences bool `json:"preferences"`
+
+	// Whether users can override their preferences per board
+	// required: true
+	BoardPreferences bool `json:"boardPreferences"`
+
+	// Whether notifications can be rolled up in board digests
+	// required: true
+	Digests bool `json:"digests"`
+
+	// Whether users can pause notifications with do not disturb
+	// required: true
+	DoNotDisturb bool `json:"doNotDisturb"`
+
+	// Whether users can snooze notifications
+	// required: true
+	Snooze bool `json:"snooze"`
+
+	// Whether notifications can be previewed before they are sent
+	// required: true
+	Preview bool `json:"preview"`
+
+	// Whether notifications are queued and stored asynchronously
+	// required: true
+	Queued bool `json:"queued"`
+
+	// The limits of notification requests
+	// required: true
+	Limits NotificationLimits `json:"limits"`
+}
+
+// NotificationCategories returns the notification categories.
+func NotificationCategories() []string {
+	return []string{NotificationCategoryMentions, NotificationCategoryTasks, NotificationCategorySystem}
+}
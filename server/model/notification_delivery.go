@@ -0,0 +1,104 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package model
+
+import "fmt"
+
+// Channels a notification is delivered through.
+const (
+	NotificationChannelInApp   = "inapp"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+// Delivery statuses. In-app notifications are delivered as soon as they are
+// stored, out-of-band channels go from pending to either sent or failed.
+const (
+	NotificationDeliveryStatusDelivered = "delivered"
+	NotificationDeliveryStatusPending   = "pending"
+	NotificationDeliveryStatusSent      = "sent"
+	NotificationDeliveryStatusFailed    = "failed"
+)
+
+// ErrInvalidNotificationDelivery is returned when a delivery status update is not valid.
+type ErrInvalidNotificationDelivery struct {
+	msg string
+}
+
+func (e ErrInvalidNotificationDelivery) Error() string {
+	return e.msg
+}
+
+// NotificationDelivery is the delivery status of a notification through one channel.
+// swagger:model
+type NotificationDelivery struct {
+	// The ID of the notification
+	// required: true
+	NotificationID string `json:"notificationId"`
+
+	// The delivery channel (inapp, email, webhook)
+	// required: true
+	Channel string `json:"channel"`
+
+	// The delivery status (delivered, pending, sent, failed)
+	// required: true
+	Status string `json:"status"`
+
+	// Number of times delivery was attempted through the channel
+	// required: true
+	Attempts int `json:"attempts"`
+
+	// The error of the last failed attempt
+	// required: false
+	LastError string `json:"lastError"`
+
+	// Created time in milliseconds since epoch
+	// required: true
+	CreateAt int64 `json:"createAt"`
+
+	// Updated time in milliseconds since epoch
+	// required: true
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid checks that the status is one the channel can be in.
+func (d *NotificationDelivery) IsValid() error {
+	if d.NotificationID == "" {
+		return ErrInvalidNotificationDelivery{"missing notification ID"}
+	}
+
+	switch d.Channel {
+	case NotificationChannelInApp:
+		if d.Status != NotificationDeliveryStatusDelivered {
+			return ErrInvalidNotificationDelivery{fmt.Sprintf("invalid status %q for channel %q", d.Status, d.Channel)}
+		}
+	case NotificationChannelEmail, NotificationChannelWebhook:
+		switch d.Status {
+		case NotificationDeliveryStatusPending, NotificationDeliveryStatusSent, NotificationDeliveryStatusFailed:
+		default:
+			return ErrInvalidNotificationDelivery{fmt.Sprintf("invalid status %q for channel %q", d.Status, d.Channel)}
+		}
+	default:
+		return ErrInvalidNotificationDelivery{fmt.Sprintf("invalid channel %q", d.Channel)}
+	}
+	return nil
+}
+
+// NotificationDeliveriesResponse is the response body to a request for notification deliveries.
+// swagger:model
+type NotificationDeliveriesResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The array of notification deliveries.
+	// required: true
+	Results []*NotificationDelivery `json:"results"`
+}
+
+type QueryNotificationDeliveriesOptions struct {
+	Channel string // if not empty then filter for deliveries through this channel
+	Status  string // if not empty then filter for deliveries in this status
+	Page    int    // page number to select when paginating
+	PerPage int    // number of deliveries per page
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+package model
+
+// NotificationDeviceIDMaxLength is the maximum length of the device ID clients send to
+// track the read state of notifications per device.
+const NotificationDeviceIDMaxLength = 64
+
+// NotificationDeviceRead records that a notification was read on one of the devices of
+// its target user. Only clients sending a device ID are tracked, the aggregate read state
+// of the notification is set as soon as any device reads it.
+// swagger:model
+type NotificationDeviceRead struct {
+	// The ID of the notification
+	// required: true
+	NotificationID string `json:"notificationId"`
+
+	// The ID of the device, chosen by the client
+	// required: true
+	DeviceID string `json:"deviceId"`
+
+	// Read time in milliseconds since epoch
+	// required: true
+	ReadAt int64 `json:"readAt"`
+}
+
+// IsNotificationDeviceIDValid returns true if the device ID can be stored.
+func IsNotificationDeviceIDValid(deviceID string) bool {
+	return deviceID != "" && len(deviceID) <= NotificationDeviceIDMaxLength
+}
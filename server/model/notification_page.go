@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NotificationsPage is a page of notifications, listed with a cursor.
+// swagger:model
+type NotificationsPage struct {
+	// True if more notifications follow this page
+	// required: true
+	HasMore bool `json:"hasMore"`
+
+	// The cursor to pass to select the next page, set if there is one
+	// required: false
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// The ID of the last notification of the page, to pass as the before parameter to
+	// select the next page, set if there is one
+	// required: false
+	NextBefore string `json:"nextBefore,omitempty"`
+
+	// The notifications of the page
+	// required: true
+	Results []*UserNotification `json:"results"`
+}
+
+// NotificationsCursor is the position of a notification in a sorted list: whether it is
+// pinned, its sort key value and its ID, which breaks ties.
+type NotificationsCursor struct {
+	Pinned    bool
+	SortValue int64
+	ID        string
+}
+
+// NewNotificationsCursor returns the cursor selecting the notifications listed after
+// notification when sorting by orderBy.
+func NewNotificationsCursor(notification *UserNotification, orderBy string) string {
+	sortValue := notification.CreateAt
+	if orderBy == NotificationOrderByUpdateAt {
+		sortValue = notification.UpdateAt
+	}
+	pinned := 0
+	if notification.Pinned {
+		pinned = 1
+	}
+	return fmt.Sprintf("%d:%d:%s", pinned, sortValue, notification.ID)
+}
+
+// ParseNotificationsCursor returns the position of the notification of a cursor.
+func ParseNotificationsCursor(cursor string) (NotificationsCursor, error) {
+	parts := strings.SplitN(cursor, ":", 3)
+	if len(parts) != 3 || (parts[0] != "0" && parts[0] != "1") || parts[2] == "" {
+		return NotificationsCursor{}, NewErrBadRequest("invalid notifications cursor: " + cursor)
+	}
+	sortValue, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return NotificationsCursor{}, NewErrBadRequest("invalid notifications cursor: " + cursor)
+	}
+	return NotificationsCursor{Pinned: parts[0] == "1", SortValue: sortValue, ID: parts[2]}, nil
+}
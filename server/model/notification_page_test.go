@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsCursor(t *testing.T) {
+	notification := &UserNotification{ID: "notification-1", Pinned: true, CreateAt: 100, UpdateAt: 200}
+
+	cursor, err := ParseNotificationsCursor(NewNotificationsCursor(notification, ""))
+	require.NoError(t, err)
+	assert.Equal(t, NotificationsCursor{Pinned: true, SortValue: 100, ID: "notification-1"}, cursor)
+
+	cursor, err = ParseNotificationsCursor(NewNotificationsCursor(notification, NotificationOrderByUpdateAt))
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), cursor.SortValue)
+
+	for _, invalid := range []string{"", "1:100", "2:100:id", "0:abc:id", "0:100:"} {
+		_, err := ParseNotificationsCursor(invalid)
+		assert.True(t, IsErrBadRequest(err), invalid)
+	}
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"encoding/json"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+const (
+	// PreferenceNameNotificationPreferences is the name of the user preference holding the
+	// JSON encoded NotificationPreferencesOverrides of a user.
+	PreferenceNameNotificationPreferences = "notificationPreferences"
+
+	// PreferenceNameNotificationLastSeen is the name of the user preference holding the
+	// time, in milliseconds since epoch, the user last opened the notification center.
+	PreferenceNameNotificationLastSeen = "notificationLastSeen"
+)
+
+const (
+	NotificationPreferenceSourceDefault = "default"
+	NotificationPreferenceSourceTeam    = "team"
+	NotificationPreferenceSourceUser    = "user"
+)
+
+// NotificationPreferencesOverrides are the notification preferences set at one level of
+// the inheritance chain (team defaults or user). Unset fields inherit the value of the
+// level below.
+// swagger:model
+type NotificationPreferencesOverrides struct {
+	// Whether all notifications are muted
+	// required: false
+	Muted *bool `json:"muted,omitempty"`
+
+	// The IDs of the boards whose notifications are muted
+	// required: false
+	MutedBoardIDs *[]string `json:"mutedBoardIds,omitempty"`
+
+	// The IDs of the boards whose notifications are rolled up in a periodic digest
+	// required: false
+	DigestBoardIDs *[]string `json:"digestBoardIds,omitempty"`
+}
+
+// NotificationPreferences are the effective preferences controlling which user
+// notifications a user receives.
+// swagger:model
+type NotificationPreferences struct {
+	// Whether all notifications are muted
+	// required: true
+	Muted bool `json:"muted"`
+
+	// The IDs of the boards whose notifications are muted
+	// required: true
+	MutedBoardIDs []string `json:"mutedBoardIds"`
+
+	// The IDs of the boards whose notifications are rolled up in a periodic digest
+	// required: true
+	DigestBoardIDs []string `json:"digestBoardIds"`
+
+	// The level (default, team, user) each preference was resolved from, keyed by preference
+	// required: true
+	Sources map[string]string `json:"sources"`
+}
+
+// DefaultNotificationPreferences returns the built-in notification preferences.
+func DefaultNotificationPreferences() *NotificationPreferences {
+	return &NotificationPreferences{
+		Muted:          false,
+		MutedBoardIDs:  []string{},
+		DigestBoardIDs: []string{},
+		Sources: map[string]string{
+			"muted":          NotificationPreferenceSourceDefault,
+			"mutedBoardIds":  NotificationPreferenceSourceDefault,
+			"digestBoardIds": NotificationPreferenceSourceDefault,
+		},
+	}
+}
+
+// ResolveNotificationPreferences merges user overrides on top of team defaults on top of
+// the built-in defaults. Either level may be nil.
+func ResolveNotificationPreferences(teamDefaults, userOverrides *NotificationPreferencesOverrides) *NotificationPreferences {
+	preferences := DefaultNotificationPreferences()
+	preferences.apply(teamDefaults, NotificationPreferenceSourceTeam)
+	preferences.apply(userOverrides, NotificationPreferenceSourceUser)
+	return preferences
+}
+
+func (p *NotificationPreferences) apply(overrides *NotificationPreferencesOverrides, source string) {
+	if overrides == nil {
+		return
+	}
+	if overrides.Muted != nil {
+		p.Muted = *overrides.Muted
+		p.Sources["muted"] = source
+	}
+	if overrides.MutedBoardIDs != nil {
+		p.MutedBoardIDs = *overrides.MutedBoardIDs
+		p.Sources["mutedBoardIds"] = source
+	}
+	if overrides.DigestBoardIDs != nil {
+		p.DigestBoardIDs = *overrides.DigestBoardIDs
+		p.Sources["digestBoardIds"] = source
+	}
+}
+
+// NotificationPreferencesOverridesFromPreferences extracts the notification preferences a
+// user has set from their preferences, returning empty overrides if none have been saved.
+func NotificationPreferencesOverridesFromPreferences(preferences mmModel.Preferences) (*NotificationPreferencesOverrides, error) {
+	overrides := &NotificationPreferencesOverrides{}
+	for _, preference := range preferences {
+		if preference.Category != PreferencesCategoryFocalboard || preference.Name != PreferenceNameNotificationPreferences {
+			continue
+		}
+		if preference.Value == "" {
+			break
+		}
+		if err := json.Unmarshal([]byte(preference.Value), overrides); err != nil {
+			return nil, err
+		}
+		break
+	}
+	return overrides, nil
+}
+
+// Suppresses returns true if the notification should not be delivered to a user with
+// these preferences.
+func (p *NotificationPreferences) Suppresses(notification *UserNotification) bool {
+	if p.Muted {
+		return true
+	}
+	for _, boardID := range p.MutedBoardIDs {
+		if boardID == notification.BoardID {
+			return true
+		}
+	}
+	return false
+}
+
+// Digests returns true if the notification should be rolled up in the digest of its board
+// instead of being delivered right away.
+func (p *NotificationPreferences) Digests(notification *UserNotification) bool {
+	if notification.BoardID == "" || notification.Type == NotificationTypeBoardDigest {
+		return false
+	}
+	for _, boardID := range p.DigestBoardIDs {
+		if boardID == notification.BoardID {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDigest is the activity on a board waiting to be summarized in a single
+// notification to a user.
+type NotificationDigest struct {
+	UserID       string
+	BoardID      string
+	PendingCount int
+	FirstAt      int64 // time of the oldest activity in milliseconds since epoch
+	LastAt       int64 // time of the latest activity in milliseconds since epoch
+}
+
+// NotificationBoardPreference enables or disables one type of notification on one board
+// for a user, overriding the user's global notification preferences.
+// swagger:model
+type NotificationBoardPreference struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The ID of the board
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The notification type the override applies to
+	// required: true
+	Type string `json:"type"`
+
+	// Whether notifications of the type are delivered on the board
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Updated time in milliseconds since epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt"`
+}
+
+// IsValid checks that the override is for a notification type known to the registry.
+func (p *NotificationBoardPreference) IsValid(types *NotificationTypeRegistry) error {
+	if !types.IsRegistered(p.Type) {
+		return ErrInvalidUserNotification{"unknown notification type: " + p.Type}
+	}
+	return nil
+}
+
+// NotificationBoardPreferenceFor returns the override of the notification type among the
+// board overrides of a user, or nil if the global preferences apply.
+func NotificationBoardPreferenceFor(preferences []*NotificationBoardPreference, notifType string) *NotificationBoardPreference {
+	for _, preference := range preferences {
+		if preference.Type == notifType {
+			return preference
+		}
+	}
+	return nil
+}
+
+// NotificationActorBlock silences the notifications a user would get from another user.
+// swagger:model
+type NotificationActorBlock struct {
+	// The ID of the user who blocked the actor
+	// required: true
+	UserID string `json:"userId"`
+
+	// The ID of the blocked actor
+	// required: true
+	BlockedActorID string `json:"blockedActorId"`
+
+	// Created time in milliseconds since epoch
+	// required: false
+	CreateAt int64 `json:"createAt"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mmModel "github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestNotificationPreferencesOverridesFromPreferences(t *testing.T) {
+	t.Run("empty when not set", func(t *testing.T) {
+		overrides, err := NotificationPreferencesOverridesFromPreferences(mmModel.Preferences{
+			{Category: PreferencesCategoryFocalboard, Name: "welcomePageViewed", Value: "1"},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, overrides.Muted)
+		assert.Nil(t, overrides.MutedBoardIDs)
+	})
+
+	t.Run("parses saved preferences", func(t *testing.T) {
+		overrides, err := NotificationPreferencesOverridesFromPreferences(mmModel.Preferences{
+			{Category: PreferencesCategoryFocalboard, Name: PreferenceNameNotificationPreferences, Value: `{"mutedBoardIds":["board-1"]}`},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, overrides.Muted)
+		require.NotNil(t, overrides.MutedBoardIDs)
+		assert.Equal(t, []string{"board-1"}, *overrides.MutedBoardIDs)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := NotificationPreferencesOverridesFromPreferences(mmModel.Preferences{
+			{Category: PreferencesCategoryFocalboard, Name: PreferenceNameNotificationPreferences, Value: `{`},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestResolveNotificationPreferences(t *testing.T) {
+	trueValue := true
+	falseValue := false
+	teamBoards := []string{"board-1"}
+	userBoards := []string{}
+
+	t.Run("built-in defaults", func(t *testing.T) {
+		preferences := ResolveNotificationPreferences(nil, nil)
+		assert.False(t, preferences.Muted)
+		assert.Empty(t, preferences.MutedBoardIDs)
+		assert.Equal(t, NotificationPreferenceSourceDefault, preferences.Sources["muted"])
+		assert.Equal(t, NotificationPreferenceSourceDefault, preferences.Sources["mutedBoardIds"])
+	})
+
+	t.Run("team defaults are inherited", func(t *testing.T) {
+		preferences := ResolveNotificationPreferences(
+			&NotificationPreferencesOverrides{Muted: &trueValue, MutedBoardIDs: &teamBoards},
+			&NotificationPreferencesOverrides{},
+		)
+		assert.True(t, preferences.Muted)
+		assert.Equal(t, teamBoards, preferences.MutedBoardIDs)
+		assert.Equal(t, NotificationPreferenceSourceTeam, preferences.Sources["muted"])
+		assert.Equal(t, NotificationPreferenceSourceTeam, preferences.Sources["mutedBoardIds"])
+	})
+
+	t.Run("user overrides win", func(t *testing.T) {
+		preferences := ResolveNotificationPreferences(
+			&NotificationPreferencesOverrides{Muted: &trueValue, MutedBoardIDs: &teamBoards},
+			&NotificationPreferencesOverrides{Muted: &falseValue, MutedBoardIDs: &userBoards},
+		)
+		assert.False(t, preferences.Muted)
+		assert.Empty(t, preferences.MutedBoardIDs)
+		assert.Equal(t, NotificationPreferenceSourceUser, preferences.Sources["muted"])
+		assert.Equal(t, NotificationPreferenceSourceUser, preferences.Sources["mutedBoardIds"])
+	})
+}
+
+func TestNotificationPreferencesSuppresses(t *testing.T) {
+	notification := &UserNotification{BoardID: "board-1"}
+
+	assert.False(t, (&NotificationPreferences{}).Suppresses(notification))
+	assert.True(t, (&NotificationPreferences{Muted: true}).Suppresses(notification))
+	assert.True(t, (&NotificationPreferences{MutedBoardIDs: []string{"board-1"}}).Suppresses(notification))
+	assert.False(t, (&NotificationPreferences{MutedBoardIDs: []string{"board-2"}}).Suppresses(notification))
+}
+
+func TestNotificationPreferencesDigests(t *testing.T) {
+	preferences := &NotificationPreferences{DigestBoardIDs: []string{"board-1"}}
+
+	assert.True(t, preferences.Digests(&UserNotification{BoardID: "board-1", Type: NotificationTypeMentioned}))
+	assert.False(t, preferences.Digests(&UserNotification{BoardID: "board-2", Type: NotificationTypeMentioned}))
+	assert.False(t, preferences.Digests(&UserNotification{BoardID: "board-1", Type: NotificationTypeBoardDigest}))
+	assert.False(t, (&NotificationPreferences{}).Digests(&UserNotification{BoardID: "board-1"}))
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// NotificationQueryClausesMax is the maximum number of alternative filters of a query.
+	NotificationQueryClausesMax = 10
+
+	// NotificationQueryValuesMax is the maximum number of values of each list of a filter.
+	NotificationQueryValuesMax = 50
+)
+
+// NotificationFilter selects the notifications matching every field that is set.
+// swagger:model
+type NotificationFilter struct {
+	// Only notifications of these types
+	// required: false
+	Types []string `json:"types,omitempty"`
+
+	// Only notifications of these boards
+	// required: false
+	BoardIDs []string `json:"boardIds,omitempty"`
+
+	// Only notifications of other boards than these
+	// required: false
+	ExcludeBoardIDs []string `json:"excludeBoardIds,omitempty"`
+
+	// Only notifications of these cards
+	// required: false
+	CardIDs []string `json:"cardIds,omitempty"`
+
+	// Only notifications of this team
+	// required: false
+	TeamID string `json:"teamId,omitempty"`
+
+	// Only notifications triggered by these users
+	// required: false
+	ActorUserIDs []string `json:"actorUserIds,omitempty"`
+
+	// Only read notifications if true, only unread ones if false
+	// required: false
+	Read *bool `json:"read,omitempty"`
+
+	// Only notifications created at or after this time, in milliseconds since epoch
+	// required: false
+	From int64 `json:"from,omitempty"`
+
+	// Only notifications created at or before this time, in milliseconds since epoch
+	// required: false
+	To int64 `json:"to,omitempty"`
+}
+
+// IsEmpty returns true if no field of the filter is set, so it matches every notification.
+func (f NotificationFilter) IsEmpty() bool {
+	return len(f.Types) == 0 && len(f.BoardIDs) == 0 && len(f.ExcludeBoardIDs) == 0 &&
+		len(f.CardIDs) == 0 && f.TeamID == "" && len(f.ActorUserIDs) == 0 && f.Read == nil &&
+		f.From == 0 && f.To == 0
+}
+
+// IsValid checks the size of the lists of the filter and its date range.
+func (f NotificationFilter) IsValid() error {
+	lists := []struct {
+		name   string
+		values []string
+	}{
+		{"types", f.Types},
+		{"boardIds", f.BoardIDs},
+		{"excludeBoardIds", f.ExcludeBoardIDs},
+		{"cardIds", f.CardIDs},
+		{"actorUserIds", f.ActorUserIDs},
+	}
+	for _, list := range lists {
+		if len(list.values) > NotificationQueryValuesMax {
+			return fmt.Errorf("%s accepts at most %d values", list.name, NotificationQueryValuesMax)
+		}
+	}
+	if f.From < 0 || f.To < 0 {
+		return errors.New("from and to must not be negative")
+	}
+	if f.From > 0 && f.To > 0 && f.From > f.To {
+		return errors.New("from must not be after to")
+	}
+	return nil
+}
+
+// NotificationQuery selects the notifications matching its filter and, if AnyOf is not
+// empty, at least one of the alternative filters of AnyOf. For example, the unread
+// assignments or mentions of a board are the filter {boardIds: [board], read: false}
+// with AnyOf [{types: [assigned]}, {types: [mentioned]}].
+// swagger:model
+type NotificationQuery struct {
+	NotificationFilter
+
+	// Alternative filters, at least one of which must match
+	// required: false
+	AnyOf []NotificationFilter `json:"anyOf,omitempty"`
+}
+
+// IsValid checks the filter and the alternative filters of the query.
+func (q NotificationQuery) IsValid() error {
+	if err := q.NotificationFilter.IsValid(); err != nil {
+		return err
+	}
+	if len(q.AnyOf) > NotificationQueryClausesMax {
+		return fmt.Errorf("anyOf accepts at most %d filters", NotificationQueryClausesMax)
+	}
+	for i, filter := range q.AnyOf {
+		if err := filter.IsValid(); err != nil {
+			return fmt.Errorf("anyOf %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NotificationQueryRequest is a request for the notifications of the current user matching
+// a query.
+// swagger:model
+type NotificationQueryRequest struct {
+	// The notifications to return
+	// required: true
+	Query NotificationQuery `json:"query"`
+
+	// Maximum number of notifications to return, 50 if not set
+	// required: false
+	Limit int `json:"limit,omitempty"`
+
+	// Also return archived notifications
+	// required: false
+	IncludeArchived bool `json:"includeArchived,omitempty"`
+
+	// Sort by creation time (createAt, the default) or by last update time (updateAt)
+	// required: false
+	OrderBy string `json:"orderBy,omitempty"`
+
+	// Return the oldest notifications first
+	// required: false
+	Ascending bool `json:"ascending,omitempty"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationQueryIsValid(t *testing.T) {
+	unread := false
+	require.NoError(t, NotificationQuery{}.IsValid())
+	require.NoError(t, NotificationQuery{
+		NotificationFilter: NotificationFilter{BoardIDs: []string{"board-1"}, Read: &unread},
+		AnyOf: []NotificationFilter{
+			{Types: []string{NotificationTypeAssigned}},
+			{Types: []string{NotificationTypeMentioned}},
+		},
+	}.IsValid())
+
+	require.Error(t, NotificationQuery{NotificationFilter: NotificationFilter{From: 200, To: 100}}.IsValid())
+	require.Error(t, NotificationQuery{NotificationFilter: NotificationFilter{From: -1}}.IsValid())
+	require.Error(t, NotificationQuery{NotificationFilter: NotificationFilter{BoardIDs: make([]string, NotificationQueryValuesMax+1)}}.IsValid())
+	require.Error(t, NotificationQuery{AnyOf: make([]NotificationFilter, NotificationQueryClausesMax+1)}.IsValid())
+	require.Error(t, NotificationQuery{AnyOf: []NotificationFilter{{From: 200, To: 100}}}.IsValid())
+}
+
+func TestQueryUserNotificationsOptionsFilter(t *testing.T) {
+	require.True(t, QueryUserNotificationsOptions{Limit: 10, Category: NotificationCategoryMentions}.Filter().IsEmpty())
+
+	opts := QueryUserNotificationsOptions{
+		BoardIDs:       []string{"board-1"},
+		ExcludeBoardID: "board-2",
+		CardID:         "card-1",
+		TeamID:         "team-1",
+		From:           100,
+		To:             200,
+	}
+	require.Equal(t, NotificationFilter{
+		BoardIDs:        []string{"board-1"},
+		ExcludeBoardIDs: []string{"board-2"},
+		CardIDs:         []string{"card-1"},
+		TeamID:          "team-1",
+		From:            100,
+		To:              200,
+	}, opts.Filter())
+}
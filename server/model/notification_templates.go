@@ -0,0 +1,70 @@
+package model
+
+import (
+	"regexp"
+)
+
+// notificationTemplatePlaceholder matches the {field} placeholders of notification templates.
+var notificationTemplatePlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// defaultNotificationTemplates are the templates of the built-in notification types, matching
+// the messages clients render from the notification fields.
+var defaultNotificationTemplates = map[string]string{
+	NotificationTypeAssigned:    `{actorName} added you to "{cardTitle}"`,
+	NotificationTypeUnassigned:  `{actorName} removed you from "{cardTitle}"`,
+	NotificationTypeMentioned:   `{actorName} mentioned you in "{cardTitle}"`,
+	NotificationTypeTest:        `{cardTitle}`,
+	NotificationTypeBoardDigest: `{cardTitle}`,
+
+	NotificationTypeDueDateChanged: `{actorName} changed the {property} of "{cardTitle}" from {oldDate} to {newDate}`,
+	NotificationTypeStatusChanged:  `{actorName} moved "{cardTitle}" from {oldStatus} to {newStatus}`,
+	NotificationTypeCommented:      `{actorName} commented on "{cardTitle}"`,
+	NotificationTypeBoardShared:    `{actorName} shared the board "{cardTitle}"`,
+	NotificationTypeBoardImported:  `{actorName} imported the board "{cardTitle}" with {count} updates for you`,
+}
+
+// genericNotificationTemplate is the template of the notification types without one of
+// their own, e.g. custom types.
+const genericNotificationTemplate = `{actorName}: "{cardTitle}"`
+
+// DefaultNotificationTemplate returns the built-in template of a notification type.
+func DefaultNotificationTemplate(notifType string) string {
+	if template, ok := defaultNotificationTemplates[notifType]; ok {
+		return template
+	}
+	return genericNotificationTemplate
+}
+
+// RenderNotificationMessage replaces the placeholders of a template with the fields of a
+// notification: {actorName}, {cardTitle}, {type}, {boardId}, {cardId} and {teamId}, or
+// with its params. An empty actor name renders as "Someone" and an empty card title as
+// "Untitled". Unknown placeholders are left as they are.
+func RenderNotificationMessage(template string, n *UserNotification) string {
+	return notificationTemplatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		switch placeholder[1 : len(placeholder)-1] {
+		case "actorName":
+			if n.ActorName == "" {
+				return "Someone"
+			}
+			return n.ActorName
+		case "cardTitle":
+			if n.CardTitle == "" {
+				return "Untitled"
+			}
+			return n.CardTitle
+		case "type":
+			return n.Type
+		case "boardId":
+			return n.BoardID
+		case "cardId":
+			return n.CardID
+		case "teamId":
+			return n.TeamID
+		default:
+			if value, ok := n.Params[placeholder[1:len(placeholder)-1]]; ok {
+				return value
+			}
+			return placeholder
+		}
+	})
+}
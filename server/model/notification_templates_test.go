@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderNotificationMessage(t *testing.T) {
+	notification := &UserNotification{Type: NotificationTypeAssigned, ActorName: "Alice", CardTitle: "Release", BoardID: "board-1"}
+
+	t.Run("default template", func(t *testing.T) {
+		message := RenderNotificationMessage(DefaultNotificationTemplate(notification.Type), notification)
+		assert.Equal(t, `Alice added you to "Release"`, message)
+	})
+
+	t.Run("custom types use the generic template", func(t *testing.T) {
+		assert.Equal(t, genericNotificationTemplate, DefaultNotificationTemplate("deployment"))
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		message := RenderNotificationMessage("{actorName} updated {cardTitle} on {teamId}", &UserNotification{})
+		assert.Equal(t, "Someone updated Untitled on ", message)
+	})
+
+	t.Run("unknown placeholders are kept", func(t *testing.T) {
+		message := RenderNotificationMessage("{actorName} did {what} on {boardId}", notification)
+		assert.Equal(t, "Alice did {what} on board-1", message)
+	})
+
+	t.Run("params fill the placeholders of the type", func(t *testing.T) {
+		dueDate := &UserNotification{
+			Type:      NotificationTypeDueDateChanged,
+			ActorName: "Alice",
+			CardTitle: "Release",
+			Params:    map[string]string{"property": "Due date", "oldDate": "none", "newDate": "January 02, 2006"},
+		}
+		message := RenderNotificationMessage(DefaultNotificationTemplate(dueDate.Type), dueDate)
+		assert.Equal(t, `Alice changed the Due date of "Release" from none to January 02, 2006`, message)
+	})
+}
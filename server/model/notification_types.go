@@ -0,0 +1,66 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NotificationTypeRegistry holds the notification types the server accepts: the built-in
+// types plus custom types registered through config or by plugins. Custom types belong to
+// NotificationCategorySystem and are rendered by clients with a generic template.
+type NotificationTypeRegistry struct {
+	mux   sync.RWMutex
+	types map[string]bool
+}
+
+// NewNotificationTypeRegistry creates a registry of the built-in types and the given
+// custom types.
+func NewNotificationTypeRegistry(customTypes []string) *NotificationTypeRegistry {
+	registry := &NotificationTypeRegistry{
+		types: map[string]bool{},
+	}
+	for _, notifType := range builtinNotificationTypes {
+		registry.types[notifType] = true
+	}
+	for _, notifType := range customTypes {
+		_ = registry.Register(notifType)
+	}
+	return registry
+}
+
+// Register adds a custom notification type. Registering a type twice is a no-op.
+func (r *NotificationTypeRegistry) Register(notifType string) error {
+	notifType = strings.TrimSpace(notifType)
+	if notifType == "" {
+		return ErrInvalidUserNotification{"notification type cannot be empty"}
+	}
+	if len(notifType) > 50 {
+		return ErrInvalidUserNotification{"notification type cannot be longer than 50 characters"}
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.types[notifType] = true
+	return nil
+}
+
+// IsRegistered returns true if the server accepts notifications of the type.
+func (r *NotificationTypeRegistry) IsRegistered(notifType string) bool {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.types[notifType]
+}
+
+// Types returns the accepted notification types, sorted.
+func (r *NotificationTypeRegistry) Types() []string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	types := make([]string, 0, len(r.types))
+	for notifType := range r.types {
+		types = append(types, notifType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationTypeRegistry(t *testing.T) {
+	registry := NewNotificationTypeRegistry([]string{"deployment", " "})
+
+	t.Run("built-in and configured types", func(t *testing.T) {
+		assert.True(t, registry.IsRegistered(NotificationTypeAssigned))
+		assert.True(t, registry.IsRegistered("deployment"))
+		assert.False(t, registry.IsRegistered(""))
+		assert.False(t, registry.IsRegistered("unknown"))
+	})
+
+	t.Run("register", func(t *testing.T) {
+		require.NoError(t, registry.Register("reminder"))
+		assert.True(t, registry.IsRegistered("reminder"))
+		assert.Contains(t, registry.Types(), "reminder")
+		assert.Error(t, registry.Register(""))
+	})
+
+	t.Run("custom types are in the system category", func(t *testing.T) {
+		assert.Equal(t, NotificationCategorySystem, NotificationCategoryForType("deployment"))
+	})
+
+	t.Run("validation consults the registry", func(t *testing.T) {
+		assert.NoError(t, (&UserNotification{TargetUserID: "user-1", Type: "deployment"}).IsValid(registry))
+		assert.Error(t, (&UserNotification{TargetUserID: "user-1", Type: "unknown"}).IsValid(registry))
+		assert.Error(t, (&UserNotification{Type: NotificationTypeMentioned}).IsValid(registry))
+	})
+}
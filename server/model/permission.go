@@ -23,3 +23,66 @@ var (
 	PermissionCommentBoardCards     = &mmModel.Permission{Id: "comment_board_cards", Name: "", Description: "", Scope: ""}
 	PermissionDeleteOthersComments  = &mmModel.Permission{Id: "delete_others_comments", Name: "", Description: "", Scope: ""}
 )
+
+// BoardPermissions are the permissions that can be granted on a board, ordered from
+// the least to the most privileged.
+var BoardPermissions = []*mmModel.Permission{
+	PermissionViewBoard,
+	PermissionCommentBoardCards,
+	PermissionManageBoardCards,
+	PermissionManageBoardProperties,
+	PermissionDeleteOthersComments,
+	PermissionShareBoard,
+	PermissionManageBoardRoles,
+	PermissionManageBoardType,
+	PermissionDeleteBoard,
+}
+
+// PermissionForBoardRole returns the board permission that sets a role apart from the
+// roles below it, or nil if the role is unknown.
+func PermissionForBoardRole(role BoardRole) *mmModel.Permission {
+	switch role {
+	case BoardRoleViewer:
+		return PermissionViewBoard
+	case BoardRoleCommenter:
+		return PermissionCommentBoardCards
+	case BoardRoleEditor:
+		return PermissionManageBoardCards
+	case BoardRoleAdmin:
+		return PermissionManageBoardRoles
+	default:
+		return nil
+	}
+}
+
+// UserPermissions describes the high-level capabilities of a user.
+// swagger:model
+type UserPermissions struct {
+	// Whether the user is a system administrator
+	// required: true
+	SystemAdmin bool `json:"systemAdmin"`
+
+	// The team the team capabilities refer to, if requested
+	// required: false
+	TeamID string `json:"teamId,omitempty"`
+
+	// Whether the user can manage the requested team
+	// required: false
+	TeamAdmin bool `json:"teamAdmin"`
+
+	// The board the board permissions refer to, if requested
+	// required: false
+	BoardID string `json:"boardId,omitempty"`
+
+	// The IDs of the permissions the user holds on the requested board
+	// required: false
+	BoardPermissions []string `json:"boardPermissions,omitempty"`
+}
+
+// AdminCacheRefresh is the outcome of recomputing the cached system admins.
+// swagger:model
+type AdminCacheRefresh struct {
+	// The ID of the first registered user, empty if the first user is not a system admin
+	// required: true
+	FirstUserAdminID string `json:"firstUserAdminId"`
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/focalboard/server/utils"
@@ -224,6 +225,107 @@ func getMapString(key string, m map[string]interface{}) string {
 	return s
 }
 
+// DueDatePropertyIDs returns the IDs of the `date` properties holding due dates, i.e. the
+// ones with "due" in their name, sorted.
+func (s PropSchema) DueDatePropertyIDs() []string {
+	ids := []string{}
+	for id, def := range s {
+		if def.Type == "date" && strings.Contains(strings.ToLower(def.Name), "due") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// StatusPropertyID returns the ID of the `select` property named "Status", or an empty
+// string if the board has none.
+func (s PropSchema) StatusPropertyID() string {
+	for id, def := range s {
+		if def.Type == "select" && strings.EqualFold(def.Name, "status") {
+			return id
+		}
+	}
+	return ""
+}
+
+// GetPersonPropertyUserIDs returns the IDs of the users set on the `person` and
+// `multiPerson` properties of a card, i.e. the users assigned to it.
+func GetPersonPropertyUserIDs(card *Block, schema PropSchema) []string {
+	userIDs := []string{}
+
+	blockProps, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return userIDs
+	}
+
+	for k, v := range blockProps {
+		def, ok := schema[k]
+		if !ok {
+			continue
+		}
+		switch def.Type {
+		case "person":
+			if userID, ok := v.(string); ok && userID != "" {
+				userIDs = append(userIDs, userID)
+			}
+		case "multiPerson":
+			values, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, value := range values {
+				if userID, ok := value.(string); ok && userID != "" {
+					userIDs = append(userIDs, userID)
+				}
+			}
+		}
+	}
+	return userIDs
+}
+
+// RemovePersonPropertyUserID returns a copy of the properties of a card without the user
+// on its `person` and `multiPerson` properties, and whether the user was found at all.
+func RemovePersonPropertyUserID(card *Block, schema PropSchema, userID string) (map[string]interface{}, bool) {
+	blockProps, ok := card.Fields["properties"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	props := make(map[string]interface{}, len(blockProps))
+	found := false
+	for k, v := range blockProps {
+		props[k] = v
+
+		def, ok := schema[k]
+		if !ok {
+			continue
+		}
+		switch def.Type {
+		case "person":
+			if value, ok := v.(string); ok && value == userID {
+				props[k] = ""
+				found = true
+			}
+		case "multiPerson":
+			values, ok := v.([]interface{})
+			if !ok {
+				continue
+			}
+			kept := make([]interface{}, 0, len(values))
+			for _, value := range values {
+				if value == userID {
+					found = true
+					continue
+				}
+				kept = append(kept, value)
+			}
+			props[k] = kept
+		}
+	}
+	return props, found
+}
+
 // ParseProperties parses a block's `Fields` to extract the properties. Properties typically exist on
 // card blocks.  A resolver can optionally be provided to fetch usernames for `person` prop type.
 func ParseProperties(block *Block, schema PropSchema, resolver PropValueResolver) (BlockProperties, error) {
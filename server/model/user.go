@@ -2,7 +2,10 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -94,6 +97,84 @@ type Session struct {
 	UpdateAt    int64                  `json:"update_at,omitempty"`
 }
 
+// QueryUsersOptions selects a page of users, newest first.
+type QueryUsersOptions struct {
+	Page    int    // page number to select when paginating without a cursor
+	PerPage int    // number of users per page
+	Cursor  string // if not empty then select the page after the user of this cursor instead of Page
+}
+
+// UsersResponse is a page of users.
+// swagger:model
+type UsersResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The cursor to pass to select the next page, set if there is one
+	// required: false
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// The array of users
+	// required: true
+	Results []*User `json:"results"`
+}
+
+// AvatarURL is a link to the avatar of a user.
+// swagger:model
+type AvatarURL struct {
+	// The avatar URL, with a signed token when avatars are private
+	// required: true
+	URL string `json:"url"`
+
+	// The time the token of the URL expires, in milliseconds since epoch, 0 if the URL
+	// has no token
+	// required: true
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// UserDeletionSummary is what deleting a user would remove or change.
+// swagger:model
+type UserDeletionSummary struct {
+	// The ID of the user
+	// required: true
+	UserID string `json:"userId"`
+
+	// The number of board memberships that would be removed
+	// required: true
+	BoardMemberships int `json:"boardMemberships"`
+
+	// The number of notifications the user received that would be deleted
+	// required: true
+	Notifications int `json:"notifications"`
+
+	// The number of notifications the user authored that would be anonymized
+	// required: true
+	AuthoredNotifications int `json:"authoredNotifications"`
+
+	// The number of comments the user authored, which are kept
+	// required: true
+	AuthoredComments int `json:"authoredComments"`
+}
+
+// NewUsersCursor returns the cursor selecting the users listed after user.
+func NewUsersCursor(user *User) string {
+	return fmt.Sprintf("%d:%s", user.CreateAt, user.ID)
+}
+
+// ParseUsersCursor returns the create time and ID of the user of a cursor.
+func ParseUsersCursor(cursor string) (int64, string, error) {
+	createAtStr, id, ok := strings.Cut(cursor, ":")
+	if !ok || id == "" {
+		return 0, "", NewErrBadRequest("invalid users cursor: " + cursor)
+	}
+	createAt, err := strconv.ParseInt(createAtStr, 10, 64)
+	if err != nil {
+		return 0, "", NewErrBadRequest("invalid users cursor: " + cursor)
+	}
+	return createAt, id, nil
+}
+
 func UserFromJSON(data io.Reader) (*User, error) {
 	var user User
 	if err := json.NewDecoder(data).Decode(&user); err != nil {
@@ -102,6 +183,13 @@ func UserFromJSON(data io.Reader) (*User, error) {
 	return &user, nil
 }
 
+// NormalizeEmail returns the form emails are stored and compared in, trimmed and lower
+// cased. Emails stored before normalization was introduced keep their casing until the
+// user is updated, lookups by email ignore the casing to still match them.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func (u *User) Sanitize(options map[string]bool) {
 	u.Password = ""
 	u.MfaSecret = ""
@@ -2,12 +2,145 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/mattermost/focalboard/server/utils"
 )
 
+const (
+	NotificationTypeAssigned   = "assigned"
+	NotificationTypeUnassigned = "unassigned"
+	NotificationTypeMentioned  = "mentioned"
+	NotificationTypeTest       = "test"
+
+	// NotificationTypeDueDateChanged tells the assignees of a card that one of its due
+	// dates was set, changed or cleared
+	NotificationTypeDueDateChanged = "due_date_changed"
+
+	// NotificationTypeStatusChanged tells the assignees and followers of a card that it
+	// moved to another status, e.g. to another column of a Kanban board
+	NotificationTypeStatusChanged = "status_changed"
+
+	// NotificationTypeCommented tells the assignees and followers of a card that a comment
+	// was added to it
+	NotificationTypeCommented = "commented"
+
+	// NotificationTypeBoardShared tells the admins of a board that it was shared publicly
+	// or that its share link changed
+	NotificationTypeBoardShared = "board_shared"
+
+	// NotificationTypeBoardDigest summarizes the activity on a board for users who
+	// receive the board's notifications as a digest
+	NotificationTypeBoardDigest = "board_digest"
+
+	// NotificationTypeBoardImported tells the users whose notifications were held back
+	// while a board was imported how many there were
+	NotificationTypeBoardImported = "board_imported"
+)
+
+// NotificationCollapseKeyMaxLength is the maximum length of the collapse key of a
+// notification, the size of its column.
+const NotificationCollapseKeyMaxLength = 100
+
+const (
+	NotificationActionDismiss  = "dismiss"
+	NotificationActionUnassign = "unassign"
+)
+
+const (
+	NotificationCategoryMentions = "mentions"
+	NotificationCategoryTasks    = "tasks"
+	NotificationCategorySystem   = "system"
+)
+
+const (
+	NotificationUrgencyLow    = "low"
+	NotificationUrgencyNormal = "normal"
+	NotificationUrgencyHigh   = "high"
+)
+
+// The reasons a user receives a notification, set by the server when it is created.
+const (
+	// NotificationReasonAssignee is for the assignees of a card, or the user who was
+	// unassigned from it
+	NotificationReasonAssignee = "assignee"
+
+	// NotificationReasonWatcher is for the users watching a card without being assigned
+	NotificationReasonWatcher = "watcher"
+
+	// NotificationReasonMentioned is for the users mentioned by name
+	NotificationReasonMentioned = "mentioned"
+
+	// NotificationReasonBoardMention is for the members of a board mentioned through a
+	// board-wide alias, like @channel
+	NotificationReasonBoardMention = "board_mention"
+
+	// NotificationReasonBoardAdmin is for the admins of a board
+	NotificationReasonBoardAdmin = "board_admin"
+
+	// NotificationReasonDigest is for the users receiving the notifications of a board as
+	// a digest
+	NotificationReasonDigest = "digest"
+
+	// NotificationReasonDirect is for the notifications addressed to a user directly, like
+	// test notifications and the ones of registered types
+	NotificationReasonDirect = "direct"
+)
+
+// The code paths a notification can be created from, recorded by the server so admins
+// can trace where notifications come from.
+const (
+	// NotificationSourceAPI is for the notifications created through the HTTP API
+	NotificationSourceAPI = "api"
+
+	// NotificationSourceWebhook is for the notifications created by incoming webhooks
+	NotificationSourceWebhook = "webhook"
+
+	// NotificationSourceJob is for the notifications created by background jobs, like
+	// board digests
+	NotificationSourceJob = "job"
+
+	// NotificationSourcePlugin is for the notifications created by plugins
+	NotificationSourcePlugin = "plugin"
+
+	// NotificationSourceServer is for the notifications the server derives from changes
+	// to cards and boards
+	NotificationSourceServer = "server"
+)
+
+// IsValidNotificationSource returns true if source is one of the notification sources.
+func IsValidNotificationSource(source string) bool {
+	switch source {
+	case NotificationSourceAPI, NotificationSourceWebhook, NotificationSourceJob, NotificationSourcePlugin, NotificationSourceServer:
+		return true
+	}
+	return false
+}
+
+// builtinNotificationTypes are the notification types the server always accepts.
+var builtinNotificationTypes = []string{
+	NotificationTypeAssigned,
+	NotificationTypeUnassigned,
+	NotificationTypeMentioned,
+	NotificationTypeTest,
+	NotificationTypeBoardDigest,
+	NotificationTypeDueDateChanged,
+	NotificationTypeStatusChanged,
+	NotificationTypeCommented,
+	NotificationTypeBoardShared,
+	NotificationTypeBoardImported,
+}
+
+// notificationCategoryTypes maps each category to the notification types it groups.
+// Types that are not listed here belong to NotificationCategorySystem.
+var notificationCategoryTypes = map[string][]string{
+	NotificationCategoryMentions: {NotificationTypeMentioned},
+	NotificationCategoryTasks:    {NotificationTypeAssigned, NotificationTypeUnassigned, NotificationTypeDueDateChanged, NotificationTypeStatusChanged, NotificationTypeCommented},
+}
+
 // UserNotification represents a notification for a user
 // swagger:model
 type UserNotification struct {
@@ -31,6 +164,10 @@ type UserNotification struct {
 	// required: true
 	Type string `json:"type"`
 
+	// The category derived from the notification type (mentions, tasks, system)
+	// required: true
+	Category string `json:"category"`
+
 	// The card ID related to this notification
 	// required: true
 	CardID string `json:"cardId"`
@@ -43,10 +180,90 @@ type UserNotification struct {
 	// required: true
 	BoardID string `json:"boardId"`
 
+	// The team ID of the board, empty for notifications without a board or created before
+	// notifications recorded their team
+	// required: false
+	TeamID string `json:"teamId"`
+
 	// Whether the notification has been read
 	// required: true
 	Read bool `json:"read"`
 
+	// Whether the notification has been archived, hiding it from the default list
+	// required: true
+	Archived bool `json:"archived"`
+
+	// Whether the notification is pinned, keeping it at the top of the list and out of
+	// retention
+	// required: true
+	Pinned bool `json:"pinned"`
+
+	// Hint for clients to alert the user without playing a sound or vibrating
+	// required: true
+	Silent bool `json:"silent"`
+
+	// Hint for clients on how prominently to alert the user (low, normal, high)
+	// required: true
+	Urgency string `json:"urgency"`
+
+	// Why the user receives the notification (assignee, watcher, mentioned, board_mention,
+	// board_admin, digest, direct). Set by the server, empty for notifications created
+	// before reasons were recorded
+	// required: false
+	Reason string `json:"reason,omitempty"`
+
+	// The code path the notification was created from (api, webhook, job, plugin,
+	// server). Set by the server, empty for notifications created before sources were
+	// recorded
+	// required: false
+	Source string `json:"source,omitempty"`
+
+	// If set, creating a notification with the same key for the same target updates the
+	// unread notification that has it in place instead of adding another one
+	// required: false
+	CollapseKey string `json:"collapseKey,omitempty"`
+
+	// Whether the notification was created after the user last opened the notification center
+	// required: false
+	New bool `json:"new"`
+
+	// Whether the notification was only broadcast and never stored, so it can't be read,
+	// archived or deleted later
+	// required: false
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// The actions the user can take on the notification, empty once one of them was taken
+	// required: false
+	Actions []NotificationAction `json:"actions,omitempty"`
+
+	// The key of the action the user took on the notification, if any
+	// required: false
+	ResolvedAction string `json:"resolvedAction,omitempty"`
+
+	// The IDs of the other users notified of the same mention, empty for notifications
+	// with a single target
+	// required: false
+	CoRecipients []string `json:"coRecipients,omitempty"`
+
+	// The human-readable message of the notification, rendered by the server from the
+	// template of its type. Not stored, and empty in websocket catch up messages.
+	// required: false
+	Message string `json:"message,omitempty"`
+
+	// Values filling the placeholders of the message template that are specific to the
+	// notification type, e.g. the old and new dates of a due date change
+	// required: false
+	Params map[string]string `json:"params,omitempty"`
+
+	// The current state of the card, only set when requested with includeCard
+	// required: false
+	Card *NotificationCardSnapshot `json:"card,omitempty"`
+
+	// The devices the notification was read on, only set when fetching a single
+	// notification and for clients tracking reads per device
+	// required: false
+	DeviceReads []*NotificationDeviceRead `json:"deviceReads,omitempty"`
+
 	// Created time in milliseconds since epoch
 	// required: true
 	CreateAt int64 `json:"createAt"`
@@ -56,6 +273,87 @@ type UserNotification struct {
 	UpdateAt int64 `json:"updateAt"`
 }
 
+// NotificationCardSnapshot is the current state of the card of a notification, which may
+// differ from the card title stored when the notification was created.
+// swagger:model
+type NotificationCardSnapshot struct {
+	// The card ID
+	// required: true
+	ID string `json:"id"`
+
+	// The current card title
+	// required: false
+	Title string `json:"title,omitempty"`
+
+	// The current card icon
+	// required: false
+	Icon string `json:"icon,omitempty"`
+
+	// The value of the Status property of the card, empty if the board has none or it is unset
+	// required: false
+	Status string `json:"status,omitempty"`
+
+	// True if the card was deleted since the notification was created
+	// required: false
+	Deleted bool `json:"deleted,omitempty"`
+
+	// Updated time of the card in milliseconds since epoch
+	// required: false
+	UpdateAt int64 `json:"updateAt,omitempty"`
+}
+
+// NotificationAction is an action a user can take straight from a notification
+// swagger:model
+type NotificationAction struct {
+	// The action key, sent back to perform the action
+	// required: true
+	Key string `json:"key"`
+
+	// The label of the button performing the action
+	// required: true
+	Label string `json:"label"`
+}
+
+// NotificationActionRequest is the body of a request performing a notification action
+// swagger:model
+type NotificationActionRequest struct {
+	// The key of the action to perform
+	// required: true
+	Action string `json:"action"`
+}
+
+// IsEphemeralNotificationID returns true if the ID belongs to a notification that was
+// only broadcast and never stored.
+func IsEphemeralNotificationID(notificationID string) bool {
+	return len(notificationID) > 0 && utils.IDType(notificationID[0]) == utils.IDTypeEphemeral
+}
+
+// ErrInvalidUserNotification is returned when a user notification fails validation.
+type ErrInvalidUserNotification struct {
+	msg string
+}
+
+func (e ErrInvalidUserNotification) Error() string {
+	return e.msg
+}
+
+// IsValid checks that the notification has a target and a type known to the registry.
+func (n *UserNotification) IsValid(types *NotificationTypeRegistry) error {
+	if n == nil {
+		return ErrInvalidUserNotification{"cannot be nil"}
+	}
+	if n.TargetUserID == "" {
+		return ErrInvalidUserNotification{"missing target user id"}
+	}
+	if !types.IsRegistered(n.Type) {
+		return ErrInvalidUserNotification{"unknown notification type: " + n.Type}
+	}
+	if len(n.CollapseKey) > NotificationCollapseKeyMaxLength {
+		return ErrInvalidUserNotification{fmt.Sprintf("collapse key longer than %d characters", NotificationCollapseKeyMaxLength)}
+	}
+	return nil
+}
+
 // UserNotificationFromJSON parses a UserNotification from JSON
 func UserNotificationFromJSON(data io.Reader) (*UserNotification, error) {
 	var notification UserNotification
@@ -74,6 +372,7 @@ func NewUserNotification(targetUserID, actorUserID, actorName, notifType, cardID
 		ActorUserID:  actorUserID,
 		ActorName:    actorName,
 		Type:         notifType,
+		Category:     NotificationCategoryForType(notifType),
 		CardID:       cardID,
 		CardTitle:    cardTitle,
 		BoardID:      boardID,
@@ -82,3 +381,562 @@ func NewUserNotification(targetUserID, actorUserID, actorName, notifType, cardID
 		UpdateAt:     now,
 	}
 }
+
+// Sort keys of a notification list.
+const (
+	NotificationOrderByCreateAt = "createAt"
+	NotificationOrderByUpdateAt = "updateAt"
+)
+
+// IsValidNotificationOrderBy returns true if notifications can be sorted by the key. An
+// empty key sorts by creation time.
+func IsValidNotificationOrderBy(orderBy string) bool {
+	switch orderBy {
+	case "", NotificationOrderByCreateAt, NotificationOrderByUpdateAt:
+		return true
+	}
+	return false
+}
+
+// QueryUserNotificationsOptions are the filters applied when listing a user's notifications.
+type QueryUserNotificationsOptions struct {
+	Category        string   // if not empty then filter for notifications whose type belongs to this category
+	Limit           int      // maximum number of notifications to return, no limit if zero
+	IncludeArchived bool     // if true then archived notifications are returned too
+	Since           int64    // if non-zero then only notifications created after this time are returned
+	From            int64    // if non-zero then only notifications created at or after this time are returned
+	To              int64    // if non-zero then only notifications created at or before this time are returned
+	Before          int64    // if non-zero then only notifications created before this time are returned
+	UnresolvedOnly  bool     // if true then only notifications of actionable types no action was taken on are returned
+	BoardIDs        []string // if not empty then filter for notifications of these boards
+	TeamID          string   // if not empty then filter for notifications of this team
+	ExcludeBoardID  string   // if not empty then filter out notifications of this board
+	ExcludeBoardIDs []string // if not empty then filter out notifications of these boards too
+	CardID          string   // if not empty then filter for notifications of this card, newest first ignoring pins
+	OrderBy         string   // the sort key, NotificationOrderByCreateAt if empty
+	Ascending       bool     // if true then the oldest notifications come first
+	Cursor          string   // if not empty then only the notifications listed after the one of this cursor are returned, see NewNotificationsCursor
+
+	Query *NotificationQuery // if not nil then only notifications matching it are returned, along with the other filters
+}
+
+// Filter returns the board, card, team and date filters of the options as a notification
+// filter.
+func (o QueryUserNotificationsOptions) Filter() NotificationFilter {
+	filter := NotificationFilter{
+		BoardIDs: o.BoardIDs,
+		TeamID:   o.TeamID,
+		From:     o.From,
+		To:       o.To,
+	}
+	if o.ExcludeBoardID != "" {
+		filter.ExcludeBoardIDs = []string{o.ExcludeBoardID}
+	}
+	filter.ExcludeBoardIDs = append(filter.ExcludeBoardIDs, o.ExcludeBoardIDs...)
+	if o.CardID != "" {
+		filter.CardIDs = []string{o.CardID}
+	}
+	return filter
+}
+
+// PurgeUserNotificationsOptions selects the notifications deleted by retention. Pinned
+// notifications are never deleted.
+type PurgeUserNotificationsOptions struct {
+	Before       int64    // notifications created before this time are deleted
+	Types        []string // if not empty then only notifications of these types are deleted
+	ExcludeTypes []string // if not empty then notifications of these types are kept
+}
+
+// NotificationBoardReassign moves the notifications of a board to another board, e.g.
+// after boards were merged.
+// swagger:model
+type NotificationBoardReassign struct {
+	// The board the notifications point at
+	// required: true
+	From string `json:"from"`
+
+	// The board the notifications are moved to
+	// required: true
+	To string `json:"to"`
+
+	// Skip checking that both boards exist, e.g. when the old board was already deleted
+	// required: false
+	Force bool `json:"force"`
+}
+
+// UserNotificationPatch corrects the content of a notification. The target, type and
+// creation time of a notification can't change: they are only accepted if they match.
+// swagger:model
+type UserNotificationPatch struct {
+	// The card title
+	// required: false
+	CardTitle *string `json:"cardTitle"`
+
+	// The actor's display name
+	// required: false
+	ActorName *string `json:"actorName"`
+
+	// The user ID who receives the notification, can't be changed
+	// required: false
+	TargetUserID *string `json:"targetUserId"`
+
+	// The notification type, can't be changed
+	// required: false
+	Type *string `json:"type"`
+
+	// Created time in milliseconds since epoch, can't be changed
+	// required: false
+	CreateAt *int64 `json:"createAt"`
+}
+
+// IsValid checks that the patch does not change the immutable fields of the notification.
+func (p *UserNotificationPatch) IsValid(notification *UserNotification) error {
+	if p.TargetUserID != nil && *p.TargetUserID != notification.TargetUserID {
+		return ErrInvalidUserNotification{"the target of a notification can't be changed"}
+	}
+	if p.Type != nil && *p.Type != notification.Type {
+		return ErrInvalidUserNotification{"the type of a notification can't be changed"}
+	}
+	if p.CreateAt != nil && *p.CreateAt != notification.CreateAt {
+		return ErrInvalidUserNotification{"the creation time of a notification can't be changed"}
+	}
+	return nil
+}
+
+// Patch applies the patch to the notification and returns it.
+func (p *UserNotificationPatch) Patch(notification *UserNotification) *UserNotification {
+	if p.CardTitle != nil {
+		notification.CardTitle = *p.CardTitle
+	}
+	if p.ActorName != nil {
+		notification.ActorName = *p.ActorName
+	}
+	return notification
+}
+
+// UnreadNotificationCount is the number of unread notifications of a user, optionally
+// capped for display.
+// swagger:model
+type UnreadNotificationCount struct {
+	// Number of unread notifications, at most the requested cap
+	// required: true
+	Count int `json:"count"`
+
+	// True if the count was capped and the user has more unread notifications
+	// required: true
+	Truncated bool `json:"truncated"`
+}
+
+// NewUnreadNotificationCount returns the count capped at countCap, or uncapped if
+// countCap is 0.
+func NewUnreadNotificationCount(count, countCap int) *UnreadNotificationCount {
+	if countCap > 0 && count > countCap {
+		return &UnreadNotificationCount{Count: countCap, Truncated: true}
+	}
+	return &UnreadNotificationCount{Count: count}
+}
+
+// OldestUnreadNotification is the create time of the oldest unread notification of a user.
+// swagger:model
+type OldestUnreadNotification struct {
+	// Created time of the oldest unread notification in milliseconds since epoch, null if
+	// the user has no unread notification
+	// required: true
+	Timestamp *int64 `json:"timestamp"`
+}
+
+// NotificationReadSync is a read state change a client made while offline
+// swagger:model
+type NotificationReadSync struct {
+	// The notification ID
+	// required: true
+	ID string `json:"id"`
+
+	// Whether the notification was marked as read or unread
+	// required: true
+	Read bool `json:"read"`
+
+	// When the change was made, in milliseconds since epoch
+	// required: true
+	ReadAt int64 `json:"readAt"`
+}
+
+// NotificationThread groups the notifications of a user about the same card
+// swagger:model
+type NotificationThread struct {
+	// The card ID of the thread
+	// required: true
+	CardID string `json:"cardId"`
+
+	// The board ID of the card
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The latest notification about the card
+	// required: true
+	Latest *UserNotification `json:"latest"`
+
+	// The notifications about the card, newest first
+	// required: true
+	Notifications []*UserNotification `json:"notifications"`
+
+	// The number of unread notifications in the thread
+	// required: true
+	UnreadCount int `json:"unreadCount"`
+
+	// Whether every notification of the thread has been read
+	// required: true
+	Read bool `json:"read"`
+}
+
+// GroupNotificationsByCard groups notifications ordered newest first into threads, one per
+// card. A notification that is not about a card is a thread of its own. The threads are
+// ordered by their latest notification, newest first.
+func GroupNotificationsByCard(notifications []*UserNotification) []*NotificationThread {
+	threads := []*NotificationThread{}
+	threadsByKey := map[string]*NotificationThread{}
+
+	for _, notification := range notifications {
+		key := notification.CardID
+		if key == "" {
+			key = notification.ID
+		}
+
+		thread, ok := threadsByKey[key]
+		if !ok {
+			thread = &NotificationThread{
+				CardID:        notification.CardID,
+				BoardID:       notification.BoardID,
+				Latest:        notification,
+				Notifications: []*UserNotification{},
+				Read:          true,
+			}
+			threadsByKey[key] = thread
+			threads = append(threads, thread)
+		}
+
+		thread.Notifications = append(thread.Notifications, notification)
+		if !notification.Read {
+			thread.UnreadCount++
+			thread.Read = false
+		}
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].Latest.CreateAt > threads[j].Latest.CreateAt
+	})
+	return threads
+}
+
+// NotificationActorCount is the number of notifications an actor generated
+// swagger:model
+type NotificationActorCount struct {
+	// The user ID of the actor
+	// required: true
+	ActorUserID string `json:"actorUserId"`
+
+	// The actor's display name, as stored on their latest notifications
+	// required: true
+	ActorName string `json:"actorName"`
+
+	// The number of notifications generated by the actor
+	// required: true
+	Count int64 `json:"count"`
+}
+
+// NotificationBoardUnreadCount is the number of unread notifications of a board
+// swagger:model
+type NotificationBoardUnreadCount struct {
+	// The board ID
+	// required: true
+	BoardID string `json:"boardId"`
+
+	// The number of unread notifications of the board
+	// required: true
+	Count int `json:"count"`
+}
+
+// NotificationSummary counts the notifications of a user by read state. Archived
+// notifications are not counted.
+// swagger:model
+type NotificationSummary struct {
+	// The number of notifications
+	// required: true
+	Total int `json:"total"`
+
+	// The number of unread notifications
+	// required: true
+	Unread int `json:"unread"`
+
+	// The number of read notifications
+	// required: true
+	Read int `json:"read"`
+}
+
+// NotificationTarget is where the client navigates to when a notification is opened.
+// swagger:model
+type NotificationTarget struct {
+	// The team ID of the board
+	// required: false
+	TeamID string `json:"teamId"`
+
+	// The board ID
+	// required: false
+	BoardID string `json:"boardId"`
+
+	// The card ID, empty if the notification targets the board
+	// required: false
+	CardID string `json:"cardId"`
+
+	// The fully qualified link to the board or card, empty if the board no longer exists
+	// required: false
+	Link string `json:"link"`
+}
+
+// OpenedNotification is the response to opening a notification.
+// swagger:model
+type OpenedNotification struct {
+	// The notification, now marked as read
+	// required: true
+	Notification *UserNotification `json:"notification"`
+
+	// Where to navigate to
+	// required: true
+	Target *NotificationTarget `json:"target"`
+}
+
+// NotificationPreview is a notification resolved the way it would be delivered and
+// rendered, without being stored or broadcast.
+// swagger:model
+type NotificationPreview struct {
+	// The notification with the fields the server derives filled in
+	// required: true
+	Notification *UserNotification `json:"notification"`
+
+	// Where the client navigates to when the notification is opened
+	// required: true
+	Target *NotificationTarget `json:"target"`
+
+	// The fully qualified link to the actor's avatar, empty if there is no actor
+	// required: false
+	ActorAvatarURL string `json:"actorAvatarUrl"`
+
+	// The key of the message clients render for the notification
+	// required: true
+	MessageKey string `json:"messageKey"`
+
+	// The values of the message placeholders
+	// required: true
+	MessageParams map[string]string `json:"messageParams"`
+}
+
+// NotificationMessageKey returns the key of the message clients render for a notification
+// type. Custom types share a generic message.
+func NotificationMessageKey(notifType string) string {
+	switch notifType {
+	case NotificationTypeAssigned:
+		return "NotificationBell.youAssigned"
+	case NotificationTypeUnassigned:
+		return "NotificationBell.youUnassigned"
+	case NotificationTypeMentioned:
+		return "NotificationBell.mentioned"
+	default:
+		return "NotificationBell.generic"
+	}
+}
+
+// NotificationLastSeen is the time a user last opened the notification center.
+// swagger:model
+type NotificationLastSeen struct {
+	// Last seen time in milliseconds since epoch, 0 if never seen
+	// required: true
+	LastSeen int64 `json:"lastSeen"`
+}
+
+// CreateUserNotificationOptions control the checks applied when creating a notification.
+type CreateUserNotificationOptions struct {
+	SkipAssigneeCheck bool   // if true then assignment notifications are not checked against the card's assignees
+	SkipPreferences   bool   // if true then the notification is delivered even if the target user's preferences suppress it
+	Synchronous       bool   // if true then the notification is stored and returned instead of being queued
+	Ephemeral         bool   // if true then the notification is only broadcast and never stored
+	Source            string // the code path creating the notification, NotificationSourceServer if empty
+}
+
+// SkippedNotification is a notification of a batch that was left out because it is
+// invalid, e.g. as its target user doesn't exist.
+// swagger:model
+type SkippedNotification struct {
+	// The index of the notification in the batch
+	// required: true
+	Index int `json:"index"`
+
+	// Why the notification was left out
+	// required: true
+	Error string `json:"error"`
+}
+
+// CreateNotificationsResponse is the response body to the creation of a batch of
+// notifications.
+// swagger:model
+type CreateNotificationsResponse struct {
+	// The notifications that were created, or updated in place of an unread one
+	// required: true
+	Created []*UserNotification `json:"created"`
+
+	// The notifications of the batch that were left out as invalid
+	// required: true
+	Skipped []*SkippedNotification `json:"skipped"`
+}
+
+// SearchUserNotificationsOptions are the filters applied when admins search the
+// notifications of every user.
+type SearchUserNotificationsOptions struct {
+	UserID  string // if not empty then filter for notifications targeting this user
+	BoardID string // if not empty then filter for notifications of this board
+	Type    string // if not empty then filter for notifications of this type
+	Source  string // if not empty then filter for notifications created from this source
+	Page    int    // page number to select when paginating
+	PerPage int    // number of notifications per page
+}
+
+// NotificationsSearchResponse is the response body to an admin search of notifications.
+// swagger:model
+type NotificationsSearchResponse struct {
+	// True if there is a next page for pagination
+	// required: true
+	HasNext bool `json:"hasNext"`
+
+	// The notifications matching the search, newest first
+	// required: true
+	Results []*UserNotification `json:"results"`
+}
+
+// MarkNotificationsAsReadOptions narrow a mark-all-as-read sweep to a subset of a user's notifications.
+type MarkNotificationsAsReadOptions struct {
+	BoardID string // if not empty then only notifications for this board are marked
+	Type    string // if not empty then only notifications of this type are marked
+	CardID  string // if not empty then only notifications for this card are marked
+}
+
+// IsScoped returns true if the sweep is narrowed to a board, a type or a card.
+func (o MarkNotificationsAsReadOptions) IsScoped() bool {
+	return o.BoardID != "" || o.Type != "" || o.CardID != ""
+}
+
+// MarkNotificationsAsReadRequest is the body of a request marking a batch of
+// notifications as read.
+// swagger:model
+type MarkNotificationsAsReadRequest struct {
+	// The IDs of the notifications to mark as read
+	// required: true
+	IDs []string `json:"ids"`
+}
+
+// NotificationCategoryForType returns the category a notification type belongs to.
+func NotificationCategoryForType(notifType string) string {
+	for category, types := range notificationCategoryTypes {
+		for _, t := range types {
+			if t == notifType {
+				return category
+			}
+		}
+	}
+	return NotificationCategorySystem
+}
+
+// NotificationActionsForType returns the actions declared for a notification type. Every
+// type can be dismissed, assignments can also be undone by the assignee.
+func NotificationActionsForType(notifType string) []NotificationAction {
+	actions := []NotificationAction{}
+	if notifType == NotificationTypeAssigned {
+		actions = append(actions, NotificationAction{Key: NotificationActionUnassign, Label: "Unassign me"})
+	}
+	return append(actions, NotificationAction{Key: NotificationActionDismiss, Label: "Dismiss"})
+}
+
+// ActionableNotificationTypes returns the notification types offering an action besides
+// dismissing them. Notifications of other types never need the user's attention.
+func ActionableNotificationTypes() []string {
+	types := []string{}
+	for _, notifType := range builtinNotificationTypes {
+		if len(NotificationActionsForType(notifType)) > 1 {
+			types = append(types, notifType)
+		}
+	}
+	return types
+}
+
+// SetActions fills the actions of a notification from its type, or clears them if the
+// notification can't be acted upon anymore.
+func (n *UserNotification) SetActions() {
+	if n.ResolvedAction != "" || n.Ephemeral {
+		n.Actions = nil
+		return
+	}
+	n.Actions = NotificationActionsForType(n.Type)
+}
+
+// HasAction returns true if the action key is one of the notification's actions.
+func (n *UserNotification) HasAction(key string) bool {
+	for _, action := range n.Actions {
+		if action.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationAlertForCategory returns the alert hints of the notifications of a category:
+// mentions are urgent, task changes are audible and system notifications are silent.
+func NotificationAlertForCategory(category string) (silent bool, urgency string) {
+	switch category {
+	case NotificationCategoryMentions:
+		return false, NotificationUrgencyHigh
+	case NotificationCategoryTasks:
+		return false, NotificationUrgencyNormal
+	default:
+		return true, NotificationUrgencyLow
+	}
+}
+
+// NotificationReasonForType returns the reason a user receives a notification of a type
+// when nothing more specific is known, e.g. for the notifications created through the API.
+func NotificationReasonForType(notifType string) string {
+	switch notifType {
+	case NotificationTypeAssigned, NotificationTypeUnassigned, NotificationTypeDueDateChanged, NotificationTypeStatusChanged, NotificationTypeCommented:
+		return NotificationReasonAssignee
+	case NotificationTypeMentioned:
+		return NotificationReasonMentioned
+	case NotificationTypeBoardShared:
+		return NotificationReasonBoardAdmin
+	case NotificationTypeBoardDigest:
+		return NotificationReasonDigest
+	default:
+		return NotificationReasonDirect
+	}
+}
+
+// NotificationTypesForCategory returns the notification types grouped by a category.
+// NotificationCategorySystem has no fixed set of types and returns nil.
+func NotificationTypesForCategory(category string) []string {
+	return notificationCategoryTypes[category]
+}
+
+// CategorizedNotificationTypes returns every notification type that belongs to a
+// category other than NotificationCategorySystem.
+func CategorizedNotificationTypes() []string {
+	var types []string
+	for _, t := range notificationCategoryTypes {
+		types = append(types, t...)
+	}
+	return types
+}
+
+// IsValidNotificationCategory returns true if the category is a known notification category.
+func IsValidNotificationCategory(category string) bool {
+	if category == NotificationCategorySystem {
+		return true
+	}
+	_, ok := notificationCategoryTypes[category]
+	return ok
+}
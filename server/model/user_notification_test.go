@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationCategoryForType(t *testing.T) {
+	assert.Equal(t, NotificationCategoryMentions, NotificationCategoryForType(NotificationTypeMentioned))
+	assert.Equal(t, NotificationCategoryTasks, NotificationCategoryForType(NotificationTypeAssigned))
+	assert.Equal(t, NotificationCategoryTasks, NotificationCategoryForType(NotificationTypeUnassigned))
+	assert.Equal(t, NotificationCategorySystem, NotificationCategoryForType("maintenance"))
+}
+
+func TestIsValidNotificationCategory(t *testing.T) {
+	assert.True(t, IsValidNotificationCategory(NotificationCategoryMentions))
+	assert.True(t, IsValidNotificationCategory(NotificationCategoryTasks))
+	assert.True(t, IsValidNotificationCategory(NotificationCategorySystem))
+	assert.False(t, IsValidNotificationCategory("unknown"))
+	assert.False(t, IsValidNotificationCategory(""))
+}
+
+func TestIsValidNotificationOrderBy(t *testing.T) {
+	assert.True(t, IsValidNotificationOrderBy(""))
+	assert.True(t, IsValidNotificationOrderBy(NotificationOrderByCreateAt))
+	assert.True(t, IsValidNotificationOrderBy(NotificationOrderByUpdateAt))
+	assert.False(t, IsValidNotificationOrderBy("update_at"))
+}
+
+func TestCategorizedNotificationTypes(t *testing.T) {
+	assert.ElementsMatch(t,
+		[]string{NotificationTypeMentioned, NotificationTypeAssigned, NotificationTypeUnassigned, NotificationTypeDueDateChanged, NotificationTypeStatusChanged, NotificationTypeCommented},
+		CategorizedNotificationTypes(),
+	)
+}
+
+func TestNotificationReasonForType(t *testing.T) {
+	assert.Equal(t, NotificationReasonAssignee, NotificationReasonForType(NotificationTypeAssigned))
+	assert.Equal(t, NotificationReasonAssignee, NotificationReasonForType(NotificationTypeStatusChanged))
+	assert.Equal(t, NotificationReasonMentioned, NotificationReasonForType(NotificationTypeMentioned))
+	assert.Equal(t, NotificationReasonBoardAdmin, NotificationReasonForType(NotificationTypeBoardShared))
+	assert.Equal(t, NotificationReasonDigest, NotificationReasonForType(NotificationTypeBoardDigest))
+	assert.Equal(t, NotificationReasonDirect, NotificationReasonForType("maintenance"))
+}
+
+func TestIsValidNotificationSource(t *testing.T) {
+	assert.True(t, IsValidNotificationSource(NotificationSourceAPI))
+	assert.True(t, IsValidNotificationSource(NotificationSourceJob))
+	assert.False(t, IsValidNotificationSource(""))
+	assert.False(t, IsValidNotificationSource("cron"))
+}
+
+func TestNotificationAlertForCategory(t *testing.T) {
+	silent, urgency := NotificationAlertForCategory(NotificationCategoryMentions)
+	assert.False(t, silent)
+	assert.Equal(t, NotificationUrgencyHigh, urgency)
+
+	silent, urgency = NotificationAlertForCategory(NotificationCategoryTasks)
+	assert.False(t, silent)
+	assert.Equal(t, NotificationUrgencyNormal, urgency)
+
+	silent, urgency = NotificationAlertForCategory(NotificationCategorySystem)
+	assert.True(t, silent)
+	assert.Equal(t, NotificationUrgencyLow, urgency)
+}
+
+func TestNewUnreadNotificationCount(t *testing.T) {
+	assert.Equal(t, &UnreadNotificationCount{Count: 1234567}, NewUnreadNotificationCount(1234567, 0))
+	assert.Equal(t, &UnreadNotificationCount{Count: 99}, NewUnreadNotificationCount(99, 99))
+	assert.Equal(t, &UnreadNotificationCount{Count: 99, Truncated: true}, NewUnreadNotificationCount(100, 99))
+}
+
+func TestNotificationActions(t *testing.T) {
+	notification := &UserNotification{Type: NotificationTypeAssigned}
+	notification.SetActions()
+	assert.True(t, notification.HasAction(NotificationActionUnassign))
+	assert.True(t, notification.HasAction(NotificationActionDismiss))
+
+	notification = &UserNotification{Type: NotificationTypeMentioned}
+	notification.SetActions()
+	assert.False(t, notification.HasAction(NotificationActionUnassign))
+	assert.True(t, notification.HasAction(NotificationActionDismiss))
+
+	notification = &UserNotification{Type: NotificationTypeAssigned, ResolvedAction: NotificationActionDismiss}
+	notification.SetActions()
+	assert.Empty(t, notification.Actions)
+}
+
+func TestGroupNotificationsByCard(t *testing.T) {
+	notifications := []*UserNotification{
+		{ID: "n-3", CardID: "card-2", Read: true, CreateAt: 400},
+		{ID: "n-1", CardID: "card-1", Read: true, CreateAt: 300},
+		{ID: "n-2", CardID: "card-1", Read: false, CreateAt: 100},
+	}
+
+	threads := GroupNotificationsByCard(notifications)
+	assert.Len(t, threads, 2)
+
+	assert.Equal(t, "card-2", threads[0].CardID)
+	assert.Equal(t, "n-3", threads[0].Latest.ID)
+	assert.True(t, threads[0].Read)
+
+	assert.Equal(t, "card-1", threads[1].CardID)
+	assert.Equal(t, "n-1", threads[1].Latest.ID)
+	assert.Len(t, threads[1].Notifications, 2)
+	assert.Equal(t, 1, threads[1].UnreadCount)
+	assert.False(t, threads[1].Read)
+}
+
+func TestGroupNotificationsByCardWithoutCard(t *testing.T) {
+	notifications := []*UserNotification{
+		{ID: "n-1", CreateAt: 300},
+		{ID: "n-2", CardID: "card-1", CreateAt: 200},
+		{ID: "n-3", CreateAt: 100},
+	}
+
+	threads := GroupNotificationsByCard(notifications)
+	assert.Len(t, threads, 3)
+	assert.Equal(t, "n-1", threads[0].Latest.ID)
+	assert.Len(t, threads[0].Notifications, 1)
+	assert.Equal(t, "card-1", threads[1].CardID)
+	assert.Equal(t, "n-3", threads[2].Latest.ID)
+	assert.Len(t, threads[2].Notifications, 1)
+}
+
+func TestActionableNotificationTypes(t *testing.T) {
+	assert.Equal(t, []string{NotificationTypeAssigned}, ActionableNotificationTypes())
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/audit"
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+// auditStore persists the records of the audit service in the database.
+type auditStore struct {
+	store store.Store
+}
+
+func (s auditStore) CreateAuditRecords(records []*audit.StoredRecord) error {
+	auditRecords := make([]*model.AuditRecord, 0, len(records))
+	for _, record := range records {
+		auditRecords = append(auditRecords, &model.AuditRecord{
+			Level:     record.Level,
+			Event:     record.Event,
+			Status:    record.Status,
+			UserID:    record.UserID,
+			SessionID: record.SessionID,
+			APIPath:   record.APIPath,
+			Client:    record.Client,
+			IPAddress: record.IPAddress,
+			Meta:      record.Meta,
+			CreateAt:  record.CreateAt,
+		})
+	}
+	return s.store.CreateAuditRecords(auditRecords)
+}
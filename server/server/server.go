@@ -38,8 +38,11 @@ import (
 )
 
 const (
-	cleanupSessionTaskFrequency = 10 * time.Minute
-	updateMetricsTaskFrequency  = 15 * time.Minute
+	cleanupSessionTaskFrequency     = 10 * time.Minute
+	updateMetricsTaskFrequency      = 15 * time.Minute
+	purgeAuditRecordsTaskFrequency  = 24 * time.Hour
+	purgeNotificationsTaskFrequency = 24 * time.Hour
+	escalationTaskFrequency         = 5 * time.Minute
 
 	minSessionExpiryTime = int64(60 * 60 * 24 * 31) // 31 days
 
@@ -58,6 +61,10 @@ type Server struct {
 	metricsServer          *metrics.Service
 	metricsService         *metrics.Metrics
 	metricsUpdaterTask     *scheduler.ScheduledTask
+	purgeAuditRecordsTask  *scheduler.ScheduledTask
+	purgeNotificationsTask *scheduler.ScheduledTask
+	notificationDigestTask *scheduler.ScheduledTask
+	escalationTask         *scheduler.ScheduledTask
 	auditService           *audit.Audit
 	notificationService    *notify.Service
 	servicesStartStopMutex sync.Mutex
@@ -78,7 +85,9 @@ func New(params Params) (*Server, error) {
 	// if no ws adapter is provided, we spin up a websocket server
 	wsAdapter := params.WSAdapter
 	if wsAdapter == nil {
-		wsAdapter = ws.NewServer(authenticator, params.SingleUserToken, params.Cfg.AuthMode == MattermostAuthMod, params.Logger, params.DBStore)
+		wsServer := ws.NewServer(authenticator, params.SingleUserToken, params.Cfg.AuthMode == MattermostAuthMod, params.Logger, params.DBStore)
+		wsServer.SetHeartbeatInterval(time.Duration(params.Cfg.WebSocketHeartbeatSeconds) * time.Second)
+		wsAdapter = wsServer
 	}
 
 	filesBackendSettings := filestore.FileBackendSettings{}
@@ -122,6 +131,7 @@ func New(params Params) (*Server, error) {
 	if err := auditService.Configure(params.Cfg.AuditCfgFile, params.Cfg.AuditCfgJSON); err != nil {
 		return nil, fmt.Errorf("unable to initialize the audit service: %w", err)
 	}
+	auditService.SetStore(auditStore{store: params.DBStore})
 
 	// Init notification services
 	notificationService, errNotify := initNotificationService(params.NotifyBackends, params.Logger)
@@ -294,6 +304,64 @@ func (s *Server) Start() error {
 	// metricsUpdater()   Calling this immediately causes integration unit tests to fail.
 	s.metricsUpdaterTask = scheduler.CreateRecurringTask("updateMetrics", metricsUpdater, updateMetricsTaskFrequency)
 
+	if s.config.AuditRetentionDays > 0 {
+		s.purgeAuditRecordsTask = scheduler.CreateRecurringTask("purgeAuditRecords", func() {
+			deleted, err := s.app.PurgeAuditRecords(s.config.AuditRetentionDays)
+			if err != nil {
+				s.logger.Error("Unable to purge audit records", mlog.Err(err))
+				return
+			}
+			s.logger.Info("Audit records purged",
+				mlog.Int("retention_days", s.config.AuditRetentionDays),
+				mlog.Int("deleted", deleted),
+			)
+		}, purgeAuditRecordsTaskFrequency)
+	}
+
+	if s.config.NotificationRetentionDays > 0 || len(s.config.NotificationTypeRetentionDays) > 0 {
+		s.purgeNotificationsTask = scheduler.CreateRecurringTask("purgeNotifications", func() {
+			deleted, err := s.app.PurgeUserNotifications(s.config.NotificationRetentionDays, s.config.NotificationTypeRetentionDays)
+			for notifType, count := range deleted {
+				retentionDays := s.config.NotificationRetentionDays
+				if notifType != "" {
+					retentionDays = s.config.NotificationTypeRetentionDays[notifType]
+				}
+				s.logger.Info("Notifications purged",
+					mlog.String("type", notifType),
+					mlog.Int("retention_days", retentionDays),
+					mlog.Int("deleted", count),
+				)
+			}
+			if err != nil {
+				s.logger.Error("Unable to purge notifications", mlog.Err(err))
+			}
+		}, purgeNotificationsTaskFrequency)
+	}
+
+	if s.config.NotificationDigestIntervalMinutes > 0 {
+		interval := time.Duration(s.config.NotificationDigestIntervalMinutes) * time.Minute
+		s.notificationDigestTask = scheduler.CreateRecurringTask("sendNotificationDigests", func() {
+			sent, err := s.app.SendNotificationDigests(interval)
+			if err != nil {
+				s.logger.Error("Unable to send notification digests", mlog.Err(err))
+				return
+			}
+			s.logger.Debug("Notification digests sent", mlog.Int("sent", sent))
+		}, interval)
+	}
+
+	if s.config.NotificationEscalationMinutes > 0 {
+		threshold := time.Duration(s.config.NotificationEscalationMinutes) * time.Minute
+		s.escalationTask = scheduler.CreateRecurringTask("escalateNotifications", func() {
+			escalated, err := s.app.EscalateNotifications(threshold)
+			if err != nil {
+				s.logger.Error("Unable to escalate notifications", mlog.Err(err))
+				return
+			}
+			s.logger.Debug("Notifications escalated", mlog.Int("escalated", escalated))
+		}, escalationTaskFrequency)
+	}
+
 	if s.config.Telemetry {
 		firstRun := utils.GetMillis()
 		s.telemetry.RunTelemetryJob(firstRun)
@@ -335,6 +403,22 @@ func (s *Server) Shutdown() error {
 		s.metricsUpdaterTask.Cancel()
 	}
 
+	if s.purgeAuditRecordsTask != nil {
+		s.purgeAuditRecordsTask.Cancel()
+	}
+
+	if s.purgeNotificationsTask != nil {
+		s.purgeNotificationsTask.Cancel()
+	}
+
+	if s.notificationDigestTask != nil {
+		s.notificationDigestTask.Cancel()
+	}
+
+	if s.escalationTask != nil {
+		s.escalationTask.Cancel()
+	}
+
 	if err := s.telemetry.Shutdown(); err != nil {
 		s.logger.Warn("Error occurred when shutting down telemetry", mlog.Err(err))
 	}
@@ -1,6 +1,11 @@
 package audit
 
 import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
@@ -28,9 +33,39 @@ var (
 	LevelRead   = mlog.Level{ID: 1002, Name: "read"}
 )
 
+// persistBatchSize caps the records written to the store in a single insert.
+const persistBatchSize = 100
+
+// StoredRecord is an audit record in the form it is persisted in, its metadata
+// serialized as a JSON object.
+type StoredRecord struct {
+	Level     string
+	Event     string
+	Status    string
+	UserID    string
+	SessionID string
+	APIPath   string
+	Client    string
+	IPAddress string
+	Meta      string
+	CreateAt  int64
+}
+
+// Store persists audit records so they can be queried after they have been logged.
+type Store interface {
+	CreateAuditRecords(records []*StoredRecord) error
+}
+
 // Audit provides auditing service.
 type Audit struct {
 	auditLogger *mlog.Logger
+	store       Store
+
+	// records waiting to be persisted, written in batches by persistRecords
+	queue     chan *StoredRecord
+	persisted chan struct{}
+	queueMux  sync.RWMutex
+	closed    bool
 }
 
 // NewAudit creates a new Audit instance which can be configured via `(*Audit).Configure`.
@@ -58,9 +93,29 @@ func (a *Audit) Configure(cfgFile string, cfgEscaped string) error {
 	return a.auditLogger.Configure(cfgFile, cfgEscaped, nil)
 }
 
+// SetStore sets the store used to persist audit records and starts writing them in the
+// background, so logging a record never waits for the database. When no store is set
+// records are only emitted to the configured log targets. It must be called at most once.
+func (a *Audit) SetStore(store Store) {
+	a.store = store
+	a.queue = make(chan *StoredRecord, DefMaxQueueSize)
+	a.persisted = make(chan struct{})
+	go a.persistRecords()
+}
+
 // Shutdown shuts down the audit service after making best efforts to flush any
 // remaining records.
 func (a *Audit) Shutdown() error {
+	a.queueMux.Lock()
+	if a.queue != nil && !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.queueMux.Unlock()
+
+	if a.persisted != nil {
+		<-a.persisted
+	}
 	return a.auditLogger.Shutdown()
 }
 
@@ -81,4 +136,79 @@ func (a *Audit) LogRecord(level mlog.Level, rec *Record) {
 	}
 
 	a.auditLogger.Log(level, "audit "+rec.Event, fields...)
+
+	a.enqueueRecord(makeStoredRecord(level, rec))
+}
+
+// enqueueRecord queues a record to be persisted. If the queue is full the record is
+// dropped rather than holding up the request being audited.
+func (a *Audit) enqueueRecord(record *StoredRecord) {
+	a.queueMux.RLock()
+	defer a.queueMux.RUnlock()
+
+	if a.queue == nil || a.closed {
+		return
+	}
+
+	select {
+	case a.queue <- record:
+	default:
+		a.auditLogger.Error("cannot persist audit record, queue is full", mlog.String(KeyEvent, record.Event))
+	}
+}
+
+// persistRecords writes the queued records to the store until the queue is closed,
+// batching the records that queued up while the previous batch was written.
+func (a *Audit) persistRecords() {
+	defer close(a.persisted)
+
+	for record := range a.queue {
+		batch := []*StoredRecord{record}
+	drain:
+		for len(batch) < persistBatchSize {
+			select {
+			case next, ok := <-a.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+
+		if err := a.store.CreateAuditRecords(batch); err != nil {
+			a.auditLogger.Error("cannot persist audit records", mlog.Int("count", len(batch)), mlog.Err(err))
+		}
+	}
+}
+
+// makeStoredRecord converts a record into its persisted form, serializing
+// the metadata as a JSON object.
+func makeStoredRecord(level mlog.Level, rec *Record) *StoredRecord {
+	record := &StoredRecord{
+		Level:     level.Name,
+		Event:     rec.Event,
+		Status:    rec.Status,
+		UserID:    rec.UserID,
+		SessionID: rec.SessionID,
+		APIPath:   rec.APIPath,
+		Client:    rec.Client,
+		IPAddress: rec.IPAddress,
+		CreateAt:  time.Now().UnixMilli(),
+	}
+
+	if len(rec.Meta) > 0 {
+		meta := make(map[string]interface{}, len(rec.Meta))
+		for _, m := range rec.Meta {
+			meta[m.K] = m.V
+		}
+		data, err := json.Marshal(meta)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{"error": fmt.Sprintf("cannot serialize meta: %v", err)})
+		}
+		record.Meta = string(data)
+	}
+
+	return record
 }
@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package audit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeStoredRecord(t *testing.T) {
+	t.Run("without meta", func(t *testing.T) {
+		rec := &Record{
+			APIPath:   "/api/v2/admin/users",
+			Event:     "adminGetAllUsers",
+			Status:    Success,
+			UserID:    "user-id",
+			SessionID: "session-id",
+			Client:    "test-client",
+			IPAddress: "127.0.0.1",
+		}
+
+		record := makeStoredRecord(LevelAuth, rec)
+		require.Equal(t, "auth", record.Level)
+		require.Equal(t, rec.Event, record.Event)
+		require.Equal(t, rec.Status, record.Status)
+		require.Equal(t, rec.UserID, record.UserID)
+		require.Equal(t, rec.SessionID, record.SessionID)
+		require.Equal(t, rec.APIPath, record.APIPath)
+		require.Equal(t, rec.Client, record.Client)
+		require.Equal(t, rec.IPAddress, record.IPAddress)
+		require.Empty(t, record.Meta)
+		require.NotZero(t, record.CreateAt)
+	})
+
+	t.Run("with meta", func(t *testing.T) {
+		rec := &Record{Event: "adminDeleteUser", Status: Fail}
+		rec.AddMeta("userID", "deleted-user")
+		rec.AddMeta("count", 3)
+
+		record := makeStoredRecord(LevelModify, rec)
+		require.Equal(t, "mod", record.Level)
+		require.JSONEq(t, `{"userID":"deleted-user","count":3}`, record.Meta)
+	})
+
+	t.Run("with unserializable meta", func(t *testing.T) {
+		rec := &Record{Event: "test"}
+		rec.AddMeta("ch", make(chan int))
+
+		record := makeStoredRecord(LevelRead, rec)
+		require.Contains(t, record.Meta, "cannot serialize meta")
+	})
+}
+
+type testStore struct {
+	mux     sync.Mutex
+	records []*StoredRecord
+}
+
+func (s *testStore) CreateAuditRecords(records []*StoredRecord) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func TestPersistRecords(t *testing.T) {
+	auditService, err := NewAudit()
+	require.NoError(t, err)
+	store := &testStore{}
+	auditService.SetStore(store)
+
+	for i := 0; i < 250; i++ {
+		auditService.LogRecord(LevelRead, &Record{Event: "getBoard", Status: Success})
+	}
+	require.NoError(t, auditService.Shutdown())
+
+	require.Len(t, store.records, 250)
+	require.Equal(t, "getBoard", store.records[0].Event)
+
+	// records logged after the shutdown are not persisted
+	auditService.LogRecord(LevelRead, &Record{Event: "getBoard", Status: Success})
+	require.Len(t, store.records, 250)
+}
@@ -62,14 +62,71 @@ type Configuration struct {
 
 	AuthMode string `json:"authMode" mapstructure:"authMode"`
 
+	EnableFirstUserAdmin bool     `json:"enable_first_user_admin" mapstructure:"enableFirstUserAdmin"`
+	AdminUserIDs         []string `json:"admin_user_ids" mapstructure:"adminUserIDs"`
+	AllowedEmailDomains  []string `json:"allowed_email_domains" mapstructure:"allowedEmailDomains"`
+
 	LoggingCfgFile string `json:"logging_cfg_file" mapstructure:"logging_cfg_file"`
 	LoggingCfgJSON string `json:"logging_cfg_json" mapstructure:"logging_cfg_json"`
 
 	AuditCfgFile string `json:"audit_cfg_file" mapstructure:"audit_cfg_file"`
 	AuditCfgJSON string `json:"audit_cfg_json" mapstructure:"audit_cfg_json"`
 
+	AuditRetentionDays int `json:"audit_retention_days" mapstructure:"auditRetentionDays"`
+
 	NotifyFreqCardSeconds  int `json:"notify_freq_card_seconds" mapstructure:"notify_freq_card_seconds"`
 	NotifyFreqBoardSeconds int `json:"notify_freq_board_seconds" mapstructure:"notify_freq_board_seconds"`
+
+	BoardMentionAliases []string `json:"board_mention_aliases" mapstructure:"boardMentionAliases"`
+
+	AvatarCacheSize        int64 `json:"avatar_cache_size" mapstructure:"avatarCacheSize"`
+	AvatarCacheMaxItemSize int64 `json:"avatar_cache_max_item_size" mapstructure:"avatarCacheMaxItemSize"`
+	AvatarMaxServeSize     int64 `json:"avatar_max_serve_size" mapstructure:"avatarMaxServeSize"`
+
+	AvatarSources    []string `json:"avatar_sources" mapstructure:"avatarSources"`
+	AvatarSyncedPath string   `json:"avatar_synced_path" mapstructure:"avatarSyncedPath"`
+
+	AvatarAllowedTypes []string `json:"avatar_allowed_types" mapstructure:"avatarAllowedTypes"`
+	AvatarMaxDimension int      `json:"avatar_max_dimension" mapstructure:"avatarMaxDimension"`
+
+	PrivateAvatars         bool `json:"private_avatars" mapstructure:"privateAvatars"`
+	AvatarURLExpirySeconds int  `json:"avatar_url_expiry_seconds" mapstructure:"avatarURLExpirySeconds"`
+
+	NotificationCustomTypes  []string `json:"notification_custom_types" mapstructure:"notificationCustomTypes"`
+	NotificationQueueSize    int      `json:"notification_queue_size" mapstructure:"notificationQueueSize"`
+	NotificationQueueWorkers int      `json:"notification_queue_workers" mapstructure:"notificationQueueWorkers"`
+	NotificationMinBoardRole string   `json:"notification_min_board_role" mapstructure:"notificationMinBoardRole"`
+	NotificationBatchMaxSize int      `json:"notification_batch_max_size" mapstructure:"notificationBatchMaxSize"`
+
+	RestrictNotificationCreate bool `json:"restrict_notification_create" mapstructure:"restrictNotificationCreate"`
+	EnableNotificationPreview  bool `json:"enable_notification_preview" mapstructure:"enableNotificationPreview"`
+
+	NotificationDigestIntervalMinutes int `json:"notification_digest_interval_minutes" mapstructure:"notificationDigestIntervalMinutes"`
+
+	DefaultBoardMemberRole string `json:"default_board_member_role" mapstructure:"defaultBoardMemberRole"`
+
+	AdminPasswordResetsPerMinute int `json:"admin_password_resets_per_minute" mapstructure:"adminPasswordResetsPerMinute"`
+
+	AvatarUploadsPerMinute int `json:"avatar_uploads_per_minute" mapstructure:"avatarUploadsPerMinute"`
+
+	NotificationEscalationMinutes  int      `json:"notification_escalation_minutes" mapstructure:"notificationEscalationMinutes"`
+	NotificationEscalationChannels []string `json:"notification_escalation_channels" mapstructure:"notificationEscalationChannels"`
+
+	NotificationRetentionDays     int            `json:"notification_retention_days" mapstructure:"notificationRetentionDays"`
+	NotificationTypeRetentionDays map[string]int `json:"notification_type_retention_days" mapstructure:"notificationTypeRetentionDays"`
+
+	NotificationTemplates map[string]string `json:"notification_templates" mapstructure:"notificationTemplates"`
+
+	NotificationStatuses              []string `json:"notification_statuses" mapstructure:"notificationStatuses"`
+	NotificationStatusDebounceSeconds int      `json:"notification_status_debounce_seconds" mapstructure:"notificationStatusDebounceSeconds"`
+
+	NotificationUnreadCountsByType bool `json:"notification_unread_counts_by_type" mapstructure:"notificationUnreadCountsByType"`
+
+	NotificationHideInaccessibleBoards bool `json:"notification_hide_inaccessible_boards" mapstructure:"notificationHideInaccessibleBoards"`
+
+	NotificationImportSummary bool `json:"notification_import_summary" mapstructure:"notificationImportSummary"`
+
+	WebSocketHeartbeatSeconds int `json:"websocket_heartbeat_seconds" mapstructure:"webSocketHeartbeatSeconds"`
 }
 
 // ReadConfigFile read the configuration from the filesystem.
@@ -103,15 +160,58 @@ func ReadConfigFile(configFilePath string) (*Configuration, error) {
 	viper.SetDefault("EnablePublicSharedBoards", false)
 	viper.SetDefault("EnableOpenRegistration", false)
 	viper.SetDefault("AuthMode", "native")
-	viper.SetDefault("NotifyFreqCardSeconds", 120)    // 2 minutes after last card edit
-	viper.SetDefault("NotifyFreqBoardSeconds", 86400) // 1 day after last card edit
+	viper.SetDefault("EnableFirstUserAdmin", true) // the first registered user is a system admin
+	viper.SetDefault("AdminUserIDs", []string{})
+	viper.SetDefault("AllowedEmailDomains", []string{}) // users can only register or change to emails of these domains, empty allows any
+	viper.SetDefault("NotifyFreqCardSeconds", 120)      // 2 minutes after last card edit
+	viper.SetDefault("NotifyFreqBoardSeconds", 86400)   // 1 day after last card edit
 	viper.SetDefault("EnableDataRetention", false)
 	viper.SetDefault("FeatureFlags", map[string]string{})
 	viper.SetDefault("DataRetentionDays", 365) // 1 year is default
 	viper.SetDefault("PrometheusAddress", "")
+	viper.SetDefault("AuditRetentionDays", 0) // 0 keeps audit records forever
 	viper.SetDefault("TeammateNameDisplay", "username")
 	viper.SetDefault("ShowEmailAddress", false)
 	viper.SetDefault("ShowFullName", false)
+	viper.SetDefault("BoardMentionAliases", []string{"board", "all"})
+	viper.SetDefault("AvatarCacheSize", 16*1024*1024)   // 16 MB of avatars kept in memory, 0 disables the cache
+	viper.SetDefault("AvatarCacheMaxItemSize", 64*1024) // larger avatars are always read from disk
+	viper.SetDefault("AvatarMaxServeSize", 5*1024*1024) // larger avatars are not served, 0 disables the limit
+	viper.SetDefault("AvatarSources", []string{"local", "synced", "default"})
+	viper.SetDefault("AvatarSyncedPath", "")
+	viper.SetDefault("PrivateAvatars", false)       // avatars are only served with a signed URL token when set
+	viper.SetDefault("AvatarURLExpirySeconds", 300) // signed avatar URLs expire after 5 minutes
+	viper.SetDefault("NotificationQueueSize", 0)    // 0 stores notifications synchronously, set a size to queue them
+	viper.SetDefault("NotificationQueueWorkers", 4)
+	viper.SetDefault("NotificationMinBoardRole", "viewer")    // board members below this role get no board notifications
+	viper.SetDefault("NotificationDigestIntervalMinutes", 60) // 0 disables sending board digests
+	viper.SetDefault("RestrictNotificationCreate", true)      // only admins may notify users they share no board with
+	viper.SetDefault("EnableNotificationPreview", true)       // lets integrators preview how notifications render
+	viper.SetDefault("NotificationRetentionDays", 0)          // 0 keeps notifications forever
+	viper.SetDefault("NotificationTypeRetentionDays", map[string]int{})
+	viper.SetDefault("NotificationBatchMaxSize", 500)              // larger notification batches are rejected, 0 disables the limit
+	viper.SetDefault("NotificationTemplates", map[string]string{}) // message templates by notification type, overriding the built-in ones
+	viper.SetDefault("WebSocketHeartbeatSeconds", 30)              // 0 disables pinging idle websocket connections
+	viper.SetDefault("NotificationStatuses", []string{})           // statuses a card moving to notifies about, empty for all of them
+	viper.SetDefault("NotificationStatusDebounceSeconds", 10)      // status changes are notified once the card stayed put this long, 0 notifies right away
+
+	viper.SetDefault("DefaultBoardMemberRole", "editor") // role of the users joining a board that sets no minimum role
+	viper.SetDefault("AdminPasswordResetsPerMinute", 60) // password resets an admin session can make per minute, 0 disables the limit
+
+	viper.SetDefault("AvatarUploadsPerMinute", 10) // avatar uploads a user can make per minute, 0 disables the limit
+
+	viper.SetDefault("NotificationUnreadCountsByType", false) // unread count broadcasts carry the count of each notification type when set
+
+	viper.SetDefault("NotificationHideInaccessibleBoards", true) // notifications of boards the user can no longer view are left out of their list and unread count
+
+	viper.SetDefault("NotificationImportSummary", true) // users get one notification per imported board summarizing the notifications the import held back
+
+	viper.SetDefault("AvatarMaxDimension", 4096) // larger uploaded avatars are rejected, in pixels per side, 0 disables the limit
+	// content types avatars can be uploaded as, detected from their content
+	viper.SetDefault("AvatarAllowedTypes", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+
+	viper.SetDefault("NotificationEscalationMinutes", 0)                             // unread high urgency notifications are escalated after this long, 0 disables escalations
+	viper.SetDefault("NotificationEscalationChannels", []string{"email", "webhook"}) // channels notifications are escalated through, in order
 
 	err := viper.ReadInConfig() // Find and read the config file
 	if err != nil {             // Handle errors reading the config file
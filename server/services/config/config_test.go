@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestReadConfigFileDefaults(t *testing.T) {
+	config, err := ReadConfigFile(writeConfigFile(t, "{}"))
+	require.NoError(t, err)
+
+	assert.True(t, config.EnableFirstUserAdmin)
+	assert.Empty(t, config.AdminUserIDs)
+	assert.Empty(t, config.AllowedEmailDomains)
+	assert.Equal(t, 0, config.AuditRetentionDays)
+	assert.Equal(t, []string{"board", "all"}, config.BoardMentionAliases)
+	assert.Equal(t, int64(16*1024*1024), config.AvatarCacheSize)
+	assert.Equal(t, int64(64*1024), config.AvatarCacheMaxItemSize)
+	assert.Equal(t, int64(5*1024*1024), config.AvatarMaxServeSize)
+	assert.Equal(t, []string{"local", "synced", "default"}, config.AvatarSources)
+	assert.Empty(t, config.AvatarSyncedPath)
+	assert.Equal(t, []string{"image/jpeg", "image/png", "image/gif", "image/webp"}, config.AvatarAllowedTypes)
+	assert.Equal(t, 4096, config.AvatarMaxDimension)
+	assert.False(t, config.PrivateAvatars)
+	assert.Equal(t, 300, config.AvatarURLExpirySeconds)
+	assert.Equal(t, 0, config.NotificationQueueSize)
+	assert.Equal(t, 4, config.NotificationQueueWorkers)
+	assert.Equal(t, "viewer", config.NotificationMinBoardRole)
+	assert.Equal(t, 500, config.NotificationBatchMaxSize)
+	assert.True(t, config.RestrictNotificationCreate)
+	assert.True(t, config.EnableNotificationPreview)
+	assert.Equal(t, 60, config.NotificationDigestIntervalMinutes)
+	assert.Equal(t, "editor", config.DefaultBoardMemberRole)
+	assert.Equal(t, 60, config.AdminPasswordResetsPerMinute)
+	assert.Equal(t, 10, config.AvatarUploadsPerMinute)
+	assert.Equal(t, 0, config.NotificationEscalationMinutes)
+	assert.Equal(t, []string{"email", "webhook"}, config.NotificationEscalationChannels)
+	assert.Equal(t, 0, config.NotificationRetentionDays)
+	assert.Empty(t, config.NotificationTypeRetentionDays)
+	assert.Empty(t, config.NotificationTemplates)
+	assert.Empty(t, config.NotificationStatuses)
+	assert.Equal(t, 10, config.NotificationStatusDebounceSeconds)
+	assert.False(t, config.NotificationUnreadCountsByType)
+	assert.True(t, config.NotificationHideInaccessibleBoards)
+	assert.True(t, config.NotificationImportSummary)
+	assert.Equal(t, 30, config.WebSocketHeartbeatSeconds)
+}
+
+func TestReadConfigFileOverrides(t *testing.T) {
+	config, err := ReadConfigFile(writeConfigFile(t, `{
+		"enableFirstUserAdmin": false,
+		"adminUserIDs": ["user-1"],
+		"notificationBatchMaxSize": 20
+	}`))
+	require.NoError(t, err)
+
+	assert.False(t, config.EnableFirstUserAdmin)
+	assert.Equal(t, []string{"user-1"}, config.AdminUserIDs)
+	assert.Equal(t, 20, config.NotificationBatchMaxSize)
+}
@@ -44,6 +44,11 @@ type Metrics struct {
 	teamCount  prometheus.Gauge
 
 	blockLastActivity prometheus.Gauge
+
+	avatarCacheHits   prometheus.Counter
+	avatarCacheMisses prometheus.Counter
+
+	notificationQueueDepth prometheus.Gauge
 }
 
 // NewMetrics Factory method to create a new metrics collector.
@@ -172,6 +177,33 @@ func NewMetrics(info InstanceInfo) *Metrics {
 	})
 	m.registry.MustRegister(m.blockLastActivity)
 
+	m.avatarCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemSystem,
+		Name:        "avatar_cache_hits_total",
+		Help:        "Total number of avatar requests served from the in-memory cache.",
+		ConstLabels: additionalLabels,
+	})
+	m.registry.MustRegister(m.avatarCacheHits)
+
+	m.avatarCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemSystem,
+		Name:        "avatar_cache_misses_total",
+		Help:        "Total number of avatar requests not found in the in-memory cache.",
+		ConstLabels: additionalLabels,
+	})
+	m.registry.MustRegister(m.avatarCacheMisses)
+
+	m.notificationQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   MetricsNamespace,
+		Subsystem:   MetricsSubsystemSystem,
+		Name:        "notification_queue_depth",
+		Help:        "Number of notifications waiting to be stored and broadcast.",
+		ConstLabels: additionalLabels,
+	})
+	m.registry.MustRegister(m.notificationQueueDepth)
+
 	return m
 }
 
@@ -231,3 +263,21 @@ func (m *Metrics) ObserveTeamCount(count int64) {
 		m.teamCount.Set(float64(count))
 	}
 }
+
+func (m *Metrics) IncrementAvatarCacheHits(num int) {
+	if m != nil {
+		m.avatarCacheHits.Add(float64(num))
+	}
+}
+
+func (m *Metrics) IncrementAvatarCacheMisses(num int) {
+	if m != nil {
+		m.avatarCacheMisses.Add(float64(num))
+	}
+}
+
+func (m *Metrics) ObserveNotificationQueueDepth(depth int) {
+	if m != nil {
+		m.notificationQueueDepth.Set(float64(depth))
+	}
+}
@@ -30,7 +30,7 @@ func SetupTestHelper(t *testing.T) *TestHelper {
 		t:           t,
 		ctrl:        ctrl,
 		store:       mockStore,
-		permissions: New(mockStore, mlog.CreateConsoleTestLogger(t)),
+		permissions: New(mockStore, true, nil, mlog.CreateConsoleTestLogger(t)),
 	}
 }
 
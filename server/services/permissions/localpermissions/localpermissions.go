@@ -4,6 +4,8 @@
 package localpermissions
 
 import (
+	"sync"
+
 	"github.com/mattermost/focalboard/server/model"
 	"github.com/mattermost/focalboard/server/services/permissions"
 
@@ -12,44 +14,90 @@ import (
 )
 
 type Service struct {
-	store         permissions.Store
-	logger        mlog.LoggerIFace
+	store          permissions.Store
+	logger         mlog.LoggerIFace
+	firstUserAdmin bool
+	adminUserIDs   map[string]bool
+
+	firstUserMu   sync.Mutex
 	firstUserID   string
 	firstUserDone bool
 }
 
-func New(store permissions.Store, logger mlog.LoggerIFace) *Service {
+// New creates the permissions service for standalone mode. The users in adminUserIDs
+// are system admins and, if firstUserAdmin is set, so is the first registered user.
+func New(store permissions.Store, firstUserAdmin bool, adminUserIDs []string, logger mlog.LoggerIFace) *Service {
+	admins := make(map[string]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+
 	return &Service{
-		store:         store,
-		logger:        logger,
-		firstUserID:   "",
-		firstUserDone: false,
+		store:          store,
+		logger:         logger,
+		firstUserAdmin: firstUserAdmin,
+		adminUserIDs:   admins,
+		firstUserID:    "",
+		firstUserDone:  false,
 	}
 }
 
 func (s *Service) HasPermissionTo(userID string, permission *mmModel.Permission) bool {
-	// For standalone mode, the first registered user is the admin
 	if permission.Id == model.PermissionManageSystem.Id {
-		// Cache the first user ID to avoid repeated DB lookups
-		if !s.firstUserDone {
-			users, err := s.store.GetAllUsers()
-			if err == nil && len(users) > 0 {
-				// Find the oldest user (first registered)
-				var oldestUser *model.User
-				for _, u := range users {
-					if oldestUser == nil || u.CreateAt < oldestUser.CreateAt {
-						oldestUser = u
-					}
-				}
-				if oldestUser != nil {
-					s.firstUserID = oldestUser.ID
+		if userID == "" {
+			return false
+		}
+		if s.adminUserIDs[userID] {
+			return true
+		}
+		if !s.firstUserAdmin {
+			return false
+		}
+
+		// For standalone mode, the first registered user is the admin
+		return userID == s.getFirstUserID()
+	}
+	return false
+}
+
+// getFirstUserID returns the ID of the first registered user. It is cached to avoid
+// repeated DB lookups until InvalidateAdminCache is called.
+func (s *Service) getFirstUserID() string {
+	s.firstUserMu.Lock()
+	defer s.firstUserMu.Unlock()
+
+	if !s.firstUserDone {
+		s.firstUserID = ""
+		users, err := s.store.GetAllUsers()
+		if err == nil && len(users) > 0 {
+			// Find the oldest user (first registered)
+			var oldestUser *model.User
+			for _, u := range users {
+				if oldestUser == nil || u.CreateAt < oldestUser.CreateAt {
+					oldestUser = u
 				}
 			}
-			s.firstUserDone = true
+			if oldestUser != nil {
+				s.firstUserID = oldestUser.ID
+			}
 		}
-		return userID == s.firstUserID
+		s.firstUserDone = true
 	}
-	return false
+	return s.firstUserID
+}
+
+// InvalidateAdminCache forgets the cached first registered user and looks it up again,
+// e.g. after a data migration changed who registered first. It returns the ID of the
+// first user, or an empty string if the first user is not an admin.
+func (s *Service) InvalidateAdminCache() string {
+	s.firstUserMu.Lock()
+	s.firstUserDone = false
+	s.firstUserMu.Unlock()
+
+	if !s.firstUserAdmin {
+		return ""
+	}
+	return s.getFirstUserID()
 }
 
 func (s *Service) HasPermissionToTeam(userID, teamID string, permission *mmModel.Permission) bool {
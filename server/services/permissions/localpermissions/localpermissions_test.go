@@ -170,3 +170,49 @@ func TestHasPermissionToBoard(t *testing.T) {
 		th.checkBoardPermissions("viewer", member, hasPermissionTo, hasNotPermissionTo)
 	})
 }
+
+func TestHasPermissionToManageSystem(t *testing.T) {
+	users := []*model.User{
+		{ID: "user-2", CreateAt: 200},
+		{ID: "user-1", CreateAt: 100},
+	}
+
+	t.Run("first registered user is admin", func(t *testing.T) {
+		th := SetupTestHelper(t)
+		th.store.EXPECT().GetAllUsers().Return(users, nil).Times(1)
+
+		assert.True(t, th.permissions.HasPermissionTo("user-1", model.PermissionManageSystem))
+		assert.False(t, th.permissions.HasPermissionTo("user-2", model.PermissionManageSystem))
+	})
+
+	t.Run("first user admin disabled", func(t *testing.T) {
+		th := SetupTestHelper(t)
+		th.permissions = New(th.store, false, nil, th.permissions.logger)
+
+		assert.False(t, th.permissions.HasPermissionTo("user-1", model.PermissionManageSystem))
+		assert.False(t, th.permissions.HasPermissionTo("user-2", model.PermissionManageSystem))
+	})
+
+	t.Run("explicitly designated admins", func(t *testing.T) {
+		th := SetupTestHelper(t)
+		th.permissions = New(th.store, false, []string{"user-2"}, th.permissions.logger)
+
+		assert.False(t, th.permissions.HasPermissionTo("user-1", model.PermissionManageSystem))
+		assert.True(t, th.permissions.HasPermissionTo("user-2", model.PermissionManageSystem))
+	})
+	t.Run("invalidating the cache picks up a new first user", func(t *testing.T) {
+		th := SetupTestHelper(t)
+		th.store.EXPECT().GetAllUsers().Return(users, nil).Times(1)
+		assert.True(t, th.permissions.HasPermissionTo("user-1", model.PermissionManageSystem))
+
+		migrated := []*model.User{
+			{ID: "user-2", CreateAt: 50},
+			{ID: "user-1", CreateAt: 100},
+		}
+		th.store.EXPECT().GetAllUsers().Return(migrated, nil).Times(1)
+		assert.Equal(t, "user-2", th.permissions.InvalidateAdminCache())
+
+		assert.False(t, th.permissions.HasPermissionTo("user-1", model.PermissionManageSystem))
+		assert.True(t, th.permissions.HasPermissionTo("user-2", model.PermissionManageSystem))
+	})
+}
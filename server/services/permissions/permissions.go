@@ -17,6 +17,12 @@ type PermissionsService interface {
 	HasPermissionToBoard(userID, boardID string, permission *mmModel.Permission) bool
 }
 
+// AdminCacheInvalidator is implemented by the permissions services that cache who the
+// system admins are.
+type AdminCacheInvalidator interface {
+	InvalidateAdminCache() string
+}
+
 type Store interface {
 	GetBoard(boardID string) (*model.Board, error)
 	GetMemberForBoard(boardID, userID string) (*model.BoardMember, error)
@@ -218,6 +218,38 @@ func (s *MattermostAuthLayer) GetUserPreferences(userID string) (mmModel.Prefere
 	return s.servicesAPI.GetPreferencesForUser(userID)
 }
 
+// GetUsersPreferences returns the boards preferences of many users at once, by user ID.
+// Users without preferences are left out of the map.
+func (s *MattermostAuthLayer) GetUsersPreferences(userIDs []string) (map[string]mmModel.Preferences, error) {
+	byUser := map[string]mmModel.Preferences{}
+	if len(userIDs) == 0 {
+		return byUser, nil
+	}
+
+	query := s.getQueryBuilder().
+		Select("UserId", "Category", "Name", "Value").
+		From("Preferences").
+		Where(sq.Eq{
+			"UserId":   userIDs,
+			"Category": model.PreferencesCategoryFocalboard,
+		})
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	for rows.Next() {
+		var preference mmModel.Preference
+		if err := rows.Scan(&preference.UserId, &preference.Category, &preference.Name, &preference.Value); err != nil {
+			return nil, err
+		}
+		byUser[preference.UserId] = append(byUser[preference.UserId], preference)
+	}
+	return byUser, nil
+}
+
 // GetActiveUserCount returns the number of users with active sessions within N seconds ago.
 func (s *MattermostAuthLayer) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
 	query := s.getQueryBuilder().
@@ -1836,7 +1836,7 @@ func (mr *MockStoreMockRecorder) CreateUserNotification(arg0 interface{}) *gomoc
 }
 
 // GetUserNotifications mocks base method.
-func (m *MockStore) GetUserNotifications(arg0 string, arg1 int) ([]*model.UserNotification, error) {
+func (m *MockStore) GetUserNotifications(arg0 string, arg1 model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserNotifications", arg0, arg1)
 	ret0, _ := ret[0].([]*model.UserNotification)
@@ -1880,17 +1880,18 @@ func (mr *MockStoreMockRecorder) MarkNotificationAsRead(arg0, arg1 interface{})
 }
 
 // MarkAllNotificationsAsRead mocks base method.
-func (m *MockStore) MarkAllNotificationsAsRead(arg0 string) error {
+func (m *MockStore) MarkAllNotificationsAsRead(arg0 string, arg1 model.MarkNotificationsAsReadOptions) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkAllNotificationsAsRead", arg0)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkAllNotificationsAsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // MarkAllNotificationsAsRead indicates an expected call of MarkAllNotificationsAsRead.
-func (mr *MockStoreMockRecorder) MarkAllNotificationsAsRead(arg0 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) MarkAllNotificationsAsRead(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsAsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsAsRead), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsAsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsAsRead), arg0, arg1)
 }
 
 // DeleteUserNotification mocks base method.
@@ -1906,3 +1907,847 @@ func (mr *MockStoreMockRecorder) DeleteUserNotification(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserNotification", reflect.TypeOf((*MockStore)(nil).DeleteUserNotification), arg0, arg1)
 }
+
+// CreateAuditRecords mocks base method.
+func (m *MockStore) CreateAuditRecords(arg0 []*model.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditRecords", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditRecords indicates an expected call of CreateAuditRecords.
+func (mr *MockStoreMockRecorder) CreateAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditRecords", reflect.TypeOf((*MockStore)(nil).CreateAuditRecords), arg0)
+}
+
+// GetAuditRecords mocks base method.
+func (m *MockStore) GetAuditRecords(arg0 model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditRecords", arg0)
+	ret0, _ := ret[0].([]*model.AuditRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuditRecords indicates an expected call of GetAuditRecords.
+func (mr *MockStoreMockRecorder) GetAuditRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditRecords", reflect.TypeOf((*MockStore)(nil).GetAuditRecords), arg0)
+}
+
+// CreateUserNotifications mocks base method.
+func (m *MockStore) CreateUserNotifications(arg0 []*model.UserNotification) ([]*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserNotifications", arg0)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserNotifications indicates an expected call of CreateUserNotifications.
+func (mr *MockStoreMockRecorder) CreateUserNotifications(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserNotifications", reflect.TypeOf((*MockStore)(nil).CreateUserNotifications), arg0)
+}
+
+// SetNotificationArchived mocks base method.
+func (m *MockStore) SetNotificationArchived(arg0, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationArchived", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationArchived indicates an expected call of SetNotificationArchived.
+func (mr *MockStoreMockRecorder) SetNotificationArchived(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationArchived", reflect.TypeOf((*MockStore)(nil).SetNotificationArchived), arg0, arg1, arg2)
+}
+
+// GetUserNotification mocks base method.
+func (m *MockStore) GetUserNotification(arg0, arg1 string) (*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotification", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotification indicates an expected call of GetUserNotification.
+func (mr *MockStoreMockRecorder) GetUserNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotification", reflect.TypeOf((*MockStore)(nil).GetUserNotification), arg0, arg1)
+}
+
+// UpdateNotificationDeliveryStatus mocks base method.
+func (m *MockStore) UpdateNotificationDeliveryStatus(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationDeliveryStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationDeliveryStatus indicates an expected call of UpdateNotificationDeliveryStatus.
+func (mr *MockStoreMockRecorder) UpdateNotificationDeliveryStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationDeliveryStatus", reflect.TypeOf((*MockStore)(nil).UpdateNotificationDeliveryStatus), arg0, arg1, arg2, arg3)
+}
+
+// GetNotificationDeliveries mocks base method.
+func (m *MockStore) GetNotificationDeliveries(arg0 model.QueryNotificationDeliveriesOptions) ([]*model.NotificationDelivery, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDeliveries", arg0)
+	ret0, _ := ret[0].([]*model.NotificationDelivery)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNotificationDeliveries indicates an expected call of GetNotificationDeliveries.
+func (mr *MockStoreMockRecorder) GetNotificationDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDeliveries", reflect.TypeOf((*MockStore)(nil).GetNotificationDeliveries), arg0)
+}
+
+// GetNotificationSummary mocks base method.
+func (m *MockStore) GetNotificationSummary(arg0 string) (*model.NotificationSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationSummary", arg0)
+	ret0, _ := ret[0].(*model.NotificationSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationSummary indicates an expected call of GetNotificationSummary.
+func (mr *MockStoreMockRecorder) GetNotificationSummary(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationSummary", reflect.TypeOf((*MockStore)(nil).GetNotificationSummary), arg0)
+}
+
+// GetUserNotificationByID mocks base method.
+func (m *MockStore) GetUserNotificationByID(arg0 string) (*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationByID", arg0)
+	ret0, _ := ret[0].(*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationByID indicates an expected call of GetUserNotificationByID.
+func (mr *MockStoreMockRecorder) GetUserNotificationByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationByID", reflect.TypeOf((*MockStore)(nil).GetUserNotificationByID), arg0)
+}
+
+// UpdateUserNotification mocks base method.
+func (m *MockStore) UpdateUserNotification(arg0 *model.UserNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserNotification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserNotification indicates an expected call of UpdateUserNotification.
+func (mr *MockStoreMockRecorder) UpdateUserNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserNotification", reflect.TypeOf((*MockStore)(nil).UpdateUserNotification), arg0)
+}
+
+// GetNotificationBoardPreferences mocks base method.
+func (m *MockStore) GetNotificationBoardPreferences(arg0, arg1 string) ([]*model.NotificationBoardPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationBoardPreferences", arg0, arg1)
+	ret0, _ := ret[0].([]*model.NotificationBoardPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationBoardPreferences indicates an expected call of GetNotificationBoardPreferences.
+func (mr *MockStoreMockRecorder) GetNotificationBoardPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationBoardPreferences", reflect.TypeOf((*MockStore)(nil).GetNotificationBoardPreferences), arg0, arg1)
+}
+
+// SetNotificationBoardPreferences mocks base method.
+func (m *MockStore) SetNotificationBoardPreferences(arg0, arg1 string, arg2 []*model.NotificationBoardPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationBoardPreferences", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationBoardPreferences indicates an expected call of SetNotificationBoardPreferences.
+func (mr *MockStoreMockRecorder) SetNotificationBoardPreferences(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationBoardPreferences", reflect.TypeOf((*MockStore)(nil).SetNotificationBoardPreferences), arg0, arg1, arg2)
+}
+
+// DeleteAuditRecordsBefore mocks base method.
+func (m *MockStore) DeleteAuditRecordsBefore(arg0 int64, arg1 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditRecordsBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditRecordsBefore indicates an expected call of DeleteAuditRecordsBefore.
+func (mr *MockStoreMockRecorder) DeleteAuditRecordsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditRecordsBefore", reflect.TypeOf((*MockStore)(nil).DeleteAuditRecordsBefore), arg0, arg1)
+}
+
+// ResolveUserNotification mocks base method.
+func (m *MockStore) ResolveUserNotification(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveUserNotification", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveUserNotification indicates an expected call of ResolveUserNotification.
+func (mr *MockStoreMockRecorder) ResolveUserNotification(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveUserNotification", reflect.TypeOf((*MockStore)(nil).ResolveUserNotification), arg0, arg1, arg2)
+}
+
+// CountNotificationsByActor mocks base method.
+func (m *MockStore) CountNotificationsByActor(arg0 int64, arg1 int) ([]*model.NotificationActorCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNotificationsByActor", arg0, arg1)
+	ret0, _ := ret[0].([]*model.NotificationActorCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNotificationsByActor indicates an expected call of CountNotificationsByActor.
+func (mr *MockStoreMockRecorder) CountNotificationsByActor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNotificationsByActor", reflect.TypeOf((*MockStore)(nil).CountNotificationsByActor), arg0, arg1)
+}
+
+// SyncNotificationReadStates mocks base method.
+func (m *MockStore) SyncNotificationReadStates(arg0 string, arg1 []*model.NotificationReadSync) ([]*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncNotificationReadStates", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncNotificationReadStates indicates an expected call of SyncNotificationReadStates.
+func (mr *MockStoreMockRecorder) SyncNotificationReadStates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncNotificationReadStates", reflect.TypeOf((*MockStore)(nil).SyncNotificationReadStates), arg0, arg1)
+}
+
+// AddNotificationDigestActivity mocks base method.
+func (m *MockStore) AddNotificationDigestActivity(arg0, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotificationDigestActivity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotificationDigestActivity indicates an expected call of AddNotificationDigestActivity.
+func (mr *MockStoreMockRecorder) AddNotificationDigestActivity(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotificationDigestActivity", reflect.TypeOf((*MockStore)(nil).AddNotificationDigestActivity), arg0, arg1, arg2)
+}
+
+// GetDueNotificationDigests mocks base method.
+func (m *MockStore) GetDueNotificationDigests(arg0 int64) ([]*model.NotificationDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueNotificationDigests", arg0)
+	ret0, _ := ret[0].([]*model.NotificationDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueNotificationDigests indicates an expected call of GetDueNotificationDigests.
+func (mr *MockStoreMockRecorder) GetDueNotificationDigests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueNotificationDigests", reflect.TypeOf((*MockStore)(nil).GetDueNotificationDigests), arg0)
+}
+
+// DeleteNotificationDigest mocks base method.
+func (m *MockStore) DeleteNotificationDigest(arg0, arg1 string, arg2 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationDigest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNotificationDigest indicates an expected call of DeleteNotificationDigest.
+func (mr *MockStoreMockRecorder) DeleteNotificationDigest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationDigest", reflect.TypeOf((*MockStore)(nil).DeleteNotificationDigest), arg0, arg1, arg2)
+}
+
+// GetUsersPage mocks base method.
+func (m *MockStore) GetUsersPage(arg0 model.QueryUsersOptions) ([]*model.User, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersPage", arg0)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsersPage indicates an expected call of GetUsersPage.
+func (mr *MockStoreMockRecorder) GetUsersPage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersPage", reflect.TypeOf((*MockStore)(nil).GetUsersPage), arg0)
+}
+
+// DeleteUserNotificationsBefore mocks base method.
+func (m *MockStore) DeleteUserNotificationsBefore(arg0 model.PurgeUserNotificationsOptions, arg1 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserNotificationsBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserNotificationsBefore indicates an expected call of DeleteUserNotificationsBefore.
+func (mr *MockStoreMockRecorder) DeleteUserNotificationsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserNotificationsBefore", reflect.TypeOf((*MockStore)(nil).DeleteUserNotificationsBefore), arg0, arg1)
+}
+
+// GetUnreadCountByBoardForMember mocks base method.
+func (m *MockStore) GetUnreadCountByBoardForMember(arg0 string) ([]*model.NotificationBoardUnreadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCountByBoardForMember", arg0)
+	ret0, _ := ret[0].([]*model.NotificationBoardUnreadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCountByBoardForMember indicates an expected call of GetUnreadCountByBoardForMember.
+func (mr *MockStoreMockRecorder) GetUnreadCountByBoardForMember(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCountByBoardForMember", reflect.TypeOf((*MockStore)(nil).GetUnreadCountByBoardForMember), arg0)
+}
+
+// ReassignNotificationsBoard mocks base method.
+func (m *MockStore) ReassignNotificationsBoard(arg0, arg1, arg2 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignNotificationsBoard", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReassignNotificationsBoard indicates an expected call of ReassignNotificationsBoard.
+func (mr *MockStoreMockRecorder) ReassignNotificationsBoard(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignNotificationsBoard", reflect.TypeOf((*MockStore)(nil).ReassignNotificationsBoard), arg0, arg1, arg2)
+}
+
+// SetNotificationPinned mocks base method.
+func (m *MockStore) SetNotificationPinned(arg0, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationPinned", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationPinned indicates an expected call of SetNotificationPinned.
+func (mr *MockStoreMockRecorder) SetNotificationPinned(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationPinned", reflect.TypeOf((*MockStore)(nil).SetNotificationPinned), arg0, arg1, arg2)
+}
+
+// MarkNotificationsAsReadReturningIDs mocks base method.
+func (m *MockStore) MarkNotificationsAsReadReturningIDs(arg0 string, arg1 model.MarkNotificationsAsReadOptions) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationsAsReadReturningIDs", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationsAsReadReturningIDs indicates an expected call of MarkNotificationsAsReadReturningIDs.
+func (mr *MockStoreMockRecorder) MarkNotificationsAsReadReturningIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsAsReadReturningIDs", reflect.TypeOf((*MockStore)(nil).MarkNotificationsAsReadReturningIDs), arg0, arg1)
+}
+
+// AddNotificationActorBlock mocks base method.
+func (m *MockStore) AddNotificationActorBlock(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotificationActorBlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotificationActorBlock indicates an expected call of AddNotificationActorBlock.
+func (mr *MockStoreMockRecorder) AddNotificationActorBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotificationActorBlock", reflect.TypeOf((*MockStore)(nil).AddNotificationActorBlock), arg0, arg1)
+}
+
+// RemoveNotificationActorBlock mocks base method.
+func (m *MockStore) RemoveNotificationActorBlock(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNotificationActorBlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNotificationActorBlock indicates an expected call of RemoveNotificationActorBlock.
+func (mr *MockStoreMockRecorder) RemoveNotificationActorBlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNotificationActorBlock", reflect.TypeOf((*MockStore)(nil).RemoveNotificationActorBlock), arg0, arg1)
+}
+
+// GetNotificationActorBlocks mocks base method.
+func (m *MockStore) GetNotificationActorBlocks(arg0 string) ([]*model.NotificationActorBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationActorBlocks", arg0)
+	ret0, _ := ret[0].([]*model.NotificationActorBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationActorBlocks indicates an expected call of GetNotificationActorBlocks.
+func (mr *MockStoreMockRecorder) GetNotificationActorBlocks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationActorBlocks", reflect.TypeOf((*MockStore)(nil).GetNotificationActorBlocks), arg0)
+}
+
+// IsNotificationActorBlocked mocks base method.
+func (m *MockStore) IsNotificationActorBlocked(arg0, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNotificationActorBlocked", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsNotificationActorBlocked indicates an expected call of IsNotificationActorBlocked.
+func (mr *MockStoreMockRecorder) IsNotificationActorBlocked(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNotificationActorBlocked", reflect.TypeOf((*MockStore)(nil).IsNotificationActorBlocked), arg0, arg1)
+}
+
+// GetUserDeletionSummary mocks base method.
+func (m *MockStore) GetUserDeletionSummary(arg0 string) (*model.UserDeletionSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDeletionSummary", arg0)
+	ret0, _ := ret[0].(*model.UserDeletionSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserDeletionSummary indicates an expected call of GetUserDeletionSummary.
+func (mr *MockStoreMockRecorder) GetUserDeletionSummary(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDeletionSummary", reflect.TypeOf((*MockStore)(nil).GetUserDeletionSummary), arg0)
+}
+
+// AddCardWatcher mocks base method.
+func (m *MockStore) AddCardWatcher(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCardWatcher", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCardWatcher indicates an expected call of AddCardWatcher.
+func (mr *MockStoreMockRecorder) AddCardWatcher(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCardWatcher", reflect.TypeOf((*MockStore)(nil).AddCardWatcher), arg0, arg1)
+}
+
+// RemoveCardWatcher mocks base method.
+func (m *MockStore) RemoveCardWatcher(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCardWatcher", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCardWatcher indicates an expected call of RemoveCardWatcher.
+func (mr *MockStoreMockRecorder) RemoveCardWatcher(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCardWatcher", reflect.TypeOf((*MockStore)(nil).RemoveCardWatcher), arg0, arg1)
+}
+
+// GetCardWatchers mocks base method.
+func (m *MockStore) GetCardWatchers(arg0 string) ([]*model.CardWatcher, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardWatchers", arg0)
+	ret0, _ := ret[0].([]*model.CardWatcher)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardWatchers indicates an expected call of GetCardWatchers.
+func (mr *MockStoreMockRecorder) GetCardWatchers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardWatchers", reflect.TypeOf((*MockStore)(nil).GetCardWatchers), arg0)
+}
+
+// DeleteNotificationsForUser mocks base method.
+func (m *MockStore) DeleteNotificationsForUser(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationsForUser", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNotificationsForUser indicates an expected call of DeleteNotificationsForUser.
+func (mr *MockStoreMockRecorder) DeleteNotificationsForUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationsForUser", reflect.TypeOf((*MockStore)(nil).DeleteNotificationsForUser), arg0)
+}
+
+// GetNotificationsToEscalate mocks base method.
+func (m *MockStore) GetNotificationsToEscalate(arg0 int64, arg1 int) ([]*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsToEscalate", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsToEscalate indicates an expected call of GetNotificationsToEscalate.
+func (mr *MockStoreMockRecorder) GetNotificationsToEscalate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsToEscalate", reflect.TypeOf((*MockStore)(nil).GetNotificationsToEscalate), arg0, arg1)
+}
+
+// EscalateNotification mocks base method.
+func (m *MockStore) EscalateNotification(arg0 string, arg1 []string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateNotification", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateNotification indicates an expected call of EscalateNotification.
+func (mr *MockStoreMockRecorder) EscalateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateNotification", reflect.TypeOf((*MockStore)(nil).EscalateNotification), arg0, arg1)
+}
+
+// GetUserNotificationsMarkingRead mocks base method.
+func (m *MockStore) GetUserNotificationsMarkingRead(arg0 string, arg1 model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationsMarkingRead", arg0, arg1)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationsMarkingRead indicates an expected call of GetUserNotificationsMarkingRead.
+func (mr *MockStoreMockRecorder) GetUserNotificationsMarkingRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationsMarkingRead", reflect.TypeOf((*MockStore)(nil).GetUserNotificationsMarkingRead), arg0, arg1)
+}
+
+// MarkNotificationAsReadOnDevice mocks base method.
+func (m *MockStore) MarkNotificationAsReadOnDevice(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationAsReadOnDevice", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationAsReadOnDevice indicates an expected call of MarkNotificationAsReadOnDevice.
+func (mr *MockStoreMockRecorder) MarkNotificationAsReadOnDevice(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationAsReadOnDevice", reflect.TypeOf((*MockStore)(nil).MarkNotificationAsReadOnDevice), arg0, arg1, arg2)
+}
+
+// GetNotificationDeviceReads mocks base method.
+func (m *MockStore) GetNotificationDeviceReads(arg0 string) ([]*model.NotificationDeviceRead, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDeviceReads", arg0)
+	ret0, _ := ret[0].([]*model.NotificationDeviceRead)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationDeviceReads indicates an expected call of GetNotificationDeviceReads.
+func (mr *MockStoreMockRecorder) GetNotificationDeviceReads(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDeviceReads", reflect.TypeOf((*MockStore)(nil).GetNotificationDeviceReads), arg0)
+}
+
+// GetOldestUnreadNotificationTime mocks base method.
+func (m *MockStore) GetOldestUnreadNotificationTime(arg0 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestUnreadNotificationTime", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestUnreadNotificationTime indicates an expected call of GetOldestUnreadNotificationTime.
+func (mr *MockStoreMockRecorder) GetOldestUnreadNotificationTime(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestUnreadNotificationTime", reflect.TypeOf((*MockStore)(nil).GetOldestUnreadNotificationTime), arg0)
+}
+
+// GetUnreadNotificationCountByType mocks base method.
+func (m *MockStore) GetUnreadNotificationCountByType(arg0 string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadNotificationCountByType", arg0)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadNotificationCountByType indicates an expected call of GetUnreadNotificationCountByType.
+func (mr *MockStoreMockRecorder) GetUnreadNotificationCountByType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadNotificationCountByType", reflect.TypeOf((*MockStore)(nil).GetUnreadNotificationCountByType), arg0)
+}
+
+// GetUnreadCountByBoard mocks base method.
+func (m *MockStore) GetUnreadCountByBoard(arg0 string) ([]*model.NotificationBoardUnreadCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCountByBoard", arg0)
+	ret0, _ := ret[0].([]*model.NotificationBoardUnreadCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCountByBoard indicates an expected call of GetUnreadCountByBoard.
+func (mr *MockStoreMockRecorder) GetUnreadCountByBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCountByBoard", reflect.TypeOf((*MockStore)(nil).GetUnreadCountByBoard), arg0)
+}
+
+// SearchUserNotifications mocks base method.
+func (m *MockStore) SearchUserNotifications(arg0 model.SearchUserNotificationsOptions) ([]*model.UserNotification, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUserNotifications", arg0)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchUserNotifications indicates an expected call of SearchUserNotifications.
+func (mr *MockStoreMockRecorder) SearchUserNotifications(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserNotifications", reflect.TypeOf((*MockStore)(nil).SearchUserNotifications), arg0)
+}
+
+// GetUnreadNotificationByCollapseKey mocks base method.
+func (m *MockStore) GetUnreadNotificationByCollapseKey(arg0, arg1, arg2, arg3 string) (*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadNotificationByCollapseKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadNotificationByCollapseKey indicates an expected call of GetUnreadNotificationByCollapseKey.
+func (mr *MockStoreMockRecorder) GetUnreadNotificationByCollapseKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadNotificationByCollapseKey", reflect.TypeOf((*MockStore)(nil).GetUnreadNotificationByCollapseKey), arg0, arg1, arg2, arg3)
+}
+
+// GetBoardMembershipsForUser mocks base method.
+func (m *MockStore) GetBoardMembershipsForUser(arg0 string, arg1, arg2 int) ([]*model.UserBoardMembership, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardMembershipsForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.UserBoardMembership)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBoardMembershipsForUser indicates an expected call of GetBoardMembershipsForUser.
+func (mr *MockStoreMockRecorder) GetBoardMembershipsForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardMembershipsForUser", reflect.TypeOf((*MockStore)(nil).GetBoardMembershipsForUser), arg0, arg1, arg2)
+}
+
+// MarkNotificationAsUnread mocks base method.
+func (m *MockStore) MarkNotificationAsUnread(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationAsUnread", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationAsUnread indicates an expected call of MarkNotificationAsUnread.
+func (mr *MockStoreMockRecorder) MarkNotificationAsUnread(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationAsUnread", reflect.TypeOf((*MockStore)(nil).MarkNotificationAsUnread), arg0, arg1)
+}
+
+// MarkNotificationsAsRead mocks base method.
+func (m *MockStore) MarkNotificationsAsRead(arg0 []string, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationsAsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationsAsRead indicates an expected call of MarkNotificationsAsRead.
+func (mr *MockStoreMockRecorder) MarkNotificationsAsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsAsRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationsAsRead), arg0, arg1)
+}
+
+// GetBoardsByIDs mocks base method.
+func (m *MockStore) GetBoardsByIDs(arg0 []string) ([]*model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoardsByIDs", arg0)
+	ret0, _ := ret[0].([]*model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoardsByIDs indicates an expected call of GetBoardsByIDs.
+func (mr *MockStoreMockRecorder) GetBoardsByIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoardsByIDs", reflect.TypeOf((*MockStore)(nil).GetBoardsByIDs), arg0)
+}
+
+// SendNotificationDigest mocks base method.
+func (m *MockStore) SendNotificationDigest(arg0 *model.NotificationDigest, arg1 *model.UserNotification) (*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendNotificationDigest", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendNotificationDigest indicates an expected call of SendNotificationDigest.
+func (mr *MockStoreMockRecorder) SendNotificationDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendNotificationDigest", reflect.TypeOf((*MockStore)(nil).SendNotificationDigest), arg0, arg1)
+}
+
+// SetNotificationsHiddenForBoard mocks base method.
+func (m *MockStore) SetNotificationsHiddenForBoard(arg0 string, arg1 bool) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationsHiddenForBoard", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNotificationsHiddenForBoard indicates an expected call of SetNotificationsHiddenForBoard.
+func (mr *MockStoreMockRecorder) SetNotificationsHiddenForBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationsHiddenForBoard", reflect.TypeOf((*MockStore)(nil).SetNotificationsHiddenForBoard), arg0, arg1)
+}
+
+// GetUserNotificationThreads mocks base method.
+func (m *MockStore) GetUserNotificationThreads(arg0 string, arg1 model.QueryUserNotificationsOptions, arg2 int) ([]*model.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserNotificationThreads", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserNotificationThreads indicates an expected call of GetUserNotificationThreads.
+func (mr *MockStoreMockRecorder) GetUserNotificationThreads(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserNotificationThreads", reflect.TypeOf((*MockStore)(nil).GetUserNotificationThreads), arg0, arg1, arg2)
+}
+
+// UnresolveUserNotification mocks base method.
+func (m *MockStore) UnresolveUserNotification(arg0, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnresolveUserNotification", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnresolveUserNotification indicates an expected call of UnresolveUserNotification.
+func (mr *MockStoreMockRecorder) UnresolveUserNotification(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnresolveUserNotification", reflect.TypeOf((*MockStore)(nil).UnresolveUserNotification), arg0, arg1, arg2, arg3)
+}
+
+// GetUsersPreferences mocks base method.
+func (m *MockStore) GetUsersPreferences(arg0 []string) (map[string]model0.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersPreferences", arg0)
+	ret0, _ := ret[0].(map[string]model0.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersPreferences indicates an expected call of GetUsersPreferences.
+func (mr *MockStoreMockRecorder) GetUsersPreferences(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersPreferences", reflect.TypeOf((*MockStore)(nil).GetUsersPreferences), arg0)
+}
+
+// GetUsersNotificationBoardPreferences mocks base method.
+func (m *MockStore) GetUsersNotificationBoardPreferences(arg0 []string, arg1 string) (map[string][]*model.NotificationBoardPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersNotificationBoardPreferences", arg0, arg1)
+	ret0, _ := ret[0].(map[string][]*model.NotificationBoardPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersNotificationBoardPreferences indicates an expected call of GetUsersNotificationBoardPreferences.
+func (mr *MockStoreMockRecorder) GetUsersNotificationBoardPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersNotificationBoardPreferences", reflect.TypeOf((*MockStore)(nil).GetUsersNotificationBoardPreferences), arg0, arg1)
+}
+
+// GetNotificationTeamDefaults mocks base method.
+func (m *MockStore) GetNotificationTeamDefaults(arg0 string) (*model.NotificationPreferencesOverrides, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationTeamDefaults", arg0)
+	ret0, _ := ret[0].(*model.NotificationPreferencesOverrides)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationTeamDefaults indicates an expected call of GetNotificationTeamDefaults.
+func (mr *MockStoreMockRecorder) GetNotificationTeamDefaults(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationTeamDefaults", reflect.TypeOf((*MockStore)(nil).GetNotificationTeamDefaults), arg0)
+}
+
+// GetNotificationTeamDefaultsForBoard mocks base method.
+func (m *MockStore) GetNotificationTeamDefaultsForBoard(arg0 string) (*model.NotificationPreferencesOverrides, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationTeamDefaultsForBoard", arg0)
+	ret0, _ := ret[0].(*model.NotificationPreferencesOverrides)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationTeamDefaultsForBoard indicates an expected call of GetNotificationTeamDefaultsForBoard.
+func (mr *MockStoreMockRecorder) GetNotificationTeamDefaultsForBoard(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationTeamDefaultsForBoard", reflect.TypeOf((*MockStore)(nil).GetNotificationTeamDefaultsForBoard), arg0)
+}
+
+// SetNotificationTeamDefaults mocks base method.
+func (m *MockStore) SetNotificationTeamDefaults(arg0 string, arg1 *model.NotificationPreferencesOverrides) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNotificationTeamDefaults", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNotificationTeamDefaults indicates an expected call of SetNotificationTeamDefaults.
+func (mr *MockStoreMockRecorder) SetNotificationTeamDefaults(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNotificationTeamDefaults", reflect.TypeOf((*MockStore)(nil).SetNotificationTeamDefaults), arg0, arg1)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var auditRecordFields = []string{
+	"id",
+	"level",
+	"event",
+	"status",
+	"user_id",
+	"session_id",
+	"api_path",
+	"client",
+	"ip_address",
+	"meta",
+	"create_at",
+}
+
+func (s *SQLStore) auditRecordsFromRows(rows *sql.Rows) ([]*model.AuditRecord, error) {
+	records := []*model.AuditRecord{}
+
+	for rows.Next() {
+		var record model.AuditRecord
+		var meta sql.NullString
+		err := rows.Scan(
+			&record.ID,
+			&record.Level,
+			&record.Event,
+			&record.Status,
+			&record.UserID,
+			&record.SessionID,
+			&record.APIPath,
+			&record.Client,
+			&record.IPAddress,
+			&meta,
+			&record.CreateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		record.Meta = meta.String
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// auditColumnMaxLengths are the sizes of the VARCHAR columns of audit_records. Longer
+// values are truncated, so an oversized user agent or path can't fail the insert.
+var auditColumnMaxLengths = map[string]int{
+	"level":      20,
+	"event":      100,
+	"status":     20,
+	"user_id":    36,
+	"session_id": 36,
+	"api_path":   512,
+	"client":     512,
+	"ip_address": 64,
+}
+
+// truncateAuditValue truncates a value to the size of its column, counted in characters.
+func truncateAuditValue(column, value string) string {
+	maxLength := auditColumnMaxLengths[column]
+	if utf8.RuneCountInString(value) <= maxLength {
+		return value
+	}
+	return string([]rune(value)[:maxLength])
+}
+
+// createAuditRecords inserts a batch of audit records in a single query.
+func (s *SQLStore) createAuditRecords(db sq.BaseRunner, records []*model.AuditRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).Insert(s.tablePrefix + "audit_records").
+		Columns(auditRecordFields...)
+
+	now := utils.GetMillis()
+	for _, record := range records {
+		if record.ID == "" {
+			record.ID = utils.NewID(utils.IDTypeNone)
+		}
+		if record.CreateAt == 0 {
+			record.CreateAt = now
+		}
+
+		query = query.Values(
+			record.ID,
+			truncateAuditValue("level", record.Level),
+			truncateAuditValue("event", record.Event),
+			truncateAuditValue("status", record.Status),
+			truncateAuditValue("user_id", record.UserID),
+			truncateAuditValue("session_id", record.SessionID),
+			truncateAuditValue("api_path", record.APIPath),
+			truncateAuditValue("client", record.Client),
+			truncateAuditValue("ip_address", record.IPAddress),
+			record.Meta,
+			record.CreateAt,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot create audit records",
+			mlog.Int("count", len(records)),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getAuditRecords(db sq.BaseRunner, opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	query := s.getQueryBuilder(db).
+		Select(auditRecordFields...).
+		From(s.tablePrefix+"audit_records").
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.UserID != "" {
+		query = query.Where(sq.Eq{"user_id": opts.UserID})
+	}
+
+	if opts.Event != "" {
+		query = query.Where(sq.Eq{"event": opts.Event})
+	}
+
+	if opts.Level != "" {
+		query = query.Where(sq.Eq{"level": opts.Level})
+	}
+
+	if opts.Since != 0 {
+		query = query.Where(sq.GtOrEq{"create_at": opts.Since})
+	}
+
+	if opts.Until != 0 {
+		query = query.Where(sq.LtOrEq{"create_at": opts.Until})
+	}
+
+	if opts.Page != 0 {
+		query = query.Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(uint64(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetAuditRecords ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	records, err := s.auditRecordsFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(records) > opts.PerPage {
+		records = records[0:opts.PerPage]
+		hasMore = true
+	}
+	return records, hasMore, nil
+}
+
+// deleteAuditRecordsBefore deletes the audit records created before the given time, in
+// batches of batchSize so each delete only holds its locks briefly. Returns the number of
+// deleted records.
+func (s *SQLStore) deleteAuditRecordsBefore(db sq.BaseRunner, before int64, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		rows, err := s.getQueryBuilder(db).
+			Select("id").
+			From(s.tablePrefix + "audit_records").
+			Where(sq.Lt{"create_at": before}).
+			Limit(uint64(batchSize)).
+			Query()
+		if err != nil {
+			return deleted, err
+		}
+
+		ids := []string{}
+		for rows.Next() {
+			var id string
+			if err = rows.Scan(&id); err != nil {
+				s.CloseRows(rows)
+				return deleted, err
+			}
+			ids = append(ids, id)
+		}
+		s.CloseRows(rows)
+
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		result, err := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + "audit_records").
+			Where(sq.Eq{"id": ids}).
+			Exec()
+		if err != nil {
+			s.logger.Error("Cannot delete audit records",
+				mlog.Int("count", len(ids)),
+				mlog.Err(err),
+			)
+			return deleted, err
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += count
+
+		if len(ids) < batchSize {
+			return deleted, nil
+		}
+	}
+}
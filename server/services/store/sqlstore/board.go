@@ -294,6 +294,26 @@ func (s *SQLStore) getBoardsInTeamByIds(db sq.BaseRunner, boardIDs []string, tea
 	return boards, nil
 }
 
+// getBoardsByIDs returns the boards among the given IDs that exist, in any team.
+func (s *SQLStore) getBoardsByIDs(db sq.BaseRunner, boardIDs []string) ([]*model.Board, error) {
+	if len(boardIDs) == 0 {
+		return []*model.Board{}, nil
+	}
+
+	rows, err := s.getQueryBuilder(db).
+		Select(boardFields("b.")...).
+		From(s.tablePrefix + "boards as b").
+		Where(sq.Eq{"b.id": boardIDs}).
+		Query()
+	if err != nil {
+		s.logger.Error(`getBoardsByIDs ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.boardsFromRows(rows)
+}
+
 func (s *SQLStore) insertBoard(db sq.BaseRunner, board *model.Board, userID string) (*model.Board, error) {
 	// Generate tracking IDs for in-built templates
 	if board.IsTemplate && board.TeamID == model.GlobalTeamID {
@@ -629,6 +649,65 @@ func (s *SQLStore) getMembersForUser(db sq.BaseRunner, userID string) ([]*model.
 	return members, nil
 }
 
+// getBoardMembershipsForUser returns a page of the memberships of a user, sorted by board
+// ID, with the team and title of their boards. The roles are left to resolve.
+func (s *SQLStore) getBoardMembershipsForUser(db sq.BaseRunner, userID string, page, perPage int) ([]*model.UserBoardMembership, bool, error) {
+	fields := append([]string{"COALESCE(B.team_id, '')", "COALESCE(B.title, '')"}, boardMemberFields...)
+	query := s.getQueryBuilder(db).
+		Select(fields...).
+		From(s.tablePrefix + "board_members AS BM").
+		LeftJoin(s.tablePrefix + "boards AS B ON B.id=BM.board_id").
+		Where(sq.Eq{"BM.user_id": userID}).
+		OrderBy("BM.board_id")
+
+	if page != 0 {
+		query = query.Offset(uint64(page * perPage))
+	}
+
+	if perPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(uint64(perPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getBoardMembershipsForUser ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	memberships := []*model.UserBoardMembership{}
+	for rows.Next() {
+		var membership model.UserBoardMembership
+		var member model.BoardMember
+		err := rows.Scan(
+			&membership.TeamID,
+			&membership.Title,
+			&member.MinimumRole,
+			&member.BoardID,
+			&member.UserID,
+			&member.Roles,
+			&member.SchemeAdmin,
+			&member.SchemeEditor,
+			&member.SchemeCommenter,
+			&member.SchemeViewer,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		membership.BoardID = member.BoardID
+		membership.Member = &member
+		memberships = append(memberships, &membership)
+	}
+
+	var hasMore bool
+	if perPage > 0 && len(memberships) > perPage {
+		memberships = memberships[0:perPage]
+		hasMore = true
+	}
+	return memberships, hasMore, nil
+}
+
 func (s *SQLStore) getMembersForBoard(db sq.BaseRunner, boardID string) ([]*model.BoardMember, error) {
 	query := s.getQueryBuilder(db).
 		Select(boardMemberFields...).
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var cardWatcherFields = []string{
+	"card_id",
+	"user_id",
+	"create_at",
+}
+
+// addCardWatcher makes the user watch the card. Watching a card twice keeps the original
+// watch.
+func (s *SQLStore) addCardWatcher(db sq.BaseRunner, cardID, userID string) error {
+	var count int
+	err := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "card_watchers").
+		Where(sq.Eq{"card_id": cardID, "user_id": userID}).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"card_watchers").
+		Columns(cardWatcherFields...).
+		Values(cardID, userID, utils.GetMillis())
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot add card watcher",
+			mlog.String("card_id", cardID),
+			mlog.String("user_id", userID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) removeCardWatcher(db sq.BaseRunner, cardID, userID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_watchers").
+		Where(sq.Eq{"card_id": cardID, "user_id": userID})
+
+	_, err := query.Exec()
+	return err
+}
+
+func (s *SQLStore) getCardWatchers(db sq.BaseRunner, cardID string) ([]*model.CardWatcher, error) {
+	query := s.getQueryBuilder(db).
+		Select(cardWatcherFields...).
+		From(s.tablePrefix+"card_watchers").
+		Where(sq.Eq{"card_id": cardID}).
+		OrderBy("create_at", "user_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetCardWatchers ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	watchers := []*model.CardWatcher{}
+	for rows.Next() {
+		var watcher model.CardWatcher
+		if err := rows.Scan(&watcher.CardID, &watcher.UserID, &watcher.CreateAt); err != nil {
+			return nil, err
+		}
+		watchers = append(watchers, &watcher)
+	}
+	return watchers, nil
+}
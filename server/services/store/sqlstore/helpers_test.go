@@ -12,6 +12,11 @@ import (
 )
 
 func SetupTests(t *testing.T) (store.Store, func()) {
+	return setupTestStore(t)
+}
+
+// setupTestStore is SetupTests for tests and benchmarks alike.
+func setupTestStore(t testing.TB) (store.Store, func()) {
 	origUnitTesting := os.Getenv("FOCALBOARD_UNIT_TESTING")
 	os.Setenv("FOCALBOARD_UNIT_TESTING", "1")
 
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}audit_records (
+    id VARCHAR(36) NOT NULL,
+    level VARCHAR(20) NOT NULL,
+    event VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    user_id VARCHAR(36) NOT NULL DEFAULT '',
+    session_id VARCHAR(36) NOT NULL DEFAULT '',
+    api_path VARCHAR(512) NOT NULL DEFAULT '',
+    client VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    meta TEXT,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_audit_records_create_at ON {{.prefix}}audit_records(create_at);
+CREATE INDEX idx_audit_records_user_id_create_at ON {{.prefix}}audit_records(user_id, create_at);
+CREATE INDEX idx_audit_records_event_create_at ON {{.prefix}}audit_records(event, create_at);
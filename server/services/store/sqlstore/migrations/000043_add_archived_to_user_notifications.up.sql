@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "is_archived" "boolean" "NOT NULL DEFAULT FALSE" }}
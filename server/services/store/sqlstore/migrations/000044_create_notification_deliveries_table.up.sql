@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_deliveries (
+    notification_id VARCHAR(36) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    create_at BIGINT NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (notification_id, channel)
+);
+
+CREATE INDEX idx_notification_deliveries_status_update_at ON {{.prefix}}notification_deliveries(status, update_at);
@@ -0,0 +1,2 @@
+{{ dropColumnIfNeeded "user_notifications" "is_silent" }}
+{{ dropColumnIfNeeded "user_notifications" "urgency" }}
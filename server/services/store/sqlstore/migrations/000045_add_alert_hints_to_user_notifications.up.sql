@@ -0,0 +1,2 @@
+{{ addColumnIfNeeded "user_notifications" "is_silent" "boolean" "NOT NULL DEFAULT FALSE" }}
+{{ addColumnIfNeeded "user_notifications" "urgency" "varchar(20)" "NOT NULL DEFAULT 'normal'" }}
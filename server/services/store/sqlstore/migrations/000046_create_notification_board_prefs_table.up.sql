@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_board_prefs (
+    user_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (user_id, board_id, type)
+);
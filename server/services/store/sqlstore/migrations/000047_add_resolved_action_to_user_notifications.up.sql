@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "resolved_action" "varchar(64)" "NOT NULL DEFAULT ''" }}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_board_digests (
+    user_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    pending_count INTEGER NOT NULL,
+    first_at BIGINT NOT NULL,
+    last_at BIGINT NOT NULL,
+    PRIMARY KEY (user_id, board_id)
+);
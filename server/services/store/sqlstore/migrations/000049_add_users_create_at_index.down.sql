@@ -0,0 +1 @@
+{{if .mysql}}DROP INDEX idx_users_create_at_id ON {{.prefix}}users;{{else}}DROP INDEX IF EXISTS idx_users_create_at_id;{{end}}
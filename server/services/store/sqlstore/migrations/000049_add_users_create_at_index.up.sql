@@ -0,0 +1,2 @@
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "users" "create_at, id" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "team_id" "varchar(36)" "NOT NULL DEFAULT ''" }}
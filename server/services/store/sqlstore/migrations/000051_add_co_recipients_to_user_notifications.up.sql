@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "co_recipients" "text" "" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "is_pinned" "boolean" "NOT NULL DEFAULT FALSE" }}
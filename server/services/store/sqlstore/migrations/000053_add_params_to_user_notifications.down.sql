@@ -0,0 +1 @@
+{{ dropColumnIfNeeded "user_notifications" "params" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "params" "text" "" }}
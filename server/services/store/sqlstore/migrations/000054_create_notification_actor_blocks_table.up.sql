@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_actor_blocks (
+    user_id VARCHAR(36) NOT NULL,
+    blocked_actor_id VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (user_id, blocked_actor_id)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}card_watchers (
+    card_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (card_id, user_id)
+);
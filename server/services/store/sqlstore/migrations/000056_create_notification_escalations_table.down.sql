@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.prefix}}notification_escalations;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_escalations (
+    notification_id VARCHAR(36) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    create_at BIGINT NOT NULL,
+    PRIMARY KEY (notification_id)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_device_reads (
+    notification_id VARCHAR(36) NOT NULL,
+    device_id VARCHAR(64) NOT NULL,
+    read_at BIGINT NOT NULL,
+    PRIMARY KEY (notification_id, device_id)
+);
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "reason" "varchar(20)" "NOT NULL DEFAULT ''" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "source" "varchar(20)" "NOT NULL DEFAULT ''" }}
@@ -0,0 +1,3 @@
+{{if .mysql}}DROP INDEX idx_user_notifications_target_user_id_collapse_key ON {{.prefix}}user_notifications;{{else}}DROP INDEX IF EXISTS idx_user_notifications_target_user_id_collapse_key;{{end}}
+
+{{ dropColumnIfNeeded "user_notifications" "collapse_key" }}
@@ -0,0 +1,4 @@
+{{ addColumnIfNeeded "user_notifications" "collapse_key" "varchar(100)" "NOT NULL DEFAULT ''" }}
+
+{{- /* createIndexIfNeeded tableName columns */ -}}
+{{ createIndexIfNeeded "user_notifications" "target_user_id, collapse_key" }}
@@ -0,0 +1 @@
+{{ addColumnIfNeeded "user_notifications" "is_hidden" "boolean" "NOT NULL DEFAULT FALSE" }}
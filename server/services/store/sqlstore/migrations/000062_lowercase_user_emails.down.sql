@@ -0,0 +1,2 @@
+{{- /* this migration is irreversible, the original case of the emails is not kept */ -}}
+SELECT 1;
@@ -0,0 +1,11 @@
+{{- /* emails that only differ by case belong to different users, lowercasing them would */ -}}
+{{- /* make the users indistinguishable, so they are left as they are for an admin to resolve */ -}}
+UPDATE {{.prefix}}users SET email = LOWER(TRIM(email))
+WHERE {{if .mysql}}BINARY {{end}}email != LOWER(TRIM(email))
+AND LOWER(TRIM(email)) NOT IN (
+	SELECT normalized_email FROM (
+		SELECT LOWER(TRIM(email)) AS normalized_email FROM {{.prefix}}users
+		GROUP BY LOWER(TRIM(email))
+		HAVING COUNT(*) > 1
+	) AS duplicated_emails
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS {{.prefix}}notification_team_defaults (
+    team_id VARCHAR(36) NOT NULL,
+    preferences TEXT NOT NULL,
+    update_at BIGINT NOT NULL,
+    PRIMARY KEY (team_id)
+);
@@ -0,0 +1,7 @@
+INSERT INTO focalboard_users
+(id, username, email)
+VALUES
+('user-id-1', 'johndoe', ' John.Doe@Example.com'),
+('user-id-2', 'janedoe', 'jane@example.com'),
+('user-id-3', 'alice', 'Alice@Example.com'),
+('user-id-4', 'alice2', 'alice@example.com');
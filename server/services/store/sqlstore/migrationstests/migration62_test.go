@@ -0,0 +1,39 @@
+package migrationstests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test62LowercaseUserEmails(t *testing.T) {
+	t.Run("no data exist", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+		th.f.MigrateToStep(62)
+	})
+
+	t.Run("emails are lower cased unless they collide", func(t *testing.T) {
+		th, tearDown := SetupTestHelper(t)
+		defer tearDown()
+
+		th.f.MigrateToStep(61).
+			ExecFile("./fixtures/test62LowercaseUserEmails.sql")
+
+		th.f.MigrateToStep(62)
+
+		users := []struct {
+			ID    string
+			Email string
+		}{}
+		err := th.f.DB().Select(&users, "SELECT id, email FROM focalboard_users ORDER BY id")
+		require.NoError(t, err)
+		require.Len(t, users, 4)
+
+		require.Equal(t, "john.doe@example.com", users[0].Email)
+		require.Equal(t, "jane@example.com", users[1].Email)
+		// emails that only differ by case are left for an admin to resolve
+		require.Equal(t, "Alice@Example.com", users[2].Email)
+		require.Equal(t, "alice@example.com", users[3].Email)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var notificationActorBlockFields = []string{
+	"user_id",
+	"blocked_actor_id",
+	"create_at",
+}
+
+// addNotificationActorBlock blocks the notifications the user gets from the actor. Blocking
+// an actor twice keeps the original block.
+func (s *SQLStore) addNotificationActorBlock(db sq.BaseRunner, userID, actorID string) error {
+	blocked, err := s.isNotificationActorBlocked(db, userID, actorID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"notification_actor_blocks").
+		Columns(notificationActorBlockFields...).
+		Values(userID, actorID, utils.GetMillis())
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot add notification actor block",
+			mlog.String("user_id", userID),
+			mlog.String("actor_id", actorID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) removeNotificationActorBlock(db sq.BaseRunner, userID, actorID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_actor_blocks").
+		Where(sq.Eq{
+			"user_id":          userID,
+			"blocked_actor_id": actorID,
+		})
+
+	_, err := query.Exec()
+	return err
+}
+
+func (s *SQLStore) getNotificationActorBlocks(db sq.BaseRunner, userID string) ([]*model.NotificationActorBlock, error) {
+	query := s.getQueryBuilder(db).
+		Select(notificationActorBlockFields...).
+		From(s.tablePrefix+"notification_actor_blocks").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("create_at", "blocked_actor_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetNotificationActorBlocks ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	blocks := []*model.NotificationActorBlock{}
+	for rows.Next() {
+		var block model.NotificationActorBlock
+		if err := rows.Scan(&block.UserID, &block.BlockedActorID, &block.CreateAt); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, &block)
+	}
+	return blocks, nil
+}
+
+func (s *SQLStore) isNotificationActorBlocked(db sq.BaseRunner, userID, actorID string) (bool, error) {
+	query := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "notification_actor_blocks").
+		Where(sq.Eq{
+			"user_id":          userID,
+			"blocked_actor_id": actorID,
+		})
+
+	var count int
+	if err := query.QueryRow().Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var notificationBoardPrefFields = []string{
+	"user_id",
+	"board_id",
+	"type",
+	"enabled",
+	"update_at",
+}
+
+func (s *SQLStore) notificationBoardPrefsFromRows(rows *sql.Rows) ([]*model.NotificationBoardPreference, error) {
+	preferences := []*model.NotificationBoardPreference{}
+
+	for rows.Next() {
+		var preference model.NotificationBoardPreference
+		err := rows.Scan(
+			&preference.UserID,
+			&preference.BoardID,
+			&preference.Type,
+			&preference.Enabled,
+			&preference.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, &preference)
+	}
+	return preferences, nil
+}
+
+func (s *SQLStore) getNotificationBoardPreferences(db sq.BaseRunner, userID, boardID string) ([]*model.NotificationBoardPreference, error) {
+	query := s.getQueryBuilder(db).
+		Select(notificationBoardPrefFields...).
+		From(s.tablePrefix + "notification_board_prefs").
+		Where(sq.Eq{
+			"user_id":  userID,
+			"board_id": boardID,
+		}).
+		OrderBy("type")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetNotificationBoardPreferences ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.notificationBoardPrefsFromRows(rows)
+}
+
+// getUsersNotificationBoardPreferences returns the board overrides of many users at once,
+// by user ID. Users without overrides are left out of the map.
+func (s *SQLStore) getUsersNotificationBoardPreferences(db sq.BaseRunner, userIDs []string, boardID string) (map[string][]*model.NotificationBoardPreference, error) {
+	byUser := map[string][]*model.NotificationBoardPreference{}
+	if len(userIDs) == 0 {
+		return byUser, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(notificationBoardPrefFields...).
+		From(s.tablePrefix+"notification_board_prefs").
+		Where(sq.Eq{
+			"user_id":  userIDs,
+			"board_id": boardID,
+		}).
+		OrderBy("user_id", "type")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetUsersNotificationBoardPreferences ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	preferences, err := s.notificationBoardPrefsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		byUser[preference.UserID] = append(byUser[preference.UserID], preference)
+	}
+	return byUser, nil
+}
+
+// setNotificationBoardPreferences replaces the board overrides of a user with the given
+// ones. An empty list removes them all, so the global preferences apply again.
+func (s *SQLStore) setNotificationBoardPreferences(db sq.BaseRunner, userID, boardID string, preferences []*model.NotificationBoardPreference) error {
+	deleteQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_board_prefs").
+		Where(sq.Eq{
+			"user_id":  userID,
+			"board_id": boardID,
+		})
+
+	if _, err := deleteQuery.Exec(); err != nil {
+		return err
+	}
+
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	now := utils.GetMillis()
+	insertQuery := s.getQueryBuilder(db).Insert(s.tablePrefix + "notification_board_prefs").
+		Columns(notificationBoardPrefFields...)
+
+	for _, preference := range preferences {
+		preference.UserID = userID
+		preference.BoardID = boardID
+		preference.UpdateAt = now
+		insertQuery = insertQuery.Values(userID, boardID, preference.Type, preference.Enabled, now)
+	}
+
+	if _, err := insertQuery.Exec(); err != nil {
+		s.logger.Error("Cannot set notification board preferences",
+			mlog.String("user_id", userID),
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var notificationDeliveryFields = []string{
+	"notification_id",
+	"channel",
+	"status",
+	"attempts",
+	"last_error",
+	"create_at",
+	"update_at",
+}
+
+func (s *SQLStore) notificationDeliveriesFromRows(rows *sql.Rows) ([]*model.NotificationDelivery, error) {
+	deliveries := []*model.NotificationDelivery{}
+
+	for rows.Next() {
+		var delivery model.NotificationDelivery
+		var lastError sql.NullString
+		err := rows.Scan(
+			&delivery.NotificationID,
+			&delivery.Channel,
+			&delivery.Status,
+			&delivery.Attempts,
+			&lastError,
+			&delivery.CreateAt,
+			&delivery.UpdateAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.LastError = lastError.String
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
+
+// createInAppDeliveries records the given notifications as delivered in-app, which
+// happens as soon as they are stored.
+func (s *SQLStore) createInAppDeliveries(db sq.BaseRunner, notifications []*model.UserNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).Insert(s.tablePrefix + "notification_deliveries").
+		Columns(notificationDeliveryFields...)
+
+	for _, notification := range notifications {
+		query = query.Values(
+			notification.ID,
+			model.NotificationChannelInApp,
+			model.NotificationDeliveryStatusDelivered,
+			1,
+			"",
+			notification.CreateAt,
+			notification.CreateAt,
+		)
+	}
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot create in-app notification deliveries",
+			mlog.Int("count", len(notifications)),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) updateNotificationDeliveryStatus(db sq.BaseRunner, notificationID, channel, status, deliveryError string) error {
+	now := utils.GetMillis()
+
+	// pending only (re)queues the delivery, sending it is what counts as an attempt
+	attempts := 0
+	if status != model.NotificationDeliveryStatusPending {
+		attempts = 1
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"notification_deliveries").
+		Set("status", status).
+		Set("attempts", sq.Expr("attempts + ?", attempts)).
+		Set("last_error", deliveryError).
+		Set("update_at", now).
+		Where(sq.Eq{
+			"notification_id": notificationID,
+			"channel":         channel,
+		}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).Insert(s.tablePrefix+"notification_deliveries").
+		Columns(notificationDeliveryFields...).
+		Values(notificationID, channel, status, attempts, deliveryError, now, now)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot create notification delivery",
+			mlog.String("notification_id", notificationID),
+			mlog.String("channel", channel),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getNotificationDeliveries(db sq.BaseRunner, opts model.QueryNotificationDeliveriesOptions) ([]*model.NotificationDelivery, bool, error) {
+	query := s.getQueryBuilder(db).
+		Select(notificationDeliveryFields...).
+		From(s.tablePrefix+"notification_deliveries").
+		OrderBy("update_at DESC", "notification_id DESC")
+
+	if opts.Channel != "" {
+		query = query.Where(sq.Eq{"channel": opts.Channel})
+	}
+
+	if opts.Status != "" {
+		query = query.Where(sq.Eq{"status": opts.Status})
+	}
+
+	if opts.Page != 0 {
+		query = query.Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(uint64(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetNotificationDeliveries ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	deliveries, err := s.notificationDeliveriesFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(deliveries) > opts.PerPage {
+		deliveries = deliveries[0:opts.PerPage]
+		hasMore = true
+	}
+	return deliveries, hasMore, nil
+}
+
+// deleteNotificationDeliveries deletes the deliveries, escalations and device reads of the
+// notifications matched by the condition on their notification_id, once the notifications
+// are deleted.
+func (s *SQLStore) deleteNotificationDeliveries(db sq.BaseRunner, notifications sq.Sqlizer) error {
+	for _, table := range []string{"notification_deliveries", "notification_escalations", "notification_device_reads"} {
+		_, err := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + table).
+			Where(notifications).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var notificationDeviceReadFields = []string{
+	"notification_id",
+	"device_id",
+	"read_at",
+}
+
+// markNotificationAsReadOnDevice marks the notification of the user as read and records
+// that it was read on the device. Reading it again on the same device keeps the time of
+// the first read.
+func (s *SQLStore) markNotificationAsReadOnDevice(db sq.BaseRunner, notificationID, userID, deviceID string) error {
+	notification, err := s.getUserNotification(db, notificationID, userID)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetMillis()
+	if !notification.Read {
+		_, err = s.getQueryBuilder(db).
+			Update(s.tablePrefix+"user_notifications").
+			Set("is_read", true).
+			Set("update_at", now).
+			Where(sq.Eq{"id": notificationID}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	var count int
+	err = s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "notification_device_reads").
+		Where(sq.Eq{"notification_id": notificationID, "device_id": deviceID}).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"notification_device_reads").
+		Columns(notificationDeviceReadFields...).
+		Values(notificationID, deviceID, now)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot record notification device read",
+			mlog.String("notification_id", notificationID),
+			mlog.String("device_id", deviceID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) getNotificationDeviceReads(db sq.BaseRunner, notificationID string) ([]*model.NotificationDeviceRead, error) {
+	query := s.getQueryBuilder(db).
+		Select(notificationDeviceReadFields...).
+		From(s.tablePrefix+"notification_device_reads").
+		Where(sq.Eq{"notification_id": notificationID}).
+		OrderBy("read_at", "device_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetNotificationDeviceReads ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	reads := []*model.NotificationDeviceRead{}
+	for rows.Next() {
+		var read model.NotificationDeviceRead
+		if err := rows.Scan(&read.NotificationID, &read.DeviceID, &read.ReadAt); err != nil {
+			return nil, err
+		}
+		reads = append(reads, &read)
+	}
+	return reads, nil
+}
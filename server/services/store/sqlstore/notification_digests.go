@@ -0,0 +1,133 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+var notificationDigestFields = []string{
+	"user_id",
+	"board_id",
+	"pending_count",
+	"first_at",
+	"last_at",
+}
+
+func (s *SQLStore) notificationDigestsFromRows(rows *sql.Rows) ([]*model.NotificationDigest, error) {
+	digests := []*model.NotificationDigest{}
+
+	for rows.Next() {
+		var digest model.NotificationDigest
+		err := rows.Scan(
+			&digest.UserID,
+			&digest.BoardID,
+			&digest.PendingCount,
+			&digest.FirstAt,
+			&digest.LastAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, &digest)
+	}
+	return digests, nil
+}
+
+// addNotificationDigestActivity counts one more activity in the pending digest of a user
+// for a board, starting a new digest if there is none.
+func (s *SQLStore) addNotificationDigestActivity(db sq.BaseRunner, userID, boardID string, at int64) error {
+	result, err := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"notification_board_digests").
+		Set("pending_count", sq.Expr("pending_count + 1")).
+		Set("last_at", at).
+		Where(sq.Eq{
+			"user_id":  userID,
+			"board_id": boardID,
+		}).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query := s.getQueryBuilder(db).Insert(s.tablePrefix+"notification_board_digests").
+		Columns(notificationDigestFields...).
+		Values(userID, boardID, 1, at, at)
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot create notification digest",
+			mlog.String("user_id", userID),
+			mlog.String("board_id", boardID),
+			mlog.Err(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// getDueNotificationDigests returns the digests whose oldest activity happened at or
+// before the given time, oldest first.
+func (s *SQLStore) getDueNotificationDigests(db sq.BaseRunner, before int64) ([]*model.NotificationDigest, error) {
+	query := s.getQueryBuilder(db).
+		Select(notificationDigestFields...).
+		From(s.tablePrefix + "notification_board_digests").
+		Where(sq.LtOrEq{"first_at": before}).
+		OrderBy("first_at")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetDueNotificationDigests ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.notificationDigestsFromRows(rows)
+}
+
+// deleteNotificationDigest removes a digest once it is sent, unless more activity was
+// added since it was read: it returns false then and the digest is sent next time.
+func (s *SQLStore) deleteNotificationDigest(db sq.BaseRunner, userID, boardID string, lastAt int64) (bool, error) {
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_board_digests").
+		Where(sq.Eq{
+			"user_id":  userID,
+			"board_id": boardID,
+			"last_at":  lastAt,
+		}).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// sendNotificationDigest creates the notification of a digest and removes the digest,
+// unless more activity was added since it was read: it returns nil then, creates nothing
+// and the digest is sent next time. If the notification cannot be created the digest is
+// kept as well.
+func (s *SQLStore) sendNotificationDigest(db sq.BaseRunner, digest *model.NotificationDigest, notification *model.UserNotification) (*model.UserNotification, error) {
+	deleted, err := s.deleteNotificationDigest(db, digest.UserID, digest.BoardID, digest.LastAt)
+	if err != nil || !deleted {
+		return nil, err
+	}
+	return s.createUserNotification(db, notification)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// getNotificationsToEscalate returns up to limit unread and unarchived high urgency
+// notifications created before the given time that were never escalated, oldest first.
+func (s *SQLStore) getNotificationsToEscalate(db sq.BaseRunner, before int64, limit int) ([]*model.UserNotification, error) {
+	query := s.getQueryBuilder(db).
+		Select(userNotificationFields...).
+		From(s.tablePrefix+"user_notifications").
+		Where(sq.Eq{
+			"is_read":     false,
+			"is_archived": false,
+			"urgency":     model.NotificationUrgencyHigh,
+		}).
+		Where(sq.Lt{"create_at": before}).
+		Where("NOT EXISTS (SELECT 1 FROM "+s.tablePrefix+"notification_escalations WHERE notification_id = "+s.tablePrefix+"user_notifications.id)").
+		OrderBy("create_at", "id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetNotificationsToEscalate ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.userNotificationFromRows(rows)
+}
+
+// escalateNotification records the escalation of a notification through the first of the
+// channels it has no delivery for yet, queueing a pending delivery there. Returns the
+// channel, or an empty string if the notification was already escalated or went through
+// all the channels. The escalation is recorded either way, so it is never tried again.
+func (s *SQLStore) escalateNotification(db sq.BaseRunner, notificationID string, channels []string) (string, error) {
+	var count int
+	err := s.getQueryBuilder(db).
+		Select("COUNT(*)").
+		From(s.tablePrefix + "notification_escalations").
+		Where(sq.Eq{"notification_id": notificationID}).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return "", err
+	}
+	if count > 0 {
+		return "", nil
+	}
+
+	rows, err := s.getQueryBuilder(db).
+		Select("channel").
+		From(s.tablePrefix + "notification_deliveries").
+		Where(sq.Eq{"notification_id": notificationID}).
+		Query()
+	if err != nil {
+		return "", err
+	}
+	usedChannels, err := idsFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return "", err
+	}
+
+	used := map[string]bool{}
+	for _, usedChannel := range usedChannels {
+		used[usedChannel] = true
+	}
+	channel := ""
+	for _, candidate := range channels {
+		if !used[candidate] {
+			channel = candidate
+			break
+		}
+	}
+
+	query := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"notification_escalations").
+		Columns("notification_id", "channel", "create_at").
+		Values(notificationID, channel, utils.GetMillis())
+
+	if _, err := query.Exec(); err != nil {
+		s.logger.Error("Cannot record notification escalation",
+			mlog.String("notification_id", notificationID),
+			mlog.String("channel", channel),
+			mlog.Err(err),
+		)
+		return "", err
+	}
+
+	if channel == "" {
+		return "", nil
+	}
+	if err := s.updateNotificationDeliveryStatus(db, notificationID, channel, model.NotificationDeliveryStatusPending, ""); err != nil {
+		return "", err
+	}
+	return channel, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+
+	"github.com/mattermost/mattermost/server/public/shared/mlog"
+)
+
+// notificationTeamDefaultsFromRow decodes the team defaults of a row, returning empty
+// defaults if the team has none.
+func (s *SQLStore) notificationTeamDefaultsFromRow(row sq.RowScanner) (*model.NotificationPreferencesOverrides, error) {
+	var value string
+	err := row.Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.NotificationPreferencesOverrides{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := &model.NotificationPreferencesOverrides{}
+	if err := json.Unmarshal([]byte(value), defaults); err != nil {
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// getNotificationTeamDefaults returns the default notification preferences of a team's
+// members, or empty defaults if the team sets none.
+func (s *SQLStore) getNotificationTeamDefaults(db sq.BaseRunner, teamID string) (*model.NotificationPreferencesOverrides, error) {
+	query := s.getQueryBuilder(db).
+		Select("preferences").
+		From(s.tablePrefix + "notification_team_defaults").
+		Where(sq.Eq{"team_id": teamID})
+
+	defaults, err := s.notificationTeamDefaultsFromRow(query.QueryRow())
+	if err != nil {
+		s.logger.Error("Cannot get notification team defaults", mlog.String("team_id", teamID), mlog.Err(err))
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// getNotificationTeamDefaultsForBoard returns the default notification preferences of the
+// team a board belongs to, or empty defaults if the team sets none.
+func (s *SQLStore) getNotificationTeamDefaultsForBoard(db sq.BaseRunner, boardID string) (*model.NotificationPreferencesOverrides, error) {
+	query := s.getQueryBuilder(db).
+		Select("d.preferences").
+		From(s.tablePrefix + "notification_team_defaults AS d").
+		Join(s.tablePrefix + "boards AS b ON b.team_id = d.team_id").
+		Where(sq.Eq{"b.id": boardID})
+
+	defaults, err := s.notificationTeamDefaultsFromRow(query.QueryRow())
+	if err != nil {
+		s.logger.Error("Cannot get notification team defaults of board", mlog.String("board_id", boardID), mlog.Err(err))
+		return nil, err
+	}
+	return defaults, nil
+}
+
+// setNotificationTeamDefaults replaces the default notification preferences of a team's
+// members. Empty defaults remove them, so the built-in defaults apply again.
+func (s *SQLStore) setNotificationTeamDefaults(db sq.BaseRunner, teamID string, defaults *model.NotificationPreferencesOverrides) error {
+	deleteQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_team_defaults").
+		Where(sq.Eq{"team_id": teamID})
+
+	if _, err := deleteQuery.Exec(); err != nil {
+		return err
+	}
+
+	if defaults == nil || *defaults == (model.NotificationPreferencesOverrides{}) {
+		return nil
+	}
+
+	value, err := json.Marshal(defaults)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := s.getQueryBuilder(db).
+		Insert(s.tablePrefix+"notification_team_defaults").
+		Columns("team_id", "preferences", "update_at").
+		Values(teamID, string(value), utils.GetMillis())
+
+	if _, err := insertQuery.Exec(); err != nil {
+		s.logger.Error("Cannot set notification team defaults", mlog.String("team_id", teamID), mlog.Err(err))
+		return err
+	}
+	return nil
+}
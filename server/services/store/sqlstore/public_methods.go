@@ -22,6 +22,78 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+func (s *SQLStore) AddCardWatcher(cardID string, userID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.addCardWatcher(s.db, cardID, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.addCardWatcher(tx, cardID, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AddCardWatcher"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) AddNotificationActorBlock(userID string, actorID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.addNotificationActorBlock(s.db, userID, actorID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.addNotificationActorBlock(tx, userID, actorID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AddNotificationActorBlock"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) AddNotificationDigestActivity(userID string, boardID string, at int64) error {
+	if s.dbType == model.SqliteDBType {
+		return s.addNotificationDigestActivity(s.db, userID, boardID, at)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.addNotificationDigestActivity(tx, userID, boardID, at)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "AddNotificationDigestActivity"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) AddUpdateCategoryBoard(userID string, categoryID string, boardIDs []string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.addUpdateCategoryBoard(s.db, userID, categoryID, boardIDs)
@@ -56,6 +128,16 @@ func (s *SQLStore) CleanUpSessions(expireTime int64) error {
 
 }
 
+func (s *SQLStore) CountNotificationsByActor(since int64, limit int) ([]*model.NotificationActorCount, error) {
+	return s.countNotificationsByActor(s.db, since, limit)
+
+}
+
+func (s *SQLStore) CreateAuditRecords(records []*model.AuditRecord) error {
+	return s.createAuditRecords(s.db, records)
+
+}
+
 func (s *SQLStore) CreateBoardsAndBlocks(bab *model.BoardsAndBlocks, userID string) (*model.BoardsAndBlocks, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.createBoardsAndBlocks(s.db, bab, userID)
@@ -143,6 +225,59 @@ func (s *SQLStore) CreateUser(user *model.User) (*model.User, error) {
 
 }
 
+func (s *SQLStore) CreateUserNotification(notification *model.UserNotification) (*model.UserNotification, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.createUserNotification(s.db, notification)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.createUserNotification(tx, notification)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateUserNotification"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) CreateUserNotifications(notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.createUserNotifications(s.db, notifications)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.createUserNotifications(tx, notifications)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "CreateUserNotifications"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+func (s *SQLStore) DeleteAuditRecordsBefore(before int64, batchSize int) (int64, error) {
+	return s.deleteAuditRecordsBefore(s.db, before, batchSize)
+
+}
+
 func (s *SQLStore) DeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.deleteBlock(s.db, blockID, modifiedBy)
@@ -235,11 +370,40 @@ func (s *SQLStore) DeleteMember(boardID string, userID string) error {
 
 }
 
+func (s *SQLStore) DeleteNotificationDigest(userID string, boardID string, lastAt int64) (bool, error) {
+	return s.deleteNotificationDigest(s.db, userID, boardID, lastAt)
+
+}
+
 func (s *SQLStore) DeleteNotificationHint(blockID string) error {
 	return s.deleteNotificationHint(s.db, blockID)
 
 }
 
+func (s *SQLStore) DeleteNotificationsForUser(userID string) (int64, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteNotificationsForUser(s.db, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return 0, txErr
+	}
+	result, err := s.deleteNotificationsForUser(tx, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteNotificationsForUser"))
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) DeleteSession(sessionID string) error {
 	return s.deleteSession(s.db, sessionID)
 
@@ -250,6 +414,40 @@ func (s *SQLStore) DeleteSubscription(blockID string, subscriberID string) error
 
 }
 
+func (s *SQLStore) DeleteUser(userID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.deleteUser(s.db, userID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.deleteUser(tx, userID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "DeleteUser"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) DeleteUserNotification(notificationID string, userID string) error {
+	return s.deleteUserNotification(s.db, notificationID, userID)
+
+}
+
+func (s *SQLStore) DeleteUserNotificationsBefore(opts model.PurgeUserNotificationsOptions, batchSize int) (int64, error) {
+	return s.deleteUserNotificationsBefore(s.db, opts, batchSize)
+
+}
+
 func (s *SQLStore) DuplicateBlock(boardID string, blockID string, userID string, asTemplate bool) ([]*model.Block, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.duplicateBlock(s.db, boardID, blockID, userID, asTemplate)
@@ -298,6 +496,30 @@ func (s *SQLStore) DuplicateBoard(boardID string, userID string, toTeam string,
 
 }
 
+func (s *SQLStore) EscalateNotification(notificationID string, channels []string) (string, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.escalateNotification(s.db, notificationID, channels)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return "", txErr
+	}
+	result, err := s.escalateNotification(tx, notificationID, channels)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "EscalateNotification"))
+		}
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) GetActiveUserCount(updatedSecondsAgo int64) (int, error) {
 	return s.getActiveUserCount(s.db, updatedSecondsAgo)
 
@@ -308,6 +530,16 @@ func (s *SQLStore) GetAllTeams() ([]*model.Team, error) {
 
 }
 
+func (s *SQLStore) GetAllUsers() ([]*model.User, error) {
+	return s.getAllUsers(s.db)
+
+}
+
+func (s *SQLStore) GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error) {
+	return s.getAuditRecords(s.db, opts)
+
+}
+
 func (s *SQLStore) GetBlock(blockID string) (*model.Block, error) {
 	return s.getBlock(s.db, blockID)
 
@@ -398,6 +630,16 @@ func (s *SQLStore) GetBoardMemberHistory(boardID string, userID string, limit ui
 
 }
 
+func (s *SQLStore) GetBoardMembershipsForUser(userID string, page int, perPage int) ([]*model.UserBoardMembership, bool, error) {
+	return s.getBoardMembershipsForUser(s.db, userID, page, perPage)
+
+}
+
+func (s *SQLStore) GetBoardsByIDs(boardIDs []string) ([]*model.Board, error) {
+	return s.getBoardsByIDs(s.db, boardIDs)
+
+}
+
 func (s *SQLStore) GetBoardsComplianceHistory(opts model.QueryBoardsComplianceHistoryOptions) ([]*model.BoardHistory, bool, error) {
 	return s.getBoardsComplianceHistory(s.db, opts)
 
@@ -423,6 +665,11 @@ func (s *SQLStore) GetCardLimitTimestamp() (int64, error) {
 
 }
 
+func (s *SQLStore) GetCardWatchers(cardID string) ([]*model.CardWatcher, error) {
+	return s.getCardWatchers(s.db, cardID)
+
+}
+
 func (s *SQLStore) GetCategory(id string) (*model.Category, error) {
 	return s.getCategory(s.db, id)
 
@@ -433,6 +680,11 @@ func (s *SQLStore) GetChannel(teamID string, channelID string) (*mmModel.Channel
 
 }
 
+func (s *SQLStore) GetDueNotificationDigests(before int64) ([]*model.NotificationDigest, error) {
+	return s.getDueNotificationDigests(s.db, before)
+
+}
+
 func (s *SQLStore) GetFileInfo(id string) (*mmModel.FileInfo, error) {
 	return s.getFileInfo(s.db, id)
 
@@ -463,11 +715,56 @@ func (s *SQLStore) GetNextNotificationHint(remove bool) (*model.NotificationHint
 
 }
 
+func (s *SQLStore) GetNotificationActorBlocks(userID string) ([]*model.NotificationActorBlock, error) {
+	return s.getNotificationActorBlocks(s.db, userID)
+
+}
+
+func (s *SQLStore) GetNotificationBoardPreferences(userID string, boardID string) ([]*model.NotificationBoardPreference, error) {
+	return s.getNotificationBoardPreferences(s.db, userID, boardID)
+
+}
+
+func (s *SQLStore) GetNotificationDeliveries(opts model.QueryNotificationDeliveriesOptions) ([]*model.NotificationDelivery, bool, error) {
+	return s.getNotificationDeliveries(s.db, opts)
+
+}
+
+func (s *SQLStore) GetNotificationDeviceReads(notificationID string) ([]*model.NotificationDeviceRead, error) {
+	return s.getNotificationDeviceReads(s.db, notificationID)
+
+}
+
 func (s *SQLStore) GetNotificationHint(blockID string) (*model.NotificationHint, error) {
 	return s.getNotificationHint(s.db, blockID)
 
 }
 
+func (s *SQLStore) GetNotificationSummary(userID string) (*model.NotificationSummary, error) {
+	return s.getNotificationSummary(s.db, userID)
+
+}
+
+func (s *SQLStore) GetNotificationTeamDefaults(teamID string) (*model.NotificationPreferencesOverrides, error) {
+	return s.getNotificationTeamDefaults(s.db, teamID)
+
+}
+
+func (s *SQLStore) GetNotificationTeamDefaultsForBoard(boardID string) (*model.NotificationPreferencesOverrides, error) {
+	return s.getNotificationTeamDefaultsForBoard(s.db, boardID)
+
+}
+
+func (s *SQLStore) GetNotificationsToEscalate(before int64, limit int) ([]*model.UserNotification, error) {
+	return s.getNotificationsToEscalate(s.db, before, limit)
+
+}
+
+func (s *SQLStore) GetOldestUnreadNotificationTime(userID string) (int64, error) {
+	return s.getOldestUnreadNotificationTime(s.db, userID)
+
+}
+
 func (s *SQLStore) GetRegisteredUserCount() (int, error) {
 	return s.getRegisteredUserCount(s.db)
 
@@ -538,6 +835,31 @@ func (s *SQLStore) GetTemplateBoards(teamID string, userID string) ([]*model.Boa
 
 }
 
+func (s *SQLStore) GetUnreadCountByBoard(userID string) ([]*model.NotificationBoardUnreadCount, error) {
+	return s.getUnreadCountByBoard(s.db, userID)
+
+}
+
+func (s *SQLStore) GetUnreadCountByBoardForMember(userID string) ([]*model.NotificationBoardUnreadCount, error) {
+	return s.getUnreadCountByBoardForMember(s.db, userID)
+
+}
+
+func (s *SQLStore) GetUnreadNotificationByCollapseKey(userID string, collapseKey string, actorUserID string, source string) (*model.UserNotification, error) {
+	return s.getUnreadNotificationByCollapseKey(s.db, userID, collapseKey, actorUserID, source)
+
+}
+
+func (s *SQLStore) GetUnreadNotificationCount(userID string) (int, error) {
+	return s.getUnreadNotificationCount(s.db, userID)
+
+}
+
+func (s *SQLStore) GetUnreadNotificationCountByType(userID string) (map[string]int, error) {
+	return s.getUnreadNotificationCountByType(s.db, userID)
+
+}
+
 func (s *SQLStore) GetUsedCardsCount() (int, error) {
 	return s.getUsedCardsCount(s.db)
 
@@ -568,6 +890,55 @@ func (s *SQLStore) GetUserCategoryBoards(userID string, teamID string) ([]model.
 
 }
 
+func (s *SQLStore) GetUserDeletionSummary(userID string) (*model.UserDeletionSummary, error) {
+	return s.getUserDeletionSummary(s.db, userID)
+
+}
+
+func (s *SQLStore) GetUserNotification(notificationID string, userID string) (*model.UserNotification, error) {
+	return s.getUserNotification(s.db, notificationID, userID)
+
+}
+
+func (s *SQLStore) GetUserNotificationByID(notificationID string) (*model.UserNotification, error) {
+	return s.getUserNotificationByID(s.db, notificationID)
+
+}
+
+func (s *SQLStore) GetUserNotificationThreads(userID string, opts model.QueryUserNotificationsOptions, limit int) ([]*model.UserNotification, error) {
+	return s.getUserNotificationThreads(s.db, userID, opts, limit)
+
+}
+
+func (s *SQLStore) GetUserNotifications(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	return s.getUserNotifications(s.db, userID, opts)
+
+}
+
+func (s *SQLStore) GetUserNotificationsMarkingRead(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.getUserNotificationsMarkingRead(s.db, userID, opts)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.getUserNotificationsMarkingRead(tx, userID, opts)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "GetUserNotificationsMarkingRead"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) GetUserPreferences(userID string) (mmModel.Preferences, error) {
 	return s.getUserPreferences(s.db, userID)
 
@@ -588,13 +959,18 @@ func (s *SQLStore) GetUsersList(userIDs []string, showEmail bool, showName bool)
 
 }
 
-func (s *SQLStore) GetAllUsers() ([]*model.User, error) {
-	return s.getAllUsers(s.db)
+func (s *SQLStore) GetUsersNotificationBoardPreferences(userIDs []string, boardID string) (map[string][]*model.NotificationBoardPreference, error) {
+	return s.getUsersNotificationBoardPreferences(s.db, userIDs, boardID)
 
 }
 
-func (s *SQLStore) DeleteUser(userID string) error {
-	return s.deleteUser(s.db, userID)
+func (s *SQLStore) GetUsersPage(opts model.QueryUsersOptions) ([]*model.User, bool, error) {
+	return s.getUsersPage(s.db, opts)
+
+}
+
+func (s *SQLStore) GetUsersPreferences(userIDs []string) (map[string]mmModel.Preferences, error) {
+	return s.getUsersPreferences(s.db, userIDs)
 
 }
 
@@ -675,6 +1051,79 @@ func (s *SQLStore) InsertBoardWithAdmin(board *model.Board, userID string) (*mod
 
 }
 
+func (s *SQLStore) IsNotificationActorBlocked(userID string, actorID string) (bool, error) {
+	return s.isNotificationActorBlocked(s.db, userID, actorID)
+
+}
+
+func (s *SQLStore) MarkAllNotificationsAsRead(userID string, opts model.MarkNotificationsAsReadOptions) (int64, error) {
+	return s.markAllNotificationsAsRead(s.db, userID, opts)
+
+}
+
+func (s *SQLStore) MarkNotificationAsRead(notificationID string, userID string) error {
+	return s.markNotificationAsRead(s.db, notificationID, userID)
+
+}
+
+func (s *SQLStore) MarkNotificationAsReadOnDevice(notificationID string, userID string, deviceID string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.markNotificationAsReadOnDevice(s.db, notificationID, userID, deviceID)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.markNotificationAsReadOnDevice(tx, notificationID, userID, deviceID)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "MarkNotificationAsReadOnDevice"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) MarkNotificationAsUnread(notificationID string, userID string) error {
+	return s.markNotificationAsUnread(s.db, notificationID, userID)
+
+}
+
+func (s *SQLStore) MarkNotificationsAsRead(ids []string, userID string) (int64, error) {
+	return s.markNotificationsAsRead(s.db, ids, userID)
+
+}
+
+func (s *SQLStore) MarkNotificationsAsReadReturningIDs(userID string, opts model.MarkNotificationsAsReadOptions) ([]string, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.markNotificationsAsReadReturningIDs(s.db, userID, opts)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.markNotificationsAsReadReturningIDs(tx, userID, opts)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "MarkNotificationsAsReadReturningIDs"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) PatchBlock(blockID string, blockPatch *model.BlockPatch, userID string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.patchBlock(s.db, blockID, blockPatch, userID)
@@ -781,16 +1230,31 @@ func (s *SQLStore) PostMessage(message string, postType string, channelID string
 
 }
 
+func (s *SQLStore) ReassignNotificationsBoard(fromBoardID string, toBoardID string, toTeamID string) (int64, error) {
+	return s.reassignNotificationsBoard(s.db, fromBoardID, toBoardID, toTeamID)
+
+}
+
 func (s *SQLStore) RefreshSession(session *model.Session) error {
 	return s.refreshSession(s.db, session)
 
 }
 
+func (s *SQLStore) RemoveCardWatcher(cardID string, userID string) error {
+	return s.removeCardWatcher(s.db, cardID, userID)
+
+}
+
 func (s *SQLStore) RemoveDefaultTemplates(boards []*model.Board) error {
 	return s.removeDefaultTemplates(s.db, boards)
 
 }
 
+func (s *SQLStore) RemoveNotificationActorBlock(userID string, actorID string) error {
+	return s.removeNotificationActorBlock(s.db, userID, actorID)
+
+}
+
 func (s *SQLStore) ReorderCategories(userID string, teamID string, newCategoryOrder []string) ([]string, error) {
 	return s.reorderCategories(s.db, userID, teamID, newCategoryOrder)
 
@@ -801,6 +1265,11 @@ func (s *SQLStore) ReorderCategoryBoards(categoryID string, newBoardsOrder []str
 
 }
 
+func (s *SQLStore) ResolveUserNotification(notificationID string, userID string, action string) error {
+	return s.resolveUserNotification(s.db, notificationID, userID, action)
+
+}
+
 func (s *SQLStore) RunDataRetention(globalRetentionDate int64, batchSize int64) (int64, error) {
 	if s.dbType == model.SqliteDBType {
 		return s.runDataRetention(s.db, globalRetentionDate, batchSize)
@@ -850,6 +1319,11 @@ func (s *SQLStore) SearchUserChannels(teamID string, userID string, query string
 
 }
 
+func (s *SQLStore) SearchUserNotifications(opts model.SearchUserNotificationsOptions) ([]*model.UserNotification, bool, error) {
+	return s.searchUserNotifications(s.db, opts)
+
+}
+
 func (s *SQLStore) SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail bool, showName bool) ([]*model.User, error) {
 	return s.searchUsersByTeam(s.db, teamID, searchQuery, asGuestID, excludeBots, showEmail, showName)
 
@@ -860,16 +1334,146 @@ func (s *SQLStore) SendMessage(message string, postType string, receipts []strin
 
 }
 
+func (s *SQLStore) SendNotificationDigest(digest *model.NotificationDigest, notification *model.UserNotification) (*model.UserNotification, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.sendNotificationDigest(s.db, digest, notification)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.sendNotificationDigest(tx, digest, notification)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SendNotificationDigest"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) SetBoardVisibility(userID string, categoryID string, boardID string, visible bool) error {
 	return s.setBoardVisibility(s.db, userID, categoryID, boardID, visible)
 
 }
 
+func (s *SQLStore) SetNotificationArchived(notificationID string, userID string, archived bool) error {
+	return s.setNotificationArchived(s.db, notificationID, userID, archived)
+
+}
+
+func (s *SQLStore) SetNotificationBoardPreferences(userID string, boardID string, preferences []*model.NotificationBoardPreference) error {
+	if s.dbType == model.SqliteDBType {
+		return s.setNotificationBoardPreferences(s.db, userID, boardID, preferences)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.setNotificationBoardPreferences(tx, userID, boardID, preferences)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetNotificationBoardPreferences"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) SetNotificationPinned(notificationID string, userID string, pinned bool) error {
+	return s.setNotificationPinned(s.db, notificationID, userID, pinned)
+
+}
+
+func (s *SQLStore) SetNotificationTeamDefaults(teamID string, defaults *model.NotificationPreferencesOverrides) error {
+	if s.dbType == model.SqliteDBType {
+		return s.setNotificationTeamDefaults(s.db, teamID, defaults)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.setNotificationTeamDefaults(tx, teamID, defaults)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetNotificationTeamDefaults"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *SQLStore) SetNotificationsHiddenForBoard(boardID string, hidden bool) ([]string, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.setNotificationsHiddenForBoard(s.db, boardID, hidden)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.setNotificationsHiddenForBoard(tx, boardID, hidden)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SetNotificationsHiddenForBoard"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) SetSystemSetting(key string, value string) error {
 	return s.setSystemSetting(s.db, key, value)
 
 }
 
+func (s *SQLStore) SyncNotificationReadStates(userID string, changes []*model.NotificationReadSync) ([]*model.UserNotification, error) {
+	if s.dbType == model.SqliteDBType {
+		return s.syncNotificationReadStates(s.db, userID, changes)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return nil, txErr
+	}
+	result, err := s.syncNotificationReadStates(tx, userID, changes)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "SyncNotificationReadStates"))
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
 func (s *SQLStore) UndeleteBlock(blockID string, modifiedBy string) error {
 	if s.dbType == model.SqliteDBType {
 		return s.undeleteBlock(s.db, blockID, modifiedBy)
@@ -918,6 +1522,11 @@ func (s *SQLStore) UndeleteBoard(boardID string, modifiedBy string) error {
 
 }
 
+func (s *SQLStore) UnresolveUserNotification(notificationID string, userID string, action string, read bool) error {
+	return s.unresolveUserNotification(s.db, notificationID, userID, action, read)
+
+}
+
 func (s *SQLStore) UpdateCardLimitTimestamp(cardLimit int) (int64, error) {
 	return s.updateCardLimitTimestamp(s.db, cardLimit)
 
@@ -928,6 +1537,30 @@ func (s *SQLStore) UpdateCategory(category model.Category) error {
 
 }
 
+func (s *SQLStore) UpdateNotificationDeliveryStatus(notificationID string, channel string, status string, deliveryError string) error {
+	if s.dbType == model.SqliteDBType {
+		return s.updateNotificationDeliveryStatus(s.db, notificationID, channel, status, deliveryError)
+	}
+	tx, txErr := s.db.BeginTx(context.Background(), nil)
+	if txErr != nil {
+		return txErr
+	}
+	err := s.updateNotificationDeliveryStatus(tx, notificationID, channel, status, deliveryError)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			s.logger.Error("transaction rollback error", mlog.Err(rollbackErr), mlog.String("methodName", "UpdateNotificationDeliveryStatus"))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
 func (s *SQLStore) UpdateSession(session *model.Session) error {
 	return s.updateSession(s.db, session)
 
@@ -943,6 +1576,11 @@ func (s *SQLStore) UpdateUser(user *model.User) (*model.User, error) {
 
 }
 
+func (s *SQLStore) UpdateUserNotification(notification *model.UserNotification) error {
+	return s.updateUserNotification(s.db, notification)
+
+}
+
 func (s *SQLStore) UpdateUserPassword(username string, password string) error {
 	return s.updateUserPassword(s.db, username, password)
 
@@ -972,29 +1610,3 @@ func (s *SQLStore) UpsertTeamSignupToken(team model.Team) error {
 	return s.upsertTeamSignupToken(s.db, team)
 
 }
-
-// User Notifications
-
-func (s *SQLStore) CreateUserNotification(notification *model.UserNotification) (*model.UserNotification, error) {
-	return s.createUserNotification(s.db, notification)
-}
-
-func (s *SQLStore) GetUserNotifications(userID string, limit int) ([]*model.UserNotification, error) {
-	return s.getUserNotifications(s.db, userID, limit)
-}
-
-func (s *SQLStore) GetUnreadNotificationCount(userID string) (int, error) {
-	return s.getUnreadNotificationCount(s.db, userID)
-}
-
-func (s *SQLStore) MarkNotificationAsRead(notificationID, userID string) error {
-	return s.markNotificationAsRead(s.db, notificationID, userID)
-}
-
-func (s *SQLStore) MarkAllNotificationsAsRead(userID string) error {
-	return s.markAllNotificationsAsRead(s.db, userID)
-}
-
-func (s *SQLStore) DeleteUserNotification(notificationID, userID string) error {
-	return s.deleteUserNotification(s.db, notificationID, userID)
-}
@@ -73,6 +73,10 @@ func New(params Params) (*SQLStore, error) {
 			return nil, mErr
 		}
 	}
+
+	if err := store.checkUserNotificationColumns(); err != nil {
+		params.Logger.Error(`Cannot check the user_notifications table columns`, mlog.Err(err))
+	}
 	return store, nil
 }
 
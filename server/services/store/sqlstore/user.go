@@ -20,6 +20,10 @@ var (
 	errUnsupportedOperation = errors.New("unsupported operation")
 )
 
+// usersMaxOffset is the number of users pages selected by number can skip, deeper pages
+// must be selected with a cursor.
+const usersMaxOffset = 1000
+
 type UserNotFoundError struct {
 	id string
 }
@@ -115,8 +119,10 @@ func (s *SQLStore) getUsersList(db sq.BaseRunner, userIDs []string, _, _ bool) (
 	return users, nil
 }
 
+// getUserByEmail looks up the normalized email, the form emails are stored in. Emails
+// stored before they were normalized are lower cased by a migration.
 func (s *SQLStore) getUserByEmail(db sq.BaseRunner, email string) (*model.User, error) {
-	return s.getUserByCondition(db, sq.Eq{"email": email})
+	return s.getUserByCondition(db, sq.Eq{"email": model.NormalizeEmail(email)})
 }
 
 func (s *SQLStore) getUserByUsername(db sq.BaseRunner, username string) (*model.User, error) {
@@ -214,6 +220,77 @@ func (s *SQLStore) updateUserPasswordByID(db sq.BaseRunner, userID, password str
 	return nil
 }
 
+// getUsersPage returns a page of users, newest first, and whether there is a next page.
+// Pages selected with a cursor seek past the users of the previous pages through the
+// create_at index, pages selected by number skip them with OFFSET which gets slow on
+// large instances, so those are limited to the first usersMaxOffset users.
+func (s *SQLStore) getUsersPage(db sq.BaseRunner, opts model.QueryUsersOptions) ([]*model.User, bool, error) {
+	if opts.PerPage <= 0 {
+		return nil, false, model.NewErrBadRequest("the number of users per page must be positive")
+	}
+
+	query := s.getQueryBuilder(db).
+		Select(
+			"id",
+			"username",
+			"email",
+			"password",
+			"mfa_secret",
+			"auth_service",
+			"auth_data",
+			"create_at",
+			"update_at",
+			"delete_at",
+		).
+		From(s.tablePrefix+"users").
+		Where(sq.Eq{"delete_at": 0}).
+		OrderBy("create_at DESC", "id DESC").
+		// N+1 to check if there's a next page for pagination
+		Limit(uint64(opts.PerPage) + 1)
+
+	if opts.Cursor != "" {
+		createAt, id, err := model.ParseUsersCursor(opts.Cursor)
+		if err != nil {
+			return nil, false, err
+		}
+		query = query.Where(sq.Or{
+			sq.Lt{"create_at": createAt},
+			sq.And{sq.Eq{"create_at": createAt}, sq.Lt{"id": id}},
+		})
+	} else if opts.Page > 0 {
+		offset := opts.Page * opts.PerPage
+		if offset > usersMaxOffset {
+			return nil, false, model.NewErrBadRequest(fmt.Sprintf("pages past the first %d users must be selected with a cursor", usersMaxOffset))
+		}
+		query = query.Offset(uint64(offset))
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`getUsersPage ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	users, err := s.usersFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Clear sensitive data
+	for _, user := range users {
+		user.Password = ""
+		user.MfaSecret = ""
+	}
+
+	var hasMore bool
+	if len(users) > opts.PerPage {
+		users = users[0:opts.PerPage]
+		hasMore = true
+	}
+	return users, hasMore, nil
+}
+
 func (s *SQLStore) getAllUsers(db sq.BaseRunner) ([]*model.User, error) {
 	query := s.getQueryBuilder(db).
 		Select(
@@ -253,6 +330,9 @@ func (s *SQLStore) getAllUsers(db sq.BaseRunner) ([]*model.User, error) {
 	return users, nil
 }
 
+// deleteUser soft deletes a user and cleans up the data that refers to them: their board
+// memberships and the notifications they receive are deleted, and the notifications they
+// authored are anonymized.
 func (s *SQLStore) deleteUser(db sq.BaseRunner, userID string) error {
 	now := utils.GetMillis()
 
@@ -275,6 +355,108 @@ func (s *SQLStore) deleteUser(db sq.BaseRunner, userID string) error {
 		return UserNotFoundError{userID}
 	}
 
+	if err := s.deleteUserMemberships(db, userID); err != nil {
+		return err
+	}
+
+	deleteBoardPrefsQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_board_prefs").
+		Where(sq.Eq{"user_id": userID})
+
+	if _, err := deleteBoardPrefsQuery.Exec(); err != nil {
+		return err
+	}
+
+	deleteActorBlocksQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_actor_blocks").
+		Where(sq.Or{
+			sq.Eq{"user_id": userID},
+			sq.Eq{"blocked_actor_id": userID},
+		})
+
+	if _, err := deleteActorBlocksQuery.Exec(); err != nil {
+		return err
+	}
+
+	deleteWatchersQuery := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "card_watchers").
+		Where(sq.Eq{"user_id": userID})
+
+	if _, err := deleteWatchersQuery.Exec(); err != nil {
+		return err
+	}
+
+	if _, err := s.deleteNotificationsForUser(db, userID); err != nil {
+		return err
+	}
+
+	anonymizeNotificationsQuery := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("actor_user_id", "").
+		Set("actor_name", "").
+		Set("update_at", now).
+		Where(sq.Eq{"actor_user_id": userID})
+
+	if _, err := anonymizeNotificationsQuery.Exec(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getUserDeletionSummary counts what deleteUser would remove or anonymize for a user,
+// without changing anything.
+func (s *SQLStore) getUserDeletionSummary(db sq.BaseRunner, userID string) (*model.UserDeletionSummary, error) {
+	if _, err := s.getUserByID(db, userID); err != nil {
+		return nil, err
+	}
+
+	summary := &model.UserDeletionSummary{UserID: userID}
+	counts := []struct {
+		count *int
+		table string
+		where sq.Sqlizer
+	}{
+		{&summary.BoardMemberships, "board_members", sq.Eq{"user_id": userID}},
+		{&summary.Notifications, "user_notifications", sq.Eq{"target_user_id": userID}},
+		{&summary.AuthoredNotifications, "user_notifications", sq.Eq{"actor_user_id": userID}},
+		{&summary.AuthoredComments, "blocks", sq.Eq{"created_by": userID, "type": model.TypeComment, "delete_at": 0}},
+	}
+
+	for _, c := range counts {
+		query := s.getQueryBuilder(db).
+			Select("COUNT(*)").
+			From(s.tablePrefix + c.table).
+			Where(c.where)
+
+		if err := query.QueryRow().Scan(c.count); err != nil {
+			s.logger.Error("Cannot count user data",
+				mlog.String("user_id", userID),
+				mlog.String("table", c.table),
+				mlog.Err(err),
+			)
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// deleteUserMemberships removes every board membership of a user, recording each removal
+// in the members history like deleteMember does.
+func (s *SQLStore) deleteUserMemberships(db sq.BaseRunner, userID string) error {
+	members, err := s.getMembersForUser(db, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.Synthetic {
+			continue
+		}
+		if err := s.deleteMember(db, member.BoardID, userID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -458,6 +640,39 @@ func (s *SQLStore) getUserPreferences(db sq.BaseRunner, userID string) (mmModel.
 	return preferences, nil
 }
 
+// getUsersPreferences returns the preferences of many users at once, by user ID. Users
+// without preferences are left out of the map.
+func (s *SQLStore) getUsersPreferences(db sq.BaseRunner, userIDs []string) (map[string]mmModel.Preferences, error) {
+	byUser := map[string]mmModel.Preferences{}
+	if len(userIDs) == 0 {
+		return byUser, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("userid", "category", "name", "value").
+		From(s.tablePrefix + "preferences").
+		Where(sq.Eq{
+			"userid":   userIDs,
+			"category": model.PreferencesCategoryFocalboard,
+		})
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error("failed to fetch users preferences", mlog.Int("user_count", len(userIDs)), mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	preferences, err := s.preferencesFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		byUser[preference.UserId] = append(byUser[preference.UserId], preference)
+	}
+	return byUser, nil
+}
+
 func (s *SQLStore) preferencesFromRows(rows *sql.Rows) ([]mmModel.Preference, error) {
 	preferences := []mmModel.Preference{}
 
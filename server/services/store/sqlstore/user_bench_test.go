@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package sqlstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkGetUsersPage compares selecting the last allowed page of users by number,
+// which skips the previous users with OFFSET, with seeking to it with a cursor.
+func BenchmarkGetUsersPage(b *testing.B) {
+	store, tearDown := setupTestStore(b)
+	defer tearDown()
+	sqlStore := store.(*SQLStore)
+
+	const perPage = 100
+	for i := 0; i < usersMaxOffset+perPage; i++ {
+		_, err := sqlStore.CreateUser(&model.User{
+			ID:       utils.NewID(utils.IDTypeUser),
+			Username: fmt.Sprintf("bench-user-%d", i),
+		})
+		require.NoError(b, err)
+	}
+
+	page := usersMaxOffset / perPage
+	users, _, err := sqlStore.GetUsersPage(model.QueryUsersOptions{Page: page - 1, PerPage: perPage})
+	require.NoError(b, err)
+	cursor := model.NewUsersCursor(users[len(users)-1])
+
+	b.Run("offset", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, err := sqlStore.GetUsersPage(model.QueryUsersOptions{Page: page, PerPage: perPage})
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("cursor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, err := sqlStore.GetUsersPage(model.QueryUsersOptions{Cursor: cursor, PerPage: perPage})
+			require.NoError(b, err)
+		}
+	})
+}
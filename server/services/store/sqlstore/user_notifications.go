@@ -5,6 +5,9 @@ package sqlstore
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/focalboard/server/model"
@@ -13,18 +16,99 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
-var userNotificationFields = []string{
-	"id",
-	"target_user_id",
-	"actor_user_id",
-	"actor_name",
-	"type",
-	"card_id",
-	"card_title",
-	"board_id",
-	"is_read",
-	"create_at",
-	"update_at",
+// userNotificationsBatchSize caps the number of rows inserted per statement when creating
+// notifications in bulk, keeping every database under its bind parameter limit. With one
+// parameter per column, the strictest limit is SQLite's default of 32766 and Postgres
+// allows up to 65535.
+const userNotificationsBatchSize = 100
+
+// markAsReadIDsBatchSize caps the number of IDs updated per statement when marking
+// preselected notifications as read.
+const markAsReadIDsBatchSize = 1000
+
+// userNotificationColumn is a column of the user_notifications table with the migration
+// that adds it.
+type userNotificationColumn struct {
+	name      string
+	migration string
+}
+
+// userNotificationColumns lists the columns of the user_notifications table, in the order
+// they are selected and scanned. With userNotificationFilterColumns it is the single
+// source of userNotificationFields and of the startup schema check.
+var userNotificationColumns = []userNotificationColumn{
+	{"id", "000041_create_user_notifications_table"},
+	{"target_user_id", "000041_create_user_notifications_table"},
+	{"actor_user_id", "000041_create_user_notifications_table"},
+	{"actor_name", "000041_create_user_notifications_table"},
+	{"type", "000041_create_user_notifications_table"},
+	{"card_id", "000041_create_user_notifications_table"},
+	{"card_title", "000041_create_user_notifications_table"},
+	{"board_id", "000041_create_user_notifications_table"},
+	{"is_read", "000041_create_user_notifications_table"},
+	{"is_archived", "000043_add_archived_to_user_notifications"},
+	{"is_pinned", "000052_add_pinned_to_user_notifications"},
+	{"is_silent", "000045_add_alert_hints_to_user_notifications"},
+	{"urgency", "000045_add_alert_hints_to_user_notifications"},
+	{"resolved_action", "000047_add_resolved_action_to_user_notifications"},
+	{"team_id", "000050_add_team_id_to_user_notifications"},
+	{"co_recipients", "000051_add_co_recipients_to_user_notifications"},
+	{"params", "000053_add_params_to_user_notifications"},
+	{"reason", "000058_add_reason_to_user_notifications"},
+	{"source", "000059_add_source_to_user_notifications"},
+	{"collapse_key", "000060_add_collapse_key_to_user_notifications"},
+	{"create_at", "000041_create_user_notifications_table"},
+	{"update_at", "000041_create_user_notifications_table"},
+}
+
+// userNotificationFilterColumns lists the columns of the user_notifications table that
+// queries filter on without selecting them.
+var userNotificationFilterColumns = []userNotificationColumn{
+	{"is_hidden", "000061_add_hidden_to_user_notifications"},
+}
+
+var userNotificationFields = func() []string {
+	fields := make([]string, len(userNotificationColumns))
+	for i, column := range userNotificationColumns {
+		fields[i] = column.name
+	}
+	return fields
+}()
+
+// checkUserNotificationColumns logs an error for every expected column missing from the
+// user_notifications table, naming the migration that adds it. A partially migrated
+// database would otherwise only fail later, with scan errors on every notification read.
+func (s *SQLStore) checkUserNotificationColumns() error {
+	rows, err := s.getQueryBuilder(s.db).
+		Select("*").
+		From(s.tablePrefix + "user_notifications").
+		Where("1 = 0").
+		Query()
+	if err != nil {
+		return err
+	}
+	defer s.CloseRows(rows)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]bool{}
+	for _, column := range columns {
+		existing[strings.ToLower(column)] = true
+	}
+
+	for _, column := range append(userNotificationColumns, userNotificationFilterColumns...) {
+		if !existing[column.name] {
+			s.logger.Error("The user_notifications table is missing a column, notifications can't be read until the migration adding it is run",
+				mlog.String("table", s.tablePrefix+"user_notifications"),
+				mlog.String("column", column.name),
+				mlog.String("migration", column.migration),
+			)
+		}
+	}
+	return nil
 }
 
 func (s *SQLStore) userNotificationFromRows(rows *sql.Rows) ([]*model.UserNotification, error) {
@@ -32,6 +116,7 @@ func (s *SQLStore) userNotificationFromRows(rows *sql.Rows) ([]*model.UserNotifi
 
 	for rows.Next() {
 		var notification model.UserNotification
+		var coRecipients, params sql.NullString
 		err := rows.Scan(
 			&notification.ID,
 			&notification.TargetUserID,
@@ -42,12 +127,35 @@ func (s *SQLStore) userNotificationFromRows(rows *sql.Rows) ([]*model.UserNotifi
 			&notification.CardTitle,
 			&notification.BoardID,
 			&notification.Read,
+			&notification.Archived,
+			&notification.Pinned,
+			&notification.Silent,
+			&notification.Urgency,
+			&notification.ResolvedAction,
+			&notification.TeamID,
+			&coRecipients,
+			&params,
+			&notification.Reason,
+			&notification.Source,
+			&notification.CollapseKey,
 			&notification.CreateAt,
 			&notification.UpdateAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if coRecipients.String != "" {
+			if err := json.Unmarshal([]byte(coRecipients.String), &notification.CoRecipients); err != nil {
+				return nil, err
+			}
+		}
+		if params.String != "" {
+			if err := json.Unmarshal([]byte(params.String), &notification.Params); err != nil {
+				return nil, err
+			}
+		}
+		notification.Category = model.NotificationCategoryForType(notification.Type)
+		notification.SetActions()
 		notifications = append(notifications, &notification)
 	}
 	return notifications, nil
@@ -58,22 +166,17 @@ func (s *SQLStore) createUserNotification(db sq.BaseRunner, notification *model.
 	notification.ID = utils.NewID(utils.IDTypeNone)
 	notification.CreateAt = now
 	notification.UpdateAt = now
+	notification.Category = model.NotificationCategoryForType(notification.Type)
+	notification.Silent, notification.Urgency = model.NotificationAlertForCategory(notification.Category)
+	notification.SetActions()
+
+	if err := s.fillNotificationTeams(db, []*model.UserNotification{notification}); err != nil {
+		return nil, err
+	}
 
-	query := s.getQueryBuilder(db).Insert(s.tablePrefix+"user_notifications").
+	query := s.getQueryBuilder(db).Insert(s.tablePrefix + "user_notifications").
 		Columns(userNotificationFields...).
-		Values(
-			notification.ID,
-			notification.TargetUserID,
-			notification.ActorUserID,
-			notification.ActorName,
-			notification.Type,
-			notification.CardID,
-			notification.CardTitle,
-			notification.BoardID,
-			notification.Read,
-			notification.CreateAt,
-			notification.UpdateAt,
-		)
+		Values(userNotificationValues(notification)...)
 
 	if _, err := query.Exec(); err != nil {
 		s.logger.Error("Cannot create user notification",
@@ -82,51 +185,385 @@ func (s *SQLStore) createUserNotification(db sq.BaseRunner, notification *model.
 		)
 		return nil, err
 	}
+
+	if err := s.createInAppDeliveries(db, []*model.UserNotification{notification}); err != nil {
+		return nil, err
+	}
 	return notification, nil
 }
 
-func (s *SQLStore) getUserNotifications(db sq.BaseRunner, userID string, limit int) ([]*model.UserNotification, error) {
+func (s *SQLStore) createUserNotifications(db sq.BaseRunner, notifications []*model.UserNotification) ([]*model.UserNotification, error) {
+	now := utils.GetMillis()
+
+	for start := 0; start < len(notifications); start += userNotificationsBatchSize {
+		end := min(start+userNotificationsBatchSize, len(notifications))
+
+		if err := s.fillNotificationTeams(db, notifications[start:end]); err != nil {
+			return nil, fmt.Errorf("cannot create user notifications %d to %d: %w", start, end-1, err)
+		}
+
+		query := s.getQueryBuilder(db).Insert(s.tablePrefix + "user_notifications").
+			Columns(userNotificationFields...)
+
+		for _, notification := range notifications[start:end] {
+			notification.ID = utils.NewID(utils.IDTypeNone)
+			notification.CreateAt = now
+			notification.UpdateAt = now
+			notification.Category = model.NotificationCategoryForType(notification.Type)
+			notification.Silent, notification.Urgency = model.NotificationAlertForCategory(notification.Category)
+			notification.SetActions()
+			query = query.Values(userNotificationValues(notification)...)
+		}
+
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("Cannot create user notifications",
+				mlog.Int("first", start),
+				mlog.Int("count", end-start),
+				mlog.Err(err),
+			)
+			return nil, fmt.Errorf("cannot create user notifications %d to %d: %w", start, end-1, err)
+		}
+
+		if err := s.createInAppDeliveries(db, notifications[start:end]); err != nil {
+			return nil, fmt.Errorf("cannot create deliveries of user notifications %d to %d: %w", start, end-1, err)
+		}
+	}
+	return notifications, nil
+}
+
+// fillNotificationTeams sets the team of the notifications to the team of their board,
+// whatever team they were created with. Notifications without a board, or whose board
+// doesn't exist, are left without team.
+func (s *SQLStore) fillNotificationTeams(db sq.BaseRunner, notifications []*model.UserNotification) error {
+	boardIDs := []string{}
+	seen := map[string]bool{}
+	for _, notification := range notifications {
+		notification.TeamID = ""
+		if notification.BoardID != "" && !seen[notification.BoardID] {
+			seen[notification.BoardID] = true
+			boardIDs = append(boardIDs, notification.BoardID)
+		}
+	}
+	if len(boardIDs) == 0 {
+		return nil
+	}
+
+	rows, err := s.getQueryBuilder(db).
+		Select("id", "team_id").
+		From(s.tablePrefix + "boards").
+		Where(sq.Eq{"id": boardIDs}).
+		Query()
+	if err != nil {
+		return err
+	}
+	defer s.CloseRows(rows)
+
+	teamIDs := map[string]string{}
+	for rows.Next() {
+		var boardID, teamID string
+		if err := rows.Scan(&boardID, &teamID); err != nil {
+			return err
+		}
+		teamIDs[boardID] = teamID
+	}
+
+	for _, notification := range notifications {
+		notification.TeamID = teamIDs[notification.BoardID]
+	}
+	return nil
+}
+
+func userNotificationValues(notification *model.UserNotification) []interface{} {
+	return []interface{}{
+		notification.ID,
+		notification.TargetUserID,
+		notification.ActorUserID,
+		notification.ActorName,
+		notification.Type,
+		notification.CardID,
+		notification.CardTitle,
+		notification.BoardID,
+		notification.Read,
+		notification.Archived,
+		notification.Pinned,
+		notification.Silent,
+		notification.Urgency,
+		notification.ResolvedAction,
+		notification.TeamID,
+		coRecipientsValue(notification.CoRecipients),
+		notificationParamsValue(notification.Params),
+		notification.Reason,
+		notification.Source,
+		notification.CollapseKey,
+		notification.CreateAt,
+		notification.UpdateAt,
+	}
+}
+
+// coRecipientsValue encodes the co-recipients of a notification as JSON, or NULL if it has
+// none.
+func coRecipientsValue(coRecipients []string) interface{} {
+	if len(coRecipients) == 0 {
+		return nil
+	}
+	// a list of strings always encodes
+	data, _ := json.Marshal(coRecipients)
+	return string(data)
+}
+
+// notificationParamsValue encodes the message params of a notification as JSON, or NULL
+// if it has none.
+func notificationParamsValue(params map[string]string) interface{} {
+	if len(params) == 0 {
+		return nil
+	}
+	// a map of strings always encodes
+	data, _ := json.Marshal(params)
+	return string(data)
+}
+
+func (s *SQLStore) getUserNotifications(db sq.BaseRunner, userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
 	query := s.getQueryBuilder(db).
 		Select(userNotificationFields...).
 		From(s.tablePrefix + "user_notifications").
-		Where(sq.Eq{"target_user_id": userID}).
-		OrderBy("create_at DESC")
+		Where(sq.Eq{"target_user_id": userID, "is_hidden": false})
 
-	if limit > 0 {
-		query = query.Limit(uint64(limit))
+	sortColumn := "create_at"
+	if opts.OrderBy == model.NotificationOrderByUpdateAt {
+		sortColumn = "update_at"
+	}
+	direction := " DESC"
+	if opts.Ascending {
+		direction = " ASC"
+	}
+	// the ID breaks ties, so pages listed with a cursor neither skip nor repeat notifications
+	orderBy, idOrderBy := sortColumn+direction, "id"+direction
+
+	pinnedFirst := opts.CardID == ""
+	if pinnedFirst {
+		query = query.OrderBy("is_pinned DESC", orderBy, idOrderBy)
+	} else {
+		query = query.OrderBy(orderBy, idOrderBy)
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := model.ParseNotificationsCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(notificationCursorCondition(cursor, sortColumn, opts.Ascending, pinnedFirst))
+	}
+
+	query = filterUserNotifications(query, opts)
+
+	if opts.Limit > 0 {
+		query = query.Limit(uint64(opts.Limit))
 	}
 
 	rows, err := query.Query()
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer s.CloseRows(rows)
 
 	return s.userNotificationFromRows(rows)
 }
 
-func (s *SQLStore) getUnreadNotificationCount(db sq.BaseRunner, userID string) (int, error) {
+// filterUserNotifications restricts a notification query to the notifications selected by
+// the filters of opts.
+func filterUserNotifications(query sq.SelectBuilder, opts model.QueryUserNotificationsOptions) sq.SelectBuilder {
+	if !opts.IncludeArchived {
+		query = query.Where(sq.Eq{"is_archived": false})
+	}
+
+	if opts.Category != "" {
+		query = query.Where(notificationCategoryFilter(opts.Category))
+	}
+
+	if opts.Since > 0 {
+		query = query.Where(sq.Gt{"create_at": opts.Since})
+	}
+
+	if opts.Before > 0 {
+		query = query.Where(sq.Lt{"create_at": opts.Before})
+	}
+
+	if filter := opts.Filter(); !filter.IsEmpty() {
+		query = query.Where(notificationFilterCondition(filter))
+	}
+
+	if opts.Query != nil {
+		query = query.Where(notificationQueryCondition(*opts.Query))
+	}
+
+	if opts.UnresolvedOnly {
+		query = query.Where(sq.Eq{
+			"type":            model.ActionableNotificationTypes(),
+			"resolved_action": "",
+		})
+	}
+	return query
+}
+
+// notificationThreadKey is the thread of a notification: its card, or the notification
+// itself if it is not about a card.
+const notificationThreadKey = "COALESCE(NULLIF(card_id, ''), id)"
+
+// getUserNotificationThreads returns the notifications selected by opts of the limit
+// threads with the latest notifications, newest first. The threads are grouped and
+// limited in the database, so only the notifications of the returned threads are read.
+func (s *SQLStore) getUserNotificationThreads(db sq.BaseRunner, userID string, opts model.QueryUserNotificationsOptions, limit int) ([]*model.UserNotification, error) {
+	keysQuery := s.getQueryBuilder(db).
+		Select(notificationThreadKey).
+		From(s.tablePrefix+"user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_hidden": false}).
+		GroupBy(notificationThreadKey).
+		OrderBy("MAX(create_at) DESC", notificationThreadKey+" DESC")
+	keysQuery = filterUserNotifications(keysQuery, opts)
+
+	if limit > 0 {
+		keysQuery = keysQuery.Limit(uint64(limit))
+	}
+
+	rows, err := keysQuery.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	keys, err := idsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []*model.UserNotification{}, nil
+	}
+
 	query := s.getQueryBuilder(db).
-		Select("COUNT(*)").
+		Select(userNotificationFields...).
+		From(s.tablePrefix+"user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_hidden": false}).
+		Where(sq.Or{
+			sq.Eq{"card_id": keys},
+			sq.And{sq.Eq{"card_id": ""}, sq.Eq{"id": keys}},
+		}).
+		OrderBy("create_at DESC", "id DESC")
+	query = filterUserNotifications(query, opts)
+
+	notificationRows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(notificationRows)
+
+	return s.userNotificationFromRows(notificationRows)
+}
+
+// notificationCursorCondition selects the notifications listed after the one of a cursor,
+// in the order of getUserNotifications. Pinned notifications come first if pinnedFirst is
+// set, whatever the direction.
+func notificationCursorCondition(cursor model.NotificationsCursor, sortColumn string, ascending, pinnedFirst bool) sq.Sqlizer {
+	var after sq.Sqlizer = sq.Or{
+		sq.Lt{sortColumn: cursor.SortValue},
+		sq.And{sq.Eq{sortColumn: cursor.SortValue}, sq.Lt{"id": cursor.ID}},
+	}
+	if ascending {
+		after = sq.Or{
+			sq.Gt{sortColumn: cursor.SortValue},
+			sq.And{sq.Eq{sortColumn: cursor.SortValue}, sq.Gt{"id": cursor.ID}},
+		}
+	}
+
+	switch {
+	case !pinnedFirst:
+		return after
+	case cursor.Pinned:
+		return sq.Or{sq.Eq{"is_pinned": false}, sq.And{sq.Eq{"is_pinned": true}, after}}
+	default:
+		return sq.And{sq.Eq{"is_pinned": false}, after}
+	}
+}
+
+func (s *SQLStore) getUserNotification(db sq.BaseRunner, notificationID, userID string) (*model.UserNotification, error) {
+	return s.getUserNotificationByCondition(db, notificationID, sq.Eq{"id": notificationID, "target_user_id": userID})
+}
+
+func (s *SQLStore) getUserNotificationByID(db sq.BaseRunner, notificationID string) (*model.UserNotification, error) {
+	return s.getUserNotificationByCondition(db, notificationID, sq.Eq{"id": notificationID})
+}
+
+func (s *SQLStore) getUserNotificationByCondition(db sq.BaseRunner, notificationID string, condition sq.Eq) (*model.UserNotification, error) {
+	query := s.getQueryBuilder(db).
+		Select(userNotificationFields...).
 		From(s.tablePrefix + "user_notifications").
-		Where(sq.Eq{"target_user_id": userID, "is_read": false})
+		Where(condition)
 
-	row := query.QueryRow()
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
 
-	var count int
-	if err := row.Scan(&count); err != nil {
-		return 0, err
+	notifications, err := s.userNotificationFromRows(rows)
+	if err != nil {
+		return nil, err
 	}
-	return count, nil
+	if len(notifications) == 0 {
+		return nil, model.NewErrNotFound("notification ID=" + notificationID)
+	}
+	return notifications[0], nil
 }
 
-func (s *SQLStore) markNotificationAsRead(db sq.BaseRunner, notificationID, userID string) error {
-	now := utils.GetMillis()
+// getUnreadNotificationByCollapseKey returns the unread notification of a user with the
+// given collapse key, the one a new notification with the key replaces. Only a
+// notification from the same actor and source is returned, so that a sender can't
+// overwrite the notifications of another one by reusing their key.
+func (s *SQLStore) getUnreadNotificationByCollapseKey(db sq.BaseRunner, userID, collapseKey, actorUserID, source string) (*model.UserNotification, error) {
+	query := s.getQueryBuilder(db).
+		Select(userNotificationFields...).
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{
+			"target_user_id": userID,
+			"collapse_key":   collapseKey,
+			"actor_user_id":  actorUserID,
+			"source":         source,
+			"is_read":        false,
+			"is_archived":    false,
+			"is_hidden":      false,
+		}).
+		OrderBy("update_at DESC").
+		Limit(1)
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetUnreadNotificationByCollapseKey ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	notifications, err := s.userNotificationFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(notifications) == 0 {
+		return nil, model.NewErrNotFound("notification collapseKey=" + collapseKey)
+	}
+	return notifications[0], nil
+}
+
+// updateUserNotification saves the content of a notification and its actor, which are the
+// only parts of it that can change after it was created.
+func (s *SQLStore) updateUserNotification(db sq.BaseRunner, notification *model.UserNotification) error {
+	notification.UpdateAt = utils.GetMillis()
+
 	query := s.getQueryBuilder(db).
 		Update(s.tablePrefix+"user_notifications").
-		Set("is_read", true).
-		Set("update_at", now).
-		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+		Set("card_title", notification.CardTitle).
+		Set("actor_user_id", notification.ActorUserID).
+		Set("actor_name", notification.ActorName).
+		Set("params", notificationParamsValue(notification.Params)).
+		Set("update_at", notification.UpdateAt).
+		Where(sq.Eq{"id": notification.ID})
 
 	result, err := query.Exec()
 	if err != nil {
@@ -137,34 +574,804 @@ func (s *SQLStore) markNotificationAsRead(db sq.BaseRunner, notificationID, user
 	if err != nil {
 		return err
 	}
-
 	if count == 0 {
-		s.logger.Warn("notification not found or already read",
-			mlog.String("notification_id", notificationID),
-			mlog.String("user_id", userID),
-		)
+		return model.NewErrNotFound("notification ID=" + notification.ID)
 	}
-
 	return nil
 }
 
-func (s *SQLStore) markAllNotificationsAsRead(db sq.BaseRunner, userID string) error {
-	now := utils.GetMillis()
+// reassignNotificationsBoard moves the notifications of a board to another board of the
+// given team, returning how many were moved. An empty team leaves their team unchanged.
+func (s *SQLStore) reassignNotificationsBoard(db sq.BaseRunner, fromBoardID, toBoardID, toTeamID string) (int64, error) {
 	query := s.getQueryBuilder(db).
 		Update(s.tablePrefix+"user_notifications").
-		Set("is_read", true).
-		Set("update_at", now).
-		Where(sq.Eq{"target_user_id": userID, "is_read": false})
+		Set("board_id", toBoardID).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"board_id": fromBoardID})
+	if toTeamID != "" {
+		query = query.Set("team_id", toTeamID)
+	}
 
-	_, err := query.Exec()
-	return err
+	result, err := query.Exec()
+	if err != nil {
+		s.logger.Error("Cannot reassign the notifications of a board",
+			mlog.String("fromBoardID", fromBoardID),
+			mlog.String("toBoardID", toBoardID),
+			mlog.Err(err),
+		)
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-func (s *SQLStore) deleteUserNotification(db sq.BaseRunner, notificationID, userID string) error {
+func (s *SQLStore) getUnreadNotificationCount(db sq.BaseRunner, userID string) (int, error) {
 	query := s.getQueryBuilder(db).
-		Delete(s.tablePrefix + "user_notifications").
-		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+		Select("COUNT(*)").
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_read": false, "is_archived": false, "is_hidden": false})
+
+	row := query.QueryRow()
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// getUnreadNotificationCountByType counts the unread notifications of a user per type.
+// Types without unread notifications are left out.
+func (s *SQLStore) getUnreadNotificationCountByType(db sq.BaseRunner, userID string) (map[string]int, error) {
+	query := s.getQueryBuilder(db).
+		Select("type", "COUNT(*)").
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_read": false, "is_archived": false, "is_hidden": false}).
+		GroupBy("type")
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var notifType string
+		var count int
+		if err := rows.Scan(&notifType, &count); err != nil {
+			return nil, err
+		}
+		counts[notifType] = count
+	}
+	return counts, nil
+}
+
+// getOldestUnreadNotificationTime returns the create time of the oldest unread
+// notification of the user, or 0 if none is unread. Archived notifications are left out
+// like in the unread count.
+func (s *SQLStore) getOldestUnreadNotificationTime(db sq.BaseRunner, userID string) (int64, error) {
+	query := s.getQueryBuilder(db).
+		Select("MIN(create_at)").
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_read": false, "is_archived": false, "is_hidden": false})
+
+	var oldest sql.NullInt64
+	if err := query.QueryRow().Scan(&oldest); err != nil {
+		return 0, err
+	}
+	return oldest.Int64, nil
+}
+
+// getUnreadCountByBoardForMember counts the unread notifications of a user per board,
+// only for the existing boards the user is still a member of, so the notifications of
+// boards they left or that were deleted are not counted.
+func (s *SQLStore) getUnreadCountByBoardForMember(db sq.BaseRunner, userID string) ([]*model.NotificationBoardUnreadCount, error) {
+	query := s.getQueryBuilder(db).
+		Select("n.board_id", "COUNT(*)").
+		From(s.tablePrefix + "user_notifications AS n").
+		InnerJoin(s.tablePrefix + "board_members AS bm ON bm.board_id = n.board_id AND bm.user_id = n.target_user_id").
+		InnerJoin(s.tablePrefix + "boards AS b ON b.id = n.board_id").
+		Where(sq.Eq{"n.target_user_id": userID, "n.is_read": false, "n.is_archived": false}).
+		GroupBy("n.board_id").
+		OrderBy("n.board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`GetUnreadCountByBoardForMember ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	counts := []*model.NotificationBoardUnreadCount{}
+	for rows.Next() {
+		var count model.NotificationBoardUnreadCount
+		if err := rows.Scan(&count.BoardID, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+	return counts, nil
+}
+
+// getUnreadCountByBoard returns every board the user has notifications of, member or not,
+// with its unread notification count, zero if they were all read.
+func (s *SQLStore) getUnreadCountByBoard(db sq.BaseRunner, userID string) ([]*model.NotificationBoardUnreadCount, error) {
+	query := s.getQueryBuilder(db).
+		Select("board_id", "COALESCE(SUM(CASE WHEN is_read OR is_archived THEN 0 ELSE 1 END), 0)").
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_hidden": false}).
+		Where(sq.NotEq{"board_id": ""}).
+		GroupBy("board_id").
+		OrderBy("board_id")
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	counts := []*model.NotificationBoardUnreadCount{}
+	for rows.Next() {
+		var count model.NotificationBoardUnreadCount
+		if err := rows.Scan(&count.BoardID, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+	return counts, nil
+}
+
+// getNotificationSummary counts the notifications of a user by read state in a single
+// query. The boolean column is used as a condition directly, which every supported
+// database accepts.
+func (s *SQLStore) getNotificationSummary(db sq.BaseRunner, userID string) (*model.NotificationSummary, error) {
+	query := s.getQueryBuilder(db).
+		Select(
+			"COUNT(*)",
+			"COALESCE(SUM(CASE WHEN is_read THEN 1 ELSE 0 END), 0)",
+		).
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID, "is_archived": false, "is_hidden": false})
 
-	_, err := query.Exec()
-	return err
+	summary := &model.NotificationSummary{}
+	if err := query.QueryRow().Scan(&summary.Total, &summary.Read); err != nil {
+		return nil, err
+	}
+	summary.Unread = summary.Total - summary.Read
+	return summary, nil
+}
+
+func (s *SQLStore) countNotificationsByActor(db sq.BaseRunner, since int64, limit int) ([]*model.NotificationActorCount, error) {
+	query := s.getQueryBuilder(db).
+		Select("actor_user_id", "MAX(actor_name)", "COUNT(*) AS count").
+		From(s.tablePrefix+"user_notifications").
+		Where(sq.GtOrEq{"create_at": since}).
+		GroupBy("actor_user_id").
+		OrderBy("count DESC", "actor_user_id").
+		Limit(uint64(limit))
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`CountNotificationsByActor ERROR`, mlog.Err(err))
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	counts := []*model.NotificationActorCount{}
+	for rows.Next() {
+		var count model.NotificationActorCount
+		if err := rows.Scan(&count.ActorUserID, &count.ActorName, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, &count)
+	}
+	return counts, nil
+}
+
+func (s *SQLStore) searchUserNotifications(db sq.BaseRunner, opts model.SearchUserNotificationsOptions) ([]*model.UserNotification, bool, error) {
+	query := s.getQueryBuilder(db).
+		Select(userNotificationFields...).
+		From(s.tablePrefix+"user_notifications").
+		OrderBy("create_at DESC", "id DESC")
+
+	if opts.UserID != "" {
+		query = query.Where(sq.Eq{"target_user_id": opts.UserID})
+	}
+
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"board_id": opts.BoardID})
+	}
+
+	if opts.Type != "" {
+		query = query.Where(sq.Eq{"type": opts.Type})
+	}
+
+	if opts.Source != "" {
+		query = query.Where(sq.Eq{"source": opts.Source})
+	}
+
+	if opts.Page != 0 {
+		query = query.Offset(uint64(opts.Page * opts.PerPage))
+	}
+
+	if opts.PerPage > 0 {
+		// N+1 to check if there's a next page for pagination
+		query = query.Limit(uint64(opts.PerPage) + 1)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		s.logger.Error(`SearchUserNotifications ERROR`, mlog.Err(err))
+		return nil, false, err
+	}
+	defer s.CloseRows(rows)
+
+	notifications, err := s.userNotificationFromRows(rows)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var hasMore bool
+	if opts.PerPage > 0 && len(notifications) > opts.PerPage {
+		notifications = notifications[0:opts.PerPage]
+		hasMore = true
+	}
+	return notifications, hasMore, nil
+}
+
+func (s *SQLStore) markNotificationAsRead(db sq.BaseRunner, notificationID, userID string) error {
+	now := utils.GetMillis()
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_read", true).
+		Set("update_at", now).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		s.logger.Warn("notification not found or already read",
+			mlog.String("notification_id", notificationID),
+			mlog.String("user_id", userID),
+		)
+	}
+
+	return nil
+}
+
+func (s *SQLStore) markNotificationAsUnread(db sq.BaseRunner, notificationID, userID string) error {
+	now := utils.GetMillis()
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_read", false).
+		Set("update_at", now).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		s.logger.Warn("notification not found or already unread",
+			mlog.String("notification_id", notificationID),
+			mlog.String("user_id", userID),
+		)
+	}
+
+	return nil
+}
+
+// markNotificationsAsRead marks the unread notifications of a user among the given IDs as
+// read in a single update and returns how many were marked.
+func (s *SQLStore) markNotificationsAsRead(db sq.BaseRunner, ids []string, userID string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_read", true).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": ids, "target_user_id": userID, "is_read": false})
+
+	result, err := query.Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) markAllNotificationsAsRead(db sq.BaseRunner, userID string, opts model.MarkNotificationsAsReadOptions) (int64, error) {
+	now := utils.GetMillis()
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_read", true).
+		Set("update_at", now).
+		Where(sq.Eq{"target_user_id": userID, "is_read": false, "is_archived": false, "is_hidden": false})
+
+	if opts.BoardID != "" {
+		query = query.Where(sq.Eq{"board_id": opts.BoardID})
+	}
+
+	if opts.Type != "" {
+		query = query.Where(sq.Eq{"type": opts.Type})
+	}
+
+	if opts.CardID != "" {
+		query = query.Where(sq.Eq{"card_id": opts.CardID})
+	}
+
+	result, err := query.Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// markNotificationsAsReadReturningIDs marks the unread notifications of a user matching
+// the options as read and returns their IDs. Postgres returns them from the update itself
+// with RETURNING. MySQL and SQLite select them first, locking the rows on MySQL, then
+// update them by ID, so the update costs an extra query and the IDs are held in memory.
+func (s *SQLStore) markNotificationsAsReadReturningIDs(db sq.BaseRunner, userID string, opts model.MarkNotificationsAsReadOptions) ([]string, error) {
+	where := sq.Eq{"target_user_id": userID, "is_read": false, "is_archived": false, "is_hidden": false}
+	if opts.BoardID != "" {
+		where["board_id"] = opts.BoardID
+	}
+	if opts.Type != "" {
+		where["type"] = opts.Type
+	}
+	if opts.CardID != "" {
+		where["card_id"] = opts.CardID
+	}
+	now := utils.GetMillis()
+
+	if s.dbType == model.PostgresDBType {
+		rows, err := s.getQueryBuilder(db).
+			Update(s.tablePrefix+"user_notifications").
+			Set("is_read", true).
+			Set("update_at", now).
+			Where(where).
+			Suffix("RETURNING id").
+			Query()
+		if err != nil {
+			return nil, err
+		}
+		defer s.CloseRows(rows)
+		return idsFromRows(rows)
+	}
+
+	query := s.getQueryBuilder(db).
+		Select("id").
+		From(s.tablePrefix + "user_notifications").
+		Where(where)
+	if s.dbType == model.MysqlDBType {
+		query = query.Suffix("FOR UPDATE")
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := idsFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(ids); start += markAsReadIDsBatchSize {
+		end := min(start+markAsReadIDsBatchSize, len(ids))
+		_, err := s.getQueryBuilder(db).
+			Update(s.tablePrefix+"user_notifications").
+			Set("is_read", true).
+			Set("update_at", now).
+			Where(sq.Eq{"id": ids[start:end], "target_user_id": userID, "is_read": false}).
+			Exec()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// getUserNotificationsMarkingRead returns the notifications of a user selected by opts and
+// marks the unread ones among them as read, leaving the notifications outside of the
+// selected page alone. The notifications are returned as they were before, so clients can
+// still tell which ones were unread.
+func (s *SQLStore) getUserNotificationsMarkingRead(db sq.BaseRunner, userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error) {
+	notifications, err := s.getUserNotifications(db, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, notification := range notifications {
+		if !notification.Read {
+			ids = append(ids, notification.ID)
+		}
+	}
+
+	now := utils.GetMillis()
+	for start := 0; start < len(ids); start += markAsReadIDsBatchSize {
+		end := min(start+markAsReadIDsBatchSize, len(ids))
+		_, err := s.getQueryBuilder(db).
+			Update(s.tablePrefix+"user_notifications").
+			Set("is_read", true).
+			Set("update_at", now).
+			Where(sq.Eq{"id": ids[start:end], "target_user_id": userID}).
+			Exec()
+		if err != nil {
+			s.logger.Error("Cannot mark fetched notifications as read",
+				mlog.String("user_id", userID),
+				mlog.Int("count", len(ids)),
+				mlog.Err(err),
+			)
+			return nil, err
+		}
+	}
+	return notifications, nil
+}
+
+// syncNotificationReadStates applies read state changes made offline. A change only wins
+// if it was made after the last update of the notification, and changes to notifications
+// the user doesn't own are skipped. Changes dated in the future count as made now, so a
+// wrong client clock can't hold off later updates. It returns the current state of the
+// notifications the user owns.
+func (s *SQLStore) syncNotificationReadStates(db sq.BaseRunner, userID string, changes []*model.NotificationReadSync) ([]*model.UserNotification, error) {
+	if len(changes) == 0 {
+		return []*model.UserNotification{}, nil
+	}
+
+	now := utils.GetMillis()
+	ids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		readAt := change.ReadAt
+		if readAt > now {
+			readAt = now
+		}
+
+		query := s.getQueryBuilder(db).
+			Update(s.tablePrefix+"user_notifications").
+			Set("is_read", change.Read).
+			Set("update_at", readAt).
+			Where(sq.Eq{"id": change.ID, "target_user_id": userID}).
+			Where(sq.Lt{"update_at": readAt})
+
+		if _, err := query.Exec(); err != nil {
+			s.logger.Error("Cannot sync notification read state",
+				mlog.String("notification_id", change.ID),
+				mlog.Err(err),
+			)
+			return nil, err
+		}
+		ids = append(ids, change.ID)
+	}
+
+	rows, err := s.getQueryBuilder(db).
+		Select(userNotificationFields...).
+		From(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"id": ids, "target_user_id": userID}).
+		OrderBy("create_at DESC").
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer s.CloseRows(rows)
+
+	return s.userNotificationFromRows(rows)
+}
+
+func (s *SQLStore) setNotificationArchived(db sq.BaseRunner, notificationID, userID string, archived bool) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_archived", archived).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.NewErrNotFound("notification ID=" + notificationID)
+	}
+	return nil
+}
+
+func (s *SQLStore) setNotificationPinned(db sq.BaseRunner, notificationID, userID string, pinned bool) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_pinned", pinned).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.NewErrNotFound("notification ID=" + notificationID)
+	}
+	return nil
+}
+
+func (s *SQLStore) resolveUserNotification(db sq.BaseRunner, notificationID, userID, action string) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("resolved_action", action).
+		Set("is_read", true).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID, "resolved_action": ""})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.NewErrNotFound("unresolved notification ID=" + notificationID)
+	}
+	return nil
+}
+
+// unresolveUserNotification undoes resolveUserNotification with action, restoring the
+// read state the notification had, e.g. when the action failed.
+func (s *SQLStore) unresolveUserNotification(db sq.BaseRunner, notificationID, userID, action string, read bool) error {
+	query := s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("resolved_action", "").
+		Set("is_read", read).
+		Set("update_at", utils.GetMillis()).
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID, "resolved_action": action})
+
+	if _, err := query.Exec(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SQLStore) deleteUserNotification(db sq.BaseRunner, notificationID, userID string) error {
+	query := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"id": notificationID, "target_user_id": userID})
+
+	result, err := query.Exec()
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return s.deleteNotificationDeliveries(db, sq.Eq{"notification_id": notificationID})
+}
+
+// deleteUserNotificationsBefore deletes the notifications selected by opts and their
+// deliveries, in batches of batchSize so each delete only holds its locks briefly. Returns
+// the number of deleted notifications.
+func (s *SQLStore) deleteUserNotificationsBefore(db sq.BaseRunner, opts model.PurgeUserNotificationsOptions, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		query := s.getQueryBuilder(db).
+			Select("id").
+			From(s.tablePrefix + "user_notifications").
+			Where(sq.Lt{"create_at": opts.Before}).
+			Where(sq.Eq{"is_pinned": false}).
+			Limit(uint64(batchSize))
+		if len(opts.Types) > 0 {
+			query = query.Where(sq.Eq{"type": opts.Types})
+		}
+		if len(opts.ExcludeTypes) > 0 {
+			query = query.Where(sq.NotEq{"type": opts.ExcludeTypes})
+		}
+
+		rows, err := query.Query()
+		if err != nil {
+			return deleted, err
+		}
+		ids, err := idsFromRows(rows)
+		s.CloseRows(rows)
+		if err != nil {
+			return deleted, err
+		}
+
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		result, err := s.getQueryBuilder(db).
+			Delete(s.tablePrefix + "user_notifications").
+			Where(sq.Eq{"id": ids}).
+			Exec()
+		if err != nil {
+			s.logger.Error("Cannot delete user notifications",
+				mlog.Int("count", len(ids)),
+				mlog.Err(err),
+			)
+			return deleted, err
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += count
+
+		if err := s.deleteNotificationDeliveries(db, sq.Eq{"notification_id": ids}); err != nil {
+			return deleted, err
+		}
+
+		if len(ids) < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// setNotificationsHiddenForBoard hides the notifications of a board while it is deleted,
+// or shows them again once it is undeleted. Hidden notifications are left out of the
+// lists and counts of their targets but kept, so that undeleting the board restores them.
+// Returns the IDs of the users whose notifications changed.
+func (s *SQLStore) setNotificationsHiddenForBoard(db sq.BaseRunner, boardID string, hidden bool) ([]string, error) {
+	condition := sq.And{sq.Eq{"board_id": boardID}, sq.NotEq{"is_hidden": hidden}}
+
+	rows, err := s.getQueryBuilder(db).
+		Select("target_user_id").
+		Distinct().
+		From(s.tablePrefix + "user_notifications").
+		Where(condition).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	userIDs, err := idsFromRows(rows)
+	s.CloseRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Update(s.tablePrefix+"user_notifications").
+		Set("is_hidden", hidden).
+		Where(condition).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot change the visibility of the notifications of a board",
+			mlog.String("boardID", boardID),
+			mlog.Bool("hidden", hidden),
+			mlog.Err(err),
+		)
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// deleteNotificationsForUser deletes the notifications a user received, with their
+// deliveries, escalations and pending digests. Returns how many notifications were deleted.
+func (s *SQLStore) deleteNotificationsForUser(db sq.BaseRunner, userID string) (int64, error) {
+	err := s.deleteNotificationDeliveries(db, sq.Expr("notification_id IN (SELECT id FROM "+s.tablePrefix+"user_notifications WHERE target_user_id = ?)", userID))
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "notification_board_digests").
+		Where(sq.Eq{"user_id": userID}).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.getQueryBuilder(db).
+		Delete(s.tablePrefix + "user_notifications").
+		Where(sq.Eq{"target_user_id": userID}).
+		Exec()
+	if err != nil {
+		s.logger.Error("Cannot delete the notifications of a user",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// notificationFilterCondition returns the condition matching the notifications that match
+// every field of the filter that is set.
+func notificationFilterCondition(filter model.NotificationFilter) sq.And {
+	condition := sq.And{}
+	if len(filter.Types) > 0 {
+		condition = append(condition, sq.Eq{"type": filter.Types})
+	}
+	if len(filter.BoardIDs) > 0 {
+		condition = append(condition, sq.Eq{"board_id": filter.BoardIDs})
+	}
+	if len(filter.ExcludeBoardIDs) > 0 {
+		condition = append(condition, sq.NotEq{"board_id": filter.ExcludeBoardIDs})
+	}
+	if len(filter.CardIDs) > 0 {
+		condition = append(condition, sq.Eq{"card_id": filter.CardIDs})
+	}
+	if filter.TeamID != "" {
+		condition = append(condition, sq.Eq{"team_id": filter.TeamID})
+	}
+	if len(filter.ActorUserIDs) > 0 {
+		condition = append(condition, sq.Eq{"actor_user_id": filter.ActorUserIDs})
+	}
+	if filter.Read != nil {
+		condition = append(condition, sq.Eq{"is_read": *filter.Read})
+	}
+
+	switch {
+	case filter.From > 0 && filter.To > 0:
+		condition = append(condition, sq.Expr("create_at BETWEEN ? AND ?", filter.From, filter.To))
+	case filter.From > 0:
+		condition = append(condition, sq.GtOrEq{"create_at": filter.From})
+	case filter.To > 0:
+		condition = append(condition, sq.LtOrEq{"create_at": filter.To})
+	}
+	return condition
+}
+
+// notificationQueryCondition returns the condition matching the notifications that match
+// the filter of the query and at least one of its alternative filters, if it has any.
+func notificationQueryCondition(query model.NotificationQuery) sq.Sqlizer {
+	condition := notificationFilterCondition(query.NotificationFilter)
+	if len(query.AnyOf) > 0 {
+		anyOf := sq.Or{}
+		for _, filter := range query.AnyOf {
+			anyOf = append(anyOf, notificationFilterCondition(filter))
+		}
+		condition = append(condition, anyOf)
+	}
+	return condition
+}
+
+// notificationCategoryFilter returns the condition matching the notification types of a
+// category. The system category collects every type not claimed by another category.
+func notificationCategoryFilter(category string) sq.Sqlizer {
+	if category == model.NotificationCategorySystem {
+		return sq.NotEq{"type": model.CategorizedNotificationTypes()}
+	}
+	return sq.Eq{"type": model.NotificationTypesForCategory(category)}
 }
@@ -68,8 +68,12 @@ type Store interface {
 	SearchUsersByTeam(teamID string, searchQuery string, asGuestID string, excludeBots bool, showEmail, showName bool) ([]*model.User, error)
 	PatchUserPreferences(userID string, patch model.UserPreferencesPatch) (mmModel.Preferences, error)
 	GetUserPreferences(userID string) (mmModel.Preferences, error)
+	GetUsersPreferences(userIDs []string) (map[string]mmModel.Preferences, error)
 	GetAllUsers() ([]*model.User, error)
+	GetUsersPage(opts model.QueryUsersOptions) ([]*model.User, bool, error)
+	// @withTransaction
 	DeleteUser(userID string) error
+	GetUserDeletionSummary(userID string) (*model.UserDeletionSummary, error)
 
 	GetActiveUserCount(updatedSecondsAgo int64) (int, error)
 	GetSession(token string, expireTime int64) (*model.Session, error)
@@ -97,6 +101,7 @@ type Store interface {
 	GetBoard(id string) (*model.Board, error)
 	GetBoardsForUserAndTeam(userID, teamID string, includePublicBoards bool) ([]*model.Board, error)
 	GetBoardsInTeamByIds(boardIDs []string, teamID string) ([]*model.Board, error)
+	GetBoardsByIDs(boardIDs []string) ([]*model.Board, error)
 	// @withTransaction
 	DeleteBoard(boardID, userID string) error
 
@@ -106,6 +111,7 @@ type Store interface {
 	GetBoardMemberHistory(boardID, userID string, limit uint64) ([]*model.BoardMemberHistoryEntry, error)
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
 	GetMembersForUser(userID string) ([]*model.BoardMember, error)
+	GetBoardMembershipsForUser(userID string, page, perPage int) ([]*model.UserBoardMembership, bool, error)
 	CanSeeUser(seerID string, seenID string) (bool, error)
 	SearchBoardsForUser(term string, searchField model.BoardSearchField, userID string, includePublicBoards bool) ([]*model.Board, error)
 	SearchBoardsForUserInTeam(teamID, term, userID string) ([]*model.Board, error)
@@ -152,12 +158,98 @@ type Store interface {
 	GetNextNotificationHint(remove bool) (*model.NotificationHint, error)
 
 	// User Notifications
+	// @withTransaction
 	CreateUserNotification(notification *model.UserNotification) (*model.UserNotification, error)
-	GetUserNotifications(userID string, limit int) ([]*model.UserNotification, error)
+	// @withTransaction
+	CreateUserNotifications(notifications []*model.UserNotification) ([]*model.UserNotification, error)
+	GetUserNotifications(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error)
+	GetUserNotificationThreads(userID string, opts model.QueryUserNotificationsOptions, limit int) ([]*model.UserNotification, error)
+	// @withTransaction
+	GetUserNotificationsMarkingRead(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error)
+	GetUserNotification(notificationID, userID string) (*model.UserNotification, error)
+	GetUserNotificationByID(notificationID string) (*model.UserNotification, error)
+	UpdateUserNotification(notification *model.UserNotification) error
+	ReassignNotificationsBoard(fromBoardID, toBoardID, toTeamID string) (int64, error)
 	GetUnreadNotificationCount(userID string) (int, error)
+	GetUnreadNotificationCountByType(userID string) (map[string]int, error)
+	GetOldestUnreadNotificationTime(userID string) (int64, error)
+	GetUnreadCountByBoardForMember(userID string) ([]*model.NotificationBoardUnreadCount, error)
+	GetUnreadCountByBoard(userID string) ([]*model.NotificationBoardUnreadCount, error)
+	GetNotificationSummary(userID string) (*model.NotificationSummary, error)
+	CountNotificationsByActor(since int64, limit int) ([]*model.NotificationActorCount, error)
+	SearchUserNotifications(opts model.SearchUserNotificationsOptions) ([]*model.UserNotification, bool, error)
+	GetUnreadNotificationByCollapseKey(userID, collapseKey, actorUserID, source string) (*model.UserNotification, error)
 	MarkNotificationAsRead(notificationID, userID string) error
-	MarkAllNotificationsAsRead(userID string) error
+	MarkNotificationAsUnread(notificationID, userID string) error
+	MarkNotificationsAsRead(ids []string, userID string) (int64, error)
+	MarkAllNotificationsAsRead(userID string, opts model.MarkNotificationsAsReadOptions) (int64, error)
+	// @withTransaction
+	MarkNotificationsAsReadReturningIDs(userID string, opts model.MarkNotificationsAsReadOptions) ([]string, error)
+	// @withTransaction
+	SyncNotificationReadStates(userID string, changes []*model.NotificationReadSync) ([]*model.UserNotification, error)
+	SetNotificationArchived(notificationID, userID string, archived bool) error
+	SetNotificationPinned(notificationID, userID string, pinned bool) error
+	ResolveUserNotification(notificationID, userID, action string) error
+	UnresolveUserNotification(notificationID, userID, action string, read bool) error
 	DeleteUserNotification(notificationID, userID string) error
+	DeleteUserNotificationsBefore(opts model.PurgeUserNotificationsOptions, batchSize int) (int64, error)
+	// @withTransaction
+	SetNotificationsHiddenForBoard(boardID string, hidden bool) ([]string, error)
+	// @withTransaction
+	DeleteNotificationsForUser(userID string) (int64, error)
+
+	// Notification Digests
+	// @withTransaction
+	AddNotificationDigestActivity(userID, boardID string, at int64) error
+	GetDueNotificationDigests(before int64) ([]*model.NotificationDigest, error)
+	DeleteNotificationDigest(userID, boardID string, lastAt int64) (bool, error)
+	// @withTransaction
+	SendNotificationDigest(digest *model.NotificationDigest, notification *model.UserNotification) (*model.UserNotification, error)
+
+	// Notification Deliveries
+	// @withTransaction
+	UpdateNotificationDeliveryStatus(notificationID, channel, status, deliveryError string) error
+	GetNotificationDeliveries(opts model.QueryNotificationDeliveriesOptions) ([]*model.NotificationDelivery, bool, error)
+
+	// Notification Escalations
+	GetNotificationsToEscalate(before int64, limit int) ([]*model.UserNotification, error)
+	// @withTransaction
+	EscalateNotification(notificationID string, channels []string) (string, error)
+
+	// Notification Board Preferences
+	GetNotificationBoardPreferences(userID, boardID string) ([]*model.NotificationBoardPreference, error)
+	GetUsersNotificationBoardPreferences(userIDs []string, boardID string) (map[string][]*model.NotificationBoardPreference, error)
+	// @withTransaction
+	SetNotificationBoardPreferences(userID, boardID string, preferences []*model.NotificationBoardPreference) error
+
+	// Notification Team Defaults
+	GetNotificationTeamDefaults(teamID string) (*model.NotificationPreferencesOverrides, error)
+	GetNotificationTeamDefaultsForBoard(boardID string) (*model.NotificationPreferencesOverrides, error)
+	// @withTransaction
+	SetNotificationTeamDefaults(teamID string, defaults *model.NotificationPreferencesOverrides) error
+
+	// Notification Actor Blocks
+	// @withTransaction
+	AddNotificationActorBlock(userID, actorID string) error
+	RemoveNotificationActorBlock(userID, actorID string) error
+	GetNotificationActorBlocks(userID string) ([]*model.NotificationActorBlock, error)
+	IsNotificationActorBlocked(userID, actorID string) (bool, error)
+
+	// Notification Device Reads
+	// @withTransaction
+	MarkNotificationAsReadOnDevice(notificationID, userID, deviceID string) error
+	GetNotificationDeviceReads(notificationID string) ([]*model.NotificationDeviceRead, error)
+
+	// Card Watchers
+	// @withTransaction
+	AddCardWatcher(cardID, userID string) error
+	RemoveCardWatcher(cardID, userID string) error
+	GetCardWatchers(cardID string) ([]*model.CardWatcher, error)
+
+	// Audit Records
+	CreateAuditRecords(records []*model.AuditRecord) error
+	GetAuditRecords(opts model.QueryAuditRecordsOptions) ([]*model.AuditRecord, bool, error)
+	DeleteAuditRecordsBefore(before int64, batchSize int) (int64, error)
 
 	RemoveDefaultTemplates(boards []*model.Board) error
 	GetTemplateBoards(teamID, userID string) ([]*model.Board, error)
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestAuditStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CreateAuditRecords", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCreateAuditRecords(t, store)
+	})
+
+	t.Run("GetAuditRecords", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetAuditRecords(t, store)
+	})
+
+	t.Run("DeleteAuditRecordsBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteAuditRecordsBefore(t, store)
+	})
+}
+
+func testCreateAuditRecords(t *testing.T, store store.Store) {
+	t.Run("an empty batch", func(t *testing.T) {
+		require.NoError(t, store.CreateAuditRecords(nil))
+	})
+
+	t.Run("a batch of records", func(t *testing.T) {
+		userID := utils.NewID(utils.IDTypeUser)
+		records := []*model.AuditRecord{
+			{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, Meta: `{"boardID":"board-1"}`},
+			{Level: "read", Event: "getBoard", Status: "fail", UserID: userID},
+		}
+		require.NoError(t, store.CreateAuditRecords(records))
+
+		got, hasNext, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID})
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.Len(t, got, 2)
+		for _, record := range got {
+			require.NotEmpty(t, record.ID)
+			require.NotZero(t, record.CreateAt)
+		}
+	})
+
+	t.Run("values longer than their column are truncated", func(t *testing.T) {
+		userID := utils.NewID(utils.IDTypeUser)
+		records := []*model.AuditRecord{{
+			Level:     "mod",
+			Event:     strings.Repeat("e", 150),
+			Status:    "success",
+			UserID:    userID,
+			APIPath:   "/api/v2/" + strings.Repeat("p", 1000),
+			Client:    strings.Repeat("ü", 600),
+			IPAddress: strings.Repeat("1", 100),
+		}}
+		require.NoError(t, store.CreateAuditRecords(records))
+
+		got, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Len(t, got[0].Event, 100)
+		require.Len(t, got[0].APIPath, 512)
+		require.Equal(t, strings.Repeat("ü", 512), got[0].Client)
+		require.Len(t, got[0].IPAddress, 64)
+	})
+}
+
+func testGetAuditRecords(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherUserID := utils.NewID(utils.IDTypeUser)
+	records := []*model.AuditRecord{
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, CreateAt: 1000},
+		{Level: "read", Event: "getBoard", Status: "success", UserID: userID, CreateAt: 2000},
+		{Level: "mod", Event: "deleteBoard", Status: "success", UserID: userID, CreateAt: 3000},
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: otherUserID, CreateAt: 4000},
+	}
+	require.NoError(t, store.CreateAuditRecords(records))
+
+	t.Run("newest first, filtered by user", func(t *testing.T) {
+		got, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, "deleteBoard", got[0].Event)
+		require.Equal(t, "createBoard", got[2].Event)
+	})
+
+	t.Run("filtered by event, level and time", func(t *testing.T) {
+		got, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{Event: "createBoard", Since: 1000, Until: 3000})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, userID, got[0].UserID)
+
+		got, _, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID, Level: "read"})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "getBoard", got[0].Event)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		got, hasNext, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID, PerPage: 2})
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Len(t, got, 2)
+
+		got, hasNext, err = store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID, Page: 1, PerPage: 2})
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.Len(t, got, 1)
+		require.Equal(t, "createBoard", got[0].Event)
+	})
+}
+
+func testDeleteAuditRecordsBefore(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	records := []*model.AuditRecord{
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, CreateAt: 1000},
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, CreateAt: 2000},
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, CreateAt: 3000},
+		{Level: "mod", Event: "createBoard", Status: "success", UserID: userID, CreateAt: 4000},
+	}
+	require.NoError(t, store.CreateAuditRecords(records))
+
+	deleted, err := store.DeleteAuditRecordsBefore(3500, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+
+	got, _, err := store.GetAuditRecords(model.QueryAuditRecordsOptions{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, int64(4000), got[0].CreateAt)
+}
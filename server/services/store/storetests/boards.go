@@ -1,6 +1,8 @@
 package storetests
 
 import (
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -27,6 +29,11 @@ func StoreTestBoardStore(t *testing.T, setup func(t *testing.T) (store.Store, fu
 		defer tearDown()
 		testGetBoardsInTeamByIds(t, store)
 	})
+	t.Run("GetBoardsByIDs", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardsByIDs(t, store)
+	})
 	t.Run("InsertBoard", func(t *testing.T) {
 		store, tearDown := setup(t)
 		defer tearDown()
@@ -97,6 +104,11 @@ func StoreTestBoardStore(t *testing.T, setup func(t *testing.T) (store.Store, fu
 		defer tearDown()
 		testGetBoardCount(t, store)
 	})
+	t.Run("GetBoardMembershipsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetBoardMembershipsForUser(t, store)
+	})
 }
 
 func testGetBoard(t *testing.T, store store.Store) {
@@ -278,6 +290,35 @@ func testGetBoardsInTeamByIds(t *testing.T, store store.Store) {
 	})
 }
 
+func testGetBoardsByIDs(t *testing.T, store store.Store) {
+	for _, board := range []*model.Board{
+		{ID: "board-id-1", TeamID: testTeamID, Type: model.BoardTypeOpen},
+		{ID: "board-id-2", TeamID: "other-team-id", Type: model.BoardTypePrivate},
+	} {
+		_, _, err := store.InsertBoardWithAdmin(board, testUserID)
+		require.NoError(t, err)
+	}
+
+	t.Run("boards of every team", func(t *testing.T) {
+		boards, err := store.GetBoardsByIDs([]string{"board-id-1", "board-id-2"})
+		require.NoError(t, err)
+		require.Len(t, boards, 2)
+	})
+
+	t.Run("only the existing boards", func(t *testing.T) {
+		boards, err := store.GetBoardsByIDs([]string{"nonexistent", "board-id-2"})
+		require.NoError(t, err)
+		require.Len(t, boards, 1)
+		require.Equal(t, "board-id-2", boards[0].ID)
+	})
+
+	t.Run("no IDs", func(t *testing.T) {
+		boards, err := store.GetBoardsByIDs(nil)
+		require.NoError(t, err)
+		require.Empty(t, boards)
+	})
+}
+
 func testInsertBoard(t *testing.T, store store.Store) {
 	userID := testUserID
 
@@ -1199,3 +1240,48 @@ func testGetBoardCount(t *testing.T, store store.Store) {
 		require.Equal(t, originalCount+1, newCount)
 	})
 }
+
+func testGetBoardMembershipsForUser(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	t.Run("no memberships", func(t *testing.T) {
+		memberships, hasNext, err := store.GetBoardMembershipsForUser(userID, 0, 10)
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.Empty(t, memberships)
+	})
+
+	boardIDs := []string{utils.NewID(utils.IDTypeBoard), utils.NewID(utils.IDTypeBoard)}
+	sort.Strings(boardIDs)
+	for i, boardID := range boardIDs {
+		board := &model.Board{
+			ID:          boardID,
+			Title:       fmt.Sprintf("Board %d", i),
+			TeamID:      testTeamID,
+			Type:        model.BoardTypeOpen,
+			MinimumRole: model.BoardRoleCommenter,
+		}
+		_, err := store.InsertBoard(board, userID)
+		require.NoError(t, err)
+		_, err = store.SaveMember(&model.BoardMember{BoardID: boardID, UserID: userID, SchemeViewer: true})
+		require.NoError(t, err)
+	}
+
+	t.Run("paginated by board", func(t *testing.T) {
+		memberships, hasNext, err := store.GetBoardMembershipsForUser(userID, 0, 1)
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Len(t, memberships, 1)
+		require.Equal(t, boardIDs[0], memberships[0].BoardID)
+		require.Equal(t, testTeamID, memberships[0].TeamID)
+		require.Equal(t, "Board 0", memberships[0].Title)
+		require.Equal(t, "commenter", memberships[0].Member.MinimumRole)
+		require.True(t, memberships[0].Member.SchemeViewer)
+
+		memberships, hasNext, err = store.GetBoardMembershipsForUser(userID, 1, 1)
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.Len(t, memberships, 1)
+		require.Equal(t, boardIDs[1], memberships[0].BoardID)
+	})
+}
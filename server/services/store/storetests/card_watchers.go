@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/services/store"
+)
+
+func StoreTestCardWatchersStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("CardWatchers", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCardWatchers(t, store)
+	})
+}
+
+func testCardWatchers(t *testing.T, store store.Store) {
+	t.Run("a card without watchers", func(t *testing.T) {
+		watchers, err := store.GetCardWatchers("card-none")
+		require.NoError(t, err)
+		require.Empty(t, watchers)
+	})
+
+	t.Run("add, list and remove watchers", func(t *testing.T) {
+		require.NoError(t, store.AddCardWatcher("card-1", "user-1"))
+		require.NoError(t, store.AddCardWatcher("card-1", "user-2"))
+		require.NoError(t, store.AddCardWatcher("card-2", "user-1"))
+
+		// watching twice is a no-op
+		require.NoError(t, store.AddCardWatcher("card-1", "user-1"))
+
+		watchers, err := store.GetCardWatchers("card-1")
+		require.NoError(t, err)
+		require.Len(t, watchers, 2)
+		userIDs := []string{watchers[0].UserID, watchers[1].UserID}
+		require.ElementsMatch(t, []string{"user-1", "user-2"}, userIDs)
+		for _, watcher := range watchers {
+			require.Equal(t, "card-1", watcher.CardID)
+			require.NotZero(t, watcher.CreateAt)
+		}
+
+		require.NoError(t, store.RemoveCardWatcher("card-1", "user-1"))
+		// removing a missing watcher is a no-op
+		require.NoError(t, store.RemoveCardWatcher("card-1", "user-1"))
+
+		watchers, err = store.GetCardWatchers("card-1")
+		require.NoError(t, err)
+		require.Len(t, watchers, 1)
+		require.Equal(t, "user-2", watchers[0].UserID)
+
+		watchers, err = store.GetCardWatchers("card-2")
+		require.NoError(t, err)
+		require.Len(t, watchers, 1)
+		require.Equal(t, "user-1", watchers[0].UserID)
+	})
+}
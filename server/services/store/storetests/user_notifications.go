@@ -0,0 +1,1814 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package storetests
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/focalboard/server/model"
+	"github.com/mattermost/focalboard/server/services/store"
+	"github.com/mattermost/focalboard/server/utils"
+)
+
+func StoreTestUserNotificationsStore(t *testing.T, setup func(t *testing.T) (store.Store, func())) {
+	t.Run("GetNotificationSummary", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetNotificationSummary(t, store)
+	})
+
+	t.Run("GetUserNotificationsBoardFilters", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsBoardFilters(t, store)
+	})
+
+	t.Run("GetUserNotificationsSeveralBoards", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsSeveralBoards(t, store)
+	})
+
+	t.Run("NotificationBoardPreferences", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationBoardPreferences(t, store)
+	})
+
+	t.Run("NotificationTeamDefaults", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationTeamDefaults(t, store)
+	})
+
+	t.Run("ResolveUserNotification", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testResolveUserNotification(t, store)
+	})
+
+	t.Run("CountNotificationsByActor", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCountNotificationsByActor(t, store)
+	})
+
+	t.Run("SyncNotificationReadStates", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSyncNotificationReadStates(t, store)
+	})
+
+	t.Run("NotificationDigests", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationDigests(t, store)
+	})
+
+	t.Run("UserNotificationTeams", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserNotificationTeams(t, store)
+	})
+
+	t.Run("GetUnreadCountByBoardForMember", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUnreadCountByBoardForMember(t, store)
+	})
+
+	t.Run("DeleteUserNotificationsBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteUserNotificationsBefore(t, store)
+	})
+
+	t.Run("GetUserNotificationsDateRange", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsDateRange(t, store)
+	})
+
+	t.Run("UserNotificationCoRecipients", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserNotificationCoRecipients(t, store)
+	})
+
+	t.Run("SetNotificationsHiddenForBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSetNotificationsHiddenForBoard(t, store)
+	})
+
+	t.Run("GetUserNotificationsUnresolvedOnly", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsUnresolvedOnly(t, store)
+	})
+
+	t.Run("ReassignNotificationsBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testReassignNotificationsBoard(t, store)
+	})
+
+	t.Run("PinnedUserNotifications", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testPinnedUserNotifications(t, store)
+	})
+
+	t.Run("MarkNotificationsAsReadReturningIDs", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMarkNotificationsAsReadReturningIDs(t, store)
+	})
+	t.Run("NotificationActorBlocks", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationActorBlocks(t, store)
+	})
+	t.Run("CardNotifications", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testCardNotifications(t, store)
+	})
+	t.Run("UserNotificationsOrder", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserNotificationsOrder(t, store)
+	})
+
+	t.Run("DeleteNotificationsForUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteNotificationsForUser(t, store)
+	})
+
+	t.Run("NotificationEscalations", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationEscalations(t, store)
+	})
+
+	t.Run("GetUserNotificationsMarkingRead", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsMarkingRead(t, store)
+	})
+
+	t.Run("NotificationQuery", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationQuery(t, store)
+	})
+
+	t.Run("NotificationDeviceReads", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testNotificationDeviceReads(t, store)
+	})
+
+	t.Run("GetOldestUnreadNotificationTime", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetOldestUnreadNotificationTime(t, store)
+	})
+
+	t.Run("GetUnreadNotificationCountByType", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUnreadNotificationCountByType(t, store)
+	})
+
+	t.Run("UserNotificationReason", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testUserNotificationReason(t, store)
+	})
+
+	t.Run("GetUnreadCountByBoard", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUnreadCountByBoard(t, store)
+	})
+
+	t.Run("GetUserNotificationsCursor", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsCursor(t, store)
+	})
+
+	t.Run("SearchUserNotifications", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testSearchUserNotifications(t, store)
+	})
+
+	t.Run("GetUnreadNotificationByCollapseKey", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUnreadNotificationByCollapseKey(t, store)
+	})
+
+	t.Run("GetUserNotificationThreads", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationThreads(t, store)
+	})
+
+	t.Run("GetUserNotificationsBefore", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUserNotificationsBefore(t, store)
+	})
+
+	t.Run("MarkNotificationAsUnread", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMarkNotificationAsUnread(t, store)
+	})
+
+	t.Run("MarkNotificationsAsRead", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testMarkNotificationsAsRead(t, store)
+	})
+}
+
+func createTestUserNotification(t *testing.T, store store.Store, userID, boardID string) *model.UserNotification {
+	notification, err := store.CreateUserNotification(&model.UserNotification{
+		TargetUserID: userID,
+		ActorUserID:  utils.NewID(utils.IDTypeUser),
+		ActorName:    "actor",
+		Type:         model.NotificationTypeMentioned,
+		CardID:       utils.NewID(utils.IDTypeCard),
+		BoardID:      boardID,
+	})
+	require.NoError(t, err)
+	return notification
+}
+
+func testGetNotificationSummary(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	t.Run("no notifications", func(t *testing.T) {
+		summary, err := store.GetNotificationSummary(userID)
+		require.NoError(t, err)
+		require.Equal(t, &model.NotificationSummary{}, summary)
+	})
+
+	t.Run("counts by read state", func(t *testing.T) {
+		read := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+		createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		archived := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		require.NoError(t, store.SetNotificationArchived(archived.ID, userID, true))
+		createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), utils.NewID(utils.IDTypeBoard))
+
+		summary, err := store.GetNotificationSummary(userID)
+		require.NoError(t, err)
+		require.Equal(t, &model.NotificationSummary{Total: 3, Unread: 2, Read: 1}, summary)
+	})
+}
+
+func testGetUserNotificationsBoardFilters(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	noisyBoardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	noisy := createTestUserNotification(t, store, userID, noisyBoardID)
+	other := createTestUserNotification(t, store, userID, otherBoardID)
+
+	t.Run("only a board", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{BoardIDs: []string{noisyBoardID}})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.Equal(t, noisy.ID, notifications[0].ID)
+	})
+
+	t.Run("everything except a board", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{ExcludeBoardID: noisyBoardID})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.Equal(t, other.ID, notifications[0].ID)
+	})
+}
+
+func testGetUserNotificationsSeveralBoards(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	first := createTestUserNotification(t, store, userID, boardID)
+	second := createTestUserNotification(t, store, userID, otherBoardID)
+	createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	opts := model.QueryUserNotificationsOptions{BoardIDs: []string{boardID, otherBoardID}}
+	notifications, err := store.GetUserNotifications(userID, opts)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	require.ElementsMatch(t, []string{first.ID, second.ID}, []string{notifications[0].ID, notifications[1].ID})
+
+	opts.Limit = 1
+	notifications, err = store.GetUserNotifications(userID, opts)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+}
+
+func testNotificationBoardPreferences(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	t.Run("no overrides", func(t *testing.T) {
+		preferences, err := store.GetNotificationBoardPreferences(userID, boardID)
+		require.NoError(t, err)
+		require.Empty(t, preferences)
+	})
+
+	t.Run("set replaces the overrides of the board", func(t *testing.T) {
+		err := store.SetNotificationBoardPreferences(userID, boardID, []*model.NotificationBoardPreference{
+			{Type: model.NotificationTypeMentioned, Enabled: true},
+			{Type: model.NotificationTypeAssigned, Enabled: false},
+		})
+		require.NoError(t, err)
+		err = store.SetNotificationBoardPreferences(userID, otherBoardID, []*model.NotificationBoardPreference{
+			{Type: model.NotificationTypeMentioned, Enabled: false},
+		})
+		require.NoError(t, err)
+
+		err = store.SetNotificationBoardPreferences(userID, boardID, []*model.NotificationBoardPreference{
+			{Type: model.NotificationTypeAssigned, Enabled: true},
+		})
+		require.NoError(t, err)
+
+		preferences, err := store.GetNotificationBoardPreferences(userID, boardID)
+		require.NoError(t, err)
+		require.Len(t, preferences, 1)
+		require.Equal(t, model.NotificationTypeAssigned, preferences[0].Type)
+		require.True(t, preferences[0].Enabled)
+		require.Equal(t, userID, preferences[0].UserID)
+		require.Equal(t, boardID, preferences[0].BoardID)
+
+		preferences, err = store.GetNotificationBoardPreferences(userID, otherBoardID)
+		require.NoError(t, err)
+		require.Len(t, preferences, 1)
+		require.False(t, preferences[0].Enabled)
+	})
+
+	t.Run("overrides of many users are read at once", func(t *testing.T) {
+		otherUserID := utils.NewID(utils.IDTypeUser)
+		byUser, err := store.GetUsersNotificationBoardPreferences([]string{userID, otherUserID}, boardID)
+		require.NoError(t, err)
+		require.Len(t, byUser, 1)
+		require.Len(t, byUser[userID], 1)
+		require.Equal(t, model.NotificationTypeAssigned, byUser[userID][0].Type)
+		require.Empty(t, byUser[otherUserID])
+	})
+
+	t.Run("empty list removes the overrides", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationBoardPreferences(userID, boardID, nil))
+
+		preferences, err := store.GetNotificationBoardPreferences(userID, boardID)
+		require.NoError(t, err)
+		require.Empty(t, preferences)
+	})
+}
+
+func testNotificationTeamDefaults(t *testing.T, store store.Store) {
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: "team-1",
+		Type:   model.BoardTypeOpen,
+	}, utils.NewID(utils.IDTypeUser))
+	require.NoError(t, err)
+
+	t.Run("no defaults", func(t *testing.T) {
+		defaults, err := store.GetNotificationTeamDefaults("team-1")
+		require.NoError(t, err)
+		require.Equal(t, &model.NotificationPreferencesOverrides{}, defaults)
+
+		defaults, err = store.GetNotificationTeamDefaultsForBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, &model.NotificationPreferencesOverrides{}, defaults)
+	})
+
+	t.Run("set replaces the defaults of the team", func(t *testing.T) {
+		muted := true
+		mutedBoardIDs := []string{board.ID}
+		require.NoError(t, store.SetNotificationTeamDefaults("team-1", &model.NotificationPreferencesOverrides{Muted: &muted}))
+		require.NoError(t, store.SetNotificationTeamDefaults("team-1", &model.NotificationPreferencesOverrides{MutedBoardIDs: &mutedBoardIDs}))
+
+		defaults, err := store.GetNotificationTeamDefaults("team-1")
+		require.NoError(t, err)
+		require.Nil(t, defaults.Muted)
+		require.Equal(t, &mutedBoardIDs, defaults.MutedBoardIDs)
+
+		defaults, err = store.GetNotificationTeamDefaultsForBoard(board.ID)
+		require.NoError(t, err)
+		require.Equal(t, &mutedBoardIDs, defaults.MutedBoardIDs)
+
+		defaults, err = store.GetNotificationTeamDefaults("team-2")
+		require.NoError(t, err)
+		require.Nil(t, defaults.MutedBoardIDs)
+	})
+
+	t.Run("empty defaults remove them", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationTeamDefaults("team-1", &model.NotificationPreferencesOverrides{}))
+
+		defaults, err := store.GetNotificationTeamDefaults("team-1")
+		require.NoError(t, err)
+		require.Equal(t, &model.NotificationPreferencesOverrides{}, defaults)
+	})
+}
+
+func testResolveUserNotification(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	notification := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	require.NotEmpty(t, notification.Actions)
+
+	t.Run("resolves and marks read", func(t *testing.T) {
+		require.NoError(t, store.ResolveUserNotification(notification.ID, userID, model.NotificationActionDismiss))
+
+		resolved, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.True(t, resolved.Read)
+		require.Equal(t, model.NotificationActionDismiss, resolved.ResolvedAction)
+		require.Empty(t, resolved.Actions)
+	})
+
+	t.Run("can't be resolved twice", func(t *testing.T) {
+		err := store.ResolveUserNotification(notification.ID, userID, model.NotificationActionDismiss)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testCountNotificationsByActor(t *testing.T, store store.Store) {
+	quiet := utils.NewID(utils.IDTypeUser)
+	noisy := utils.NewID(utils.IDTypeUser)
+
+	notifications := []*model.UserNotification{}
+	for _, actorID := range []string{quiet, noisy, noisy, noisy} {
+		notifications = append(notifications, &model.UserNotification{
+			TargetUserID: utils.NewID(utils.IDTypeUser),
+			ActorUserID:  actorID,
+			ActorName:    "actor",
+			Type:         model.NotificationTypeMentioned,
+			CardID:       utils.NewID(utils.IDTypeCard),
+			BoardID:      utils.NewID(utils.IDTypeBoard),
+		})
+	}
+	_, err := store.CreateUserNotifications(notifications)
+	require.NoError(t, err)
+
+	t.Run("most notifications first", func(t *testing.T) {
+		counts, err := store.CountNotificationsByActor(0, 10)
+		require.NoError(t, err)
+		require.Len(t, counts, 2)
+		require.Equal(t, noisy, counts[0].ActorUserID)
+		require.EqualValues(t, 3, counts[0].Count)
+		require.Equal(t, quiet, counts[1].ActorUserID)
+		require.EqualValues(t, 1, counts[1].Count)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		counts, err := store.CountNotificationsByActor(0, 1)
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		require.Equal(t, noisy, counts[0].ActorUserID)
+	})
+
+	t.Run("since", func(t *testing.T) {
+		counts, err := store.CountNotificationsByActor(utils.GetMillis()+1000, 10)
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+}
+
+func testSyncNotificationReadStates(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	notification := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	other := createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), utils.NewID(utils.IDTypeBoard))
+
+	t.Run("later change wins", func(t *testing.T) {
+		changes := []*model.NotificationReadSync{{ID: notification.ID, Read: true, ReadAt: notification.UpdateAt + 1}}
+		notifications, err := store.SyncNotificationReadStates(userID, changes)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.True(t, notifications[0].Read)
+	})
+
+	t.Run("earlier change loses", func(t *testing.T) {
+		changes := []*model.NotificationReadSync{{ID: notification.ID, Read: false, ReadAt: notification.UpdateAt}}
+		notifications, err := store.SyncNotificationReadStates(userID, changes)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.True(t, notifications[0].Read)
+	})
+
+	t.Run("future change counts as made now", func(t *testing.T) {
+		time.Sleep(2 * time.Millisecond)
+		future := utils.GetMillis() + int64(time.Hour/time.Millisecond)
+		changes := []*model.NotificationReadSync{{ID: notification.ID, Read: false, ReadAt: future}}
+		notifications, err := store.SyncNotificationReadStates(userID, changes)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.False(t, notifications[0].Read)
+		require.Less(t, notifications[0].UpdateAt, future)
+	})
+
+	t.Run("notifications of other users are skipped", func(t *testing.T) {
+		changes := []*model.NotificationReadSync{{ID: other.ID, Read: true, ReadAt: other.UpdateAt + 1}}
+		notifications, err := store.SyncNotificationReadStates(userID, changes)
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+
+		stored, err := store.GetUserNotificationByID(other.ID)
+		require.NoError(t, err)
+		require.False(t, stored.Read)
+	})
+}
+
+func testNotificationDigests(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	require.NoError(t, store.AddNotificationDigestActivity(userID, boardID, 100))
+	require.NoError(t, store.AddNotificationDigestActivity(userID, boardID, 200))
+
+	t.Run("not due yet", func(t *testing.T) {
+		digests, err := store.GetDueNotificationDigests(99)
+		require.NoError(t, err)
+		require.Empty(t, digests)
+	})
+
+	t.Run("activity accumulates", func(t *testing.T) {
+		digests, err := store.GetDueNotificationDigests(100)
+		require.NoError(t, err)
+		require.Len(t, digests, 1)
+		require.Equal(t, 2, digests[0].PendingCount)
+		require.EqualValues(t, 100, digests[0].FirstAt)
+		require.EqualValues(t, 200, digests[0].LastAt)
+	})
+
+	t.Run("delete only if unchanged", func(t *testing.T) {
+		deleted, err := store.DeleteNotificationDigest(userID, boardID, 100)
+		require.NoError(t, err)
+		require.False(t, deleted)
+
+		deleted, err = store.DeleteNotificationDigest(userID, boardID, 200)
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		digests, err := store.GetDueNotificationDigests(200)
+		require.NoError(t, err)
+		require.Empty(t, digests)
+	})
+
+	t.Run("send creates the notification and deletes the digest", func(t *testing.T) {
+		require.NoError(t, store.AddNotificationDigestActivity(userID, boardID, 300))
+		digests, err := store.GetDueNotificationDigests(300)
+		require.NoError(t, err)
+		require.Len(t, digests, 1)
+		digest := digests[0]
+
+		require.NoError(t, store.AddNotificationDigestActivity(userID, boardID, 400))
+		created, err := store.SendNotificationDigest(digest, &model.UserNotification{
+			TargetUserID: userID,
+			BoardID:      boardID,
+			Type:         model.NotificationTypeBoardDigest,
+		})
+		require.NoError(t, err)
+		require.Nil(t, created)
+
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+
+		digest.LastAt = 400
+		created, err = store.SendNotificationDigest(digest, &model.UserNotification{
+			TargetUserID: userID,
+			BoardID:      boardID,
+			Type:         model.NotificationTypeBoardDigest,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, created)
+
+		notifications, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+
+		digests, err = store.GetDueNotificationDigests(400)
+		require.NoError(t, err)
+		require.Empty(t, digests)
+	})
+}
+
+func testDeleteUserNotificationsBefore(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	mentioned := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	assigned, err := store.CreateUserNotification(&model.UserNotification{
+		TargetUserID: userID,
+		Type:         model.NotificationTypeAssigned,
+		CardID:       utils.NewID(utils.IDTypeCard),
+		BoardID:      utils.NewID(utils.IDTypeBoard),
+	})
+	require.NoError(t, err)
+
+	t.Run("newer notifications are kept", func(t *testing.T) {
+		opts := model.PurgeUserNotificationsOptions{Before: mentioned.CreateAt - 1}
+		deleted, err := store.DeleteUserNotificationsBefore(opts, 10)
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+	})
+
+	before := utils.GetMillis() + 1000
+
+	t.Run("only the given types are deleted", func(t *testing.T) {
+		opts := model.PurgeUserNotificationsOptions{Before: before, Types: []string{model.NotificationTypeAssigned}}
+		deleted, err := store.DeleteUserNotificationsBefore(opts, 10)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		_, err = store.GetUserNotification(assigned.ID, userID)
+		require.True(t, model.IsErrNotFound(err))
+		_, err = store.GetUserNotification(mentioned.ID, userID)
+		require.NoError(t, err)
+	})
+
+	t.Run("excluded types are kept", func(t *testing.T) {
+		createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		opts := model.PurgeUserNotificationsOptions{Before: before, ExcludeTypes: []string{model.NotificationTypeMentioned}}
+		deleted, err := store.DeleteUserNotificationsBefore(opts, 10)
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+
+		opts = model.PurgeUserNotificationsOptions{Before: before, ExcludeTypes: []string{model.NotificationTypeAssigned}}
+		deleted, err = store.DeleteUserNotificationsBefore(opts, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deleted)
+	})
+}
+
+func testUserNotificationTeams(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	board, err := store.InsertBoard(&model.Board{
+		ID:     utils.NewID(utils.IDTypeBoard),
+		TeamID: "team-1",
+		Type:   model.BoardTypeOpen,
+	}, userID)
+	require.NoError(t, err)
+
+	t.Run("team of the board is set on create", func(t *testing.T) {
+		notification := createTestUserNotification(t, store, userID, board.ID)
+		require.Equal(t, "team-1", notification.TeamID)
+
+		created, err := store.CreateUserNotifications([]*model.UserNotification{
+			{TargetUserID: userID, Type: model.NotificationTypeMentioned, BoardID: board.ID},
+			{TargetUserID: userID, Type: model.NotificationTypeMentioned, BoardID: board.ID, TeamID: "team-2"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "team-1", created[0].TeamID)
+		// the team of the board wins over the one the notification was created with
+		require.Equal(t, "team-1", created[1].TeamID)
+	})
+
+	t.Run("notifications without a board have no team", func(t *testing.T) {
+		notification := createTestUserNotification(t, store, userID, "")
+		require.Empty(t, notification.TeamID)
+
+		notification = createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+		require.Empty(t, notification.TeamID)
+	})
+
+	t.Run("filter by team", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{TeamID: "team-1"})
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+		for _, notification := range notifications {
+			require.Equal(t, "team-1", notification.TeamID)
+		}
+
+		notifications, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{TeamID: "team-2"})
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+}
+
+func testGetUnreadCountByBoardForMember(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	newBoard := func() string {
+		board, err := store.InsertBoard(&model.Board{
+			ID:     utils.NewID(utils.IDTypeBoard),
+			TeamID: "team-1",
+			Type:   model.BoardTypeOpen,
+		}, userID)
+		require.NoError(t, err)
+		_, err = store.SaveMember(&model.BoardMember{BoardID: board.ID, UserID: userID, SchemeEditor: true})
+		require.NoError(t, err)
+		return board.ID
+	}
+
+	memberBoardID := newBoard()
+	leftBoardID := newBoard()
+	deletedBoardID := newBoard()
+
+	createTestUserNotification(t, store, userID, memberBoardID)
+	createTestUserNotification(t, store, userID, memberBoardID)
+	read := createTestUserNotification(t, store, userID, memberBoardID)
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+	createTestUserNotification(t, store, userID, leftBoardID)
+	createTestUserNotification(t, store, userID, deletedBoardID)
+	createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	require.NoError(t, store.DeleteMember(leftBoardID, userID))
+	require.NoError(t, store.DeleteBoard(deletedBoardID, userID))
+
+	counts, err := store.GetUnreadCountByBoardForMember(userID)
+	require.NoError(t, err)
+	require.Equal(t, []*model.NotificationBoardUnreadCount{{BoardID: memberBoardID, Count: 2}}, counts)
+
+	counts, err = store.GetUnreadCountByBoardForMember(utils.NewID(utils.IDTypeUser))
+	require.NoError(t, err)
+	require.Empty(t, counts)
+}
+
+func testGetUserNotificationsDateRange(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	first := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	second := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	third := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	ids := func(notifications []*model.UserNotification) []string {
+		result := make([]string, len(notifications))
+		for i, notification := range notifications {
+			result[i] = notification.ID
+		}
+		return result
+	}
+
+	t.Run("from and to are inclusive", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{From: first.CreateAt, To: second.CreateAt}
+		notifications, err := store.GetUserNotifications(userID, opts)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{first.ID, second.ID}, ids(notifications))
+	})
+
+	t.Run("only from", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{From: second.CreateAt}
+		notifications, err := store.GetUserNotifications(userID, opts)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{second.ID, third.ID}, ids(notifications))
+	})
+
+	t.Run("only to", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{To: first.CreateAt}
+		notifications, err := store.GetUserNotifications(userID, opts)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{first.ID}, ids(notifications))
+	})
+
+	t.Run("composes with other filters", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{From: second.CreateAt, BoardIDs: []string{boardID}}
+		notifications, err := store.GetUserNotifications(userID, opts)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{second.ID}, ids(notifications))
+	})
+}
+
+func testUserNotificationCoRecipients(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherIDs := []string{utils.NewID(utils.IDTypeUser), utils.NewID(utils.IDTypeUser)}
+
+	created, err := store.CreateUserNotifications([]*model.UserNotification{
+		{TargetUserID: userID, Type: model.NotificationTypeMentioned, CoRecipients: otherIDs},
+		{TargetUserID: userID, Type: model.NotificationTypeMentioned},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+
+	notification, err := store.GetUserNotification(created[0].ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, otherIDs, notification.CoRecipients)
+
+	notification, err = store.GetUserNotification(created[1].ID, userID)
+	require.NoError(t, err)
+	require.Empty(t, notification.CoRecipients)
+}
+
+func testSetNotificationsHiddenForBoard(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherUserID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	createTestUserNotification(t, store, userID, boardID)
+	createTestUserNotification(t, store, userID, boardID)
+	createTestUserNotification(t, store, otherUserID, boardID)
+	kept := createTestUserNotification(t, store, userID, otherBoardID)
+
+	t.Run("hides the notifications of the board", func(t *testing.T) {
+		userIDs, err := store.SetNotificationsHiddenForBoard(boardID, true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{userID, otherUserID}, userIDs)
+
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		require.Equal(t, kept.ID, notifications[0].ID)
+
+		count, err := store.GetUnreadNotificationCount(userID)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		notifications, err = store.GetUserNotifications(otherUserID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+
+	t.Run("hiding twice changes nothing", func(t *testing.T) {
+		userIDs, err := store.SetNotificationsHiddenForBoard(boardID, true)
+		require.NoError(t, err)
+		require.Empty(t, userIDs)
+	})
+
+	t.Run("shows the notifications of the board again", func(t *testing.T) {
+		userIDs, err := store.SetNotificationsHiddenForBoard(boardID, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{userID, otherUserID}, userIDs)
+
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+
+		count, err := store.GetUnreadNotificationCount(userID)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+}
+
+func testGetUserNotificationsUnresolvedOnly(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	created, err := store.CreateUserNotifications([]*model.UserNotification{
+		{TargetUserID: userID, Type: model.NotificationTypeAssigned, CardID: utils.NewID(utils.IDTypeCard)},
+		{TargetUserID: userID, Type: model.NotificationTypeAssigned, CardID: utils.NewID(utils.IDTypeCard)},
+		{TargetUserID: userID, Type: model.NotificationTypeAssigned, CardID: utils.NewID(utils.IDTypeCard)},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+	resolved, read, pending := created[0], created[1], created[2]
+	require.NoError(t, store.ResolveUserNotification(resolved.ID, userID, model.NotificationActionUnassign))
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+
+	// mentions can only be dismissed, they never need the user's attention
+	createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{UnresolvedOnly: true})
+	require.NoError(t, err)
+	ids := []string{}
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+	require.ElementsMatch(t, []string{read.ID, pending.ID}, ids)
+}
+
+func testReassignNotificationsBoard(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	fromBoardID := utils.NewID(utils.IDTypeBoard)
+	toBoardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	moved := createTestUserNotification(t, store, userID, fromBoardID)
+	createTestUserNotification(t, store, userID, fromBoardID)
+	other := createTestUserNotification(t, store, userID, otherBoardID)
+
+	count, err := store.ReassignNotificationsBoard(fromBoardID, toBoardID, "team-2")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	notification, err := store.GetUserNotificationByID(moved.ID)
+	require.NoError(t, err)
+	require.Equal(t, toBoardID, notification.BoardID)
+	require.Equal(t, "team-2", notification.TeamID)
+
+	notification, err = store.GetUserNotificationByID(other.ID)
+	require.NoError(t, err)
+	require.Equal(t, otherBoardID, notification.BoardID)
+
+	count, err = store.ReassignNotificationsBoard(fromBoardID, toBoardID, "")
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func testPinnedUserNotifications(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	oldest := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	time.Sleep(10 * time.Millisecond)
+	middle := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	time.Sleep(10 * time.Millisecond)
+	newest := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	require.NoError(t, store.SetNotificationPinned(oldest.ID, userID, true))
+
+	t.Run("pinned notifications come first", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+		require.Equal(t, oldest.ID, notifications[0].ID)
+		require.True(t, notifications[0].Pinned)
+		require.Equal(t, newest.ID, notifications[1].ID)
+		require.Equal(t, middle.ID, notifications[2].ID)
+	})
+
+	t.Run("pinned notifications are kept by retention", func(t *testing.T) {
+		opts := model.PurgeUserNotificationsOptions{Before: utils.GetMillis() + 1000}
+		deleted, err := store.DeleteUserNotificationsBefore(opts, 10)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, deleted)
+
+		notification, err := store.GetUserNotification(oldest.ID, userID)
+		require.NoError(t, err)
+		require.True(t, notification.Pinned)
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationPinned(oldest.ID, userID, false))
+		notification, err := store.GetUserNotification(oldest.ID, userID)
+		require.NoError(t, err)
+		require.False(t, notification.Pinned)
+	})
+
+	t.Run("notifications of other users can't be pinned", func(t *testing.T) {
+		err := store.SetNotificationPinned(oldest.ID, utils.NewID(utils.IDTypeUser), true)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("notifications of other users can't be archived", func(t *testing.T) {
+		err := store.SetNotificationArchived(oldest.ID, utils.NewID(utils.IDTypeUser), true)
+		require.True(t, model.IsErrNotFound(err))
+
+		err = store.SetNotificationArchived(utils.NewID(utils.IDTypeNone), userID, false)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testMarkNotificationsAsReadReturningIDs(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	first := createTestUserNotification(t, store, userID, boardID)
+	second := createTestUserNotification(t, store, userID, boardID)
+	alreadyRead := createTestUserNotification(t, store, userID, boardID)
+	require.NoError(t, store.MarkNotificationAsRead(alreadyRead.ID, userID))
+	otherBoard := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	otherUser := createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), boardID)
+	archived := createTestUserNotification(t, store, userID, boardID)
+	require.NoError(t, store.SetNotificationArchived(archived.ID, userID, true))
+	hiddenBoardID := utils.NewID(utils.IDTypeBoard)
+	createTestUserNotification(t, store, userID, hiddenBoardID)
+	_, err := store.SetNotificationsHiddenForBoard(hiddenBoardID, true)
+	require.NoError(t, err)
+
+	ids, err := store.MarkNotificationsAsReadReturningIDs(userID, model.MarkNotificationsAsReadOptions{BoardID: boardID})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{first.ID, second.ID}, ids)
+
+	t.Run("archived and hidden notifications are left unread", func(t *testing.T) {
+		stored, err := store.GetUserNotification(archived.ID, userID)
+		require.NoError(t, err)
+		require.False(t, stored.Read)
+
+		ids, err := store.MarkNotificationsAsReadReturningIDs(userID, model.MarkNotificationsAsReadOptions{BoardID: hiddenBoardID})
+		require.NoError(t, err)
+		require.Empty(t, ids)
+
+		count, err := store.MarkAllNotificationsAsRead(userID, model.MarkNotificationsAsReadOptions{BoardID: hiddenBoardID})
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
+	for _, notification := range []*model.UserNotification{first, second} {
+		stored, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.True(t, stored.Read)
+	}
+	stored, err := store.GetUserNotification(otherBoard.ID, userID)
+	require.NoError(t, err)
+	require.False(t, stored.Read)
+	stored, err = store.GetUserNotification(otherUser.ID, otherUser.TargetUserID)
+	require.NoError(t, err)
+	require.False(t, stored.Read)
+
+	// nothing is left unread
+	ids, err = store.MarkNotificationsAsReadReturningIDs(userID, model.MarkNotificationsAsReadOptions{BoardID: boardID})
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func testNotificationActorBlocks(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	actorID := utils.NewID(utils.IDTypeUser)
+	otherActorID := utils.NewID(utils.IDTypeUser)
+
+	t.Run("no blocks", func(t *testing.T) {
+		blocks, err := store.GetNotificationActorBlocks(userID)
+		require.NoError(t, err)
+		require.Empty(t, blocks)
+
+		blocked, err := store.IsNotificationActorBlocked(userID, actorID)
+		require.NoError(t, err)
+		require.False(t, blocked)
+	})
+
+	t.Run("blocking is idempotent and per actor", func(t *testing.T) {
+		require.NoError(t, store.AddNotificationActorBlock(userID, actorID))
+		require.NoError(t, store.AddNotificationActorBlock(userID, actorID))
+
+		blocks, err := store.GetNotificationActorBlocks(userID)
+		require.NoError(t, err)
+		require.Len(t, blocks, 1)
+		require.Equal(t, actorID, blocks[0].BlockedActorID)
+		require.NotZero(t, blocks[0].CreateAt)
+
+		blocked, err := store.IsNotificationActorBlocked(userID, actorID)
+		require.NoError(t, err)
+		require.True(t, blocked)
+
+		blocked, err = store.IsNotificationActorBlocked(userID, otherActorID)
+		require.NoError(t, err)
+		require.False(t, blocked)
+
+		// the block only applies to the user who added it
+		blocked, err = store.IsNotificationActorBlocked(otherActorID, actorID)
+		require.NoError(t, err)
+		require.False(t, blocked)
+	})
+
+	t.Run("unblocking", func(t *testing.T) {
+		require.NoError(t, store.RemoveNotificationActorBlock(userID, actorID))
+
+		blocked, err := store.IsNotificationActorBlocked(userID, actorID)
+		require.NoError(t, err)
+		require.False(t, blocked)
+	})
+}
+
+func testCardNotifications(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	cardID := utils.NewID(utils.IDTypeCard)
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		notification, err := store.CreateUserNotification(&model.UserNotification{
+			TargetUserID: userID,
+			Type:         model.NotificationTypeMentioned,
+			CardID:       cardID,
+			BoardID:      boardID,
+		})
+		require.NoError(t, err)
+		ids = append(ids, notification.ID)
+	}
+	otherCard := createTestUserNotification(t, store, userID, boardID)
+
+	t.Run("filters by card, newest first", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{CardID: cardID})
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		require.Equal(t, ids[1], notifications[0].ID)
+		require.Equal(t, ids[0], notifications[1].ID)
+	})
+
+	t.Run("marks only the notifications of the card as read", func(t *testing.T) {
+		count, err := store.MarkAllNotificationsAsRead(userID, model.MarkNotificationsAsReadOptions{CardID: cardID})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, count)
+
+		notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{CardID: cardID})
+		require.NoError(t, err)
+		for _, notification := range notifications {
+			require.True(t, notification.Read)
+		}
+
+		stored, err := store.GetUserNotification(otherCard.ID, userID)
+		require.NoError(t, err)
+		require.False(t, stored.Read)
+	})
+}
+
+func testUserNotificationsOrder(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	first := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	second := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	// reading the first notification makes it the most recently updated one
+	require.NoError(t, store.MarkNotificationAsRead(first.ID, userID))
+
+	testCases := []struct {
+		name     string
+		opts     model.QueryUserNotificationsOptions
+		expected []string
+	}{
+		{"created, newest first", model.QueryUserNotificationsOptions{}, []string{second.ID, first.ID}},
+		{"created, oldest first", model.QueryUserNotificationsOptions{Ascending: true}, []string{first.ID, second.ID}},
+		{"updated, newest first", model.QueryUserNotificationsOptions{OrderBy: model.NotificationOrderByUpdateAt}, []string{first.ID, second.ID}},
+		{"updated, oldest first", model.QueryUserNotificationsOptions{OrderBy: model.NotificationOrderByUpdateAt, Ascending: true}, []string{second.ID, first.ID}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifications, err := store.GetUserNotifications(userID, tc.opts)
+			require.NoError(t, err)
+			require.Len(t, notifications, 2)
+			require.Equal(t, tc.expected, []string{notifications[0].ID, notifications[1].ID})
+		})
+	}
+}
+
+func testDeleteNotificationsForUser(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherUserID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	for i := 0; i < 3; i++ {
+		createTestUserNotification(t, store, userID, boardID)
+	}
+	other := createTestUserNotification(t, store, otherUserID, boardID)
+
+	count, err := store.DeleteNotificationsForUser(userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+	require.NoError(t, err)
+	require.Empty(t, notifications)
+
+	// the notifications of other users are kept
+	notifications, err = store.GetUserNotifications(otherUserID, model.QueryUserNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, other.ID, notifications[0].ID)
+
+	count, err = store.DeleteNotificationsForUser(userID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func testNotificationEscalations(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	channels := []string{model.NotificationChannelEmail, model.NotificationChannelWebhook}
+
+	// mentions are high urgency
+	first := createTestUserNotification(t, store, userID, boardID)
+	second := createTestUserNotification(t, store, userID, boardID)
+	read := createTestUserNotification(t, store, userID, boardID)
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+	normal, err := store.CreateUserNotification(&model.UserNotification{
+		TargetUserID: userID,
+		Type:         model.NotificationTypeAssigned,
+		CardID:       utils.NewID(utils.IDTypeCard),
+		BoardID:      boardID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, model.NotificationUrgencyNormal, normal.Urgency)
+
+	before := utils.GetMillis() + 1000
+
+	t.Run("selects the unread high urgency notifications", func(t *testing.T) {
+		notifications, err := store.GetNotificationsToEscalate(before, 10)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, notification := range notifications {
+			ids = append(ids, notification.ID)
+		}
+		require.ElementsMatch(t, []string{first.ID, second.ID}, ids)
+
+		notifications, err = store.GetNotificationsToEscalate(first.CreateAt, 10)
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+
+	t.Run("escalates through the first unused channel once", func(t *testing.T) {
+		require.NoError(t, store.UpdateNotificationDeliveryStatus(first.ID, model.NotificationChannelEmail, model.NotificationDeliveryStatusSent, ""))
+
+		channel, err := store.EscalateNotification(first.ID, channels)
+		require.NoError(t, err)
+		require.Equal(t, model.NotificationChannelWebhook, channel)
+
+		deliveries, _, err := store.GetNotificationDeliveries(model.QueryNotificationDeliveriesOptions{
+			Channel: model.NotificationChannelWebhook,
+			Status:  model.NotificationDeliveryStatusPending,
+			PerPage: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, first.ID, deliveries[0].NotificationID)
+
+		channel, err = store.EscalateNotification(first.ID, channels)
+		require.NoError(t, err)
+		require.Empty(t, channel)
+	})
+
+	t.Run("escalation is recorded even without a channel left", func(t *testing.T) {
+		for _, channel := range channels {
+			require.NoError(t, store.UpdateNotificationDeliveryStatus(second.ID, channel, model.NotificationDeliveryStatusSent, ""))
+		}
+
+		channel, err := store.EscalateNotification(second.ID, channels)
+		require.NoError(t, err)
+		require.Empty(t, channel)
+
+		notifications, err := store.GetNotificationsToEscalate(before, 10)
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+}
+
+func testGetUserNotificationsMarkingRead(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		ids = append(ids, createTestUserNotification(t, store, userID, boardID).ID)
+	}
+	other := createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), boardID)
+
+	notifications, err := store.GetUserNotificationsMarkingRead(userID, model.QueryUserNotificationsOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	require.Equal(t, ids[2], notifications[0].ID)
+	require.Equal(t, ids[1], notifications[1].ID)
+	// returned as they were fetched
+	require.False(t, notifications[0].Read)
+	require.False(t, notifications[1].Read)
+
+	// only the returned page was marked as read
+	count, err := store.GetUnreadNotificationCount(userID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	notifications, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{})
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	for _, notification := range notifications {
+		require.Equal(t, notification.ID != ids[0], notification.Read, notification.ID)
+	}
+
+	count, err = store.GetUnreadNotificationCount(other.TargetUserID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func testNotificationQuery(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	otherBoardID := utils.NewID(utils.IDTypeBoard)
+
+	create := func(notifType, boardID string, read bool) *model.UserNotification {
+		notification, err := store.CreateUserNotification(&model.UserNotification{
+			TargetUserID: userID,
+			ActorUserID:  "actor-1",
+			Type:         notifType,
+			CardID:       utils.NewID(utils.IDTypeCard),
+			BoardID:      boardID,
+		})
+		require.NoError(t, err)
+		if read {
+			require.NoError(t, store.MarkNotificationAsRead(notification.ID, userID))
+		}
+		return notification
+	}
+
+	assigned := create(model.NotificationTypeAssigned, boardID, false)
+	mentioned := create(model.NotificationTypeMentioned, boardID, false)
+	create(model.NotificationTypeMentioned, boardID, true)
+	create(model.NotificationTypeMentioned, otherBoardID, false)
+	create(model.NotificationTypeStatusChanged, boardID, false)
+
+	queryIDs := func(opts model.QueryUserNotificationsOptions) []string {
+		notifications, err := store.GetUserNotifications(userID, opts)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, notification := range notifications {
+			ids = append(ids, notification.ID)
+		}
+		return ids
+	}
+
+	unread := false
+	query := &model.NotificationQuery{
+		NotificationFilter: model.NotificationFilter{BoardIDs: []string{boardID}, Read: &unread},
+		AnyOf: []model.NotificationFilter{
+			{Types: []string{model.NotificationTypeAssigned}},
+			{Types: []string{model.NotificationTypeMentioned}},
+		},
+	}
+
+	t.Run("unread assignments or mentions of a board", func(t *testing.T) {
+		ids := queryIDs(model.QueryUserNotificationsOptions{Query: query})
+		require.ElementsMatch(t, []string{assigned.ID, mentioned.ID}, ids)
+	})
+
+	t.Run("combined with the other filters", func(t *testing.T) {
+		ids := queryIDs(model.QueryUserNotificationsOptions{Query: query, Category: model.NotificationCategoryMentions})
+		require.Equal(t, []string{mentioned.ID}, ids)
+
+		ids = queryIDs(model.QueryUserNotificationsOptions{Query: query, BoardIDs: []string{otherBoardID}})
+		require.Empty(t, ids)
+	})
+
+	t.Run("actors", func(t *testing.T) {
+		ids := queryIDs(model.QueryUserNotificationsOptions{Query: &model.NotificationQuery{
+			NotificationFilter: model.NotificationFilter{ActorUserIDs: []string{"actor-2"}},
+		}})
+		require.Empty(t, ids)
+
+		ids = queryIDs(model.QueryUserNotificationsOptions{Query: &model.NotificationQuery{
+			NotificationFilter: model.NotificationFilter{ActorUserIDs: []string{"actor-1"}},
+		}})
+		require.Len(t, ids, 5)
+	})
+}
+
+func testNotificationDeviceReads(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	notification := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+
+	t.Run("only the target user can read the notification", func(t *testing.T) {
+		err := store.MarkNotificationAsReadOnDevice(notification.ID, utils.NewID(utils.IDTypeUser), "phone")
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("any device reading the notification marks it as read", func(t *testing.T) {
+		require.NoError(t, store.MarkNotificationAsReadOnDevice(notification.ID, userID, "phone"))
+
+		read, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.True(t, read.Read)
+
+		count, err := store.GetUnreadNotificationCount(userID)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("reads are tracked per device", func(t *testing.T) {
+		reads, err := store.GetNotificationDeviceReads(notification.ID)
+		require.NoError(t, err)
+		require.Len(t, reads, 1)
+		firstReadAt := reads[0].ReadAt
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, store.MarkNotificationAsReadOnDevice(notification.ID, userID, "laptop"))
+		require.NoError(t, store.MarkNotificationAsReadOnDevice(notification.ID, userID, "phone"))
+
+		reads, err = store.GetNotificationDeviceReads(notification.ID)
+		require.NoError(t, err)
+		require.Len(t, reads, 2)
+		require.Equal(t, "phone", reads[0].DeviceID)
+		require.Equal(t, firstReadAt, reads[0].ReadAt)
+		require.Equal(t, "laptop", reads[1].DeviceID)
+	})
+
+	t.Run("deleted with the notification", func(t *testing.T) {
+		require.NoError(t, store.DeleteUserNotification(notification.ID, userID))
+
+		reads, err := store.GetNotificationDeviceReads(notification.ID)
+		require.NoError(t, err)
+		require.Empty(t, reads)
+	})
+}
+
+func testGetOldestUnreadNotificationTime(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	t.Run("nothing unread", func(t *testing.T) {
+		oldest, err := store.GetOldestUnreadNotificationTime(userID)
+		require.NoError(t, err)
+		require.Zero(t, oldest)
+	})
+
+	read := createTestUserNotification(t, store, userID, boardID)
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+	time.Sleep(10 * time.Millisecond)
+	first := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	createTestUserNotification(t, store, userID, boardID)
+	createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), boardID)
+
+	t.Run("oldest unread of the user", func(t *testing.T) {
+		oldest, err := store.GetOldestUnreadNotificationTime(userID)
+		require.NoError(t, err)
+		require.Equal(t, first.CreateAt, oldest)
+	})
+
+	t.Run("archived notifications are left out", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationArchived(first.ID, userID, true))
+
+		oldest, err := store.GetOldestUnreadNotificationTime(userID)
+		require.NoError(t, err)
+		require.Greater(t, oldest, first.CreateAt)
+	})
+}
+
+func testGetUnreadNotificationCountByType(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	t.Run("nothing unread", func(t *testing.T) {
+		counts, err := store.GetUnreadNotificationCountByType(userID)
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+
+	create := func(notifType string) *model.UserNotification {
+		notification, err := store.CreateUserNotification(&model.UserNotification{
+			TargetUserID: userID,
+			ActorUserID:  "actor-1",
+			Type:         notifType,
+			CardID:       utils.NewID(utils.IDTypeCard),
+			BoardID:      boardID,
+		})
+		require.NoError(t, err)
+		return notification
+	}
+	create(model.NotificationTypeMentioned)
+	create(model.NotificationTypeMentioned)
+	create(model.NotificationTypeAssigned)
+	read := create(model.NotificationTypeStatusChanged)
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+	archived := create(model.NotificationTypeAssigned)
+	require.NoError(t, store.SetNotificationArchived(archived.ID, userID, true))
+	createTestUserNotification(t, store, utils.NewID(utils.IDTypeUser), boardID)
+
+	counts, err := store.GetUnreadNotificationCountByType(userID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		model.NotificationTypeMentioned: 2,
+		model.NotificationTypeAssigned:  1,
+	}, counts)
+}
+
+func testUserNotificationReason(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+
+	created, err := store.CreateUserNotifications([]*model.UserNotification{
+		{TargetUserID: userID, Type: model.NotificationTypeStatusChanged, Reason: model.NotificationReasonWatcher},
+		{TargetUserID: userID, Type: model.NotificationTypeMentioned},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+
+	notification, err := store.GetUserNotification(created[0].ID, userID)
+	require.NoError(t, err)
+	require.Equal(t, model.NotificationReasonWatcher, notification.Reason)
+
+	notification, err = store.GetUserNotification(created[1].ID, userID)
+	require.NoError(t, err)
+	require.Empty(t, notification.Reason)
+}
+
+func testGetUnreadCountByBoard(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardIDs := []string{utils.NewID(utils.IDTypeBoard), utils.NewID(utils.IDTypeBoard)}
+	sort.Strings(boardIDs)
+
+	createTestUserNotification(t, store, userID, boardIDs[0])
+	createTestUserNotification(t, store, userID, boardIDs[0])
+	read := createTestUserNotification(t, store, userID, boardIDs[1])
+	require.NoError(t, store.MarkNotificationAsRead(read.ID, userID))
+	createTestUserNotification(t, store, userID, "")
+
+	counts, err := store.GetUnreadCountByBoard(userID)
+	require.NoError(t, err)
+	require.Equal(t, []*model.NotificationBoardUnreadCount{
+		{BoardID: boardIDs[0], Count: 2},
+		{BoardID: boardIDs[1], Count: 0},
+	}, counts)
+
+	counts, err = store.GetUnreadCountByBoard(utils.NewID(utils.IDTypeUser))
+	require.NoError(t, err)
+	require.Empty(t, counts)
+}
+
+func testGetUserNotificationsCursor(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	ids := func(notifications []*model.UserNotification) []string {
+		result := make([]string, len(notifications))
+		for i, notification := range notifications {
+			result[i] = notification.ID
+		}
+		return result
+	}
+
+	// created in a batch, so they share their creation time and only their IDs order them
+	batch := make([]*model.UserNotification, 5)
+	for i := range batch {
+		batch[i] = &model.UserNotification{TargetUserID: userID, Type: model.NotificationTypeMentioned}
+	}
+	_, err := store.CreateUserNotifications(batch)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	require.NoError(t, store.SetNotificationPinned(batch[2].ID, userID, true))
+
+	for _, ascending := range []bool{false, true} {
+		all, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{Ascending: ascending})
+		require.NoError(t, err)
+		require.Len(t, all, 6)
+		require.Equal(t, batch[2].ID, all[0].ID)
+
+		paged := []*model.UserNotification{}
+		opts := model.QueryUserNotificationsOptions{Limit: 2, Ascending: ascending}
+		for {
+			notifications, err := store.GetUserNotifications(userID, opts)
+			require.NoError(t, err)
+			if len(notifications) == 0 {
+				break
+			}
+			paged = append(paged, notifications...)
+			opts.Cursor = model.NewNotificationsCursor(notifications[len(notifications)-1], "")
+		}
+		require.Equal(t, ids(all), ids(paged))
+	}
+
+	_, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{Cursor: "invalid"})
+	require.True(t, model.IsErrBadRequest(err))
+}
+
+func testSearchUserNotifications(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	otherUserID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	ids := func(notifications []*model.UserNotification) []string {
+		result := make([]string, len(notifications))
+		for i, notification := range notifications {
+			result[i] = notification.ID
+		}
+		return result
+	}
+
+	created, err := store.CreateUserNotifications([]*model.UserNotification{
+		{TargetUserID: userID, Type: model.NotificationTypeMentioned, BoardID: boardID, Source: model.NotificationSourceAPI},
+		{TargetUserID: userID, Type: model.NotificationTypeAssigned, BoardID: boardID, Source: model.NotificationSourceServer},
+		{TargetUserID: otherUserID, Type: model.NotificationTypeMentioned, Source: model.NotificationSourceAPI},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 3)
+
+	t.Run("by source", func(t *testing.T) {
+		notifications, hasNext, err := store.SearchUserNotifications(model.SearchUserNotificationsOptions{Source: model.NotificationSourceAPI})
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.ElementsMatch(t, []string{created[0].ID, created[2].ID}, ids(notifications))
+		for _, notification := range notifications {
+			require.Equal(t, model.NotificationSourceAPI, notification.Source)
+		}
+	})
+
+	t.Run("by user, board and type", func(t *testing.T) {
+		notifications, _, err := store.SearchUserNotifications(model.SearchUserNotificationsOptions{UserID: userID, BoardID: boardID})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{created[0].ID, created[1].ID}, ids(notifications))
+
+		notifications, _, err = store.SearchUserNotifications(model.SearchUserNotificationsOptions{UserID: userID, Type: model.NotificationTypeAssigned})
+		require.NoError(t, err)
+		require.Equal(t, []string{created[1].ID}, ids(notifications))
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		opts := model.SearchUserNotificationsOptions{UserID: userID, PerPage: 1}
+		first, hasNext, err := store.SearchUserNotifications(opts)
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Len(t, first, 1)
+
+		opts.Page = 1
+		second, hasNext, err := store.SearchUserNotifications(opts)
+		require.NoError(t, err)
+		require.False(t, hasNext)
+		require.Len(t, second, 1)
+		require.ElementsMatch(t, []string{created[0].ID, created[1].ID}, append(ids(first), ids(second)...))
+	})
+}
+
+func testGetUnreadNotificationByCollapseKey(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	actorID := utils.NewID(utils.IDTypeUser)
+
+	created, err := store.CreateUserNotifications([]*model.UserNotification{
+		{TargetUserID: userID, ActorUserID: actorID, Source: model.NotificationSourceServer, Type: model.NotificationTypeMentioned, CardTitle: "Launch", CollapseKey: "card-updates"},
+		{TargetUserID: userID, ActorUserID: actorID, Source: model.NotificationSourceServer, Type: model.NotificationTypeMentioned, CollapseKey: "other"},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+
+	t.Run("other actors and sources", func(t *testing.T) {
+		_, err := store.GetUnreadNotificationByCollapseKey(userID, "card-updates", utils.NewID(utils.IDTypeUser), model.NotificationSourceServer)
+		require.True(t, model.IsErrNotFound(err))
+
+		_, err = store.GetUnreadNotificationByCollapseKey(userID, "card-updates", actorID, model.NotificationSourceAPI)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	notification, err := store.GetUnreadNotificationByCollapseKey(userID, "card-updates", actorID, model.NotificationSourceServer)
+	require.NoError(t, err)
+	require.Equal(t, created[0].ID, notification.ID)
+	require.Equal(t, "card-updates", notification.CollapseKey)
+
+	t.Run("updated in place", func(t *testing.T) {
+		notification.ActorName = "bob"
+		notification.CardTitle = "Launch v2"
+		notification.Params = map[string]string{"count": "5"}
+		require.NoError(t, store.UpdateUserNotification(notification))
+
+		updated, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.Equal(t, "bob", updated.ActorName)
+		require.Equal(t, "Launch v2", updated.CardTitle)
+		require.Equal(t, map[string]string{"count": "5"}, updated.Params)
+	})
+
+	t.Run("archived notifications are not replaced", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationArchived(created[1].ID, userID, true))
+
+		_, err := store.GetUnreadNotificationByCollapseKey(userID, "other", actorID, model.NotificationSourceServer)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("read notifications are not replaced", func(t *testing.T) {
+		require.NoError(t, store.MarkNotificationAsRead(created[0].ID, userID))
+
+		_, err := store.GetUnreadNotificationByCollapseKey(userID, "card-updates", actorID, model.NotificationSourceServer)
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	t.Run("other users", func(t *testing.T) {
+		_, err := store.GetUnreadNotificationByCollapseKey(utils.NewID(utils.IDTypeUser), "other", actorID, model.NotificationSourceServer)
+		require.True(t, model.IsErrNotFound(err))
+	})
+}
+
+func testGetUserNotificationsBefore(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+
+	oldest := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	middle := createTestUserNotification(t, store, userID, boardID)
+	time.Sleep(10 * time.Millisecond)
+	newest := createTestUserNotification(t, store, userID, boardID)
+
+	notifications, err := store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{Before: newest.CreateAt})
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	require.Equal(t, middle.ID, notifications[0].ID)
+	require.Equal(t, oldest.ID, notifications[1].ID)
+
+	notifications, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{Before: middle.CreateAt, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, oldest.ID, notifications[0].ID)
+
+	notifications, err = store.GetUserNotifications(userID, model.QueryUserNotificationsOptions{Before: oldest.CreateAt})
+	require.NoError(t, err)
+	require.Empty(t, notifications)
+}
+
+func testGetUserNotificationThreads(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	boardID := utils.NewID(utils.IDTypeBoard)
+	cardID := utils.NewID(utils.IDTypeCard)
+
+	create := func(cardID string) *model.UserNotification {
+		notification, err := store.CreateUserNotification(&model.UserNotification{
+			TargetUserID: userID,
+			ActorUserID:  utils.NewID(utils.IDTypeUser),
+			ActorName:    "actor",
+			Type:         model.NotificationTypeMentioned,
+			CardID:       cardID,
+			BoardID:      boardID,
+		})
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		return notification
+	}
+
+	oldestOnCard := create(cardID)
+	withoutCard := create("")
+	newestOnCard := create(cardID)
+	newestWithoutCard := create("")
+
+	t.Run("notifications without a card are threads of their own", func(t *testing.T) {
+		notifications, err := store.GetUserNotificationThreads(userID, model.QueryUserNotificationsOptions{}, 0)
+		require.NoError(t, err)
+		require.Len(t, notifications, 4)
+		require.Equal(t, newestWithoutCard.ID, notifications[0].ID)
+		require.Equal(t, oldestOnCard.ID, notifications[3].ID)
+	})
+
+	t.Run("limit caps the threads", func(t *testing.T) {
+		notifications, err := store.GetUserNotificationThreads(userID, model.QueryUserNotificationsOptions{}, 2)
+		require.NoError(t, err)
+		require.Len(t, notifications, 3)
+		require.Equal(t, newestWithoutCard.ID, notifications[0].ID)
+		require.Equal(t, newestOnCard.ID, notifications[1].ID)
+		require.Equal(t, oldestOnCard.ID, notifications[2].ID)
+
+		for _, notification := range notifications {
+			require.NotEqual(t, withoutCard.ID, notification.ID)
+		}
+	})
+
+	t.Run("filters apply to the threads", func(t *testing.T) {
+		require.NoError(t, store.SetNotificationArchived(newestWithoutCard.ID, userID, true))
+
+		notifications, err := store.GetUserNotificationThreads(userID, model.QueryUserNotificationsOptions{}, 1)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		require.Equal(t, newestOnCard.ID, notifications[0].ID)
+	})
+}
+
+func testMarkNotificationAsUnread(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	notification := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	require.NoError(t, store.MarkNotificationAsRead(notification.ID, userID))
+
+	t.Run("another user cannot mark it as unread", func(t *testing.T) {
+		require.NoError(t, store.MarkNotificationAsUnread(notification.ID, utils.NewID(utils.IDTypeUser)))
+
+		got, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.True(t, got.Read)
+	})
+
+	t.Run("the target user marks it as unread", func(t *testing.T) {
+		read, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		require.NoError(t, store.MarkNotificationAsUnread(notification.ID, userID))
+
+		got, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.False(t, got.Read)
+		require.Greater(t, got.UpdateAt, read.UpdateAt)
+	})
+}
+
+func testMarkNotificationsAsRead(t *testing.T, store store.Store) {
+	userID := utils.NewID(utils.IDTypeUser)
+	first := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	second := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	alreadyRead := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	untouched := createTestUserNotification(t, store, userID, utils.NewID(utils.IDTypeBoard))
+	require.NoError(t, store.MarkNotificationAsRead(alreadyRead.ID, userID))
+	otherUserID := utils.NewID(utils.IDTypeUser)
+	other := createTestUserNotification(t, store, otherUserID, utils.NewID(utils.IDTypeBoard))
+
+	count, err := store.MarkNotificationsAsRead([]string{first.ID, second.ID, alreadyRead.ID, other.ID}, userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	for _, notification := range []*model.UserNotification{first, second} {
+		got, err := store.GetUserNotification(notification.ID, userID)
+		require.NoError(t, err)
+		require.True(t, got.Read)
+	}
+
+	got, err := store.GetUserNotification(untouched.ID, userID)
+	require.NoError(t, err)
+	require.False(t, got.Read)
+
+	got, err = store.GetUserNotification(other.ID, otherUserID)
+	require.NoError(t, err)
+	require.False(t, got.Read)
+
+	count, err = store.MarkNotificationsAsRead(nil, userID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
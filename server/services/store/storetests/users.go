@@ -52,6 +52,18 @@ func StoreTestUserStore(t *testing.T, setup func(t *testing.T) (store.Store, fun
 		defer tearDown()
 		testPatchUserProps(t, store)
 	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testDeleteUser(t, store)
+	})
+
+	t.Run("GetUsersPage", func(t *testing.T) {
+		store, tearDown := setup(t)
+		defer tearDown()
+		testGetUsersPage(t, store)
+	})
 }
 
 func testGetUsersByTeam(t *testing.T, store store.Store) {
@@ -136,6 +148,12 @@ func testCreateAndGetUser(t *testing.T, store store.Store) {
 		require.Equal(t, user.Email, got.Email)
 	})
 
+	t.Run("GetUserByEmail ignores casing", func(t *testing.T) {
+		got, err := store.GetUserByEmail(" MOCK@Email.com ")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, got.ID)
+	})
+
 	t.Run("GetUserByEmail nonexistent", func(t *testing.T) {
 		got, err := store.GetUserByID("nonexistent-email")
 		var nf *model.ErrNotFound
@@ -343,3 +361,159 @@ func testPatchUserProps(t *testing.T, store store.Store) {
 		}
 	}
 }
+
+func testDeleteUser(t *testing.T, store store.Store) {
+	user, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "deleted.user",
+	})
+	require.NoError(t, err)
+
+	otherUser, err := store.CreateUser(&model.User{
+		ID:       utils.NewID(utils.IDTypeUser),
+		Username: "other.user",
+	})
+	require.NoError(t, err)
+
+	boardID := utils.NewID(utils.IDTypeBoard)
+	for _, userID := range []string{user.ID, otherUser.ID} {
+		_, err = store.SaveMember(&model.BoardMember{
+			BoardID:      boardID,
+			UserID:       userID,
+			SchemeEditor: true,
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = store.CreateUserNotification(&model.UserNotification{
+		TargetUserID: user.ID,
+		ActorUserID:  otherUser.ID,
+		ActorName:    otherUser.Username,
+		Type:         model.NotificationTypeMentioned,
+		BoardID:      boardID,
+	})
+	require.NoError(t, err)
+
+	authored, err := store.CreateUserNotification(&model.UserNotification{
+		TargetUserID: otherUser.ID,
+		ActorUserID:  user.ID,
+		ActorName:    user.Username,
+		Type:         model.NotificationTypeMentioned,
+		BoardID:      boardID,
+	})
+	require.NoError(t, err)
+
+	comment := &model.Block{
+		ID:       utils.NewID(utils.IDTypeBlock),
+		BoardID:  boardID,
+		ParentID: utils.NewID(utils.IDTypeCard),
+		Type:     model.TypeComment,
+		Title:    "a comment",
+	}
+	require.NoError(t, store.InsertBlock(comment, user.ID))
+
+	t.Run("summary counts what would be removed without deleting", func(t *testing.T) {
+		summary, err := store.GetUserDeletionSummary(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, &model.UserDeletionSummary{
+			UserID:                user.ID,
+			BoardMemberships:      1,
+			Notifications:         1,
+			AuthoredNotifications: 1,
+			AuthoredComments:      1,
+		}, summary)
+
+		members, err := store.GetMembersForUser(user.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+
+		_, err = store.GetUserDeletionSummary(utils.NewID(utils.IDTypeUser))
+		require.True(t, model.IsErrNotFound(err))
+	})
+
+	require.NoError(t, store.DeleteUser(user.ID))
+
+	t.Run("memberships are removed", func(t *testing.T) {
+		members, err := store.GetMembersForUser(user.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+
+		members, err = store.GetMembersForBoard(boardID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, otherUser.ID, members[0].UserID)
+	})
+
+	t.Run("received notifications are removed", func(t *testing.T) {
+		notifications, err := store.GetUserNotifications(user.ID, model.QueryUserNotificationsOptions{IncludeArchived: true})
+		require.NoError(t, err)
+		require.Empty(t, notifications)
+	})
+
+	t.Run("authored notifications are anonymized", func(t *testing.T) {
+		notification, err := store.GetUserNotification(authored.ID, otherUser.ID)
+		require.NoError(t, err)
+		require.Empty(t, notification.ActorUserID)
+		require.Empty(t, notification.ActorName)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		err := store.DeleteUser(utils.NewID(utils.IDTypeUser))
+		require.Error(t, err)
+	})
+}
+
+func testGetUsersPage(t *testing.T, store store.Store) {
+	for i := 0; i < 5; i++ {
+		_, err := store.CreateUser(&model.User{
+			ID:       utils.NewID(utils.IDTypeUser),
+			Username: fmt.Sprintf("paged-user-%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	all, err := store.GetAllUsers()
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+
+	t.Run("pages by number and by cursor match", func(t *testing.T) {
+		var byNumber, byCursor []string
+		for page := 0; ; page++ {
+			users, more, err := store.GetUsersPage(model.QueryUsersOptions{Page: page, PerPage: 2})
+			require.NoError(t, err)
+			for _, user := range users {
+				byNumber = append(byNumber, user.ID)
+			}
+			if !more {
+				break
+			}
+		}
+
+		cursor := ""
+		for {
+			users, more, err := store.GetUsersPage(model.QueryUsersOptions{Cursor: cursor, PerPage: 2})
+			require.NoError(t, err)
+			for _, user := range users {
+				byCursor = append(byCursor, user.ID)
+				require.Empty(t, user.Password)
+			}
+			if !more {
+				break
+			}
+			cursor = model.NewUsersCursor(users[len(users)-1])
+		}
+
+		require.Len(t, byNumber, 5)
+		require.Equal(t, byNumber, byCursor)
+	})
+
+	t.Run("deep pages need a cursor", func(t *testing.T) {
+		_, _, err := store.GetUsersPage(model.QueryUsersOptions{Page: 1000, PerPage: 100})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, _, err := store.GetUsersPage(model.QueryUsersOptions{Cursor: "invalid", PerPage: 2})
+		require.True(t, model.IsErrBadRequest(err))
+	})
+}
@@ -13,3 +13,8 @@ func MakeCardLink(serverRoot string, teamID string, boardID string, cardID strin
 func MakeBoardLink(serverRoot string, teamID string, board string) string {
 	return fmt.Sprintf("%s/team/%s/%s", serverRoot, teamID, board)
 }
+
+// MakeAvatarLink creates the fully qualified link to a user's avatar.
+func MakeAvatarLink(serverRoot string, userID string) string {
+	return fmt.Sprintf("%s/api/v2/users/%s/avatar", serverRoot, userID)
+}
@@ -23,6 +23,7 @@ const (
 	IDTypeToken      IDType = 'k'
 	IDTypeBlock      IDType = 'a'
 	IDTypeAttachment IDType = 'i'
+	IDTypeEphemeral  IDType = 'e'
 )
 
 // NewId is a globally unique identifier.  It is a [A-Z0-9] string 27
@@ -33,6 +34,11 @@ func NewID(idType IDType) string {
 	return string(idType) + mmModel.NewId()
 }
 
+// IsValidID returns true if id has the format of an ID created by NewID for idType.
+func IsValidID(id string, idType IDType) bool {
+	return len(id) == 27 && id[0] == byte(idType) && mmModel.IsValidId(id[1:])
+}
+
 // GetMillis is a convenience method to get milliseconds since epoch.
 func GetMillis() int64 {
 	return mmModel.GetMillis()
@@ -23,11 +23,19 @@ const (
 	websocketActionReorderCategories        = "REORDER_CATEGORIES"
 	websocketActionReorderCategoryBoards    = "REORDER_CATEGORY_BOARDS"
 	websocketActionUserNotification         = "USER_NOTIFICATION"
+	websocketActionCatchUpNotifications     = "CATCH_UP_NOTIFICATIONS"
+	websocketActionNotificationsCaughtUp    = "USER_NOTIFICATIONS_CAUGHT_UP"
+	websocketActionUpdateUnreadCount        = "UPDATE_NOTIFICATION_UNREAD_COUNT"
 )
 
+// notificationCatchUpLimit is the maximum number of notifications replayed
+// to a reconnecting client. Clients told there are more should refetch them.
+const notificationCatchUpLimit = 100
+
 type Store interface {
 	GetBlock(blockID string) (*model.Block, error)
 	GetMembersForBoard(boardID string) ([]*model.BoardMember, error)
+	GetUserNotifications(userID string, opts model.QueryUserNotificationsOptions) ([]*model.UserNotification, error)
 }
 
 type Adapter interface {
@@ -45,4 +53,8 @@ type Adapter interface {
 	BroadcastCategoryReorder(teamID, userID string, categoryOrder []string)
 	BroadcastCategoryBoardsReorder(teamID, userID, categoryID string, boardsOrder []string)
 	BroadcastUserNotification(targetUserID string, notification *model.UserNotification)
+	BroadcastUnreadNotificationCount(userID string, count int, countsByType map[string]int)
+	ConnectedUserIDs() []string
+	BroadcastAvatarChange(userID string)
+	OnAvatarChange(handler func(userID string))
 }
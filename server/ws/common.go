@@ -58,6 +58,7 @@ type WebsocketCommand struct {
 	Token     string   `json:"token"`
 	ReadToken string   `json:"readToken"`
 	BlockIDs  []string `json:"blockIds"`
+	Since     int64    `json:"since"`
 }
 
 type CategoryReorderMessage struct {
@@ -78,3 +79,53 @@ type UserNotificationMsg struct {
 	Action       string                  `json:"action"`
 	Notification *model.UserNotification `json:"notification"`
 }
+
+// UnreadNotificationCountMsg is sent when the number of unread notifications of a user
+// changed without a new notification, e.g. when notifications were deleted. The count of
+// each notification type is only sent if the server is configured to.
+type UnreadNotificationCountMsg struct {
+	Action       string         `json:"action"`
+	Count        int            `json:"count"`
+	CountsByType map[string]int `json:"countsByType,omitempty"`
+}
+
+// NotificationsCaughtUpMsg is sent after the notifications missed by a
+// reconnecting client have been replayed.
+type NotificationsCaughtUpMsg struct {
+	Action  string `json:"action"`
+	Count   int    `json:"count"`
+	HasMore bool   `json:"hasMore"`
+}
+
+// canReceiveNotification returns true if a connection authenticated as connUserID may
+// be sent the notification. The send paths check it so that even a miswired target never
+// delivers a notification to a connection of another user.
+func canReceiveNotification(connUserID string, notification *model.UserNotification) bool {
+	return connUserID != "" && notification != nil && notification.TargetUserID == connUserID
+}
+
+// getMissedNotifications returns, oldest first, the notifications of the user
+// created after since, up to notificationCatchUpLimit of them, and whether
+// there were more to replay.
+func getMissedNotifications(store Store, userID string, since int64) ([]*model.UserNotification, bool, error) {
+	opts := model.QueryUserNotificationsOptions{
+		Since: since,
+		Limit: notificationCatchUpLimit + 1,
+	}
+	notifications, err := store.GetUserNotifications(userID, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the store returns the newest first; keep the ones closest to since so
+	// the client can continue from the last one it receives
+	hasMore := len(notifications) > notificationCatchUpLimit
+	if hasMore {
+		notifications = notifications[len(notifications)-notificationCatchUpLimit:]
+	}
+
+	for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+		notifications[i], notifications[j] = notifications[j], notifications[i]
+	}
+	return notifications, hasMore, nil
+}
@@ -47,6 +47,9 @@ type PluginAdapter struct {
 	subscriptionsMU  sync.RWMutex
 	listenersByTeam  map[string][]*PluginAdapterClient
 	listenersByBlock map[string][]*PluginAdapterClient
+
+	avatarChangeMU       sync.RWMutex
+	avatarChangeHandlers []func(userID string)
 }
 
 // servicesAPI is the interface required by the PluginAdapter to interact with
@@ -308,12 +311,25 @@ func (pa *PluginAdapter) OnWebSocketDisconnect(webConnID, userID string) {
 func commandFromRequest(req *mmModel.WebSocketRequest) (*WebsocketCommand, error) {
 	c := &WebsocketCommand{Action: strings.TrimPrefix(req.Action, websocketMessagePrefix)}
 
+	// catching up on notifications is the only command that isn't
+	// scoped to a team
 	if teamID, ok := req.Data["teamId"]; ok {
 		c.TeamID = teamID.(string)
-	} else {
+	} else if c.Action != websocketActionCatchUpNotifications {
 		return nil, errMissingTeamInCommand
 	}
 
+	if since, ok := req.Data["since"]; ok {
+		switch v := since.(type) {
+		case float64:
+			c.Since = int64(v)
+		case int64:
+			c.Since = v
+		case int:
+			c.Since = int64(v)
+		}
+	}
+
 	if readToken, ok := req.Data["readToken"]; ok {
 		c.ReadToken = readToken.(string)
 	}
@@ -385,9 +401,58 @@ func (pa *PluginAdapter) WebSocketMessageHasBeenPosted(webConnID, userID string,
 		)
 
 		pa.unsubscribeListenerFromTeam(pac, command.TeamID)
+	case websocketActionCatchUpNotifications:
+		pa.logger.Debug(`Command: CATCH_UP_NOTIFICATIONS`,
+			mlog.String("webConnID", webConnID),
+			mlog.String("userID", userID),
+			mlog.Int("since", command.Since),
+		)
+
+		pa.catchUpNotifications(webConnID, userID, command.Since)
 	}
 }
 
+// catchUpNotifications replays to a single connection the notifications its
+// user received after since, which the client missed while it was disconnected.
+func (pa *PluginAdapter) catchUpNotifications(webConnID, userID string, since int64) {
+	if since <= 0 {
+		return
+	}
+
+	notifications, hasMore, err := getMissedNotifications(pa.store, userID, since)
+	if err != nil {
+		pa.logger.Error("error getting missed notifications",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	broadcast := &mmModel.WebsocketBroadcast{UserId: userID, ConnectionId: webConnID}
+	for _, notification := range notifications {
+		if !canReceiveNotification(userID, notification) {
+			pa.logger.Error("Dropping catch up notification targeting another user",
+				mlog.String("userID", userID),
+				mlog.String("notificationID", notification.ID),
+			)
+			continue
+		}
+
+		message := UserNotificationMsg{
+			Action:       websocketActionUserNotification,
+			Notification: notification,
+		}
+		pa.api.PublishWebSocketEvent(websocketMessagePrefix+websocketActionUserNotification, utils.StructToMap(message), broadcast)
+	}
+
+	message := NotificationsCaughtUpMsg{
+		Action:  websocketActionNotificationsCaughtUp,
+		Count:   len(notifications),
+		HasMore: hasMore,
+	}
+	pa.api.PublishWebSocketEvent(websocketMessagePrefix+websocketActionNotificationsCaughtUp, utils.StructToMap(message), broadcast)
+}
+
 // sendMessageToAll will send a websocket message to all clients on all nodes.
 func (pa *PluginAdapter) sendMessageToAll(event string, payload map[string]interface{}) {
 	// Empty &mmModel.WebsocketBroadcast will send to all users
@@ -680,6 +745,16 @@ func (pa *PluginAdapter) BroadcastUserNotification(targetUserID string, notifica
 		mlog.String("type", notification.Type),
 	)
 
+	// the server routes the event to the connections of targetUserID, make sure that is
+	// the user the notification is for
+	if !canReceiveNotification(targetUserID, notification) {
+		pa.logger.Error("Dropping user notification targeting another user",
+			mlog.String("targetUserID", targetUserID),
+			mlog.String("notificationID", notification.ID),
+		)
+		return
+	}
+
 	message := UserNotificationMsg{
 		Action:       websocketActionUserNotification,
 		Notification: notification,
@@ -691,3 +766,37 @@ func (pa *PluginAdapter) BroadcastUserNotification(targetUserID string, notifica
 		&mmModel.WebsocketBroadcast{UserId: targetUserID},
 	)
 }
+
+func (pa *PluginAdapter) BroadcastUnreadNotificationCount(userID string, count int, countsByType map[string]int) {
+	pa.logger.Debug("BroadcastUnreadNotificationCount",
+		mlog.String("userID", userID),
+		mlog.Int("count", count),
+	)
+
+	message := UnreadNotificationCountMsg{
+		Action:       websocketActionUpdateUnreadCount,
+		Count:        count,
+		CountsByType: countsByType,
+	}
+
+	pa.api.PublishWebSocketEvent(
+		websocketMessagePrefix+websocketActionUpdateUnreadCount,
+		utils.StructToMap(message),
+		&mmModel.WebsocketBroadcast{UserId: userID},
+	)
+}
+
+// ConnectedUserIDs returns the users with at least one websocket connection to this node.
+// Users only connected to other nodes of the cluster are not included.
+func (pa *PluginAdapter) ConnectedUserIDs() []string {
+	pa.listenersMU.RLock()
+	defer pa.listenersMU.RUnlock()
+
+	userIDs := make([]string, 0, len(pa.listenersByUserID))
+	for userID, listeners := range pa.listenersByUserID {
+		if len(listeners) > 0 {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
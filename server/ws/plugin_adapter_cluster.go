@@ -7,6 +7,11 @@ import (
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 )
 
+const (
+	clusterEventWebsocketMessage = "websocket_message"
+	clusterEventAvatarChanged    = "avatar_changed"
+)
+
 type ClusterMessage struct {
 	TeamID      string
 	BoardID     string
@@ -16,7 +21,7 @@ type ClusterMessage struct {
 }
 
 func (pa *PluginAdapter) sendMessageToCluster(clusterMessage *ClusterMessage) {
-	const id = "websocket_message"
+	const id = clusterEventWebsocketMessage
 	b, err := json.Marshal(clusterMessage)
 	if err != nil {
 		pa.logger.Error("couldn't get JSON bytes from cluster message",
@@ -42,6 +47,11 @@ func (pa *PluginAdapter) sendMessageToCluster(clusterMessage *ClusterMessage) {
 func (pa *PluginAdapter) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
 	pa.logger.Debug("received cluster event", mlog.String("id", ev.Id))
 
+	if ev.Id == clusterEventAvatarChanged {
+		pa.handleAvatarChangeEvent(ev)
+		return
+	}
+
 	var clusterMessage ClusterMessage
 	if err := json.Unmarshal(ev.Data, &clusterMessage); err != nil {
 		pa.logger.Error("cannot unmarshal cluster message data",
@@ -78,3 +88,59 @@ func (pa *PluginAdapter) HandleClusterEvent(ev mmModel.PluginClusterEvent) {
 
 	pa.sendTeamMessageSkipCluster(action, clusterMessage.TeamID, clusterMessage.Payload)
 }
+
+// AvatarChangeMessage tells the other nodes of the cluster that the avatar of a user
+// changed.
+type AvatarChangeMessage struct {
+	UserID string `json:"userId"`
+}
+
+// BroadcastAvatarChange tells the other nodes of the cluster that the avatar of a user was
+// uploaded or deleted, so they evict it from their caches.
+func (pa *PluginAdapter) BroadcastAvatarChange(userID string) {
+	b, err := json.Marshal(AvatarChangeMessage{UserID: userID})
+	if err != nil {
+		pa.logger.Error("couldn't get JSON bytes from avatar change message",
+			mlog.String("userID", userID),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	event := mmModel.PluginClusterEvent{Id: clusterEventAvatarChanged, Data: b}
+	opts := mmModel.PluginClusterEventSendOptions{
+		SendType: mmModel.PluginClusterEventSendTypeReliable,
+	}
+
+	if err := pa.api.PublishPluginClusterEvent(event, opts); err != nil {
+		pa.logger.Error("error publishing cluster event",
+			mlog.String("id", clusterEventAvatarChanged),
+			mlog.Err(err),
+		)
+	}
+}
+
+// OnAvatarChange registers a handler called with the user ID of every avatar change
+// received from the other nodes of the cluster.
+func (pa *PluginAdapter) OnAvatarChange(handler func(userID string)) {
+	pa.avatarChangeMU.Lock()
+	defer pa.avatarChangeMU.Unlock()
+	pa.avatarChangeHandlers = append(pa.avatarChangeHandlers, handler)
+}
+
+func (pa *PluginAdapter) handleAvatarChangeEvent(ev mmModel.PluginClusterEvent) {
+	var message AvatarChangeMessage
+	if err := json.Unmarshal(ev.Data, &message); err != nil || message.UserID == "" {
+		pa.logger.Error("cannot unmarshal avatar change message data",
+			mlog.String("id", ev.Id),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	pa.avatarChangeMU.RLock()
+	defer pa.avatarChangeMU.RUnlock()
+	for _, handler := range pa.avatarChangeHandlers {
+		handler(message.UserID)
+	}
+}
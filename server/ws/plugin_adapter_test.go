@@ -8,6 +8,7 @@ import (
 
 	mmModel "github.com/mattermost/mattermost/server/public/model"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -551,3 +552,128 @@ func TestParallelSubscriptionsOnMultipleConnections(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPluginAdapterCatchUpNotifications(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	webConnID := mmModel.NewId()
+	userID := mmModel.NewId()
+	th.pa.OnWebSocketConnect(webConnID, userID)
+
+	broadcast := &mmModel.WebsocketBroadcast{UserId: userID, ConnectionId: webConnID}
+
+	t.Run("missed notifications are replayed oldest first", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{Since: 100, Limit: notificationCatchUpLimit + 1}
+		th.store.EXPECT().
+			GetUserNotifications(userID, opts).
+			Return([]*model.UserNotification{
+				{ID: "notification-2", TargetUserID: userID, CreateAt: 300},
+				{ID: "notification-1", TargetUserID: userID, CreateAt: 200},
+			}, nil)
+
+		var replayed []string
+		th.api.EXPECT().
+			PublishWebSocketEvent(websocketMessagePrefix+websocketActionUserNotification, gomock.Any(), broadcast).
+			Do(func(_ string, payload map[string]interface{}, _ *mmModel.WebsocketBroadcast) {
+				notification := payload["notification"].(map[string]interface{})
+				replayed = append(replayed, notification["id"].(string))
+			}).
+			Times(2)
+		th.api.EXPECT().
+			PublishWebSocketEvent(websocketMessagePrefix+websocketActionNotificationsCaughtUp, gomock.Any(), broadcast).
+			Do(func(_ string, payload map[string]interface{}, _ *mmModel.WebsocketBroadcast) {
+				require.EqualValues(t, 2, payload["count"])
+				require.Equal(t, false, payload["hasMore"])
+			})
+
+		th.ReceiveWebSocketMessage(webConnID, userID, websocketActionCatchUpNotifications, map[string]interface{}{"since": 100})
+		require.Equal(t, []string{"notification-1", "notification-2"}, replayed)
+	})
+
+	t.Run("notifications of other users are not replayed", func(t *testing.T) {
+		opts := model.QueryUserNotificationsOptions{Since: 100, Limit: notificationCatchUpLimit + 1}
+		th.store.EXPECT().
+			GetUserNotifications(userID, opts).
+			Return([]*model.UserNotification{
+				{ID: "notification-2", TargetUserID: "other-user", CreateAt: 300},
+				{ID: "notification-1", TargetUserID: userID, CreateAt: 200},
+			}, nil)
+
+		th.api.EXPECT().
+			PublishWebSocketEvent(websocketMessagePrefix+websocketActionUserNotification, gomock.Any(), broadcast).
+			Do(func(_ string, payload map[string]interface{}, _ *mmModel.WebsocketBroadcast) {
+				notification := payload["notification"].(map[string]interface{})
+				require.Equal(t, "notification-1", notification["id"])
+			})
+		th.api.EXPECT().
+			PublishWebSocketEvent(websocketMessagePrefix+websocketActionNotificationsCaughtUp, gomock.Any(), broadcast)
+
+		th.ReceiveWebSocketMessage(webConnID, userID, websocketActionCatchUpNotifications, map[string]interface{}{"since": 100})
+	})
+
+	t.Run("nothing is replayed without a cursor", func(t *testing.T) {
+		th.ReceiveWebSocketMessage(webConnID, userID, websocketActionCatchUpNotifications, map[string]interface{}{})
+	})
+}
+
+func TestPluginAdapterBroadcastUserNotification(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	t.Run("notification is sent to its target", func(t *testing.T) {
+		notification := &model.UserNotification{ID: "notification-1", TargetUserID: "user-b"}
+		th.api.EXPECT().
+			PublishWebSocketEvent(websocketMessagePrefix+websocketActionUserNotification, gomock.Any(), &mmModel.WebsocketBroadcast{UserId: "user-b"})
+
+		th.pa.BroadcastUserNotification("user-b", notification)
+	})
+
+	t.Run("notification is never sent to another user", func(t *testing.T) {
+		// no PublishWebSocketEvent call is expected
+		notification := &model.UserNotification{ID: "notification-1", TargetUserID: "user-b"}
+		th.pa.BroadcastUserNotification("user-a", notification)
+	})
+}
+
+func TestPluginAdapterAvatarChange(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	t.Run("changes are published to the cluster", func(t *testing.T) {
+		th.api.EXPECT().
+			PublishPluginClusterEvent(mmModel.PluginClusterEvent{Id: clusterEventAvatarChanged, Data: []byte(`{"userId":"user-1"}`)}, gomock.Any()).
+			Return(nil)
+
+		th.pa.BroadcastAvatarChange("user-1")
+	})
+
+	t.Run("changes from other nodes reach the handlers", func(t *testing.T) {
+		changed := []string{}
+		th.pa.OnAvatarChange(func(userID string) {
+			changed = append(changed, userID)
+		})
+
+		th.pa.HandleClusterEvent(mmModel.PluginClusterEvent{Id: clusterEventAvatarChanged, Data: []byte(`{"userId":"user-1"}`)})
+		th.pa.HandleClusterEvent(mmModel.PluginClusterEvent{Id: clusterEventAvatarChanged, Data: []byte(`{}`)})
+		require.Equal(t, []string{"user-1"}, changed)
+	})
+}
+
+func TestGetMissedNotifications(t *testing.T) {
+	th := SetupTestHelper(t)
+
+	userID := mmModel.NewId()
+	notifications := make([]*model.UserNotification, 0, notificationCatchUpLimit+1)
+	for i := notificationCatchUpLimit + 1; i > 0; i-- {
+		notifications = append(notifications, &model.UserNotification{CreateAt: int64(i)})
+	}
+
+	th.store.EXPECT().
+		GetUserNotifications(userID, model.QueryUserNotificationsOptions{Since: 0, Limit: notificationCatchUpLimit + 1}).
+		Return(notifications, nil)
+
+	missed, hasMore, err := getMissedNotifications(th.store, userID, 0)
+	require.NoError(t, err)
+	require.True(t, hasMore)
+	require.Len(t, missed, notificationCatchUpLimit)
+	require.EqualValues(t, 1, missed[0].CreateAt)
+	require.EqualValues(t, notificationCatchUpLimit, missed[len(missed)-1].CreateAt)
+}
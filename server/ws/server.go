@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
@@ -41,18 +42,23 @@ func (wss *websocketSession) isSubscribedToBlock(blockID string) bool {
 	return false
 }
 
+// heartbeatWriteTimeout is how long sending a ping may take before the peer is
+// considered dead.
+const heartbeatWriteTimeout = 10 * time.Second
+
 // Server is a WebSocket server.
 type Server struct {
-	upgrader         websocket.Upgrader
-	listeners        map[*websocketSession]bool
-	listenersByTeam  map[string][]*websocketSession
-	listenersByBlock map[string][]*websocketSession
-	mu               sync.RWMutex
-	auth             *auth.Auth
-	singleUserToken  string
-	isMattermostAuth bool
-	logger           mlog.LoggerIFace
-	store            Store
+	upgrader          websocket.Upgrader
+	listeners         map[*websocketSession]bool
+	listenersByTeam   map[string][]*websocketSession
+	listenersByBlock  map[string][]*websocketSession
+	mu                sync.RWMutex
+	auth              *auth.Auth
+	singleUserToken   string
+	isMattermostAuth  bool
+	logger            mlog.LoggerIFace
+	store             Store
+	heartbeatInterval time.Duration
 }
 
 type websocketSession struct {
@@ -86,6 +92,14 @@ func NewServer(auth *auth.Auth, singleUserToken string, isMattermostAuth bool, l
 	}
 }
 
+// SetHeartbeatInterval makes the server ping every connection at the given interval, so
+// proxies don't close idle connections, and close the connections that don't answer with
+// a pong before the next ping is due. Zero disables the heartbeat. It must be called
+// before the server accepts connections.
+func (ws *Server) SetHeartbeatInterval(interval time.Duration) {
+	ws.heartbeatInterval = interval
+}
+
 // RegisterRoutes registers routes.
 func (ws *Server) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/ws", ws.handleWebSocket)
@@ -114,11 +128,17 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	ws.addListener(wsSession)
 
+	heartbeatDone := make(chan struct{})
+	if ws.heartbeatInterval > 0 {
+		ws.startHeartbeat(wsSession, heartbeatDone)
+	}
+
 	// Make sure we close the connection when the function returns
 	defer func() {
 		ws.logger.Debug("DISCONNECT WebSocket", mlog.Stringer("client", wsSession.conn.RemoteAddr()))
 
 		// Remove session from listeners
+		close(heartbeatDone)
 		ws.removeListener(wsSession)
 		wsSession.conn.Close()
 	}()
@@ -238,12 +258,53 @@ func (ws *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			)
 
 			ws.unsubscribeListenerFromTeam(wsSession, command.TeamID)
+		case websocketActionCatchUpNotifications:
+			ws.logger.Debug(`Command: CATCH_UP_NOTIFICATIONS`,
+				mlog.Int("since", command.Since),
+				mlog.Stringer("client", wsSession.conn.RemoteAddr()),
+			)
+
+			ws.catchUpNotifications(wsSession, command.Since)
 		default:
 			ws.logger.Error(`ERROR webSocket command, invalid action`, mlog.String("action", command.Action))
 		}
 	}
 }
 
+// startHeartbeat pings the peer of a session until done is closed. A peer that doesn't
+// answer before the next ping is due makes the read loop fail, closing the connection.
+func (ws *Server) startHeartbeat(wsSession *websocketSession, done chan struct{}) {
+	pongWait := 2 * ws.heartbeatInterval
+	extendDeadline := func(string) error {
+		return wsSession.conn.SetReadDeadline(time.Now().Add(pongWait))
+	}
+	_ = extendDeadline("")
+	wsSession.conn.SetPongHandler(extendDeadline)
+
+	go func() {
+		ticker := time.NewTicker(ws.heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// WriteControl is safe to call concurrently with the other writes
+				err := wsSession.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeatWriteTimeout))
+				if err != nil {
+					ws.logger.Debug("Cannot ping WebSocket, closing it",
+						mlog.Stringer("client", wsSession.conn.RemoteAddr()),
+						mlog.Err(err),
+					)
+					wsSession.conn.Close()
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
 // isCommandReadTokenValid ensures that a command contains a read
 // token and a set of block ids that said token is valid for.
 func (ws *Server) isCommandReadTokenValid(command WebsocketCommand) bool {
@@ -790,6 +851,53 @@ func (ws *Server) BroadcastCardLimitTimestampChange(cardLimitTimestamp int64) {
 	// not implemented for standalone server.
 }
 
+// catchUpNotifications replays to a single session the notifications its user
+// received after since, which the client missed while it was disconnected.
+func (ws *Server) catchUpNotifications(listener *websocketSession, since int64) {
+	if since <= 0 {
+		return
+	}
+
+	notifications, hasMore, err := getMissedNotifications(ws.store, listener.userID, since)
+	if err != nil {
+		ws.logger.Error("error getting missed notifications",
+			mlog.String("userID", listener.userID),
+			mlog.Err(err),
+		)
+		return
+	}
+
+	for _, notification := range notifications {
+		if !canReceiveNotification(listener.userID, notification) {
+			ws.logger.Error("Dropping catch up notification targeting another user",
+				mlog.String("userID", listener.userID),
+				mlog.String("notificationID", notification.ID),
+			)
+			continue
+		}
+
+		message := UserNotificationMsg{
+			Action:       websocketActionUserNotification,
+			Notification: notification,
+		}
+		if err := listener.WriteJSON(message); err != nil {
+			ws.logger.Error("catch up notification error", mlog.Err(err))
+			listener.conn.Close()
+			return
+		}
+	}
+
+	message := NotificationsCaughtUpMsg{
+		Action:  websocketActionNotificationsCaughtUp,
+		Count:   len(notifications),
+		HasMore: hasMore,
+	}
+	if err := listener.WriteJSON(message); err != nil {
+		ws.logger.Error("catch up notification error", mlog.Err(err))
+		listener.conn.Close()
+	}
+}
+
 // BroadcastUserNotification sends a notification to all sessions for a specific user.
 func (ws *Server) BroadcastUserNotification(targetUserID string, notification *model.UserNotification) {
 	message := UserNotificationMsg{
@@ -803,6 +911,14 @@ func (ws *Server) BroadcastUserNotification(targetUserID string, notification *m
 	// Find all listeners for this user across all teams
 	for listener := range ws.listeners {
 		if listener.userID == targetUserID {
+			if !canReceiveNotification(listener.userID, notification) {
+				ws.logger.Error("Dropping user notification targeting another user",
+					mlog.String("targetUserID", targetUserID),
+					mlog.String("notificationID", notification.ID),
+				)
+				continue
+			}
+
 			ws.logger.Debug("Broadcast user notification",
 				mlog.String("targetUserID", targetUserID),
 				mlog.Stringer("remoteAddr", listener.conn.RemoteAddr()),
@@ -816,3 +932,50 @@ func (ws *Server) BroadcastUserNotification(targetUserID string, notification *m
 		}
 	}
 }
+
+// BroadcastUnreadNotificationCount sends the number of unread notifications, and of each
+// type if countsByType is not nil, to all sessions of a user.
+func (ws *Server) BroadcastUnreadNotificationCount(userID string, count int, countsByType map[string]int) {
+	message := UnreadNotificationCountMsg{
+		Action:       websocketActionUpdateUnreadCount,
+		Count:        count,
+		CountsByType: countsByType,
+	}
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	for listener := range ws.listeners {
+		if listener.userID != userID {
+			continue
+		}
+		if err := listener.WriteJSON(message); err != nil {
+			ws.logger.Error("broadcast unread notification count error", mlog.Err(err))
+			listener.conn.Close()
+		}
+	}
+}
+
+// ConnectedUserIDs returns the users with at least one authenticated session, each once.
+func (ws *Server) ConnectedUserIDs() []string {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	seen := map[string]bool{}
+	userIDs := []string{}
+	for listener := range ws.listeners {
+		if listener.isAuthenticated() && !seen[listener.userID] {
+			seen[listener.userID] = true
+			userIDs = append(userIDs, listener.userID)
+		}
+	}
+	return userIDs
+}
+
+// BroadcastAvatarChange does nothing: a standalone server has no other nodes whose avatar
+// cache could be stale.
+func (ws *Server) BroadcastAvatarChange(userID string) {}
+
+// OnAvatarChange does nothing: a standalone server never receives avatar changes from
+// other nodes.
+func (ws *Server) OnAvatarChange(handler func(userID string)) {}
@@ -1,14 +1,19 @@
 package ws
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/mattermost/focalboard/server/auth"
 	"github.com/mattermost/focalboard/server/model"
 
 	"github.com/mattermost/mattermost/server/public/shared/mlog"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
 )
@@ -281,3 +286,116 @@ func TestGetUserIDForTokenInSingleUserMode(t *testing.T) {
 		require.Equal(t, model.SingleUser, server.getUserIDForToken(singleUserToken))
 	})
 }
+
+func TestBroadcastUserNotificationIsolation(t *testing.T) {
+	server := NewServer(&auth.Auth{}, "", true, mlog.CreateConsoleTestLogger(t), nil)
+	router := mux.NewRouter()
+	server.RegisterRoutes(router)
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+
+	connect := func(userID string) *websocket.Conn {
+		header := http.Header{}
+		header.Set("Mattermost-User-Id", userID)
+		conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http")+"/ws", header)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return conn
+	}
+	connA := connect("user-a")
+	defer connA.Close()
+	connB := connect("user-b")
+	defer connB.Close()
+
+	require.Eventually(t, func() bool {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+		return len(server.listeners) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	notification := &model.UserNotification{ID: "notification-1", TargetUserID: "user-b"}
+
+	// miswired target, the notification of user B must not reach user A
+	server.BroadcastUserNotification("user-a", notification)
+	server.BroadcastUserNotification("user-b", notification)
+
+	var message UserNotificationMsg
+	require.NoError(t, connB.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, connB.ReadJSON(&message))
+	require.Equal(t, "notification-1", message.Notification.ID)
+
+	require.NoError(t, connA.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err := connA.ReadMessage()
+	require.Error(t, err)
+}
+
+func TestCanReceiveNotification(t *testing.T) {
+	notification := &model.UserNotification{TargetUserID: "user-b"}
+
+	require.True(t, canReceiveNotification("user-b", notification))
+	require.False(t, canReceiveNotification("user-a", notification))
+	require.False(t, canReceiveNotification("", &model.UserNotification{}))
+	require.False(t, canReceiveNotification("user-b", nil))
+}
+
+func TestWebSocketHeartbeat(t *testing.T) {
+	server := NewServer(&auth.Auth{}, "", true, mlog.CreateConsoleTestLogger(t), nil)
+	server.SetHeartbeatInterval(50 * time.Millisecond)
+	router := mux.NewRouter()
+	server.RegisterRoutes(router)
+	httpServer := httptest.NewServer(router)
+	defer httpServer.Close()
+
+	connect := func(userID string) *websocket.Conn {
+		header := http.Header{}
+		header.Set("Mattermost-User-Id", userID)
+		conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http")+"/ws", header)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return conn
+	}
+	listenerCount := func() int {
+		server.mu.RLock()
+		defer server.mu.RUnlock()
+		return len(server.listeners)
+	}
+
+	t.Run("live peers are pinged and kept open", func(t *testing.T) {
+		conn := connect("user-a")
+		defer conn.Close()
+
+		pings := make(chan struct{}, 10)
+		conn.SetPingHandler(func(data string) error {
+			pings <- struct{}{}
+			return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		// reading processes the pings
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for i := 0; i < 3; i++ {
+			select {
+			case <-pings:
+			case <-time.After(time.Second):
+				require.Fail(t, "no ping received")
+			}
+		}
+		require.Equal(t, 1, listenerCount())
+	})
+
+	require.Eventually(t, func() bool { return listenerCount() == 0 }, time.Second, 10*time.Millisecond)
+
+	t.Run("dead peers are closed", func(t *testing.T) {
+		// never reading means never answering the pings
+		conn := connect("user-b")
+		defer conn.Close()
+
+		require.Eventually(t, func() bool { return listenerCount() == 1 }, time.Second, 10*time.Millisecond)
+		require.Eventually(t, func() bool { return listenerCount() == 0 }, time.Second, 10*time.Millisecond)
+	})
+}
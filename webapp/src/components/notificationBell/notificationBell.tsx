@@ -139,7 +139,17 @@ const NotificationBell = (): JSX.Element => {
                     />
                 )
             default:
-                return <span>{notification.cardTitle}</span>
+                // Custom notification types registered on the server render generically
+                return (
+                    <FormattedMessage
+                        id='NotificationBell.generic'
+                        defaultMessage='{actorName}: "{cardTitle}"'
+                        values={{
+                            actorName: <strong>{notification.actorName || 'Someone'}</strong>,
+                            cardTitle: notification.cardTitle,
+                        }}
+                    />
+                )
         }
     }
 
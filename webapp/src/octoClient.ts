@@ -1118,15 +1118,85 @@ class OctoClient {
 
     // User Notifications API
 
-    async getNotifications(limit = 50): Promise<UserNotification[]> {
-        const path = `/api/v2/notifications?limit=${limit}`
+    // With markReadOnFetch, the returned notifications are marked as read on the server
+    async getNotifications(limit = 50, markReadOnFetch = false): Promise<UserNotification[]> {
+        let path = `/api/v2/notifications?limit=${limit}`
+        if (markReadOnFetch) {
+            path += '&markReadOnFetch=true'
+        }
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return []
+        }
+        return (await this.getJson(response, [])) as UserNotification[]
+    }
+
+    // Returns the notifications along with the global unread count, to update the badge
+    // from the same request
+    async getNotificationsWithUnreadCount(limit = 50, markReadOnFetch = false): Promise<{notifications: UserNotification[], unreadCount: number}> {
+        let path = `/api/v2/notifications?limit=${limit}`
+        if (markReadOnFetch) {
+            path += '&markReadOnFetch=true'
+        }
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return {notifications: [], unreadCount: 0}
+        }
+        const unreadCount = parseInt(response.headers.get('X-Unread-Count') || '0', 10)
+        const notifications = (await this.getJson(response, [])) as UserNotification[]
+        return {notifications, unreadCount}
+    }
+
+    // Returns a page of notifications, from the main list or scoped to a card or a board.
+    // Pass an empty cursor for the first page, then the nextCursor of the previous page.
+    async getNotificationsPage(cursor = '', limit = 50, scope?: {cardId?: string, boardId?: string}): Promise<NotificationsPage> {
+        let path = '/api/v2/notifications'
+        if (scope?.cardId) {
+            path += `/by-card/${encodeURIComponent(scope.cardId)}`
+        } else if (scope?.boardId) {
+            path += `/by-board/${encodeURIComponent(scope.boardId)}`
+        }
+        path += `?limit=${limit}&cursor=${encodeURIComponent(cursor)}`
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return {hasMore: false, results: []}
+        }
+        return (await this.getJson(response, {hasMore: false, results: []})) as NotificationsPage
+    }
+
+    // Returns the page of notifications created before a time in milliseconds since epoch,
+    // or before a notification ID such as the nextBefore of the previous page.
+    async getNotificationsBefore(before: string | number, limit = 50): Promise<NotificationsPage> {
+        const path = `/api/v2/notifications?limit=${limit}&before=${encodeURIComponent(String(before))}`
         const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return {hasMore: false, results: []}
+        }
+        return (await this.getJson(response, {hasMore: false, results: []})) as NotificationsPage
+    }
+
+    async queryNotifications(request: NotificationQueryRequest): Promise<UserNotification[]> {
+        const path = '/api/v2/notifications/query'
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+            body: JSON.stringify(request),
+        })
         if (response.status !== 200) {
             return []
         }
         return (await this.getJson(response, [])) as UserNotification[]
     }
 
+    async getNotificationThreads(limit = 20): Promise<NotificationThread[]> {
+        const path = `/api/v2/notifications/threads?limit=${limit}`
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return []
+        }
+        return (await this.getJson(response, [])) as NotificationThread[]
+    }
+
     async getUnreadNotificationCount(): Promise<number> {
         const path = '/api/v2/notifications/unread-count'
         const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
@@ -1137,6 +1207,36 @@ class OctoClient {
         return data.count
     }
 
+    // Returns the unread count capped at countCap, flagging larger counts as truncated
+    async getUnreadNotificationBadge(countCap: number): Promise<UnreadNotificationCount> {
+        const path = `/api/v2/notifications/unread-count?cap=${countCap}`
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return {count: 0, truncated: false}
+        }
+        return (await this.getJson(response, {count: 0, truncated: false})) as UnreadNotificationCount
+    }
+
+    // Returns the create time of the oldest unread notification, or null if nothing is unread
+    async getOldestUnreadNotificationTime(): Promise<number | null> {
+        const path = '/api/v2/notifications/oldest-unread'
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return null
+        }
+        const data = (await this.getJson(response, {timestamp: null})) as {timestamp: number | null}
+        return data.timestamp
+    }
+
+    async getNotificationSummary(): Promise<NotificationSummary> {
+        const path = '/api/v2/notifications/summary'
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return {total: 0, unread: 0, read: 0}
+        }
+        return (await this.getJson(response, {total: 0, unread: 0, read: 0})) as NotificationSummary
+    }
+
     async createNotification(notification: Partial<UserNotification>): Promise<UserNotification | undefined> {
         const path = '/api/v2/notifications'
         const response = await fetch(this.getBaseURL() + path, {
@@ -1150,8 +1250,31 @@ class OctoClient {
         return (await this.getJson(response, undefined)) as UserNotification | undefined
     }
 
-    async markNotificationAsRead(notificationId: string): Promise<boolean> {
+    async getNotification(notificationId: string): Promise<UserNotification | undefined> {
+        const path = `/api/v2/notifications/${encodeURIComponent(notificationId)}`
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return undefined
+        }
+        return (await this.getJson(response, undefined)) as UserNotification | undefined
+    }
+
+    // deviceId is optional, clients sending it get the devices a notification was read on
+    async markNotificationAsRead(notificationId: string, deviceId?: string): Promise<boolean> {
         const path = `/api/v2/notifications/${notificationId}/read`
+        const headers: Record<string, string> = this.headers()
+        if (deviceId) {
+            headers['X-Device-Id'] = deviceId
+        }
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers,
+        })
+        return response.status === 200
+    }
+
+    async markNotificationAsUnread(notificationId: string): Promise<boolean> {
+        const path = `/api/v2/notifications/${notificationId}/unread`
         const response = await fetch(this.getBaseURL() + path, {
             method: 'POST',
             headers: this.headers(),
@@ -1159,6 +1282,34 @@ class OctoClient {
         return response.status === 200
     }
 
+    async performNotificationAction(notificationId: string, action: string): Promise<UserNotification | undefined> {
+        const path = `/api/v2/notifications/${notificationId}/action`
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+            body: JSON.stringify({action}),
+        })
+        if (response.status !== 200) {
+            return undefined
+        }
+        return (await this.getJson(response, {})) as UserNotification
+    }
+
+    async markNotificationsAsRead(notificationIds: string[]): Promise<number | undefined> {
+        const path = '/api/v2/notifications/read'
+        const body = JSON.stringify({ids: notificationIds})
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+            body,
+        })
+        if (response.status !== 200) {
+            return undefined
+        }
+        const {count} = (await this.getJson(response, {})) as {count: number}
+        return count
+    }
+
     async markAllNotificationsAsRead(): Promise<boolean> {
         const path = '/api/v2/notifications/read-all'
         const response = await fetch(this.getBaseURL() + path, {
@@ -1176,6 +1327,60 @@ class OctoClient {
         })
         return response.status === 200
     }
+
+    async getNotificationActorBlocks(): Promise<NotificationActorBlock[]> {
+        const path = '/api/v2/notifications/blocked-actors'
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return []
+        }
+        return (await this.getJson(response, [])) as NotificationActorBlock[]
+    }
+
+    async blockNotificationActor(actorId: string): Promise<boolean> {
+        const path = `/api/v2/notifications/blocked-actors/${encodeURIComponent(actorId)}`
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+        })
+        return response.status === 200
+    }
+
+    async unblockNotificationActor(actorId: string): Promise<boolean> {
+        const path = `/api/v2/notifications/blocked-actors/${encodeURIComponent(actorId)}`
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'DELETE',
+            headers: this.headers(),
+        })
+        return response.status === 200
+    }
+
+    async getCardWatchers(cardId: string): Promise<CardWatcher[]> {
+        const path = `/api/v2/cards/${encodeURIComponent(cardId)}/watchers`
+        const response = await fetch(this.getBaseURL() + path, {headers: this.headers()})
+        if (response.status !== 200) {
+            return []
+        }
+        return (await this.getJson(response, [])) as CardWatcher[]
+    }
+
+    async watchCard(cardId: string): Promise<boolean> {
+        const path = `/api/v2/cards/${encodeURIComponent(cardId)}/watch`
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'POST',
+            headers: this.headers(),
+        })
+        return response.status === 200
+    }
+
+    async unwatchCard(cardId: string): Promise<boolean> {
+        const path = `/api/v2/cards/${encodeURIComponent(cardId)}/watch`
+        const response = await fetch(this.getBaseURL() + path, {
+            method: 'DELETE',
+            headers: this.headers(),
+        })
+        return response.status === 200
+    }
 }
 
 // UserNotification type
@@ -1188,11 +1393,112 @@ export interface UserNotification {
     cardId: string
     cardTitle: string
     boardId: string
+    teamId?: string
     read: boolean
+    silent: boolean
+    urgency: 'low' | 'normal' | 'high'
+    reason?: NotificationReason
+    source?: NotificationSource
+    collapseKey?: string
+    ephemeral?: boolean
+    actions?: NotificationAction[]
+    resolvedAction?: string
+    coRecipients?: string[]
+    message?: string
+    params?: Record<string, string>
+    card?: NotificationCardSnapshot
+    deviceReads?: NotificationDeviceRead[]
     createAt: number
     updateAt: number
 }
 
+export type NotificationReason = 'assignee' | 'watcher' | 'mentioned' | 'board_mention' | 'board_admin' | 'digest' | 'direct'
+
+export type NotificationSource = 'api' | 'webhook' | 'job' | 'plugin' | 'server'
+
+export interface NotificationsPage {
+    hasMore: boolean
+    nextCursor?: string
+    nextBefore?: string
+    results: UserNotification[]
+}
+
+export interface NotificationDeviceRead {
+    notificationId: string
+    deviceId: string
+    readAt: number
+}
+
+export interface NotificationCardSnapshot {
+    id: string
+    title?: string
+    icon?: string
+    status?: string
+    deleted?: boolean
+    updateAt?: number
+}
+
+export interface NotificationFilter {
+    types?: string[]
+    boardIds?: string[]
+    excludeBoardIds?: string[]
+    cardIds?: string[]
+    teamId?: string
+    actorUserIds?: string[]
+    read?: boolean
+    from?: number
+    to?: number
+}
+
+export interface NotificationQuery extends NotificationFilter {
+    anyOf?: NotificationFilter[]
+}
+
+export interface NotificationQueryRequest {
+    query: NotificationQuery
+    limit?: number
+    includeArchived?: boolean
+    orderBy?: 'createAt' | 'updateAt'
+    ascending?: boolean
+}
+
+export interface NotificationActorBlock {
+    userId: string
+    blockedActorId: string
+    createAt: number
+}
+
+export interface CardWatcher {
+    cardId: string
+    userId: string
+    createAt: number
+}
+
+export interface NotificationAction {
+    key: string
+    label: string
+}
+
+export interface NotificationThread {
+    cardId: string
+    boardId: string
+    latest: UserNotification
+    notifications: UserNotification[]
+    unreadCount: number
+    read: boolean
+}
+
+export interface UnreadNotificationCount {
+    count: number
+    truncated: boolean
+}
+
+export interface NotificationSummary {
+    total: number
+    unread: number
+    read: number
+}
+
 const octoClient = new OctoClient()
 
 export {OctoClient}
@@ -134,9 +134,23 @@ const notificationsSlice = createSlice({
 
             console.log('[Notifications] receiveNotification via WebSocket:', notification)
 
+            // Ephemeral notifications are never stored on the server, so they are only
+            // shown as a toast and can't be marked as read or deleted later
+            if ('ephemeral' in action.payload && action.payload.ephemeral) {
+                state.currentToast = notification
+                state.showToast = true
+                return
+            }
+
             // Check if we already have this notification
-            const exists = state.notifications.some(n => n.id === notification.id)
-            if (!exists) {
+            const index = state.notifications.findIndex(n => n.id === notification.id)
+            if (index !== -1) {
+                // A corrected notification pushed again replaces the one we have, without a toast
+                state.notifications[index] = notification
+                if (state.currentToast?.id === notification.id) {
+                    state.currentToast = notification
+                }
+            } else {
                 state.notifications.unshift(notification)
                 state.currentToast = notification
                 state.showToast = true
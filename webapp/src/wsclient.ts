@@ -16,6 +16,7 @@ type WSCommand = {
     teamId?: string
     readToken?: string
     blockIds?: string[]
+    since?: number
 }
 
 // These are messages from the server
@@ -31,6 +32,8 @@ export type WSMessage = {
     timestamp?: number
     categoryOrder?: string[]
     notification?: UserNotification
+    count?: number
+    hasMore?: boolean
 }
 
 export const ACTION_UPDATE_BOARD = 'UPDATE_BOARD'
@@ -49,6 +52,8 @@ export const ACTION_UPDATE_SUBSCRIPTION = 'UPDATE_SUBSCRIPTION'
 export const ACTION_UPDATE_CARD_LIMIT_TIMESTAMP = 'UPDATE_CARD_LIMIT_TIMESTAMP'
 export const ACTION_REORDER_CATEGORIES = 'REORDER_CATEGORIES'
 export const ACTION_USER_NOTIFICATION = 'USER_NOTIFICATION'
+export const ACTION_CATCH_UP_NOTIFICATIONS = 'CATCH_UP_NOTIFICATIONS'
+export const ACTION_USER_NOTIFICATIONS_CAUGHT_UP = 'USER_NOTIFICATIONS_CAUGHT_UP'
 
 type WSSubscriptionMsg = {
     action?: string
@@ -123,6 +128,7 @@ class WSClient {
     state: 'init'|'open'|'close' = 'init'
     onStateChange: OnStateChangeHandler[] = []
     onReconnect: OnReconnectHandler[] = []
+    lastNotificationAt = 0
     onChange: ChangeHandlers = {Block: [], Category: [], BoardCategory: [], Board: [], BoardMember: [], CategoryReorder: []}
     onError: OnErrorHandler[] = []
     onConfigChange: OnConfigChangeHandler[] = []
@@ -220,6 +226,21 @@ class WSClient {
         this.sendCommand(command)
     }
 
+    // when reconnecting, asks the server to replay the notifications
+    // broadcast while the connection was down
+    sendCatchUpNotificationsCommand(): void {
+        if (this.lastNotificationAt <= 0) {
+            return
+        }
+
+        const command: WSCommand = {
+            action: ACTION_CATCH_UP_NOTIFICATIONS,
+            since: this.lastNotificationAt,
+        }
+
+        this.sendCommand(command)
+    }
+
     addOnChange(handler: OnChangeHandler, type: ChangeHandlerType): void {
         switch (type) {
         case 'block':
@@ -362,6 +383,7 @@ class WSClient {
                 Utils.logWarn('WSClient reconnected')
 
                 onConnect()
+                this.sendCatchUpNotificationsCommand()
                 for (const handler of this.onReconnect) {
                     handler(this)
                 }
@@ -429,6 +451,10 @@ class WSClient {
             // send their subscribe messages
             this.subscribe()
 
+            if (this.token) {
+                this.sendCatchUpNotificationsCommand()
+            }
+
             for (const handler of this.onStateChange) {
                 handler(this, 'open')
             }
@@ -508,6 +534,9 @@ class WSClient {
                 case ACTION_USER_NOTIFICATION:
                     this.notificationHandler(message)
                     break
+                case ACTION_USER_NOTIFICATIONS_CAUGHT_UP:
+                    Utils.log(`WSClient replayed ${message.count} notifications, more available: ${message.hasMore}`)
+                    break
                 default:
                     Utils.logError(`Unexpected action: ${message.action}`)
                 }
@@ -572,6 +601,8 @@ class WSClient {
             return
         }
 
+        this.lastNotificationAt = Math.max(this.lastNotificationAt, message.notification.createAt)
+
         for (const handler of this.onNotification) {
             handler(this, message.notification)
         }